@@ -0,0 +1,403 @@
+/*
+Etcdsnapshot transfers the etcd snapshots taken by RKE2 and K3s between the etcd nodes and the backup targets the
+distributions can not upload to themselves. It is run on the etcd nodes from the agent image by the plans of the nodes.
+*/
+
+package etcdsnapshot
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rancher/rancher/pkg/etcdsnapshot"
+	"github.com/rancher/rancher/pkg/etcdsnapshot/target"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	installPath = "/var/lib/rancher/etcd-snapshot/agent"
+	unitName    = "rancher-etcd-snapshot-sync"
+	unitDir     = "/etc/systemd/system"
+	// defaultConnectionInfoPath is written by the system agent, its service account can read the etcd snapshot secret
+	defaultConnectionInfoPath = "/var/lib/rancher/agent/rancher2_connection_info.json"
+)
+
+type options struct {
+	config         string
+	credentials    string
+	secret         string
+	connectionInfo string
+	dir            string
+	name           string
+	retention      int
+	interval       time.Duration
+}
+
+func usage() string {
+	return fmt.Sprintf(`etcd-snapshot usage:
+    sync --config=FILE --dir=DIR [--retention=N]         upload the snapshots missing in the target and delete expired ones
+    download --config=FILE --dir=DIR --name=SNAPSHOT     download a snapshot from the target
+    install --config=FILE --dir=DIR [--retention=N] [--interval=DURATION]
+                                                         sync the snapshots periodically with a systemd timer
+
+    --secret=NAME reads the credentials of the target in the --credentials=KEY entry and the encryption keys from the
+    etcd snapshot secret of the cluster, with the connection of the system agent in --connection-info=FILE. With
+    encryption keys, the uploaded snapshots are encrypted with the active key and the downloaded ones are decrypted.`)
+}
+
+func Run(ctx context.Context, args []string) error {
+	if len(args) < 3 {
+		fmt.Println(usage())
+		return nil
+	}
+
+	opts := options{}
+	flags := flag.NewFlagSet(args[2], flag.ContinueOnError)
+	flags.StringVar(&opts.config, "config", "", "target config file")
+	flags.StringVar(&opts.credentials, "credentials", "", "entry of the etcd snapshot secret holding the target credentials")
+	flags.StringVar(&opts.secret, "secret", "", "etcd snapshot secret of the cluster")
+	flags.StringVar(&opts.connectionInfo, "connection-info", defaultConnectionInfoPath, "connection info file of the system agent")
+	flags.StringVar(&opts.dir, "dir", "", "snapshot directory of the etcd node")
+	flags.StringVar(&opts.name, "name", "", "snapshot to download")
+	flags.IntVar(&opts.retention, "retention", 0, "number of snapshots kept in the target, 0 keeps all")
+	flags.DurationVar(&opts.interval, "interval", 15*time.Minute, "interval of the periodic sync")
+	if err := flags.Parse(args[3:]); err != nil {
+		return err
+	}
+	if opts.config == "" || opts.dir == "" {
+		fmt.Println(usage())
+		return fmt.Errorf("--config and --dir are required")
+	}
+
+	switch args[2] {
+	case "sync":
+		return syncTarget(ctx, opts)
+	case "download":
+		return download(ctx, opts)
+	case "install":
+		return install(opts)
+	default:
+		fmt.Println(usage())
+		return nil
+	}
+}
+
+// connectionInfo is the connection of the system agent to rancher.
+type connectionInfo struct {
+	KubeConfig string `json:"kubeConfig"`
+	Namespace  string `json:"namespace"`
+}
+
+// readSecret returns the etcd snapshot secret of the cluster, it is read on every run so that the credentials and keys
+// are never stored on the node.
+func readSecret(ctx context.Context, opts options) (map[string][]byte, error) {
+	if opts.secret == "" {
+		return nil, nil
+	}
+	data, err := ioutil.ReadFile(opts.connectionInfo)
+	if err != nil {
+		return nil, err
+	}
+	info := &connectionInfo{}
+	if err := json.Unmarshal(data, info); err != nil {
+		return nil, fmt.Errorf("invalid connection info %s: %w", opts.connectionInfo, err)
+	}
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig([]byte(info.KubeConfig))
+	if err != nil {
+		return nil, err
+	}
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := client.CoreV1().Secrets(info.Namespace).Get(ctx, opts.secret, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get etcd snapshot secret %s: %w", opts.secret, err)
+	}
+	return secret.Data, nil
+}
+
+func readKeyRing(secret map[string][]byte) (*etcdsnapshot.KeyRing, error) {
+	data, ok := secret[etcdsnapshot.KeyRingSecretKey]
+	if !ok {
+		return nil, nil
+	}
+	ring := &etcdsnapshot.KeyRing{}
+	if err := json.Unmarshal(data, ring); err != nil {
+		return nil, fmt.Errorf("invalid encryption keys: %w", err)
+	}
+	return ring, nil
+}
+
+func newTarget(ctx context.Context, opts options, secret map[string][]byte) (target.Target, error) {
+	data, err := ioutil.ReadFile(opts.config)
+	if err != nil {
+		return nil, err
+	}
+	config := &target.Config{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("invalid target config %s: %w", opts.config, err)
+	}
+	if opts.credentials != "" {
+		credentials, ok := secret[opts.credentials]
+		if !ok {
+			return nil, fmt.Errorf("target credentials %s not found in etcd snapshot secret %s", opts.credentials, opts.secret)
+		}
+		if err := json.Unmarshal(credentials, &config.Credentials); err != nil {
+			return nil, fmt.Errorf("invalid target credentials: %w", err)
+		}
+	}
+	return target.New(ctx, config)
+}
+
+// syncTarget uploads the local snapshots missing in the target, and deletes the snapshots exceeding the retention from it.
+// Only the active key is given to the nodes, snapshots encrypted with a key that was rotated keep their key.
+func syncTarget(ctx context.Context, opts options) error {
+	secret, err := readSecret(ctx, opts)
+	if err != nil {
+		return err
+	}
+	t, err := newTarget(ctx, opts, secret)
+	if err != nil {
+		return err
+	}
+	ring, err := readKeyRing(secret)
+	if err != nil {
+		return err
+	}
+
+	remote, err := t.List(ctx)
+	if err != nil {
+		return err
+	}
+	uploaded := map[string]bool{}
+	for _, object := range remote {
+		uploaded[object.Name] = true
+	}
+
+	entries, err := ioutil.ReadDir(opts.dir)
+	if os.IsNotExist(err) {
+		entries = nil
+	} else if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.Mode().IsRegular() || strings.HasPrefix(entry.Name(), ".") || uploaded[entry.Name()] {
+			continue
+		}
+		logrus.Infof("Uploading etcd snapshot %s", entry.Name())
+		if err := uploadFile(ctx, t, ring, opts.dir, entry.Name()); err != nil {
+			return fmt.Errorf("failed to upload etcd snapshot %s: %w", entry.Name(), err)
+		}
+	}
+
+	deleted, err := target.Rotate(ctx, t, opts.retention)
+	for _, name := range deleted {
+		logrus.Infof("Deleted expired etcd snapshot %s", name)
+	}
+	return err
+}
+
+func uploadFile(ctx context.Context, t target.Target, ring *etcdsnapshot.KeyRing, dir, name string) error {
+	f, err := os.Open(filepath.Join(dir, name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return upload(ctx, t, ring, dir, name, f)
+}
+
+// upload stores the snapshot in the target, encrypted if there are encryption keys. Encrypted snapshots are staged in
+// the snapshot directory, as their size has to be known.
+func upload(ctx context.Context, t target.Target, ring *etcdsnapshot.KeyRing, dir, name string, r io.Reader) error {
+	if ring == nil {
+		if f, ok := r.(*os.File); ok {
+			info, err := f.Stat()
+			if err != nil {
+				return err
+			}
+			return t.Upload(ctx, name, f, info.Size())
+		}
+	}
+
+	tmp, err := ioutil.TempFile(dir, "."+name+"-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if ring == nil {
+		_, err = io.Copy(tmp, r)
+	} else {
+		err = encrypt(tmp, r, ring)
+	}
+	if err != nil {
+		return err
+	}
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return t.Upload(ctx, name, tmp, size)
+}
+
+func encrypt(w io.Writer, r io.Reader, ring *etcdsnapshot.KeyRing) error {
+	ew, err := etcdsnapshot.Encrypt(w, ring)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(ew, r); err != nil {
+		return err
+	}
+	return ew.Close()
+}
+
+// decrypt returns the decrypted snapshot if it is encrypted, and the snapshot itself otherwise.
+func decrypt(r *bufio.Reader, ring *etcdsnapshot.KeyRing) (io.Reader, error) {
+	if !etcdsnapshot.IsEncrypted(r) {
+		return r, nil
+	}
+	snapshot, _, err := etcdsnapshot.Decrypt(r, ring)
+	return snapshot, err
+}
+
+// download stores the snapshot in the snapshot directory, where it is restored from. Encrypted snapshots are decrypted.
+func download(ctx context.Context, opts options) error {
+	if opts.name == "" || opts.name != filepath.Base(opts.name) {
+		return fmt.Errorf("invalid snapshot name %q", opts.name)
+	}
+
+	secret, err := readSecret(ctx, opts)
+	if err != nil {
+		return err
+	}
+	t, err := newTarget(ctx, opts, secret)
+	if err != nil {
+		return err
+	}
+	ring, err := readKeyRing(secret)
+	if err != nil {
+		return err
+	}
+	rc, err := t.Download(ctx, opts.name)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	snapshot, err := decrypt(bufio.NewReader(rc), ring)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(opts.dir, 0700); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(opts.dir, "."+opts.name+"-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, snapshot); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	logrus.Infof("Downloaded etcd snapshot %s", opts.name)
+	return os.Rename(tmp.Name(), filepath.Join(opts.dir, opts.name))
+}
+
+// install copies the agent binary to the node and enables a systemd timer running the sync, so that the snapshots
+// taken on schedule by the distributions are uploaded as well.
+func install(opts options) error {
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if err := copyFile(self, installPath, 0755); err != nil {
+		return err
+	}
+
+	service := fmt.Sprintf(`[Unit]
+Description=Upload etcd snapshots to the backup target
+After=network-online.target
+
+[Service]
+Type=oneshot
+ExecStart=%s etcd-snapshot sync --config=%s --credentials=%s --secret=%s --connection-info=%s --dir=%s --retention=%d
+`, installPath, opts.config, opts.credentials, opts.secret, opts.connectionInfo, opts.dir, opts.retention)
+	timer := fmt.Sprintf(`[Unit]
+Description=Upload etcd snapshots to the backup target periodically
+
+[Timer]
+OnBootSec=%[1]s
+OnUnitActiveSec=%[1]s
+
+[Install]
+WantedBy=timers.target
+`, opts.interval)
+
+	if err := ioutil.WriteFile(filepath.Join(unitDir, unitName+".service"), []byte(service), 0644); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(unitDir, unitName+".timer"), []byte(timer), 0644); err != nil {
+		return err
+	}
+
+	for _, args := range [][]string{
+		{"daemon-reload"},
+		{"enable", "--now", unitName + ".timer"},
+		// sync right away, the timer only fires after the interval
+		{"start", "--no-block", unitName + ".service"},
+	} {
+		if output, err := exec.Command("systemctl", args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("systemctl %s: %w: %s", strings.Join(args, " "), err, output)
+		}
+	}
+	return nil
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	// write next to the destination and rename, the installed binary may be running
+	tmp := dst + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
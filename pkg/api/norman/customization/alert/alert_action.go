@@ -11,6 +11,7 @@
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/rbac"
 	"github.com/rancher/rancher/pkg/types/config/dialer"
+	corecontrollers "github.com/rancher/wrangler/pkg/generated/controllers/core/v1"
 	"github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -19,6 +20,7 @@ type Handler struct {
 	ClusterAlertRule v3.ClusterAlertRuleInterface
 	ProjectAlertRule v3.ProjectAlertRuleInterface
 	Notifiers        v3.NotifierInterface
+	ConfigMaps       corecontrollers.ConfigMapClient
 	DialerFactory    dialer.Factory
 }
 
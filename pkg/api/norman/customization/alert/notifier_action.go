@@ -4,6 +4,8 @@
 	"context"
 	"encoding/json"
 	"io/ioutil"
+	"net/http"
+	"time"
 
 	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 
@@ -30,12 +32,17 @@ func NotifierFormatter(apiContext *types.APIContext, resource *types.RawResource
 	if canCreateNotifier(apiContext, resource, "") {
 		resource.AddAction(apiContext, "send")
 	}
+	if canUpdateNotifier(apiContext, resource) {
+		resource.AddAction(apiContext, "resend")
+	}
 }
 
 func (h *Handler) NotifierActionHandler(actionName string, action *types.Action, apiContext *types.APIContext) error {
 	switch actionName {
 	case "send":
 		return h.testNotifier(apiContext.Request.Context(), actionName, action, apiContext)
+	case "resend":
+		return h.resendNotifier(apiContext)
 	}
 
 	return httperror.NewAPIError(httperror.InvalidAction, "invalid action: "+actionName)
@@ -82,6 +89,10 @@ func (h *Handler) testNotifier(ctx context.Context, actionName string, action *t
 	if notifier.Spec.SMTPConfig != nil {
 		notifierMessage.Title = testSMTPTitle
 	}
+	if notifier.Spec.Template != nil {
+		// give the templates an alert to render, so that they can be tried before an alert fires
+		notifierMessage.Alert = testAlert(clientNotifier.ClusterID)
+	}
 
 	dialer, err := h.DialerFactory.ClusterDialer(clientNotifier.ClusterID)
 	if err != nil {
@@ -90,6 +101,60 @@ func (h *Handler) testNotifier(ctx context.Context, actionName string, action *t
 	return notifiers.SendMessage(ctx, notifier, "", notifierMessage, dialer)
 }
 
+// resendNotifier moves dead letters of the notifier back to its delivery queue.
+func (h *Handler) resendNotifier(apiContext *types.APIContext) error {
+	if !canUpdateNotifier(apiContext, nil) {
+		return httperror.NewAPIError(httperror.NotFound, "not found")
+	}
+
+	data, err := ioutil.ReadAll(apiContext.Request.Body)
+	if err != nil {
+		return errors.Wrap(err, "reading request body error")
+	}
+	input := &v32.NotifierResendInput{}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, input); err != nil {
+			return httperror.NewAPIError(httperror.InvalidBodyContent, err.Error())
+		}
+	}
+
+	ns, id := ref.Parse(apiContext.ID)
+	notifier, err := h.Notifiers.GetNamespaced(ns, id, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	resent := 0
+	if _, err := notifiers.UpdateQueue(h.ConfigMaps, notifier, func(q *notifiers.Queue) bool {
+		resent = q.Resend(input.DeadLetterIDs, time.Now())
+		return resent > 0
+	}); err != nil {
+		return err
+	}
+
+	apiContext.WriteResponse(http.StatusOK, map[string]interface{}{
+		"resent": resent,
+	})
+	return nil
+}
+
+func testAlert(clusterID string) *notifiers.Alert {
+	return &notifiers.Alert{
+		Status:      notifiers.AlertStatusFiring,
+		Name:        "Test alert",
+		Type:        "test",
+		Severity:    "info",
+		ClusterID:   clusterID,
+		ClusterName: clusterID,
+		StartsAt:    time.Now(),
+		Labels: map[string]string{
+			"alert_name": "Test alert",
+			"alert_type": "test",
+			"severity":   "info",
+		},
+	}
+}
+
 func canCreateNotifier(apiContext *types.APIContext, resource *types.RawResource, clusterID string) bool {
 	obj := rbac.ObjFromContext(apiContext, resource)
 	if clusterID != "" {
@@ -97,3 +162,8 @@ func canCreateNotifier(apiContext *types.APIContext, resource *types.RawResource
 	}
 	return apiContext.AccessControl.CanDo(v3.NotifierGroupVersionKind.Group, v3.NotifierResource.Name, "create", apiContext, obj, apiContext.Schema) == nil
 }
+
+func canUpdateNotifier(apiContext *types.APIContext, resource *types.RawResource) bool {
+	obj := rbac.ObjFromContext(apiContext, resource)
+	return apiContext.AccessControl.CanDo(v3.NotifierGroupVersionKind.Group, v3.NotifierResource.Name, "update", apiContext, obj, apiContext.Schema) == nil
+}
@@ -10,6 +10,8 @@
 	"github.com/rancher/norman/types"
 	"github.com/rancher/norman/types/convert"
 	v3client "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"github.com/rancher/rancher/pkg/controllers/managementuserlegacy/alert/silence"
+	"github.com/rancher/rancher/pkg/notifiers"
 	"github.com/rancher/rancher/pkg/ref"
 )
 
@@ -75,3 +77,35 @@ func ProjectAlertRuleValidator(resquest *types.APIContext, schema *types.Schema,
 
 	return nil
 }
+
+func NotifierValidator(resquest *types.APIContext, schema *types.Schema, data map[string]interface{}) error {
+	var spec v32.NotifierSpec
+	if err := convert.ToObj(data, &spec); err != nil {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, fmt.Sprintf("%v", err))
+	}
+
+	if err := notifiers.ValidateTemplate(spec.Template); err != nil {
+		return httperror.NewFieldAPIError(httperror.InvalidFormat, v3client.NotifierFieldTemplate, err.Error())
+	}
+
+	return nil
+}
+
+func AlertSilenceValidator(resquest *types.APIContext, schema *types.Schema, data map[string]interface{}) error {
+	var spec v32.AlertSilenceSpec
+	if err := convert.ToObj(data, &spec); err != nil {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, fmt.Sprintf("%v", err))
+	}
+
+	if spec.ProjectName != "" {
+		if clusterID, _ := ref.Parse(spec.ProjectName); clusterID != spec.ClusterName {
+			return httperror.NewFieldAPIError(httperror.InvalidReference, v3client.AlertSilenceFieldProjectName, "project is not in the cluster of the silence")
+		}
+	}
+
+	if err := silence.Validate(&spec); err != nil {
+		return httperror.NewAPIError(httperror.InvalidFormat, err.Error())
+	}
+
+	return nil
+}
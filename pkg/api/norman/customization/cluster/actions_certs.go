@@ -5,14 +5,20 @@
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"sort"
+	"time"
 
 	rketypes "github.com/rancher/rke/types"
 
 	"github.com/pkg/errors"
 	"github.com/rancher/norman/api/access"
+	"github.com/rancher/norman/httperror"
 	"github.com/rancher/norman/types"
+	"github.com/rancher/norman/types/convert"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
 	mgmtv3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/rkecerts"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -74,3 +80,74 @@ func (a ActionHandler) RotateCertificates(actionName string, action *types.Actio
 	apiContext.WriteResponse(http.StatusOK, rtn)
 	return nil
 }
+
+// ExpiringCertificates lists the certificates of every cluster visible to the user that expire within the requested
+// number of days, soonest first.
+func (a ActionHandler) ExpiringCertificates(actionName string, action *types.Action, apiContext *types.APIContext) error {
+	input := v32.ExpiringCertificatesInput{}
+	data, err := ioutil.ReadAll(apiContext.Request.Body)
+	if err != nil {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, fmt.Sprintf("failed to read request body: %v", err))
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &input); err != nil {
+			return httperror.NewAPIError(httperror.InvalidFormat, fmt.Sprintf("failed to parse request content: %v", err))
+		}
+	}
+	if input.WithinDays < 0 {
+		return httperror.NewAPIError(httperror.InvalidOption, "withinDays must not be negative")
+	}
+
+	critical, warning := rkecerts.ExpirationThresholds()
+	if input.WithinDays > 0 {
+		warning = time.Duration(input.WithinDays) * 24 * time.Hour
+	}
+
+	var clusters []client.Cluster
+	if err := access.List(apiContext, apiContext.Version, client.ClusterType, &types.QueryOptions{}, &clusters); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	output := v32.ExpiringCertificatesOutput{
+		Certificates: []v32.ExpiringCertificate{},
+	}
+	for _, cluster := range clusters {
+		for name, certExp := range cluster.CertificatesExpiration {
+			date, err := time.Parse(time.RFC3339, certExp.ExpirationDate)
+			if err != nil {
+				continue
+			}
+			severity := rkecerts.ExpirationSeverity(date, now, critical, warning)
+			if severity == "" {
+				continue
+			}
+			output.Certificates = append(output.Certificates, v32.ExpiringCertificate{
+				ClusterID:      cluster.ID,
+				ClusterName:    cluster.Name,
+				Name:           name,
+				ExpirationDate: certExp.ExpirationDate,
+				Severity:       severity,
+			})
+		}
+	}
+	// expiration dates are RFC3339 timestamps in UTC and sort chronologically as strings
+	sort.Slice(output.Certificates, func(i, j int) bool {
+		ci, cj := output.Certificates[i], output.Certificates[j]
+		if ci.ExpirationDate != cj.ExpirationDate {
+			return ci.ExpirationDate < cj.ExpirationDate
+		}
+		if ci.ClusterID != cj.ClusterID {
+			return ci.ClusterID < cj.ClusterID
+		}
+		return ci.Name < cj.Name
+	})
+
+	rtn, err := convert.EncodeToMap(output)
+	if err != nil {
+		return err
+	}
+	rtn["type"] = client.ExpiringCertificatesOutputType
+	apiContext.WriteResponse(http.StatusOK, rtn)
+	return nil
+}
@@ -13,10 +13,14 @@
 	"github.com/rancher/norman/api/access"
 	"github.com/rancher/norman/httperror"
 	"github.com/rancher/norman/types"
+	"github.com/rancher/norman/types/convert"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
 	"github.com/rancher/rancher/pkg/controllers/management/etcdbackup"
+	"github.com/rancher/rancher/pkg/etcdsnapshot"
 	mgmtv3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/ref"
+	rketypes "github.com/rancher/rke/types"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -138,6 +142,42 @@ func (a ActionHandler) RestoreFromEtcdBackupHandler(actionName string, action *t
 		cluster.Spec.RancherKubernetesEngineConfig = clusterBackup.Spec.RancherKubernetesEngineConfig
 	}
 
+	ring, ringErr := etcdbackup.KeyRing(a.SecretLister, cluster)
+	messages := validateRestore(backup, cluster.Spec.RancherKubernetesEngineConfig.Version)
+	messages = append(messages, validateDecryption(backup, ring, ringErr)...)
+	if input.DryRun {
+		rtn, err := convert.EncodeToMap(&client.RestoreFromEtcdBackupOutput{
+			Valid:    len(messages) == 0,
+			Messages: messages,
+		})
+		if err != nil {
+			return err
+		}
+		rtn["type"] = client.RestoreFromEtcdBackupOutputType
+		apiContext.WriteResponse(http.StatusOK, rtn)
+		return nil
+	}
+	if len(messages) > 0 {
+		return httperror.NewAPIError(httperror.InvalidState,
+			fmt.Sprintf("unable to restore backup %s: %s", input.EtcdBackupID, strings.Join(messages, ", ")))
+	}
+
+	if backup.Annotations[etcdbackup.EncryptionKeyAnnotation] != "" {
+		// RKE downloads the snapshot itself, it is stored in cleartext until the restore is done and encrypted again
+		// by the etcdbackup controller afterwards
+		decrypted, err := etcdbackup.Decrypt(apiContext.Request.Context(), a.DialerFactory, backup, ring)
+		if err != nil {
+			response["message"] = "failed to decrypt snapshot"
+			apiContext.WriteResponse(http.StatusInternalServerError, response)
+			return errors.Wrapf(err, "failed to decrypt snapshot of backup %s", input.EtcdBackupID)
+		}
+		if _, err := a.BackupClient.Update(decrypted); err != nil {
+			response["message"] = "failed to update etcdbackup object"
+			apiContext.WriteResponse(http.StatusInternalServerError, response)
+			return errors.Wrapf(err, "unable to update backup %s", input.EtcdBackupID)
+		}
+	}
+
 	// flag cluster for restore
 	cluster.Spec.RancherKubernetesEngineConfig.Restore.SnapshotName = input.EtcdBackupID
 	cluster.Spec.RancherKubernetesEngineConfig.Restore.Restore = true
@@ -150,3 +190,35 @@ func (a ActionHandler) RestoreFromEtcdBackupHandler(actionName string, action *t
 	apiContext.WriteResponse(http.StatusCreated, response)
 	return nil
 }
+
+// validateRestore returns the reasons why the snapshot of the backup cannot be restored on a cluster running the
+// given Kubernetes version.
+func validateRestore(backup *mgmtv3.EtcdBackup, kubernetesVersion string) (messages []string) {
+	if !rketypes.BackupConditionCompleted.IsTrue(backup) {
+		messages = append(messages, "backup has not completed")
+	}
+	if v32.EtcdBackupConditionVerified.IsFalse(backup) {
+		messages = append(messages, fmt.Sprintf("snapshot failed verification: %s", v32.EtcdBackupConditionVerified.GetMessage(backup)))
+	}
+	if err := etcdsnapshot.CheckRestoreVersion(backup.Status.KubernetesVersion, kubernetesVersion); err != nil {
+		messages = append(messages, err.Error())
+	}
+	return messages
+}
+
+// validateDecryption returns the reasons why the snapshot of the backup cannot be decrypted with the encryption keys
+// of the cluster.
+func validateDecryption(backup *mgmtv3.EtcdBackup, ring *etcdsnapshot.KeyRing, ringErr error) []string {
+	keyID := backup.Annotations[etcdbackup.EncryptionKeyAnnotation]
+	switch {
+	case keyID == "":
+		return nil
+	case ringErr != nil:
+		return []string{ringErr.Error()}
+	case ring == nil:
+		return []string{fmt.Sprintf("snapshot is encrypted with key %s, but etcd backup encryption is not configured for the cluster", keyID)}
+	case ring.Keys[keyID] == nil:
+		return []string{fmt.Sprintf("snapshot is encrypted with key %s, which is not in the etcd backup encryption keys of the cluster", keyID)}
+	}
+	return nil
+}
@@ -202,6 +202,7 @@ func hideClusterTemplateAnswers(answerMap map[string]interface{}, clusterSpecPwd
 
 func (f *Formatter) CollectionFormatter(request *types.APIContext, collection *types.GenericCollection) {
 	collection.AddAction(request, "createFromTemplate")
+	collection.AddAction(request, v32.ClusterCollectionActionExpiringCertificates)
 }
 
 func gatherClusterSpecPwdFields(schemas *types.Schemas, schema *types.Schema) map[string]interface{} {
@@ -2,6 +2,7 @@
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -10,11 +11,14 @@
 	"github.com/rancher/norman/types"
 	"github.com/rancher/norman/types/convert"
 	"github.com/rancher/norman/types/slice"
+	"github.com/rancher/rancher/pkg/auth/audit/policy"
 	"github.com/rancher/rancher/pkg/auth/providerrefresh"
+	"github.com/rancher/rancher/pkg/auth/providers/local"
 	"github.com/rancher/rancher/pkg/auth/tokens"
 	v3client "github.com/rancher/rancher/pkg/client/generated/management/v3"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/showback"
 )
 
 var ReadOnlySettings = []string{
@@ -77,6 +81,54 @@ func Validator(request *types.APIContext, schema *types.Schema, data map[string]
 
 	var err error
 	switch id {
+	case "audit-policy":
+		if newValueString != "" {
+			_, err = policy.Parse(newValueString)
+		}
+	case "auth-token-idle-timeout-days":
+		var days int
+		days, err = strconv.Atoi(newValueString)
+		if err == nil && days < 0 {
+			err = fmt.Errorf("must not be negative")
+		}
+	case "certificate-expiration-critical-days", "certificate-expiration-warning-days":
+		var days int
+		days, err = strconv.Atoi(newValueString)
+		if err == nil && days < 0 {
+			err = fmt.Errorf("must not be negative")
+		}
+	case "etcd-snapshot-verify-interval-hours":
+		var hours int
+		hours, err = strconv.Atoi(newValueString)
+		if err == nil && hours < 0 {
+			err = fmt.Errorf("must not be negative")
+		}
+	case "notifier-dedup-window-minutes", "notifier-rate-limit-per-minute":
+		var value int
+		value, err = strconv.Atoi(newValueString)
+		if err == nil && value < 0 {
+			err = fmt.Errorf("must not be negative")
+		}
+	case "stats-history-interval-minutes":
+		var minutes int
+		minutes, err = strconv.Atoi(newValueString)
+		if err == nil && minutes < 1 {
+			err = fmt.Errorf("must be at least 1")
+		}
+	case "stats-history-retention-days":
+		var days int
+		days, err = strconv.Atoi(newValueString)
+		if err == nil && days < 0 {
+			err = fmt.Errorf("must not be negative")
+		}
+	case "showback-cpu-core-hour-price", "showback-memory-gib-hour-price", "showback-storage-gib-hour-price":
+		_, err = showback.ParsePrice(newValueString)
+	case "showback-retention-days":
+		var days int
+		days, err = strconv.Atoi(newValueString)
+		if err == nil && days < 0 {
+			err = fmt.Errorf("must not be negative")
+		}
 	case "auth-user-info-max-age-seconds":
 		_, err = providerrefresh.ParseMaxAge(newValueString)
 	case "auth-user-info-resync-cron":
@@ -103,6 +155,12 @@ func Validator(request *types.APIContext, schema *types.Schema, data map[string]
 				}
 			}
 		}
+	case "local-auth-mfa-required":
+		switch newValueString {
+		case local.MFARequiredNone, local.MFARequiredAdmins, local.MFARequiredAll:
+		default:
+			err = fmt.Errorf("must be one of %s, %s or %s", local.MFARequiredNone, local.MFARequiredAdmins, local.MFARequiredAll)
+		}
 	}
 
 	if err != nil {
@@ -138,6 +138,7 @@ func Setup(ctx context.Context, apiContext *config.ScaledContext, clusterManager
 		client.ProjectCatalogType,
 		client.ProjectLoggingType,
 		client.ProjectAlertRuleType,
+		client.AlertSilenceType,
 		client.ProjectMonitorGraphType,
 		client.CisConfigType,
 		client.CisBenchmarkVersionType,
@@ -270,6 +271,8 @@ func Clusters(schemas *types.Schemas, managementContext *config.ScaledContext, c
 		ClusterTemplateRevisionClient: managementContext.Management.ClusterTemplateRevisions(""),
 		SubjectAccessReviewClient:     managementContext.K8sClient.AuthorizationV1().SubjectAccessReviews(),
 		TokenClient:                   managementContext.Management.Tokens(""),
+		SecretLister:                  managementContext.Core.Secrets("").Controller().Lister(),
+		DialerFactory:                 managementContext.Dialer,
 	}
 
 	clusterValidator := ccluster.Validator{
@@ -574,12 +577,14 @@ func Alert(schemas *types.Schemas, management *config.ScaledContext) {
 		ClusterAlertRule: management.Management.ClusterAlertRules(""),
 		ProjectAlertRule: management.Management.ProjectAlertRules(""),
 		Notifiers:        management.Management.Notifiers(""),
+		ConfigMaps:       management.Wrangler.Core.ConfigMap(),
 		DialerFactory:    management.Dialer,
 	}
 
 	schema := schemas.Schema(&managementschema.Version, client.NotifierType)
 	schema.CollectionFormatter = alert.NotifierCollectionFormatter
 	schema.Formatter = alert.NotifierFormatter
+	schema.Validator = alert.NotifierValidator
 	schema.ActionHandler = handler.NotifierActionHandler
 
 	schema = schemas.Schema(&managementschema.Version, client.ClusterAlertRuleType)
@@ -592,6 +597,9 @@ func Alert(schemas *types.Schemas, management *config.ScaledContext) {
 	schema.Validator = alert.ProjectAlertRuleValidator
 	schema.ActionHandler = handler.ProjectAlertRuleActionHandler
 
+	schema = schemas.Schema(&managementschema.Version, client.AlertSilenceType)
+	schema.Validator = alert.AlertSilenceValidator
+
 	//old schema just for migrate
 	schema = schemas.Schema(&managementschema.Version, client.ClusterAlertType)
 	schema = schemas.Schema(&managementschema.Version, client.ProjectAlertType)
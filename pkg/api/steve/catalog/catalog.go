@@ -39,6 +39,9 @@ func addSchemas(server *steve.Server, ops *operation, index http.Handler) {
 	server.BaseSchemas.MustImportAndCustomize(types2.ChartInstallAction{}, nil)
 	server.BaseSchemas.MustImportAndCustomize(types2.ChartInstall{}, nil)
 	server.BaseSchemas.MustImportAndCustomize(types2.ChartActionOutput{}, nil)
+	server.BaseSchemas.MustImportAndCustomize(types2.ResourcePreview{}, nil)
+	server.BaseSchemas.MustImportAndCustomize(types2.ChartPreview{}, nil)
+	server.BaseSchemas.MustImportAndCustomize(types2.ChartPreviewOutput{}, nil)
 
 	operationTemplate := schema2.Template{
 		Group: catalog.GroupName,
@@ -74,8 +77,10 @@ func addSchemas(server *steve.Server, ops *operation, index http.Handler) {
 		Kind:  "Repo",
 		Customize: func(apiSchema *types.APISchema) {
 			apiSchema.ActionHandlers = map[string]http.Handler{
-				"install": ops,
-				"upgrade": ops,
+				"install":       ops,
+				"upgrade":       ops,
+				"dryRunInstall": ops,
+				"dryRunUpgrade": ops,
 			}
 			apiSchema.ResourceActions = map[string]schemas3.Action{
 				"install": {
@@ -86,6 +91,14 @@ func addSchemas(server *steve.Server, ops *operation, index http.Handler) {
 					Input:  "chartUpgradeAction",
 					Output: "chartActionOutput",
 				},
+				"dryRunInstall": {
+					Input:  "chartInstallAction",
+					Output: "chartPreviewOutput",
+				},
+				"dryRunUpgrade": {
+					Input:  "chartUpgradeAction",
+					Output: "chartPreviewOutput",
+				},
 			}
 			apiSchema.ByIDHandler = func(request *types.APIRequest) (types.APIObject, error) {
 				if request.Name == "index.yaml" {
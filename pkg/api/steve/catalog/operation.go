@@ -34,8 +34,9 @@ func (o *operation) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	}
 
 	var (
-		op  *catalog.Operation
-		err error
+		op      *catalog.Operation
+		preview *catalogtypes.ChartPreviewOutput
+		err     error
 	)
 
 	ns, name := nsAndName(apiRequest)
@@ -46,6 +47,10 @@ func (o *operation) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		op, err = o.ops.Upgrade(apiRequest.Context(), user, ns, name, req.Body)
 	case "uninstall":
 		op, err = o.ops.Uninstall(apiRequest.Context(), user, ns, name, req.Body)
+	case "dryRunInstall":
+		preview, err = o.ops.PreviewInstall(apiRequest.Context(), ns, name, req.Body)
+	case "dryRunUpgrade":
+		preview, err = o.ops.PreviewUpgrade(apiRequest.Context(), ns, name, req.Body)
 	}
 
 	switch apiRequest.Link {
@@ -59,6 +64,14 @@ func (o *operation) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if preview != nil {
+		apiRequest.WriteResponse(http.StatusOK, types.APIObject{
+			Type:   "chartPreviewOutput",
+			Object: preview,
+		})
+		return
+	}
+
 	if op == nil {
 		return
 	}
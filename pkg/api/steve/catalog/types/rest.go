@@ -1,6 +1,7 @@
 package types
 
 import (
+	catalog "github.com/rancher/rancher/pkg/apis/catalog.cattle.io/v1"
 	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -42,17 +43,18 @@ type ChartUninstallAction struct {
 }
 
 type ChartUpgradeAction struct {
-	Timeout                  *metav1.Duration `json:"timeout,omitempty"`
-	Wait                     bool             `json:"wait,omitempty"`
-	DisableHooks             bool             `json:"noHooks,omitempty"`
-	DisableOpenAPIValidation bool             `json:"disableOpenAPIValidation,omitempty"`
-	Force                    bool             `json:"force,omitempty"`
-	ForceAdopt               bool             `json:"forceAdopt,omitempty"`
-	MaxHistory               int              `json:"historyMax,omitempty"`
-	Install                  bool             `json:"install,omitempty"`
-	Namespace                string           `json:"namespace,omitempty"`
-	CleanupOnFail            bool             `json:"cleanupOnFail,omitempty"`
-	Charts                   []ChartUpgrade   `json:"charts,omitempty"`
+	Timeout                  *metav1.Duration       `json:"timeout,omitempty"`
+	Wait                     bool                   `json:"wait,omitempty"`
+	DisableHooks             bool                   `json:"noHooks,omitempty"`
+	DisableOpenAPIValidation bool                   `json:"disableOpenAPIValidation,omitempty"`
+	Force                    bool                   `json:"force,omitempty"`
+	ForceAdopt               bool                   `json:"forceAdopt,omitempty"`
+	MaxHistory               int                    `json:"historyMax,omitempty"`
+	Install                  bool                   `json:"install,omitempty"`
+	Namespace                string                 `json:"namespace,omitempty"`
+	CleanupOnFail            bool                   `json:"cleanupOnFail,omitempty"`
+	UpgradePolicy            *catalog.UpgradePolicy `json:"upgradePolicy,omitempty"`
+	Charts                   []ChartUpgrade         `json:"charts,omitempty"`
 }
 
 type ChartUpgrade struct {
@@ -70,3 +72,27 @@ type ChartActionOutput struct {
 	OperationName      string `json:"operationName,omitempty"`
 	OperationNamespace string `json:"operationNamespace,omitempty"`
 }
+
+type ChartPreviewOutput struct {
+	Charts []ChartPreview `json:"charts,omitempty"`
+}
+
+type ChartPreview struct {
+	ChartName        string            `json:"chartName,omitempty"`
+	Version          string            `json:"version,omitempty"`
+	ReleaseName      string            `json:"releaseName,omitempty"`
+	ReleaseNamespace string            `json:"releaseNamespace,omitempty"`
+	Resources        []ResourcePreview `json:"resources,omitempty"`
+}
+
+// ResourcePreview is the change a dry run of an install or upgrade would make to one resource.
+// Diff is a JSON merge patch from the live object to the object the server would persist.
+type ResourcePreview struct {
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Change     string `json:"change,omitempty"`
+	Diff       string `json:"diff,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
@@ -40,6 +40,12 @@ func Register(ctx context.Context, server *steve.Server, wrangler *wrangler.Cont
 	kubeconfig := kubeconfigDownload{
 		userMgr: userManager,
 	}
+	stats := &statsHistory{
+		configMaps: wrangler.Core.ConfigMap(),
+	}
+	showbackReport := &showbackReport{
+		configMaps: wrangler.Core.ConfigMap(),
+	}
 
 	server.ClusterCache.OnAdd(ctx, shell.impersonator.PurgeOldRoles)
 	server.ClusterCache.OnChange(ctx, func(gvk schema.GroupVersionKind, key string, obj, oldObj runtime.Object) error {
@@ -56,6 +62,8 @@ func Register(ctx context.Context, server *steve.Server, wrangler *wrangler.Cont
 				schema.LinkHandlers = map[string]http.Handler{}
 			}
 			schema.LinkHandlers["shell"] = shell
+			schema.LinkHandlers["stats"] = stats
+			schema.LinkHandlers["showback"] = showbackReport
 			if schema.ActionHandlers == nil {
 				schema.ActionHandlers = map[string]http.Handler{}
 			}
@@ -85,6 +93,11 @@ func Register(ctx context.Context, server *steve.Server, wrangler *wrangler.Cont
 		Group: "management.cattle.io",
 		Kind:  "Project",
 		Customize: func(schema *types.APISchema) {
+			if schema.LinkHandlers == nil {
+				schema.LinkHandlers = map[string]http.Handler{}
+			}
+			schema.LinkHandlers["stats"] = stats
+			schema.LinkHandlers["showback"] = showbackReport
 			// Everybody can list even if they have no list or get privileges. The users
 			// authorization will still be used to determine what can be seen but just
 			// may result in an empty list
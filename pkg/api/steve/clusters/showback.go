@@ -0,0 +1,113 @@
+package clusters
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rancher/apiserver/pkg/types"
+	"github.com/rancher/rancher/pkg/showback"
+	corecontrollers "github.com/rancher/wrangler/pkg/generated/controllers/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxShowbackDays limits the days of a showback report.
+const maxShowbackDays = 366
+
+// showbackReport serves the showback report of clusters and projects. The from and to query parameters are the first
+// and last day of the report, which default to the current month, and format is either json or csv.
+type showbackReport struct {
+	configMaps corecontrollers.ConfigMapClient
+}
+
+func (s *showbackReport) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	apiRequest := types.GetAPIContext(req.Context())
+	if err := apiRequest.AccessControl.CanGet(apiRequest, apiRequest.Schema); err != nil {
+		apiRequest.WriteError(err)
+		return
+	}
+
+	query := req.URL.Query()
+	now := time.Now().UTC()
+	from, err := parseDay(query.Get("from"), time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("invalid from: %v", err), http.StatusUnprocessableEntity)
+		return
+	}
+	to, err := parseDay(query.Get("to"), now.Truncate(24*time.Hour))
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("invalid to: %v", err), http.StatusUnprocessableEntity)
+		return
+	}
+	days := showback.Days(from, to)
+	if len(days) == 0 || len(days) > maxShowbackDays {
+		http.Error(rw, fmt.Sprintf("the report must span from 1 to %d days", maxShowbackDays), http.StatusUnprocessableEntity)
+		return
+	}
+	format := query.Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		http.Error(rw, "invalid format: must be json or csv", http.StatusUnprocessableEntity)
+		return
+	}
+
+	// projects are reported from the usage of their cluster
+	cluster, project := apiRequest.Name, ""
+	if apiRequest.Namespace != "" {
+		cluster, project = apiRequest.Namespace, apiRequest.Name
+	}
+
+	usage, err := s.usage(cluster, days)
+	if err != nil {
+		apiRequest.WriteError(err)
+		return
+	}
+	report := showback.NewReport(cluster, project, days, usage, showback.GetPrices())
+
+	if format == "csv" {
+		rw.Header().Set("Content-Type", "text/csv")
+		rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=showback-%s-%s-%s.csv", apiRequest.Name, report.From, report.To))
+		err = showback.WriteCSV(rw, report)
+	} else {
+		rw.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(rw).Encode(report)
+	}
+	if err != nil {
+		apiRequest.WriteError(err)
+	}
+}
+
+// usage returns the usage of the days of the cluster, read from the config maps of the days.
+func (s *showbackReport) usage(cluster string, days []string) (map[string]showback.DayUsage, error) {
+	cms, err := s.configMaps.List(cluster, metav1.ListOptions{
+		LabelSelector: showback.Label + "=true",
+	})
+	if err != nil {
+		return nil, err
+	}
+	byName := map[string]map[string][]byte{}
+	for _, cm := range cms.Items {
+		byName[cm.Name] = cm.BinaryData
+	}
+
+	result := map[string]showback.DayUsage{}
+	for _, day := range days {
+		t, err := time.Parse(showback.DateFormat, day)
+		if err != nil {
+			return nil, err
+		}
+		dayUsage, err := showback.LoadDay(byName[showback.ConfigMapName(t)])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", day, err)
+		}
+		result[day] = dayUsage
+	}
+	return result, nil
+}
+
+func parseDay(value string, def time.Time) (time.Time, error) {
+	if value == "" {
+		return def, nil
+	}
+	return time.Parse(showback.DateFormat, value)
+}
@@ -0,0 +1,92 @@
+package clusters
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rancher/apiserver/pkg/types"
+	"github.com/rancher/rancher/pkg/statshistory"
+	corecontrollers "github.com/rancher/wrangler/pkg/generated/controllers/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StatsHistory is the response of the stats link of clusters and projects.
+type StatsHistory struct {
+	// Interval is the time between two samples of the history.
+	Interval string                `json:"interval"`
+	Samples  []statshistory.Sample `json:"samples"`
+	Trend    *statshistory.Trend   `json:"trend,omitempty"`
+}
+
+// statsHistory serves the resource stats history of clusters and projects. The since query parameter limits the
+// samples to the given duration, step averages the samples over the given duration.
+type statsHistory struct {
+	configMaps corecontrollers.ConfigMapClient
+}
+
+func (s *statsHistory) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	apiRequest := types.GetAPIContext(req.Context())
+	if err := apiRequest.AccessControl.CanGet(apiRequest, apiRequest.Schema); err != nil {
+		apiRequest.WriteError(err)
+		return
+	}
+
+	query := req.URL.Query()
+	since, err := parseDuration(query.Get("since"), statshistory.Retention())
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("invalid since: %v", err), http.StatusUnprocessableEntity)
+		return
+	}
+	step, err := parseDuration(query.Get("step"), 0)
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("invalid step: %v", err), http.StatusUnprocessableEntity)
+		return
+	}
+
+	// projects are stored in the namespace of their cluster, clusters have a namespace of their own name
+	namespace, name := apiRequest.Name, statshistory.ClusterConfigMapName()
+	if apiRequest.Namespace != "" {
+		namespace, name = apiRequest.Namespace, statshistory.ProjectConfigMapName(apiRequest.Name)
+	}
+
+	var samples []statshistory.Sample
+	cm, err := s.configMaps.Get(namespace, name, metav1.GetOptions{})
+	if err == nil {
+		samples, err = statshistory.Load(cm)
+	} else if apierrors.IsNotFound(err) {
+		err = nil
+	}
+	if err != nil {
+		apiRequest.WriteError(err)
+		return
+	}
+
+	samples = statshistory.Query(samples, time.Now().Add(-since), step)
+	interval := statshistory.Interval()
+	if step > interval {
+		interval = step
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(&StatsHistory{
+		Interval: interval.String(),
+		Samples:  samples,
+		Trend:    statshistory.GetTrend(samples),
+	}); err != nil {
+		apiRequest.WriteError(err)
+	}
+}
+
+func parseDuration(value string, def time.Duration) (time.Duration, error) {
+	if value == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err == nil && d < 0 {
+		err = fmt.Errorf("must not be negative")
+	}
+	return d, err
+}
@@ -23,7 +23,8 @@ type SecretReference struct {
 }
 
 type RepoSpec struct {
-	// URL A http URL of the repo to connect to
+	// URL A http URL of the repo to connect to, or an oci:// URL of a registry path whose repositories
+	// are charts, for example oci://registry.example.com/charts
 	URL string `json:"url,omitempty"`
 
 	// GitRepo a git repo to clone and index as the helm repo
@@ -61,6 +62,21 @@ type RepoSpec struct {
 
 	// If disabled the repo clone will not be updated or allowed to be installed from
 	Enabled *bool `json:"enabled,omitempty"`
+
+	// Verification requires the chart versions of the repo to be signed. Versions that fail verification
+	// are not served or installed.
+	Verification *ChartVerification `json:"verification,omitempty"`
+}
+
+type ChartVerification struct {
+	// Type of signature to require, "provenance" for Helm provenance files signed with a PGP key or
+	// "cosign" for cosign signatures
+	Type string `json:"type,omitempty"`
+
+	// KeySecret is the secret holding the public keys. Provenance files are verified with the PGP keyring
+	// in the "keyring" key and cosign signatures with the PEM encoded public key in the "cosign.pub" key.
+	// For a Repo the Namespace field will be ignored
+	KeySecret *SecretReference `json:"keySecret,omitempty"`
 }
 
 type RepoCondition string
@@ -115,5 +131,32 @@ type OperationStatus struct {
 	PodName            string                              `json:"podName,omitempty"`
 	PodNamespace       string                              `json:"podNamespace,omitempty"`
 	PodCreated         bool                                `json:"podCreated,omitempty"`
+	UpgradePolicy      *UpgradePolicy                      `json:"upgradePolicy,omitempty"`
+	UpgradeReleases    []string                            `json:"upgradeReleases,omitempty"`
+	UpgradeRevisions   map[string]int                      `json:"upgradeRevisions,omitempty"`
+	RollbackRevisions  map[string]int                      `json:"rollbackRevisions,omitempty"`
+	UpgradeMaxHistory  int                                 `json:"upgradeMaxHistory,omitempty"`
+	User               string                              `json:"user,omitempty"`
+	UserGroups         []string                            `json:"userGroups,omitempty"`
 	Conditions         []genericcondition.GenericCondition `json:"conditions,omitempty"`
 }
+
+type OperationCondition string
+
+const (
+	// OperationCompleted is true once helm has finished successfully, its last update is when helm finished
+	OperationCompleted  OperationCondition = "Completed"
+	OperationHealthy    OperationCondition = "Healthy"
+	OperationRolledBack OperationCondition = "RolledBack"
+)
+
+// UpgradePolicy opts an upgrade into health gating. Once helm has finished, the operation waits for the
+// Deployments, StatefulSets and DaemonSets of the release to become ready.
+type UpgradePolicy struct {
+	// HealthTimeout is how long to wait for the workloads of the release to become ready, defaults to 5 minutes
+	HealthTimeout *metav1.Duration `json:"healthTimeout,omitempty"`
+
+	// RollbackOnFailure rolls the release back to its revision from before the upgrade if the workloads do
+	// not become ready within the timeout
+	RollbackOnFailure bool `json:"rollbackOnFailure,omitempty"`
+}
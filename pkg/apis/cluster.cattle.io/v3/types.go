@@ -34,4 +34,6 @@ type ClusterAuthToken struct {
 	ExpiresAt     string `json:"expiresAt,omitempty"`
 	SecretKeyHash string `json:"hash"`
 	Enabled       bool   `json:"enabled"`
+	// LastUsedAt is set by the authorized cluster endpoint when the token authenticates a request
+	LastUsedAt string `json:"lastUsedAt,omitempty"`
 }
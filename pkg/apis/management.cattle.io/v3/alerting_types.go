@@ -250,11 +250,14 @@ func (p *ProjectAlertRule) ObjClusterName() string {
 
 type ProjectAlertRuleSpec struct {
 	CommonRuleField
-	ProjectName  string        `json:"projectName" norman:"type=reference[project]"`
-	GroupName    string        `json:"groupName" norman:"type=reference[projectAlertGroup]"`
-	PodRule      *PodRule      `json:"podRule,omitempty"`
-	WorkloadRule *WorkloadRule `json:"workloadRule,omitempty"`
-	MetricRule   *MetricRule   `json:"metricRule,omitempty"`
+	ProjectName     string           `json:"projectName" norman:"type=reference[project]"`
+	GroupName       string           `json:"groupName" norman:"type=reference[projectAlertGroup]"`
+	PodRule         *PodRule         `json:"podRule,omitempty"`
+	WorkloadRule    *WorkloadRule    `json:"workloadRule,omitempty"`
+	MetricRule      *MetricRule      `json:"metricRule,omitempty"`
+	VolumeRule      *VolumeRule      `json:"volumeRule,omitempty"`
+	CertificateRule *CertificateRule `json:"certificateRule,omitempty"`
+	JobRule         *JobRule         `json:"jobRule,omitempty"`
 }
 
 func (p *ProjectAlertRuleSpec) ObjClusterName() string {
@@ -322,6 +325,31 @@ type WorkloadRule struct {
 	AvailablePercentage int               `json:"availablePercentage,omitempty" norman:"required,min=1,max=100,default=70"`
 }
 
+// VolumeRule alerts when the used space of persistent volume claims, as reported by the kubelets, is over the
+// percentage of their capacity. It watches the claim, or else the claims of the project matching the selector.
+type VolumeRule struct {
+	PersistentVolumeClaimID string            `json:"persistentVolumeClaimId,omitempty" norman:"type=reference[/v3/projects/schemas/persistentVolumeClaim]"`
+	Selector                map[string]string `json:"selector,omitempty"`
+	UsedPercentage          int               `json:"usedPercentage,omitempty" norman:"required,min=1,max=100,default=80"`
+}
+
+// CertificateRule alerts when the certificates of TLS secrets expire within the number of days. It watches the
+// certificate, or else the certificates of the project matching the selector, only those used by ingresses if
+// IngressOnly is set.
+type CertificateRule struct {
+	CertificateID    string            `json:"certificateId,omitempty" norman:"type=reference[/v3/projects/schemas/namespacedCertificate]"`
+	Selector         map[string]string `json:"selector,omitempty"`
+	IngressOnly      bool              `json:"ingressOnly,omitempty"`
+	DaysBeforeExpiry int               `json:"daysBeforeExpiry,omitempty" norman:"required,min=1,default=14"`
+}
+
+// JobRule alerts when jobs fail, or when the last run of cron jobs failed. It watches the job or cron job, e.g.
+// cronjob:default:backup, or else the jobs and cron jobs of the project matching the selector.
+type JobRule struct {
+	WorkloadID string            `json:"workloadId,omitempty"`
+	Selector   map[string]string `json:"selector,omitempty"`
+}
+
 type SystemServiceRule struct {
 	Condition string `json:"condition,omitempty" norman:"required,options=etcd|controller-manager|scheduler,default=scheduler"`
 }
@@ -329,6 +357,73 @@ type SystemServiceRule struct {
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// AlertSilence mutes the cluster and project alerts of a cluster during a one-off or recurring time window, e.g. during
+// planned maintenance.
+type AlertSilence struct {
+	types.Namespaced
+
+	metav1.TypeMeta `json:",inline"`
+	// Standard object’s metadata. More info:
+	// https://github.com/kubernetes/community/blob/master/contributors/devel/api-conventions.md#metadata
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AlertSilenceSpec `json:"spec"`
+	// Most recent observed status of the silence. More info:
+	// https://github.com/kubernetes/community/blob/master/contributors/devel/api-conventions.md#spec-and-status
+	Status AlertSilenceStatus `json:"status"`
+}
+
+func (a *AlertSilence) ObjClusterName() string {
+	return a.Spec.ObjClusterName()
+}
+
+// AlertSilenceSpec selects the alerts to mute and when. An alert is muted if it matches all of the project, groups,
+// rules and matchers that are set, all the alerts of the cluster are muted if none is set.
+type AlertSilenceSpec struct {
+	ClusterName string `json:"clusterName" norman:"type=reference[cluster]"`
+	DisplayName string `json:"displayName,omitempty" norman:"required"`
+	Description string `json:"description,omitempty"`
+	// ProjectName mutes the alerts of the project, it is the id of the project, e.g. c-xxxxx:p-xxxxx.
+	ProjectName string `json:"projectName,omitempty"`
+	// GroupNames mutes the alerts of the cluster or project alert groups.
+	GroupNames []string `json:"groupNames,omitempty"`
+	// RuleNames mutes the alerts of the cluster or project alert rules.
+	RuleNames []string              `json:"ruleNames,omitempty"`
+	Matchers  []AlertSilenceMatcher `json:"matchers,omitempty"`
+
+	// StartsAt and EndsAt are the RFC3339 times of a one-off window, or bound the recurring windows of the schedule.
+	StartsAt string `json:"startsAt,omitempty"`
+	EndsAt   string `json:"endsAt,omitempty"`
+	// Schedule is the cron expression of the start of the recurring windows, which last DurationMinutes.
+	Schedule        string `json:"schedule,omitempty"`
+	DurationMinutes int    `json:"durationMinutes,omitempty" norman:"min=1"`
+	// TimeZone is the IANA time zone of the schedule, UTC by default.
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+func (a *AlertSilenceSpec) ObjClusterName() string {
+	return a.ClusterName
+}
+
+// AlertSilenceMatcher matches a label of the alerts, e.g. severity, node_name or workload_name.
+type AlertSilenceMatcher struct {
+	Name    string `json:"name,omitempty" norman:"required"`
+	Value   string `json:"value,omitempty"`
+	IsRegex bool   `json:"isRegex,omitempty"`
+}
+
+type AlertSilenceStatus struct {
+	// SilenceState is pending before a window, active during a window and expired after the last window.
+	SilenceState string `json:"silenceState,omitempty"`
+	// StartTime and EndTime are the RFC3339 times of the current or the next window.
+	StartTime string `json:"startTime,omitempty"`
+	EndTime   string `json:"endTime,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 type Notifier struct {
 	types.Namespaced
 
@@ -350,16 +445,21 @@ func (n *Notifier) ObjClusterName() string {
 type NotifierSpec struct {
 	ClusterName string `json:"clusterName" norman:"type=reference[cluster]"`
 
-	DisplayName     string           `json:"displayName,omitempty" norman:"required"`
-	Description     string           `json:"description,omitempty"`
-	SendResolved    bool             `json:"sendResolved,omitempty"`
-	SMTPConfig      *SMTPConfig      `json:"smtpConfig,omitempty"`
-	SlackConfig     *SlackConfig     `json:"slackConfig,omitempty"`
-	PagerdutyConfig *PagerdutyConfig `json:"pagerdutyConfig,omitempty"`
-	WebhookConfig   *WebhookConfig   `json:"webhookConfig,omitempty"`
-	WechatConfig    *WechatConfig    `json:"wechatConfig,omitempty"`
-	DingtalkConfig  *DingtalkConfig  `json:"dingtalkConfig,omitempty"`
-	MSTeamsConfig   *MSTeamsConfig   `json:"msteamsConfig,omitempty"`
+	DisplayName      string            `json:"displayName,omitempty" norman:"required"`
+	Description      string            `json:"description,omitempty"`
+	SendResolved     bool              `json:"sendResolved,omitempty"`
+	SMTPConfig       *SMTPConfig       `json:"smtpConfig,omitempty"`
+	SlackConfig      *SlackConfig      `json:"slackConfig,omitempty"`
+	PagerdutyConfig  *PagerdutyConfig  `json:"pagerdutyConfig,omitempty"`
+	WebhookConfig    *WebhookConfig    `json:"webhookConfig,omitempty"`
+	WechatConfig     *WechatConfig     `json:"wechatConfig,omitempty"`
+	DingtalkConfig   *DingtalkConfig   `json:"dingtalkConfig,omitempty"`
+	MSTeamsConfig    *MSTeamsConfig    `json:"msteamsConfig,omitempty"`
+	OpsgenieConfig   *OpsgenieConfig   `json:"opsgenieConfig,omitempty"`
+	TelegramConfig   *TelegramConfig   `json:"telegramConfig,omitempty"`
+	GoogleChatConfig *GoogleChatConfig `json:"googleChatConfig,omitempty"`
+	MattermostConfig *MattermostConfig `json:"mattermostConfig,omitempty"`
+	Template         *NotifierTemplate `json:"template,omitempty"`
 }
 
 func (n *NotifierSpec) ObjClusterName() string {
@@ -367,14 +467,18 @@ func (n *NotifierSpec) ObjClusterName() string {
 }
 
 type Notification struct {
-	Message         string           `json:"message,omitempty"`
-	SMTPConfig      *SMTPConfig      `json:"smtpConfig,omitempty"`
-	SlackConfig     *SlackConfig     `json:"slackConfig,omitempty"`
-	PagerdutyConfig *PagerdutyConfig `json:"pagerdutyConfig,omitempty"`
-	WebhookConfig   *WebhookConfig   `json:"webhookConfig,omitempty"`
-	WechatConfig    *WechatConfig    `json:"wechatConfig,omitempty"`
-	DingtalkConfig  *DingtalkConfig  `json:"dingtalkConfig,omitempty"`
-	MSTeamsConfig   *MSTeamsConfig   `json:"msteamsConfig,omitempty"`
+	Message          string            `json:"message,omitempty"`
+	SMTPConfig       *SMTPConfig       `json:"smtpConfig,omitempty"`
+	SlackConfig      *SlackConfig      `json:"slackConfig,omitempty"`
+	PagerdutyConfig  *PagerdutyConfig  `json:"pagerdutyConfig,omitempty"`
+	WebhookConfig    *WebhookConfig    `json:"webhookConfig,omitempty"`
+	WechatConfig     *WechatConfig     `json:"wechatConfig,omitempty"`
+	DingtalkConfig   *DingtalkConfig   `json:"dingtalkConfig,omitempty"`
+	MSTeamsConfig    *MSTeamsConfig    `json:"msteamsConfig,omitempty"`
+	OpsgenieConfig   *OpsgenieConfig   `json:"opsgenieConfig,omitempty"`
+	TelegramConfig   *TelegramConfig   `json:"telegramConfig,omitempty"`
+	GoogleChatConfig *GoogleChatConfig `json:"googleChatConfig,omitempty"`
+	MattermostConfig *MattermostConfig `json:"mattermostConfig,omitempty"`
 }
 
 type SMTPConfig struct {
@@ -400,6 +504,9 @@ type PagerdutyConfig struct {
 
 type WebhookConfig struct {
 	URL string `json:"url,omitempty" norman:"required"`
+	// Secret signs the requests with an HMAC-SHA256 of the timestamp and the body, sent in the
+	// X-Rancher-Timestamp and X-Rancher-Signature headers.
+	Secret string `json:"secret,omitempty" norman:"type=password"`
 	*HTTPClientConfig
 }
 
@@ -424,7 +531,71 @@ type MSTeamsConfig struct {
 	*HTTPClientConfig
 }
 
+type OpsgenieConfig struct {
+	APIKey string `json:"apiKey,omitempty" norman:"type=password,required"`
+	// APIURL is the URL of the Opsgenie API, https://api.eu.opsgenie.com for the EU instance.
+	APIURL string `json:"apiUrl,omitempty" norman:"default=https://api.opsgenie.com"`
+	// DefaultRecipient is the name of the team the alerts are assigned to.
+	DefaultRecipient string   `json:"defaultRecipient,omitempty"`
+	Tags             []string `json:"tags,omitempty"`
+	*HTTPClientConfig
+}
+
+type TelegramConfig struct {
+	BotToken string `json:"botToken,omitempty" norman:"type=password,required"`
+	// DefaultRecipient is the id of the chat the messages are sent to.
+	DefaultRecipient string `json:"defaultRecipient,omitempty" norman:"required"`
+	APIURL           string `json:"apiUrl,omitempty" norman:"default=https://api.telegram.org"`
+	*HTTPClientConfig
+}
+
+type GoogleChatConfig struct {
+	URL string `json:"url,omitempty" norman:"required"`
+	*HTTPClientConfig
+}
+
+type MattermostConfig struct {
+	URL string `json:"url,omitempty" norman:"required"`
+	// DefaultRecipient is the channel the messages are sent to, instead of the channel of the webhook.
+	DefaultRecipient string `json:"defaultRecipient,omitempty"`
+	Username         string `json:"username,omitempty"`
+	*HTTPClientConfig
+}
+
+// NotifierTemplate holds Go templates, with the sprig functions, that customize the messages of a notifier. They are
+// rendered with the title and text of the message and the context of the alert.
+type NotifierTemplate struct {
+	// Title replaces the title of the message, used as the subject of emails and the header of chat messages.
+	Title string `json:"title,omitempty"`
+	// Text replaces the text of the message.
+	Text string `json:"text,omitempty"`
+	// Payload replaces the whole body sent to Slack, Microsoft Teams, Dingtalk and webhooks, it must render JSON.
+	Payload string `json:"payload,omitempty"`
+}
+
 type NotifierStatus struct {
+	// Pending is the number of messages waiting in the delivery queue of the notifier.
+	Pending          int    `json:"pending,omitempty"`
+	LastDeliveryTime string `json:"lastDeliveryTime,omitempty"`
+	LastFailureTime  string `json:"lastFailureTime,omitempty"`
+	LastError        string `json:"lastError,omitempty"`
+	// DeadLetters are the messages that could not be delivered, they can be resent with the resend action.
+	DeadLetters []NotifierDeadLetter `json:"deadLetters,omitempty"`
+}
+
+type NotifierDeadLetter struct {
+	ID          string `json:"id,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Recipient   string `json:"recipient,omitempty"`
+	AlertName   string `json:"alertName,omitempty"`
+	Attempts    int    `json:"attempts,omitempty"`
+	CreatedTime string `json:"createdTime,omitempty"`
+	LastError   string `json:"lastError,omitempty"`
+}
+
+type NotifierResendInput struct {
+	// DeadLetterIDs are the dead letters to resend, all of them are resent if it is empty.
+	DeadLetterIDs []string `json:"deadLetterIds,omitempty"`
 }
 
 // HTTPClientConfig configures an HTTP client.
@@ -25,6 +25,8 @@ type Token struct {
 	AuthProvider    string            `json:"authProvider"`
 	TTLMillis       int64             `json:"ttl"`
 	LastUpdateTime  string            `json:"lastUpdateTime"`
+	LastUsedAt      string            `json:"lastUsedAt,omitempty" norman:"nocreate,noupdate"`
+	LastUsedFrom    string            `json:"lastUsedFrom,omitempty" norman:"nocreate,noupdate"`
 	IsDerived       bool              `json:"isDerived"`
 	Description     string            `json:"description"`
 	Expired         bool              `json:"expired"`
@@ -38,6 +40,39 @@ func (t *Token) ObjClusterName() string {
 	return t.ClusterName
 }
 
+// TokenRevokeInput selects the tokens revoked by the revoke action. Only tokens matching
+// every set field are selected, and at least one field other than dryRun must be set.
+type TokenRevokeInput struct {
+	AuthProvider     string `json:"authProvider,omitempty"`
+	UserID           string `json:"userId,omitempty"`
+	PrincipalID      string `json:"principalId,omitempty"`
+	GroupPrincipalID string `json:"groupPrincipalId,omitempty"`
+	ClusterName      string `json:"clusterName,omitempty"`
+	CreatedBefore    string `json:"createdBefore,omitempty"`
+	CreatedAfter     string `json:"createdAfter,omitempty"`
+	LastUsedBefore   string `json:"lastUsedBefore,omitempty"`
+	DryRun           bool   `json:"dryRun,omitempty"`
+}
+
+type TokenRevokeOutput struct {
+	DryRun  bool           `json:"dryRun"`
+	Revoked int            `json:"revoked"`
+	Tokens  []TokenSummary `json:"tokens"`
+}
+
+// TokenSummary describes a token selected by the revoke action without exposing its key.
+type TokenSummary struct {
+	Name         string `json:"name"`
+	UserID       string `json:"userId"`
+	AuthProvider string `json:"authProvider"`
+	ClusterName  string `json:"clusterName,omitempty"`
+	Description  string `json:"description,omitempty"`
+	Created      string `json:"created"`
+	LastUsedAt   string `json:"lastUsedAt,omitempty"`
+	IsDerived    bool   `json:"isDerived"`
+	Error        string `json:"error,omitempty"`
+}
+
 // +genclient
 // +genclient:nonNamespaced
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -50,6 +85,7 @@ type User struct {
 	Description        string     `json:"description"`
 	Username           string     `json:"username,omitempty"`
 	Password           string     `json:"password,omitempty" norman:"writeOnly,noupdate"`
+	PasswordHistory    []string   `json:"passwordHistory,omitempty" norman:"writeOnly,nocreate,noupdate"`
 	MustChangePassword bool       `json:"mustChangePassword,omitempty"`
 	PrincipalIDs       []string   `json:"principalIds,omitempty" norman:"type=array[reference[principal]]"`
 	Me                 bool       `json:"me,omitempty" norman:"nocreate,noupdate"`
@@ -60,6 +96,12 @@ type User struct {
 
 type UserStatus struct {
 	Conditions []UserCondition `json:"conditions"`
+	// PasswordChangedAt is the time the local password was last set, in RFC3339 format.
+	PasswordChangedAt string `json:"passwordChangedAt,omitempty" norman:"nocreate,noupdate"`
+	// FailedLoginAttempts counts consecutive failed local logins since the last successful one.
+	FailedLoginAttempts int `json:"failedLoginAttempts,omitempty" norman:"nocreate,noupdate"`
+	// LockedUntil is the time until which local logins are refused, in RFC3339 format.
+	LockedUntil string `json:"lockedUntil,omitempty" norman:"nocreate,noupdate"`
 }
 
 type UserCondition struct {
@@ -154,6 +196,10 @@ type SetPasswordInput struct {
 	NewPassword string `json:"newPassword" norman:"type=string,required"`
 }
 
+type VerifyMFAInput struct {
+	Code string `json:"code" norman:"type=string,required"`
+}
+
 // +genclient
 // +genclient:nonNamespaced
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
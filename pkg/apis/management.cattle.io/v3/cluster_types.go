@@ -39,6 +39,8 @@ func init() {
 	ClusterActionRunSecurityScan       = "runSecurityScan"
 	ClusterActionSaveAsTemplate        = "saveAsTemplate"
 
+	ClusterCollectionActionExpiringCertificates = "expiringCertificates"
+
 	// ClusterConditionReady Cluster ready to serve API (healthy when true, unhealthy when false)
 	ClusterConditionReady          condition.Cond = "Ready"
 	ClusterConditionPending        condition.Cond = "Pending"
@@ -53,6 +55,18 @@ func init() {
 	ClusterConditionNoDiskPressure condition.Cond = "NoDiskPressure"
 	// ClusterConditionNoMemoryPressure true when all cluster nodes have sufficient memory
 	ClusterConditionNoMemoryPressure condition.Cond = "NoMemoryPressure"
+	// ClusterConditionAPIServerHealthy true when the readyz and livez checks of the kube-apiserver pass
+	ClusterConditionAPIServerHealthy condition.Cond = "APIServerHealthy"
+	// ClusterConditionEtcdHealthy true when the etcd checks of the kube-apiserver pass and a quorum of the etcd nodes
+	// is ready
+	ClusterConditionEtcdHealthy condition.Cond = "EtcdHealthy"
+	// ClusterConditionSchedulerHealthy true when the leader lease of the kube-scheduler is renewed in time
+	ClusterConditionSchedulerHealthy condition.Cond = "SchedulerHealthy"
+	// ClusterConditionControllerManagerHealthy true when the leader lease of the kube-controller-manager is renewed
+	// in time
+	ClusterConditionControllerManagerHealthy condition.Cond = "ControllerManagerHealthy"
+	// ClusterConditionNodesReady true when at least half of the cluster nodes are ready
+	ClusterConditionNodesReady condition.Cond = "NodesReady"
 	// ClusterConditionconditionDefaultProjectCreated true when default project has been created
 	ClusterConditionconditionDefaultProjectCreated condition.Cond = "DefaultProjectCreated"
 	// ClusterConditionconditionSystemProjectCreated true when system project has been created
@@ -116,6 +130,19 @@ type ClusterSpecBase struct {
 	WindowsPreferedCluster               bool                                    `json:"windowsPreferedCluster" norman:"noupdate"`
 	LocalClusterAuthEndpoint             LocalClusterAuthEndpoint                `json:"localClusterAuthEndpoint,omitempty"`
 	ScheduledClusterScan                 *ScheduledClusterScan                   `json:"scheduledClusterScan,omitempty"`
+	EtcdBackupEncryption                 *EtcdBackupEncryptionConfig             `json:"etcdBackupEncryption,omitempty"`
+}
+
+// EtcdBackupEncryptionConfig selects the keys the etcd snapshots of an RKE cluster are encrypted with in S3. RKE uploads
+// the snapshots itself, rancher encrypts them in the bucket right after the upload and decrypts them there again for a
+// restore. Every entry of the secret is a 256 bit AES key, raw or base64 encoded, named by its key ID. Keys are
+// rotated by adding a new entry and selecting it, the backups are then re-encrypted with the new key.
+type EtcdBackupEncryptionConfig struct {
+	// SecretName is the secret holding the keys, as namespace:name. Without a namespace it is looked up in cattle-global-data.
+	SecretName string `json:"secretName" norman:"required"`
+	// KeyID is the key new snapshots are encrypted with, it defaults to the etcdsnapshot.cattle.io/active-key annotation
+	// of the secret, or its only key.
+	KeyID string `json:"keyId,omitempty"`
 }
 
 type ClusterSpec struct {
@@ -327,6 +354,13 @@ type MonitoringOutput struct {
 type RestoreFromEtcdBackupInput struct {
 	EtcdBackupName   string `json:"etcdBackupName,omitempty" norman:"type=reference[etcdBackup]"`
 	RestoreRkeConfig string `json:"restoreRkeConfig,omitempty"`
+	// DryRun only validates the snapshot against the cluster, the cluster is not flagged for restore
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+type RestoreFromEtcdBackupOutput struct {
+	Valid    bool     `json:"valid"`
+	Messages []string `json:"messages,omitempty"`
 }
 
 type RotateCertificateInput struct {
@@ -352,6 +386,23 @@ type CertExpiration struct {
 	ExpirationDate string `json:"expirationDate,omitempty"`
 }
 
+type ExpiringCertificatesInput struct {
+	// WithinDays selects the certificates expiring within this many days, defaults to the certificate-expiration-warning-days setting
+	WithinDays int `json:"withinDays,omitempty"`
+}
+
+type ExpiringCertificatesOutput struct {
+	Certificates []ExpiringCertificate `json:"certificates"`
+}
+
+type ExpiringCertificate struct {
+	ClusterID      string `json:"clusterId,omitempty" norman:"type=reference[cluster]"`
+	ClusterName    string `json:"clusterName,omitempty"`
+	Name           string `json:"name,omitempty"`
+	ExpirationDate string `json:"expirationDate,omitempty"`
+	Severity       string `json:"severity,omitempty"`
+}
+
 type SaveAsTemplateInput struct {
 	ClusterTemplateName         string `json:"clusterTemplateName,omitempty"`
 	ClusterTemplateRevisionName string `json:"clusterTemplateRevisionName,omitempty"`
@@ -39,6 +39,29 @@ type BasicLogin struct {
 	Password     string `json:"password" norman:"type=string,required"`
 }
 
+// MFAChallenge is returned by a local login when a second factor is required. Its
+// challenge token is exchanged for a login token by the mfalogin action.
+type MFAChallenge struct {
+	ChallengeToken     string `json:"challengeToken"`
+	EnrollmentRequired bool   `json:"enrollmentRequired"`
+}
+
+type MFALoginInput struct {
+	GenericLogin   `json:",inline"`
+	ChallengeToken string `json:"challengeToken" norman:"type=string,required"`
+	Code           string `json:"code" norman:"type=string,required"`
+}
+
+type MFAEnrollInput struct {
+	ChallengeToken string `json:"challengeToken" norman:"type=string,required"`
+}
+
+type MFAEnrollOutput struct {
+	Secret        string   `json:"secret"`
+	OTPAuthURL    string   `json:"otpAuthUrl"`
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
 // +genclient
 // +genclient:nonNamespaced
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -1,11 +1,17 @@
 package v3
 
 import (
+	"github.com/rancher/norman/condition"
 	"github.com/rancher/norman/types"
 	rketypes "github.com/rancher/rke/types"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// EtcdBackupConditionVerified is true when the stored snapshot could be downloaded and opened the last time it was verified
+	EtcdBackupConditionVerified condition.Cond = "Verified"
+)
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
@@ -383,6 +383,131 @@ func (in *AlertCommonSpec) DeepCopy() *AlertCommonSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertSilence) DeepCopyInto(out *AlertSilence) {
+	*out = *in
+	out.Namespaced = in.Namespaced
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertSilence.
+func (in *AlertSilence) DeepCopy() *AlertSilence {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertSilence)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AlertSilence) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertSilenceList) DeepCopyInto(out *AlertSilenceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AlertSilence, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertSilenceList.
+func (in *AlertSilenceList) DeepCopy() *AlertSilenceList {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertSilenceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AlertSilenceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertSilenceMatcher) DeepCopyInto(out *AlertSilenceMatcher) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertSilenceMatcher.
+func (in *AlertSilenceMatcher) DeepCopy() *AlertSilenceMatcher {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertSilenceMatcher)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertSilenceSpec) DeepCopyInto(out *AlertSilenceSpec) {
+	*out = *in
+	if in.GroupNames != nil {
+		in, out := &in.GroupNames, &out.GroupNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RuleNames != nil {
+		in, out := &in.RuleNames, &out.RuleNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Matchers != nil {
+		in, out := &in.Matchers, &out.Matchers
+		*out = make([]AlertSilenceMatcher, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertSilenceSpec.
+func (in *AlertSilenceSpec) DeepCopy() *AlertSilenceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertSilenceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertSilenceStatus) DeepCopyInto(out *AlertSilenceStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertSilenceStatus.
+func (in *AlertSilenceStatus) DeepCopy() *AlertSilenceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertSilenceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlertStatus) DeepCopyInto(out *AlertStatus) {
 	*out = *in
@@ -1111,6 +1236,29 @@ func (in *CertExpiration) DeepCopy() *CertExpiration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateRule) DeepCopyInto(out *CertificateRule) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateRule.
+func (in *CertificateRule) DeepCopy() *CertificateRule {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChangePasswordInput) DeepCopyInto(out *ChangePasswordInput) {
 	*out = *in
@@ -2445,6 +2593,11 @@ func (in *ClusterSpecBase) DeepCopyInto(out *ClusterSpecBase) {
 		*out = new(ScheduledClusterScan)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.EtcdBackupEncryption != nil {
+		in, out := &in.EtcdBackupEncryption, &out.EtcdBackupEncryption
+		*out = new(EtcdBackupEncryptionConfig)
+		**out = **in
+	}
 	return
 }
 
@@ -3353,6 +3506,22 @@ func (in *EtcdBackup) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdBackupEncryptionConfig) DeepCopyInto(out *EtcdBackupEncryptionConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdBackupEncryptionConfig.
+func (in *EtcdBackupEncryptionConfig) DeepCopy() *EtcdBackupEncryptionConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdBackupEncryptionConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EtcdBackupList) DeepCopyInto(out *EtcdBackupList) {
 	*out = *in
@@ -3402,6 +3571,59 @@ func (in *EventRule) DeepCopy() *EventRule {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExpiringCertificate) DeepCopyInto(out *ExpiringCertificate) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExpiringCertificate.
+func (in *ExpiringCertificate) DeepCopy() *ExpiringCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(ExpiringCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExpiringCertificatesInput) DeepCopyInto(out *ExpiringCertificatesInput) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExpiringCertificatesInput.
+func (in *ExpiringCertificatesInput) DeepCopy() *ExpiringCertificatesInput {
+	if in == nil {
+		return nil
+	}
+	out := new(ExpiringCertificatesInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExpiringCertificatesOutput) DeepCopyInto(out *ExpiringCertificatesOutput) {
+	*out = *in
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = make([]ExpiringCertificate, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExpiringCertificatesOutput.
+func (in *ExpiringCertificatesOutput) DeepCopy() *ExpiringCertificatesOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(ExpiringCertificatesOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExportOutput) DeepCopyInto(out *ExportOutput) {
 	*out = *in
@@ -4378,6 +4600,27 @@ func (in *GlobalRoleList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GoogleChatConfig) DeepCopyInto(out *GoogleChatConfig) {
+	*out = *in
+	if in.HTTPClientConfig != nil {
+		in, out := &in.HTTPClientConfig, &out.HTTPClientConfig
+		*out = new(HTTPClientConfig)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleChatConfig.
+func (in *GoogleChatConfig) DeepCopy() *GoogleChatConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GoogleChatConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GoogleOAuthProvider) DeepCopyInto(out *GoogleOAuthProvider) {
 	*out = *in
@@ -4749,6 +4992,29 @@ func (in *IngressCapabilities) DeepCopy() *IngressCapabilities {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobRule) DeepCopyInto(out *JobRule) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobRule.
+func (in *JobRule) DeepCopy() *JobRule {
+	if in == nil {
+		return nil
+	}
+	out := new(JobRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *K3sConfig) DeepCopyInto(out *K3sConfig) {
 	*out = *in
@@ -5356,6 +5622,76 @@ func (in *LoggingTargets) DeepCopy() *LoggingTargets {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MFAChallenge) DeepCopyInto(out *MFAChallenge) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MFAChallenge.
+func (in *MFAChallenge) DeepCopy() *MFAChallenge {
+	if in == nil {
+		return nil
+	}
+	out := new(MFAChallenge)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MFAEnrollInput) DeepCopyInto(out *MFAEnrollInput) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MFAEnrollInput.
+func (in *MFAEnrollInput) DeepCopy() *MFAEnrollInput {
+	if in == nil {
+		return nil
+	}
+	out := new(MFAEnrollInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MFAEnrollOutput) DeepCopyInto(out *MFAEnrollOutput) {
+	*out = *in
+	if in.RecoveryCodes != nil {
+		in, out := &in.RecoveryCodes, &out.RecoveryCodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MFAEnrollOutput.
+func (in *MFAEnrollOutput) DeepCopy() *MFAEnrollOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(MFAEnrollOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MFALoginInput) DeepCopyInto(out *MFALoginInput) {
+	*out = *in
+	out.GenericLogin = in.GenericLogin
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MFALoginInput.
+func (in *MFALoginInput) DeepCopy() *MFALoginInput {
+	if in == nil {
+		return nil
+	}
+	out := new(MFALoginInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MSTeamsConfig) DeepCopyInto(out *MSTeamsConfig) {
 	*out = *in
@@ -5532,6 +5868,27 @@ func (in MapStringInterface) DeepCopyInto(out *MapStringInterface) {
 	}
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MattermostConfig) DeepCopyInto(out *MattermostConfig) {
+	*out = *in
+	if in.HTTPClientConfig != nil {
+		in, out := &in.HTTPClientConfig, &out.HTTPClientConfig
+		*out = new(HTTPClientConfig)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MattermostConfig.
+func (in *MattermostConfig) DeepCopy() *MattermostConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MattermostConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Member) DeepCopyInto(out *Member) {
 	*out = *in
@@ -6674,6 +7031,26 @@ func (in *Notification) DeepCopyInto(out *Notification) {
 		*out = new(MSTeamsConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.OpsgenieConfig != nil {
+		in, out := &in.OpsgenieConfig, &out.OpsgenieConfig
+		*out = new(OpsgenieConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TelegramConfig != nil {
+		in, out := &in.TelegramConfig, &out.TelegramConfig
+		*out = new(TelegramConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GoogleChatConfig != nil {
+		in, out := &in.GoogleChatConfig, &out.GoogleChatConfig
+		*out = new(GoogleChatConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MattermostConfig != nil {
+		in, out := &in.MattermostConfig, &out.MattermostConfig
+		*out = new(MattermostConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -6694,7 +7071,7 @@ func (in *Notifier) DeepCopyInto(out *Notifier) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -6716,6 +7093,22 @@ func (in *Notifier) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotifierDeadLetter) DeepCopyInto(out *NotifierDeadLetter) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotifierDeadLetter.
+func (in *NotifierDeadLetter) DeepCopy() *NotifierDeadLetter {
+	if in == nil {
+		return nil
+	}
+	out := new(NotifierDeadLetter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NotifierList) DeepCopyInto(out *NotifierList) {
 	*out = *in
@@ -6749,6 +7142,27 @@ func (in *NotifierList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotifierResendInput) DeepCopyInto(out *NotifierResendInput) {
+	*out = *in
+	if in.DeadLetterIDs != nil {
+		in, out := &in.DeadLetterIDs, &out.DeadLetterIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotifierResendInput.
+func (in *NotifierResendInput) DeepCopy() *NotifierResendInput {
+	if in == nil {
+		return nil
+	}
+	out := new(NotifierResendInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NotifierSpec) DeepCopyInto(out *NotifierSpec) {
 	*out = *in
@@ -6787,6 +7201,31 @@ func (in *NotifierSpec) DeepCopyInto(out *NotifierSpec) {
 		*out = new(MSTeamsConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.OpsgenieConfig != nil {
+		in, out := &in.OpsgenieConfig, &out.OpsgenieConfig
+		*out = new(OpsgenieConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TelegramConfig != nil {
+		in, out := &in.TelegramConfig, &out.TelegramConfig
+		*out = new(TelegramConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GoogleChatConfig != nil {
+		in, out := &in.GoogleChatConfig, &out.GoogleChatConfig
+		*out = new(GoogleChatConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MattermostConfig != nil {
+		in, out := &in.MattermostConfig, &out.MattermostConfig
+		*out = new(MattermostConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(NotifierTemplate)
+		**out = **in
+	}
 	return
 }
 
@@ -6803,6 +7242,11 @@ func (in *NotifierSpec) DeepCopy() *NotifierSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NotifierStatus) DeepCopyInto(out *NotifierStatus) {
 	*out = *in
+	if in.DeadLetters != nil {
+		in, out := &in.DeadLetters, &out.DeadLetters
+		*out = make([]NotifierDeadLetter, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -6816,6 +7260,22 @@ func (in *NotifierStatus) DeepCopy() *NotifierStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotifierTemplate) DeepCopyInto(out *NotifierTemplate) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotifierTemplate.
+func (in *NotifierTemplate) DeepCopy() *NotifierTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(NotifierTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OIDCApplyInput) DeepCopyInto(out *OIDCApplyInput) {
 	*out = *in
@@ -7117,6 +7577,32 @@ func (in *OpenLdapTestAndApplyInput) DeepCopy() *OpenLdapTestAndApplyInput {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpsgenieConfig) DeepCopyInto(out *OpsgenieConfig) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HTTPClientConfig != nil {
+		in, out := &in.HTTPClientConfig, &out.HTTPClientConfig
+		*out = new(HTTPClientConfig)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpsgenieConfig.
+func (in *OpsgenieConfig) DeepCopy() *OpsgenieConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OpsgenieConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PagerdutyConfig) DeepCopyInto(out *PagerdutyConfig) {
 	*out = *in
@@ -7691,6 +8177,21 @@ func (in *ProjectAlertRuleSpec) DeepCopyInto(out *ProjectAlertRuleSpec) {
 		*out = new(MetricRule)
 		**out = **in
 	}
+	if in.VolumeRule != nil {
+		in, out := &in.VolumeRule, &out.VolumeRule
+		*out = new(VolumeRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CertificateRule != nil {
+		in, out := &in.CertificateRule, &out.CertificateRule
+		*out = new(CertificateRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JobRule != nil {
+		in, out := &in.JobRule, &out.JobRule
+		*out = new(JobRule)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -8602,6 +9103,27 @@ func (in *RestoreFromEtcdBackupInput) DeepCopy() *RestoreFromEtcdBackupInput {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreFromEtcdBackupOutput) DeepCopyInto(out *RestoreFromEtcdBackupOutput) {
+	*out = *in
+	if in.Messages != nil {
+		in, out := &in.Messages, &out.Messages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreFromEtcdBackupOutput.
+func (in *RestoreFromEtcdBackupOutput) DeepCopy() *RestoreFromEtcdBackupOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreFromEtcdBackupOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Rke2Config) DeepCopyInto(out *Rke2Config) {
 	*out = *in
@@ -9659,6 +10181,27 @@ func (in *TargetWorkload) DeepCopy() *TargetWorkload {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TelegramConfig) DeepCopyInto(out *TelegramConfig) {
+	*out = *in
+	if in.HTTPClientConfig != nil {
+		in, out := &in.HTTPClientConfig, &out.HTTPClientConfig
+		*out = new(HTTPClientConfig)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TelegramConfig.
+func (in *TelegramConfig) DeepCopy() *TelegramConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TelegramConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Template) DeepCopyInto(out *Template) {
 	*out = *in
@@ -10064,6 +10607,59 @@ func (in *TokenList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenRevokeInput) DeepCopyInto(out *TokenRevokeInput) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenRevokeInput.
+func (in *TokenRevokeInput) DeepCopy() *TokenRevokeInput {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenRevokeInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenRevokeOutput) DeepCopyInto(out *TokenRevokeOutput) {
+	*out = *in
+	if in.Tokens != nil {
+		in, out := &in.Tokens, &out.Tokens
+		*out = make([]TokenSummary, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenRevokeOutput.
+func (in *TokenRevokeOutput) DeepCopy() *TokenRevokeOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenRevokeOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenSummary) DeepCopyInto(out *TokenSummary) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenSummary.
+func (in *TokenSummary) DeepCopy() *TokenSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UpdateGlobalDNSTargetsInput) DeepCopyInto(out *UpdateGlobalDNSTargetsInput) {
 	*out = *in
@@ -10139,6 +10735,11 @@ func (in *User) DeepCopyInto(out *User) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.PasswordHistory != nil {
+		in, out := &in.PasswordHistory, &out.PasswordHistory
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.PrincipalIDs != nil {
 		in, out := &in.PrincipalIDs, &out.PrincipalIDs
 		*out = make([]string, len(*in))
@@ -10345,6 +10946,22 @@ func (in *Values) DeepCopy() *Values {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerifyMFAInput) DeepCopyInto(out *VerifyMFAInput) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerifyMFAInput.
+func (in *VerifyMFAInput) DeepCopy() *VerifyMFAInput {
+	if in == nil {
+		return nil
+	}
+	out := new(VerifyMFAInput)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VersionCommits) DeepCopyInto(out *VersionCommits) {
 	*out = *in
@@ -10368,6 +10985,29 @@ func (in *VersionCommits) DeepCopy() *VersionCommits {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeRule) DeepCopyInto(out *VolumeRule) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeRule.
+func (in *VolumeRule) DeepCopy() *VolumeRule {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WebhookConfig) DeepCopyInto(out *WebhookConfig) {
 	*out = *in
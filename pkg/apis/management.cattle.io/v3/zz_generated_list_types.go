@@ -60,6 +60,23 @@ func NewActiveDirectoryProvider(namespace, name string, obj ActiveDirectoryProvi
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// AlertSilenceList is a list of AlertSilence resources
+type AlertSilenceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []AlertSilence `json:"items"`
+}
+
+func NewAlertSilence(namespace, name string, obj AlertSilence) *AlertSilence {
+	obj.APIVersion, obj.Kind = SchemeGroupVersion.WithKind("AlertSilence").ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // AuthConfigList is a list of AuthConfig resources
 type AuthConfigList struct {
 	metav1.TypeMeta `json:",inline"`
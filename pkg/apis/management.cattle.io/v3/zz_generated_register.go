@@ -30,6 +30,7 @@
 var (
 	APIServiceResourceName                              = "apiservices"
 	ActiveDirectoryProviderResourceName                 = "activedirectoryproviders"
+	AlertSilenceResourceName                            = "alertsilences"
 	AuthConfigResourceName                              = "authconfigs"
 	AuthProviderResourceName                            = "authproviders"
 	AuthTokenResourceName                               = "authtokens"
@@ -132,6 +133,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&APIServiceList{},
 		&ActiveDirectoryProvider{},
 		&ActiveDirectoryProviderList{},
+		&AlertSilence{},
+		&AlertSilenceList{},
 		&AuthConfig{},
 		&AuthConfigList{},
 		&AuthProvider{},
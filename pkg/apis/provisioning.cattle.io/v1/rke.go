@@ -60,6 +60,8 @@ type RKEConfig struct {
 
 	ETCDSnapshotCreate  *rkev1.ETCDSnapshotCreate `json:"etcdSnapshotCreate,omitempty"`
 	ETCDSnapshotRestore *rkev1.ETCDSnapshot       `json:"etcdSnapshotRestore,omitempty"`
+	ETCDSnapshotSource  *rkev1.ETCDSnapshotSource `json:"etcdSnapshotSource,omitempty"`
+	RotateCertificates  *rkev1.RotateCertificates `json:"rotateCertificates,omitempty"`
 	MachinePools        []RKEMachinePool          `json:"machinePools,omitempty"`
 	InfrastructureRef   *corev1.ObjectReference   `json:"infrastructureRef,omitempty"`
 }
@@ -201,6 +201,16 @@ func (in *RKEConfig) DeepCopyInto(out *RKEConfig) {
 		*out = new(rkecattleiov1.ETCDSnapshot)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ETCDSnapshotSource != nil {
+		in, out := &in.ETCDSnapshotSource, &out.ETCDSnapshotSource
+		*out = new(rkecattleiov1.ETCDSnapshotSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RotateCertificates != nil {
+		in, out := &in.RotateCertificates, &out.RotateCertificates
+		*out = new(rkecattleiov1.RotateCertificates)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.MachinePools != nil {
 		in, out := &in.MachinePools, &out.MachinePools
 		*out = make([]RKEMachinePool, len(*in))
@@ -0,0 +1,21 @@
+package v1
+
+type CertificateRotationPhase string
+
+var (
+	CertificateRotationPhaseRotating CertificateRotationPhase = "Rotating"
+	CertificateRotationPhaseFinished CertificateRotationPhase = "Finished"
+	// CertificateRotationPhaseUnsupported means the requested rotation was rejected because the Kubernetes version
+	// of the cluster can not rotate certificates.
+	CertificateRotationPhaseUnsupported CertificateRotationPhase = "Unsupported"
+)
+
+type RotateCertificates struct {
+	// Changing the Generation is the only thing required to initiate a certificate rotation.
+	Generation int64 `json:"generation,omitempty"`
+	// Services limits the rotation to the certificates of these services, all certificates are rotated if empty.
+	Services []string `json:"services,omitempty"`
+	// ExpirationThresholdDays rotates the certificates once one of them expires within this many days, 0 disables
+	// the automatic rotation.
+	ExpirationThresholdDays int `json:"expirationThresholdDays,omitempty"`
+}
@@ -22,6 +22,8 @@ type RKEControlPlaneSpec struct {
 	AgentEnvVars          []corev1.EnvVar     `json:"agentEnvVars,omitempty"`
 	ETCDSnapshotCreate    *ETCDSnapshotCreate `json:"etcdSnapshotCreate,omitempty"`
 	ETCDSnapshotRestore   *ETCDSnapshot       `json:"etcdSnapshotRestore,omitempty"`
+	ETCDSnapshotSource    *ETCDSnapshotSource `json:"etcdSnapshotSource,omitempty"`
+	RotateCertificates    *RotateCertificates `json:"rotateCertificates,omitempty"`
 	KubernetesVersion     string              `json:"kubernetesVersion,omitempty"`
 	ClusterName           string              `json:"clusterName,omitempty" wrangler:"required"`
 	ManagementClusterName string              `json:"managementClusterName,omitempty" wrangler:"required"`
@@ -46,4 +48,15 @@ type RKEControlPlaneStatus struct {
 	ETCDSnapshotCreate       *ETCDSnapshotCreate                 `json:"etcdSnapshotCreate,omitempty"`
 	ETCDSnapshotCreatePhase  ETCDSnapshotPhase                   `json:"etcdSnapshotCreatePhase,omitempty"`
 	ConfigGeneration         int64                               `json:"configGeneration,omitempty"`
+
+	// ETCDSnapshotSourcePhase is Restore while a new cluster is bootstrapped from the ETCDSnapshotSource, and Finished
+	// once it is provisioned. The source is never restored again afterwards.
+	ETCDSnapshotSourcePhase ETCDSnapshotPhase `json:"etcdSnapshotSourcePhase,omitempty"`
+
+	// CertificateRotationGeneration is increased every time a certificate rotation is started and rolled out with the node plans.
+	CertificateRotationGeneration int64                    `json:"certificateRotationGeneration,omitempty"`
+	CertificateRotationPhase      CertificateRotationPhase `json:"certificateRotationPhase,omitempty"`
+	CertificateRotationProgress   string                   `json:"certificateRotationProgress,omitempty"`
+	CertificateRotationTime       *metav1.Time             `json:"certificateRotationTime,omitempty"`
+	RotateCertificates            *RotateCertificates      `json:"rotateCertificates,omitempty"`
 }
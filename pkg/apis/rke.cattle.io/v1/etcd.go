@@ -12,25 +12,83 @@ type ETCDSnapshotS3 struct {
 	Folder              string `json:"folder,omitempty"`
 }
 
+// ETCDSnapshotTarget is a backup target besides S3. Snapshots are still taken by the etcd nodes, the rancher agent
+// transfers them to and from the remote targets, and the directory of the filesystem target is used for the snapshots.
+type ETCDSnapshotTarget struct {
+	// Type is one of azure, gcs, sftp or filesystem.
+	Type string `json:"type,omitempty" wrangler:"required"`
+	// Bucket is the GCS bucket or Azure blob container.
+	Bucket string `json:"bucket,omitempty"`
+	Folder string `json:"folder,omitempty"`
+	// Endpoint is the storage endpoint suffix of the Azure cloud, core.windows.net by default.
+	Endpoint string `json:"endpoint,omitempty"`
+	// Host and HostKey are the address and the public key in authorized_keys format of the SFTP server.
+	Host    string `json:"host,omitempty"`
+	HostKey string `json:"hostKey,omitempty"`
+	// Path is the directory of the filesystem target on the etcd nodes, usually an NFS export or mounted volume.
+	Path                string `json:"path,omitempty"`
+	CloudCredentialName string `json:"cloudCredentialName,omitempty"`
+}
+
+// ETCDSnapshotEncryption encrypts the snapshots with AES-256-GCM before the rancher agent uploads them, setting it
+// makes the agent upload the snapshots to S3 instead of the distribution. Snapshots kept on the etcd nodes and in the
+// filesystem target are not encrypted.
+type ETCDSnapshotEncryption struct {
+	// SecretName is the secret in the namespace of the cluster holding the keys. Every entry is a 256 bit key, raw or
+	// base64 encoded, named by its key ID. Keys are rotated by adding a new entry and selecting it. The etcd nodes are
+	// only given the active key, and the key of a snapshot while it is restored, so stored snapshots keep the key they
+	// were encrypted with, which has to be kept as long as they are.
+	SecretName string `json:"secretName,omitempty" wrangler:"required"`
+	// KeyID is the key new snapshots are encrypted with, it defaults to the etcdsnapshot.cattle.io/active-key
+	// annotation of the secret, or its only key.
+	KeyID string `json:"keyID,omitempty"`
+}
+
 type ETCDSnapshotCreate struct {
-	Name     string          `json:"name,omitempty"`
-	NodeName string          `json:"nodeName,omitempty"`
-	S3       *ETCDSnapshotS3 `json:"s3,omitempty"`
+	Name     string              `json:"name,omitempty"`
+	NodeName string              `json:"nodeName,omitempty"`
+	S3       *ETCDSnapshotS3     `json:"s3,omitempty"`
+	Target   *ETCDSnapshotTarget `json:"target,omitempty"`
 	// Changing the Generation is the only thing required to initiate a snapshot creation.
 	Generation int `json:"generation,omitempty"`
 }
 
 type ETCDSnapshot struct {
-	Name      string          `json:"name,omitempty"`
-	NodeName  string          `json:"nodeName,omitempty"`
-	CreatedAt *metav1.Time    `json:"createdAt,omitempty"`
-	Size      int64           `json:"size,omitempty"`
-	S3        *ETCDSnapshotS3 `json:"s3,omitempty"`
+	Name      string              `json:"name,omitempty"`
+	NodeName  string              `json:"nodeName,omitempty"`
+	CreatedAt *metav1.Time        `json:"createdAt,omitempty"`
+	Size      int64               `json:"size,omitempty"`
+	S3        *ETCDSnapshotS3     `json:"s3,omitempty"`
+	Target    *ETCDSnapshotTarget `json:"target,omitempty"`
+	// EncryptionKeyID is the key the copy of the snapshot uploaded by the agent is encrypted with, it is given to the
+	// etcd nodes for a restore. The active key of the cluster is used if it is not set.
+	EncryptionKeyID string `json:"encryptionKeyID,omitempty"`
+}
+
+// ETCDSnapshotSource bootstraps a new cluster from a snapshot of another cluster, to clone it or to recover it after a
+// disaster. The snapshot is restored on the init node before the other machines join, the nodes of the source cluster
+// are then removed and the cluster registers under its own name.
+type ETCDSnapshotSource struct {
+	// ClusterName is the cluster in the same namespace the snapshot was taken from. RKE2 and K3s encrypt the bootstrap
+	// data in the snapshot with the server token, so the new cluster takes over the server token of the source cluster.
+	ClusterName string `json:"clusterName,omitempty"`
+	// ServerTokenSecretName is a secret in the namespace of the cluster holding the server token of the source cluster
+	// in the serverToken key, for source clusters that no longer exist. It defaults to the state of ClusterName. The
+	// creator of the cluster must be able to get the source cluster and the secret, and must have created the secret.
+	ServerTokenSecretName string              `json:"serverTokenSecretName,omitempty"`
+	Name                  string              `json:"name,omitempty" wrangler:"required"`
+	S3                    *ETCDSnapshotS3     `json:"s3,omitempty"`
+	Target                *ETCDSnapshotTarget `json:"target,omitempty"`
+	// EncryptionKeyID is the key the snapshot is encrypted with, it has to be in the encryption secret of the new
+	// cluster. The active key of the cluster is used if it is not set.
+	EncryptionKeyID string `json:"encryptionKeyID,omitempty"`
 }
 
 type ETCD struct {
-	DisableSnapshots     bool            `json:"disableSnapshots,omitempty"`
-	SnapshotScheduleCron string          `json:"snapshotScheduleCron,omitempty"`
-	SnapshotRetention    int             `json:"snapshotRetention,omitempty"`
-	S3                   *ETCDSnapshotS3 `json:"s3,omitempty"`
+	DisableSnapshots     bool                    `json:"disableSnapshots,omitempty"`
+	SnapshotScheduleCron string                  `json:"snapshotScheduleCron,omitempty"`
+	SnapshotRetention    int                     `json:"snapshotRetention,omitempty"`
+	S3                   *ETCDSnapshotS3         `json:"s3,omitempty"`
+	Target               *ETCDSnapshotTarget     `json:"target,omitempty"`
+	Encryption           *ETCDSnapshotEncryption `json:"encryption,omitempty"`
 }
@@ -189,6 +189,16 @@ func (in *ETCD) DeepCopyInto(out *ETCD) {
 		*out = new(ETCDSnapshotS3)
 		**out = **in
 	}
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(ETCDSnapshotTarget)
+		**out = **in
+	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(ETCDSnapshotEncryption)
+		**out = **in
+	}
 	return
 }
 
@@ -214,6 +224,11 @@ func (in *ETCDSnapshot) DeepCopyInto(out *ETCDSnapshot) {
 		*out = new(ETCDSnapshotS3)
 		**out = **in
 	}
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(ETCDSnapshotTarget)
+		**out = **in
+	}
 	return
 }
 
@@ -235,6 +250,11 @@ func (in *ETCDSnapshotCreate) DeepCopyInto(out *ETCDSnapshotCreate) {
 		*out = new(ETCDSnapshotS3)
 		**out = **in
 	}
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(ETCDSnapshotTarget)
+		**out = **in
+	}
 	return
 }
 
@@ -248,6 +268,22 @@ func (in *ETCDSnapshotCreate) DeepCopy() *ETCDSnapshotCreate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ETCDSnapshotEncryption) DeepCopyInto(out *ETCDSnapshotEncryption) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ETCDSnapshotEncryption.
+func (in *ETCDSnapshotEncryption) DeepCopy() *ETCDSnapshotEncryption {
+	if in == nil {
+		return nil
+	}
+	out := new(ETCDSnapshotEncryption)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ETCDSnapshotS3) DeepCopyInto(out *ETCDSnapshotS3) {
 	*out = *in
@@ -264,6 +300,48 @@ func (in *ETCDSnapshotS3) DeepCopy() *ETCDSnapshotS3 {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ETCDSnapshotSource) DeepCopyInto(out *ETCDSnapshotSource) {
+	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(ETCDSnapshotS3)
+		**out = **in
+	}
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(ETCDSnapshotTarget)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ETCDSnapshotSource.
+func (in *ETCDSnapshotSource) DeepCopy() *ETCDSnapshotSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ETCDSnapshotSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ETCDSnapshotTarget) DeepCopyInto(out *ETCDSnapshotTarget) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ETCDSnapshotTarget.
+func (in *ETCDSnapshotTarget) DeepCopy() *ETCDSnapshotTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ETCDSnapshotTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Endpoint) DeepCopyInto(out *Endpoint) {
 	*out = *in
@@ -761,6 +839,16 @@ func (in *RKEControlPlaneSpec) DeepCopyInto(out *RKEControlPlaneSpec) {
 		*out = new(ETCDSnapshot)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ETCDSnapshotSource != nil {
+		in, out := &in.ETCDSnapshotSource, &out.ETCDSnapshotSource
+		*out = new(ETCDSnapshotSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RotateCertificates != nil {
+		in, out := &in.RotateCertificates, &out.RotateCertificates
+		*out = new(RotateCertificates)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -792,6 +880,15 @@ func (in *RKEControlPlaneStatus) DeepCopyInto(out *RKEControlPlaneStatus) {
 		*out = new(ETCDSnapshotCreate)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CertificateRotationTime != nil {
+		in, out := &in.CertificateRotationTime, &out.CertificateRotationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RotateCertificates != nil {
+		in, out := &in.RotateCertificates, &out.RotateCertificates
+		*out = new(RotateCertificates)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -898,3 +995,24 @@ func (in *RegistryConfig) DeepCopy() *RegistryConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RotateCertificates) DeepCopyInto(out *RotateCertificates) {
+	*out = *in
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RotateCertificates.
+func (in *RotateCertificates) DeepCopy() *RotateCertificates {
+	if in == nil {
+		return nil
+	}
+	out := new(RotateCertificates)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,97 @@
+package user
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/rancher/pkg/settings"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// passwordPolicy holds the requirements local user passwords must meet when they are
+// created or changed.
+type passwordPolicy struct {
+	minLength         int
+	requireComplexity bool
+	historySize       int
+}
+
+func currentPasswordPolicy() passwordPolicy {
+	return passwordPolicy{
+		minLength:         settings.PasswordMinLength.GetInt(),
+		requireComplexity: strings.EqualFold(settings.PasswordRequireComplexity.Get(), "true"),
+		historySize:       settings.PasswordHistorySize.GetInt(),
+	}
+}
+
+// validate checks the password against the length and complexity requirements.
+func (p passwordPolicy) validate(username, password string) error {
+	if len([]rune(password)) < p.minLength {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, fmt.Sprintf("password must be at least %d characters", p.minLength))
+	}
+	if username != "" && strings.EqualFold(username, password) {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, "password cannot be the same as the username")
+	}
+	if !p.requireComplexity {
+		return nil
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	if !upper || !lower || !digit || !symbol {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, "password must contain upper and lower case letters, digits and symbols")
+	}
+	return nil
+}
+
+// checkHistory rejects a password matching the current hash or any of the hashes kept in history.
+func (p passwordPolicy) checkHistory(password, currentHash string, history []string) error {
+	if p.historySize <= 0 {
+		return nil
+	}
+	hashes := append([]string{currentHash}, history...)
+	if len(hashes) > p.historySize {
+		hashes = hashes[:p.historySize]
+	}
+	for _, hash := range hashes {
+		if hash == "" {
+			continue
+		}
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil {
+			return httperror.NewAPIError(httperror.InvalidBodyContent, fmt.Sprintf("password cannot be one of the last %d passwords", p.historySize))
+		}
+	}
+	return nil
+}
+
+// nextHistory returns the password history after currentHash has been replaced.
+func (p passwordPolicy) nextHistory(currentHash string, history []string) []string {
+	// the new password is checked against the current hash as well, so only
+	// historySize-1 older hashes need to be kept
+	if p.historySize <= 1 || currentHash == "" {
+		return nil
+	}
+	next := append([]string{currentHash}, history...)
+	if len(next) > p.historySize-1 {
+		next = next[:p.historySize-1]
+	}
+	return next
+}
+
+func passwordChangedAt() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
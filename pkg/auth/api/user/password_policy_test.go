@@ -0,0 +1,99 @@
+package user
+
+import (
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   passwordPolicy
+		username string
+		password string
+		wantErr  bool
+	}{
+		{
+			name:     "long enough",
+			policy:   passwordPolicy{minLength: 12},
+			password: "correcthorsebattery",
+		},
+		{
+			name:     "too short",
+			policy:   passwordPolicy{minLength: 12},
+			password: "short",
+			wantErr:  true,
+		},
+		{
+			name:     "same as username",
+			policy:   passwordPolicy{minLength: 5},
+			username: "administrator",
+			password: "Administrator",
+			wantErr:  true,
+		},
+		{
+			name:     "complex",
+			policy:   passwordPolicy{minLength: 8, requireComplexity: true},
+			password: "Tr0ub4dor&3",
+		},
+		{
+			name:     "missing symbol",
+			policy:   passwordPolicy{minLength: 8, requireComplexity: true},
+			password: "Tr0ub4dor3",
+			wantErr:  true,
+		},
+		{
+			name:     "missing digit",
+			policy:   passwordPolicy{minLength: 8, requireComplexity: true},
+			password: "Troubador&!",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.validate(tt.username, tt.password)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPasswordPolicyHistory(t *testing.T) {
+	hash := func(password string) string {
+		h, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(h)
+	}
+
+	policy := passwordPolicy{historySize: 3}
+	current := hash("third")
+	history := policy.nextHistory(hash("second"), []string{hash("first")})
+	if len(history) != 2 {
+		t.Fatalf("expected history of 2 hashes, got %d", len(history))
+	}
+
+	for _, reused := range []string{"third", "second", "first"} {
+		if err := policy.checkHistory(reused, current, history); err == nil {
+			t.Errorf("expected reusing %q to be rejected", reused)
+		}
+	}
+	if err := policy.checkHistory("fourth", current, history); err != nil {
+		t.Errorf("expected new password to be accepted: %v", err)
+	}
+
+	history = policy.nextHistory(current, history)
+	if len(history) != 2 {
+		t.Fatalf("expected history to be capped at 2 hashes, got %d", len(history))
+	}
+	if err := policy.checkHistory("first", hash("fourth"), history); err != nil {
+		t.Errorf("expected password older than the history to be accepted: %v", err)
+	}
+
+	if history := (passwordPolicy{}).nextHistory(current, nil); history != nil {
+		t.Errorf("expected no history to be kept when disabled, got %v", history)
+	}
+}
@@ -9,6 +9,7 @@
 	"github.com/rancher/norman/parse"
 	"github.com/rancher/norman/types"
 	"github.com/rancher/rancher/pkg/auth/providerrefresh"
+	"github.com/rancher/rancher/pkg/auth/providers/local"
 	"github.com/rancher/rancher/pkg/auth/settings"
 	client "github.com/rancher/rancher/pkg/client/generated/management/v3"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
@@ -19,6 +20,14 @@
 func (h *Handler) UserFormatter(apiContext *types.APIContext, resource *types.RawResource) {
 	resource.AddAction(apiContext, "setpassword")
 
+	if lockedUntil, _ := resource.Values[client.UserFieldLockedUntil].(string); lockedUntil != "" && h.userCanUpdate(apiContext) {
+		resource.AddAction(apiContext, "unlock")
+	}
+
+	if h.userCanUpdate(apiContext) {
+		resource.AddAction(apiContext, "resetmfa")
+	}
+
 	if canRefresh := h.userCanRefresh(apiContext); canRefresh {
 		resource.AddAction(apiContext, "refreshauthprovideraccess")
 	}
@@ -26,6 +35,8 @@ func (h *Handler) UserFormatter(apiContext *types.APIContext, resource *types.Ra
 
 func (h *Handler) CollectionFormatter(apiContext *types.APIContext, collection *types.GenericCollection) {
 	collection.AddAction(apiContext, "changepassword")
+	collection.AddAction(apiContext, "enablemfa")
+	collection.AddAction(apiContext, "verifymfa")
 	if canRefresh := h.userCanRefresh(apiContext); canRefresh {
 		collection.AddAction(apiContext, "refreshauthprovideraccess")
 	}
@@ -35,6 +46,7 @@ type Handler struct {
 	UserClient               v3.UserInterface
 	GlobalRoleBindingsClient v3.GlobalRoleBindingInterface
 	UserAuthRefresher        providerrefresh.UserAuthRefresher
+	MFA                      *local.MFAManager
 }
 
 func (h *Handler) Actions(actionName string, action *types.Action, apiContext *types.APIContext) error {
@@ -51,6 +63,16 @@ func (h *Handler) Actions(actionName string, action *types.Action, apiContext *t
 		if err := h.refreshAttributes(actionName, action, apiContext); err != nil {
 			return err
 		}
+	case "unlock":
+		if err := h.unlock(actionName, action, apiContext); err != nil {
+			return err
+		}
+	case "enablemfa":
+		return h.enableMFA(actionName, action, apiContext)
+	case "verifymfa":
+		return h.verifyMFA(actionName, action, apiContext)
+	case "resetmfa":
+		return h.resetMFA(actionName, action, apiContext)
 	default:
 		return errors.Errorf("bad action %v", actionName)
 	}
@@ -98,13 +120,23 @@ func (h *Handler) changePassword(actionName string, action *types.Action, reques
 		return httperror.NewAPIError(httperror.InvalidBodyContent, "invalid current password")
 	}
 
+	policy := currentPasswordPolicy()
+	if err := policy.validate(user.Username, newPass); err != nil {
+		return err
+	}
+	if err := policy.checkHistory(newPass, user.Password, user.PasswordHistory); err != nil {
+		return err
+	}
+
 	newPassHash, err := HashPasswordString(newPass)
 	if err != nil {
 		return err
 	}
 
+	user.PasswordHistory = policy.nextHistory(user.Password, user.PasswordHistory)
 	user.Password = newPassHash
 	user.MustChangePassword = false
+	user.Status.PasswordChangedAt = passwordChangedAt()
 	user, err = h.UserClient.Update(user)
 	if err != nil {
 		return err
@@ -134,10 +166,25 @@ func (h *Handler) setPassword(actionName string, action *types.Action, request *
 		return errors.New("Invalid password")
 	}
 
+	user, err := h.UserClient.Get(request.ID, v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	policy := currentPasswordPolicy()
+	if err := policy.validate(user.Username, newPass); err != nil {
+		return err
+	}
+	if err := policy.checkHistory(newPass, user.Password, user.PasswordHistory); err != nil {
+		return err
+	}
+
 	userData[client.UserFieldPassword] = newPass
 	if err := hashPassword(userData); err != nil {
 		return err
 	}
+	userData[client.UserFieldPasswordHistory] = policy.nextHistory(user.Password, user.PasswordHistory)
+	userData[client.UserFieldPasswordChangedAt] = passwordChangedAt()
 	userData[client.UserFieldMustChangePassword] = false
 	delete(userData, "me")
 
@@ -167,6 +214,119 @@ func (h *Handler) refreshAttributes(actionName string, action *types.Action, req
 	return nil
 }
 
+// unlock clears the failed login counter and lockout of a local user.
+func (h *Handler) unlock(actionName string, action *types.Action, request *types.APIContext) error {
+	if !h.userCanUpdate(request) {
+		return httperror.NewAPIError(httperror.PermissionDenied, "not allowed to unlock users")
+	}
+
+	user, err := h.UserClient.Get(request.ID, v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	user.Status.FailedLoginAttempts = 0
+	user.Status.LockedUntil = ""
+	if _, err := h.UserClient.Update(user); err != nil {
+		return err
+	}
+
+	store := request.Schema.Store
+	if store == nil {
+		return errors.New("no user store available")
+	}
+	userData, err := store.ByID(request, request.Schema, request.ID)
+	if err != nil {
+		return err
+	}
+
+	request.WriteResponse(http.StatusOK, userData)
+	return nil
+}
+
+// enableMFA starts the TOTP enrollment of the current user. The returned secret has to be
+// confirmed with the verifymfa action before it is required at login.
+func (h *Handler) enableMFA(actionName string, action *types.Action, request *types.APIContext) error {
+	userID := request.Request.Header.Get("Impersonate-User")
+	if userID == "" {
+		return errors.New("can't find user")
+	}
+
+	user, err := h.UserClient.Get(userID, v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if user.Password == "" {
+		return httperror.NewAPIError(httperror.InvalidAction, "multi-factor authentication is only available for local users")
+	}
+
+	output, err := h.MFA.Enroll(user)
+	if err != nil {
+		return err
+	}
+
+	request.WriteResponse(http.StatusOK, map[string]interface{}{
+		"type":                                   client.MFAEnrollOutputType,
+		client.MFAEnrollOutputFieldSecret:        output.Secret,
+		client.MFAEnrollOutputFieldOTPAuthURL:    output.OTPAuthURL,
+		client.MFAEnrollOutputFieldRecoveryCodes: output.RecoveryCodes,
+	})
+	return nil
+}
+
+// verifyMFA checks a code of the current user, completing a pending enrollment.
+func (h *Handler) verifyMFA(actionName string, action *types.Action, request *types.APIContext) error {
+	actionInput, err := parse.ReadBody(request.Request)
+	if err != nil {
+		return err
+	}
+
+	code, ok := actionInput[client.VerifyMFAInputFieldCode].(string)
+	if !ok || len(code) == 0 {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, "must specify code")
+	}
+
+	userID := request.Request.Header.Get("Impersonate-User")
+	if userID == "" {
+		return errors.New("can't find user")
+	}
+
+	user, err := h.UserClient.Get(userID, v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	valid, err := h.MFA.Verify(user, code)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, "invalid code")
+	}
+
+	request.WriteResponse(http.StatusOK, nil)
+	return nil
+}
+
+// resetMFA removes the second factor of a user, for example after they lost their device
+// and recovery codes.
+func (h *Handler) resetMFA(actionName string, action *types.Action, request *types.APIContext) error {
+	if !h.userCanUpdate(request) {
+		return httperror.NewAPIError(httperror.PermissionDenied, "not allowed to reset multi-factor authentication")
+	}
+
+	if err := h.MFA.Reset(request.ID); err != nil {
+		return err
+	}
+
+	request.WriteResponse(http.StatusOK, nil)
+	return nil
+}
+
+func (h *Handler) userCanUpdate(request *types.APIContext) bool {
+	return request.AccessControl.CanDo(v3.UserGroupVersionKind.Group, v3.UserResource.Name, "update", request, nil, request.Schema) == nil
+}
+
 func (h *Handler) userCanRefresh(request *types.APIContext) bool {
 	return request.AccessControl.CanDo(v3.UserGroupVersionKind.Group, v3.UserResource.Name, "create", request, nil, request.Schema) == nil
 }
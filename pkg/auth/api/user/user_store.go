@@ -107,6 +107,12 @@ func HashPasswordString(password string) (string, error) {
 }
 
 func (s *userStore) Create(apiContext *types.APIContext, schema *types.Schema, data map[string]interface{}) (map[string]interface{}, error) {
+	username, _ := data[client.UserFieldUsername].(string)
+	password, _ := data[client.UserFieldPassword].(string)
+	if err := currentPasswordPolicy().validate(username, password); err != nil {
+		return nil, err
+	}
+
 	if err := hashPassword(data); err != nil {
 		return nil, err
 	}
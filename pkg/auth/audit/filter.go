@@ -13,6 +13,7 @@
 
 	"github.com/rancher/rancher/pkg/auth/util"
 	"github.com/rancher/rancher/pkg/data/management"
+	"github.com/rancher/rancher/pkg/settings"
 	"github.com/sirupsen/logrus"
 )
 
@@ -23,6 +24,7 @@ func NewAuditLogMiddleware(auditWriter *LogWriter) (func(http.Handler) http.Hand
 			next:            next,
 			auditWriter:     auditWriter,
 			sanitizingRegex: sensitiveRegex,
+			policies:        &policyCache{},
 		}
 	}, err
 }
@@ -50,6 +52,7 @@ type auditHandler struct {
 	next            http.Handler
 	auditWriter     *LogWriter
 	sanitizingRegex *regexp.Regexp
+	policies        *policyCache
 }
 
 func (h auditHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
@@ -63,7 +66,18 @@ func (h auditHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	context := context.WithValue(req.Context(), userKey, user)
 	req = req.WithContext(context)
 
-	auditLog, err := newAuditLog(h.auditWriter, req, h.sanitizingRegex)
+	level := h.auditWriter.Level
+	if policy := h.policies.get(settings.AuditPolicy.Get()); policy != nil {
+		if matched, ok := policyLevel(policy, newRequestAttributes(req, user)); ok {
+			level = matched
+		}
+	}
+	if level == levelNull {
+		h.next.ServeHTTP(rw, req)
+		return
+	}
+
+	auditLog, err := newAuditLog(h.auditWriter, level, req, h.sanitizingRegex)
 	if err != nil {
 		util.ReturnHTTPError(rw, req, 500, err.Error())
 		return
@@ -2,13 +2,20 @@
 
 import (
 	"context"
+	"os"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/pkg/errors"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/sirupsen/logrus"
 	lumberjack "gopkg.in/natefinch/lumberjack.v2"
 )
 
 type LogWriter struct {
 	Level  int
-	Output *lumberjack.Logger
+	Output Sink
 }
 
 func (l *LogWriter) Start(ctx context.Context) {
@@ -17,22 +24,148 @@ func (l *LogWriter) Start(ctx context.Context) {
 	}
 	go func() {
 		<-ctx.Done()
-		l.Output.Close()
+		if err := l.Output.Close(); err != nil {
+			logrus.Errorf("failed to close audit log sinks: %v", err)
+		}
 	}()
 }
 
+// NewLogWriter builds the writer at level 0 as well, nothing is audited then unless the audit-policy setting
+// raises the level of a request. The path and rotation arguments configure the file sink, which sinks are
+// enabled and where the others deliver entries follows the audit-log-* settings, which can change at runtime.
 func NewLogWriter(path string, level, maxAge, maxBackup, maxSize int) *LogWriter {
-	if path == "" || level == levelNull {
-		return nil
-	}
-
 	return &LogWriter{
 		Level: level,
-		Output: &lumberjack.Logger{
-			Filename:   path,
-			MaxAge:     maxAge,
-			MaxBackups: maxBackup,
-			MaxSize:    maxSize,
+		Output: &settingsSink{
+			file: &lumberjack.Logger{
+				Filename:   path,
+				MaxAge:     maxAge,
+				MaxBackups: maxBackup,
+				MaxSize:    maxSize,
+			},
 		},
 	}
 }
+
+// sinkConfig is the state of the audit-log-* settings the sinks were built from.
+type sinkConfig struct {
+	sinks                string
+	webhookURL           string
+	webhookBatchSize     int
+	webhookFlushInterval time.Duration
+	webhookMaxRetries    int
+	syslogAddress        string
+	syslogTLS            bool
+	syslogCACerts        string
+}
+
+func currentSinkConfig() sinkConfig {
+	return sinkConfig{
+		sinks:                settings.AuditLogSinks.Get(),
+		webhookURL:           settings.AuditLogWebhookURL.Get(),
+		webhookBatchSize:     settings.AuditLogWebhookBatchSize.GetInt(),
+		webhookFlushInterval: time.Duration(settings.AuditLogWebhookFlushSeconds.GetInt()) * time.Second,
+		webhookMaxRetries:    settings.AuditLogWebhookMaxRetries.GetInt(),
+		syslogAddress:        settings.AuditLogSyslogAddress.Get(),
+		syslogTLS:            settings.AuditLogSyslogTLS.Get() == "true",
+		syslogCACerts:        settings.AuditLogSyslogCACerts.Get(),
+	}
+}
+
+// settingsSink writes entries to the sinks enabled by the audit-log-sinks setting. The sinks are built again
+// once any of the audit-log-* settings changes, the replaced ones are closed in the background so that they
+// can still deliver the entries they have queued.
+type settingsSink struct {
+	sync.RWMutex
+	file   *lumberjack.Logger
+	config *sinkConfig
+	sinks  multiSink
+	closed bool
+}
+
+func (s *settingsSink) Write(p []byte) (int, error) {
+	config := currentSinkConfig()
+
+	s.RLock()
+	if s.config == nil || *s.config != config {
+		s.RUnlock()
+		s.reload(config)
+		s.RLock()
+	}
+	defer s.RUnlock()
+
+	if s.closed {
+		return 0, errors.New("audit log sinks are closed")
+	}
+	return s.sinks.Write(p)
+}
+
+func (s *settingsSink) Close() error {
+	s.Lock()
+	sinks := s.sinks
+	s.sinks = nil
+	s.closed = true
+	s.Unlock()
+	return sinks.Close()
+}
+
+func (s *settingsSink) reload(config sinkConfig) {
+	s.Lock()
+	if s.closed || (s.config != nil && *s.config == config) {
+		s.Unlock()
+		return
+	}
+	old := s.sinks
+	s.sinks = newSinks(config, s.file)
+	s.config = &config
+	s.Unlock()
+
+	if len(old) == 0 {
+		return
+	}
+	go func() {
+		if err := old.Close(); err != nil {
+			logrus.Errorf("failed to close replaced audit log sinks: %v", err)
+		}
+	}()
+}
+
+// newSinks builds the enabled sinks. A sink that can not be built is logged and left out, so that a bad
+// setting of one sink does not stop the others.
+func newSinks(config sinkConfig, file *lumberjack.Logger) multiSink {
+	var sinks multiSink
+	for _, sinkType := range strings.Split(config.sinks, ",") {
+		switch sinkType = strings.TrimSpace(sinkType); sinkType {
+		case SinkFile:
+			if file.Filename == "" {
+				continue
+			}
+			sinks = append(sinks, &lumberjack.Logger{
+				Filename:   file.Filename,
+				MaxAge:     file.MaxAge,
+				MaxBackups: file.MaxBackups,
+				MaxSize:    file.MaxSize,
+			})
+		case SinkStdout:
+			sinks = append(sinks, newStreamSink(os.Stdout))
+		case SinkWebhook:
+			sink, err := newWebhookSink(config.webhookURL, config.webhookBatchSize, 0, config.webhookMaxRetries, config.webhookFlushInterval)
+			if err != nil {
+				logrus.Errorf("audit log webhook sink disabled: %v", err)
+				continue
+			}
+			sinks = append(sinks, sink)
+		case SinkSyslog:
+			sink, err := newSyslogSink(config.syslogAddress, config.syslogTLS, config.syslogCACerts)
+			if err != nil {
+				logrus.Errorf("audit log syslog sink disabled: %v", err)
+				continue
+			}
+			sinks = append(sinks, sink)
+		case "":
+		default:
+			logrus.Errorf("ignoring unknown audit log sink type %q", sinkType)
+		}
+	}
+	return sinks
+}
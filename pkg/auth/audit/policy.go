@@ -0,0 +1,139 @@
+package audit
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+
+	"github.com/rancher/rancher/pkg/auth/audit/policy"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+var (
+	policyLevels = map[string]int{
+		policy.LevelNone:            levelNull,
+		policy.LevelMetadata:        levelMetadata,
+		policy.LevelRequest:         levelRequest,
+		policy.LevelRequestResponse: levelRequestResponse,
+	}
+
+	k8sRequestInfoFactory = &request.RequestInfoFactory{
+		APIPrefixes:          sets.NewString("api", "apis"),
+		GrouplessAPIPrefixes: sets.NewString("api"),
+	}
+)
+
+// newRequestAttributes works out the verb, API group and resource type of requests to
+// the norman (/v3), steve (/v1) and proxied Kubernetes (/k8s/clusters/<id>, /api, /apis) APIs.
+func newRequestAttributes(req *http.Request, user *User) *policy.Attributes {
+	attrs := &policy.Attributes{
+		Path: req.URL.Path,
+	}
+	if user != nil {
+		attrs.User = user.Name
+		attrs.Groups = user.Group
+	}
+
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	switch {
+	case len(parts) > 3 && parts[0] == "k8s" && parts[1] == "clusters":
+		k8sReq := req.Clone(req.Context())
+		k8sReq.URL = &url.URL{Path: "/" + strings.Join(parts[3:], "/"), RawQuery: req.URL.RawQuery}
+		fromK8sRequest(attrs, k8sReq)
+		return attrs
+	case parts[0] == "api" || parts[0] == "apis":
+		fromK8sRequest(attrs, req)
+		return attrs
+	case parts[0] == "v3" && len(parts) > 1:
+		attrs.APIGroup = "management.cattle.io"
+		if (parts[1] == "cluster" || parts[1] == "project") && len(parts) > 3 {
+			attrs.APIGroup = parts[1] + ".cattle.io"
+			parts = parts[2:]
+		}
+		attrs.Resource = strings.ToLower(parts[1])
+	case parts[0] == "v1" && len(parts) > 1:
+		if i := strings.LastIndex(parts[1], "."); i >= 0 {
+			attrs.APIGroup = parts[1][:i]
+			attrs.Resource = parts[1][i+1:]
+		} else {
+			attrs.Resource = parts[1]
+		}
+	}
+
+	attrs.Verb = verbForMethod(req, len(parts) > 2)
+	return attrs
+}
+
+func fromK8sRequest(attrs *policy.Attributes, req *http.Request) {
+	info, err := k8sRequestInfoFactory.NewRequestInfo(req)
+	if err != nil || !info.IsResourceRequest {
+		attrs.Verb = verbForMethod(req, true)
+		return
+	}
+	attrs.Verb = info.Verb
+	attrs.APIGroup = info.APIGroup
+	attrs.Resource = info.Resource
+}
+
+func verbForMethod(req *http.Request, hasName bool) string {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		if req.URL.Query().Get("watch") == "true" || strings.EqualFold(req.Header.Get("Upgrade"), "websocket") {
+			return "watch"
+		}
+		if hasName {
+			return "get"
+		}
+		return "list"
+	case http.MethodPost:
+		return "create"
+	case http.MethodPut:
+		return "update"
+	case http.MethodPatch:
+		return "patch"
+	case http.MethodDelete:
+		return "delete"
+	}
+	return strings.ToLower(req.Method)
+}
+
+// policyCache keeps the parsed form of the most recently seen policy so that it is only
+// parsed again when the setting changes. It is read on every audited request without locking.
+type policyCache struct {
+	value atomic.Value
+}
+
+type cachedPolicy struct {
+	raw    string
+	policy *policy.Policy
+}
+
+func (c *policyCache) get(raw string) *policy.Policy {
+	if cached, ok := c.value.Load().(cachedPolicy); ok && cached.raw == raw {
+		return cached.policy
+	}
+
+	cached := cachedPolicy{raw: raw}
+	if strings.TrimSpace(raw) != "" {
+		p, err := policy.Parse(raw)
+		if err != nil {
+			logrus.Errorf("ignoring invalid audit policy, the global audit level is used instead: %v", err)
+		} else {
+			cached.policy = p
+		}
+	}
+	c.value.Store(cached)
+	return cached.policy
+}
+
+// policyLevel returns the audit level of the first rule of the policy matching the request, or false if no rule matches.
+func policyLevel(p *policy.Policy, attrs *policy.Attributes) (int, bool) {
+	level, ok := p.Level(attrs)
+	if !ok {
+		return 0, false
+	}
+	return policyLevels[level], true
+}
@@ -0,0 +1,153 @@
+// Package policy holds the audit policy of the audit-policy setting. It imports nothing from rancher so that both
+// the audit log writer and the validation of the setting can use it.
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	LevelNone            = "None"
+	LevelMetadata        = "Metadata"
+	LevelRequest         = "Request"
+	LevelRequestResponse = "RequestResponse"
+)
+
+var levels = map[string]bool{
+	LevelNone:            true,
+	LevelMetadata:        true,
+	LevelRequest:         true,
+	LevelRequestResponse: true,
+}
+
+// Policy is an ordered list of rules that decide the level a request is audited at.
+// The first rule matching a request wins; requests that match no rule are audited at
+// the global audit level.
+type Policy struct {
+	Rules []Rule `json:"rules,omitempty"`
+}
+
+// Rule matches requests on every non-empty field. A field matches when any of
+// its values matches the request.
+type Rule struct {
+	// Level is one of None, Metadata, Request or RequestResponse. None omits matching
+	// requests from the audit log.
+	Level string `json:"level"`
+	// Users are user names such as "user-xxxxx" or "system:admin".
+	Users []string `json:"users,omitempty"`
+	// UserGroups are group principals such as "system:authenticated".
+	UserGroups []string `json:"userGroups,omitempty"`
+	// Verbs are Kubernetes style verbs: get, list, watch, create, update, patch and delete.
+	Verbs []string `json:"verbs,omitempty"`
+	// Resources match the API group and type of the request.
+	Resources []GroupResources `json:"resources,omitempty"`
+	// URLPrefixes match the path of the request, a trailing "*" is ignored.
+	URLPrefixes []string `json:"urlPrefixes,omitempty"`
+}
+
+// GroupResources matches resource types in an API group. The core group is "", a
+// group of "*" matches any group and empty Resources match every type in the group.
+type GroupResources struct {
+	Group     string   `json:"group"`
+	Resources []string `json:"resources,omitempty"`
+}
+
+// Attributes is what a Rule is matched against.
+type Attributes struct {
+	User     string
+	Groups   []string
+	Verb     string
+	APIGroup string
+	Resource string
+	Path     string
+}
+
+// Parse parses and validates a JSON encoded Policy.
+func Parse(data string) (*Policy, error) {
+	policy := &Policy{}
+	if err := json.Unmarshal([]byte(data), policy); err != nil {
+		return nil, fmt.Errorf("failed to parse audit policy: %w", err)
+	}
+	for i, rule := range policy.Rules {
+		if !levels[rule.Level] {
+			return nil, fmt.Errorf("audit policy rule %d has invalid level %q", i, rule.Level)
+		}
+	}
+	return policy, nil
+}
+
+// Level returns the level of the first rule matching attrs, or false if no rule matches.
+func (p *Policy) Level(attrs *Attributes) (string, bool) {
+	if p == nil {
+		return "", false
+	}
+	for _, rule := range p.Rules {
+		if rule.matches(attrs) {
+			return rule.Level, true
+		}
+	}
+	return "", false
+}
+
+func (r *Rule) matches(attrs *Attributes) bool {
+	if len(r.Users) > 0 && (attrs.User == "" || !contains(r.Users, attrs.User)) {
+		return false
+	}
+	if len(r.UserGroups) > 0 && !r.matchesGroups(attrs.Groups) {
+		return false
+	}
+	if len(r.Verbs) > 0 && !contains(r.Verbs, attrs.Verb) {
+		return false
+	}
+	if len(r.Resources) > 0 && !r.matchesResources(attrs) {
+		return false
+	}
+	if len(r.URLPrefixes) > 0 && !r.matchesURL(attrs.Path) {
+		return false
+	}
+	return true
+}
+
+func (r *Rule) matchesGroups(groups []string) bool {
+	for _, group := range groups {
+		if contains(r.UserGroups, group) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesResources(attrs *Attributes) bool {
+	if attrs.Resource == "" {
+		return false
+	}
+	for _, gr := range r.Resources {
+		if gr.Group != "*" && gr.Group != attrs.APIGroup {
+			continue
+		}
+		if len(gr.Resources) == 0 || contains(gr.Resources, "*") || contains(gr.Resources, attrs.Resource) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesURL(path string) bool {
+	for _, prefix := range r.URLPrefixes {
+		if strings.HasPrefix(path, strings.TrimSuffix(prefix, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package policy
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	if _, err := Parse(`{"rules": [{"level": "None", "verbs": ["watch"]}, {"level": "RequestResponse", "urlPrefixes": ["/v3/*"]}]}`); err != nil {
+		t.Fatalf("unexpected error parsing policy: %v", err)
+	}
+	if _, err := Parse(`{"rules": [{"level": "Everything"}]}`); err == nil {
+		t.Error("expected error for invalid level")
+	}
+	if _, err := Parse(`{"rules": `); err == nil {
+		t.Error("expected error for invalid json")
+	}
+}
+
+func TestLevel(t *testing.T) {
+	p := &Policy{Rules: []Rule{
+		{Level: LevelNone, Users: []string{"system:serviceaccount:cattle-system:rancher"}},
+		{Level: LevelRequest, UserGroups: []string{"github_org://1234"}, Verbs: []string{"create"}},
+		{Level: LevelRequestResponse, Resources: []GroupResources{{Group: "*", Resources: []string{"secrets"}}}},
+	}}
+
+	tests := []struct {
+		name      string
+		attrs     Attributes
+		wantLevel string
+		wantMatch bool
+	}{
+		{
+			name:      "user",
+			attrs:     Attributes{User: "system:serviceaccount:cattle-system:rancher", Verb: "get"},
+			wantLevel: LevelNone,
+			wantMatch: true,
+		},
+		{
+			name:      "group and verb",
+			attrs:     Attributes{User: "user-abcde", Groups: []string{"github_org://1234"}, Verb: "create"},
+			wantLevel: LevelRequest,
+			wantMatch: true,
+		},
+		{
+			name:      "group without verb",
+			attrs:     Attributes{User: "user-abcde", Groups: []string{"github_org://1234"}, Verb: "delete"},
+			wantMatch: false,
+		},
+		{
+			name:      "any group",
+			attrs:     Attributes{Verb: "get", APIGroup: "", Resource: "secrets"},
+			wantLevel: LevelRequestResponse,
+			wantMatch: true,
+		},
+		{
+			name:      "anonymous user does not match users",
+			attrs:     Attributes{Verb: "get"},
+			wantMatch: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, ok := p.Level(&tt.attrs)
+			if ok != tt.wantMatch {
+				t.Fatalf("Level() matched = %v, want %v", ok, tt.wantMatch)
+			}
+			if ok && level != tt.wantLevel {
+				t.Errorf("Level() = %s, want %s", level, tt.wantLevel)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package audit
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rancher/rancher/pkg/auth/audit/policy"
+)
+
+const testPolicy = `{"rules": [
+	{"level": "None", "verbs": ["watch"]},
+	{"level": "RequestResponse", "resources": [
+		{"group": "management.cattle.io", "resources": ["globalrolebindings", "authconfigs"]},
+		{"group": "rbac.authorization.k8s.io"}
+	]},
+	{"level": "Request", "userGroups": ["github_org://1234"]},
+	{"level": "Metadata", "verbs": ["get", "list"]},
+	{"level": "Request", "urlPrefixes": ["/v3-public/*"]}
+]}`
+
+func TestPolicyLevel(t *testing.T) {
+	p, err := policy.Parse(testPolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		method    string
+		url       string
+		groups    []string
+		wantLevel int
+		wantMatch bool
+	}{
+		{
+			name:      "steve watch is omitted",
+			method:    "GET",
+			url:       "/v1/management.cattle.io.clusters?watch=true",
+			wantLevel: levelNull,
+			wantMatch: true,
+		},
+		{
+			name:      "norman global role binding create",
+			method:    "POST",
+			url:       "/v3/globalRoleBindings",
+			wantLevel: levelRequestResponse,
+			wantMatch: true,
+		},
+		{
+			name:      "steve auth config update",
+			method:    "PUT",
+			url:       "/v1/management.cattle.io.authconfigs/github",
+			wantLevel: levelRequestResponse,
+			wantMatch: true,
+		},
+		{
+			name:      "downstream cluster rbac change",
+			method:    "DELETE",
+			url:       "/k8s/clusters/c-abcde/apis/rbac.authorization.k8s.io/v1/clusterrolebindings/foo",
+			wantLevel: levelRequestResponse,
+			wantMatch: true,
+		},
+		{
+			name:      "downstream cluster watch",
+			method:    "GET",
+			url:       "/k8s/clusters/c-abcde/api/v1/namespaces/default/pods?watch=true",
+			wantLevel: levelNull,
+			wantMatch: true,
+		},
+		{
+			name:      "group member",
+			method:    "POST",
+			url:       "/v3/clusters",
+			groups:    []string{"system:authenticated", "github_org://1234"},
+			wantLevel: levelRequest,
+			wantMatch: true,
+		},
+		{
+			name:      "list is metadata only",
+			method:    "GET",
+			url:       "/v3/projects",
+			wantLevel: levelMetadata,
+			wantMatch: true,
+		},
+		{
+			name:      "url prefix",
+			method:    "POST",
+			url:       "/v3-public/localProviders/local?action=login",
+			wantLevel: levelRequest,
+			wantMatch: true,
+		},
+		{
+			name:      "no rule matches",
+			method:    "POST",
+			url:       "/v3/clusters",
+			wantMatch: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			user := &User{Name: "user-abcde", Group: tt.groups}
+			level, ok := policyLevel(p, newRequestAttributes(req, user))
+			if ok != tt.wantMatch {
+				t.Fatalf("Level() matched = %v, want %v", ok, tt.wantMatch)
+			}
+			if ok && level != tt.wantLevel {
+				t.Errorf("Level() = %d, want %d", level, tt.wantLevel)
+			}
+		})
+	}
+}
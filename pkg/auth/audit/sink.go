@@ -0,0 +1,67 @@
+package audit
+
+import (
+	"io"
+	"sync"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+const (
+	SinkFile    = "file"
+	SinkWebhook = "webhook"
+	SinkSyslog  = "syslog"
+	SinkStdout  = "stdout"
+)
+
+// Sink is a destination for audit log entries. Every call to Write receives a single
+// compacted JSON document terminated by a newline. Implementations that keep the entry
+// after Write returns must copy it.
+type Sink interface {
+	io.Writer
+	io.Closer
+}
+
+// multiSink fans every entry out to all of its sinks. A failing sink does not prevent
+// the entry from reaching the others.
+type multiSink []Sink
+
+func (m multiSink) Write(p []byte) (int, error) {
+	var errs []error
+	for _, sink := range m {
+		if _, err := sink.Write(p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return len(p), utilerrors.NewAggregate(errs)
+}
+
+func (m multiSink) Close() error {
+	var errs []error
+	for _, sink := range m {
+		if err := sink.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// streamSink writes entries to a stream such as stdout, which it does not own.
+type streamSink struct {
+	sync.Mutex
+	out io.Writer
+}
+
+func newStreamSink(out io.Writer) *streamSink {
+	return &streamSink{out: out}
+}
+
+func (s *streamSink) Write(p []byte) (int, error) {
+	s.Lock()
+	defer s.Unlock()
+	return s.out.Write(p)
+}
+
+func (s *streamSink) Close() error {
+	return nil
+}
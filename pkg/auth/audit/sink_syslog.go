@@ -0,0 +1,186 @@
+package audit
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// syslogPriority is facility 13 (log audit) with severity 6 (informational).
+	syslogPriority     = 13*8 + 6
+	syslogAppName      = "rancher"
+	syslogMsgID        = "audit"
+	syslogTimestamp    = "2006-01-02T15:04:05.000000Z07:00"
+	syslogDialTimeout  = 10 * time.Second
+	syslogWriteTimeout = 10 * time.Second
+	syslogQueueSize    = 10000
+)
+
+var errSyslogQueueFull = errors.New("audit syslog queue is full, dropping entry")
+
+// syslogSink sends entries as RFC5424 messages over TCP, optionally wrapped in TLS,
+// using the octet-counting framing from RFC6587. Like the webhook sink, entries are
+// buffered in a bounded queue and sent by a single goroutine, so that API requests are
+// not stalled by a slow or unreachable server. The connection is re-established on the
+// next entry after a failure; entries that can not be queued or sent are dropped and
+// counted.
+type syslogSink struct {
+	// dropped is the number of entries dropped since the last one was sent, it is
+	// first for the alignment of the atomic operations
+	dropped uint64
+
+	address   string
+	tlsConfig *tls.Config
+	hostname  string
+	pid       int
+	conn      net.Conn
+
+	queue     chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+func newSyslogSink(address string, useTLS bool, caCerts string) (*syslogSink, error) {
+	if address == "" {
+		return nil, errors.New("audit log syslog sink requires an address")
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid audit log syslog address")
+	}
+
+	s := &syslogSink{
+		address: address,
+		pid:     os.Getpid(),
+		queue:   make(chan []byte, syslogQueueSize),
+		done:    make(chan struct{}),
+	}
+	s.hostname, _ = os.Hostname()
+	if s.hostname == "" {
+		s.hostname = "-"
+	}
+
+	if useTLS {
+		s.tlsConfig = &tls.Config{
+			ServerName: host,
+			MinVersion: tls.VersionTLS12,
+		}
+		if caCerts != "" {
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM([]byte(caCerts)) {
+				return nil, errors.New("no certificates found in the audit log syslog CA bundle")
+			}
+			s.tlsConfig.RootCAs = pool
+		}
+	}
+
+	s.wg.Add(1)
+	go s.run()
+	return s, nil
+}
+
+func (s *syslogSink) Write(p []byte) (int, error) {
+	// the frame is rendered right away, so that it carries the time of the entry
+	frame := s.frame(time.Now(), bytes.TrimSuffix(p, []byte("\n")))
+
+	select {
+	case <-s.done:
+		return 0, errors.New("audit syslog sink is closed")
+	default:
+	}
+
+	select {
+	case s.queue <- frame:
+		return len(p), nil
+	default:
+		atomic.AddUint64(&s.dropped, 1)
+		return 0, errSyslogQueueFull
+	}
+}
+
+// Close stops accepting entries, sends whatever is still queued and closes the connection.
+func (s *syslogSink) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+	s.wg.Wait()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// Dropped returns the number of entries dropped since the last entry was sent.
+func (s *syslogSink) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+func (s *syslogSink) run() {
+	defer s.wg.Done()
+	for {
+		select {
+		case frame := <-s.queue:
+			s.send(frame)
+		case <-s.done:
+			for {
+				select {
+				case frame := <-s.queue:
+					s.send(frame)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// send writes the frame, reconnecting once if the connection failed. The drops since the
+// last sent entry are reported once the server is reachable again.
+func (s *syslogSink) send(frame []byte) {
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if s.conn == nil {
+			if s.conn, err = s.dial(); err != nil {
+				continue
+			}
+		}
+		s.conn.SetWriteDeadline(time.Now().Add(syslogWriteTimeout))
+		if _, err = s.conn.Write(frame); err == nil {
+			if dropped := atomic.SwapUint64(&s.dropped, 0); dropped > 0 {
+				logrus.Warnf("dropped %d audit log entries while syslog %s was unavailable", dropped, s.address)
+			}
+			return
+		}
+		s.conn.Close()
+		s.conn = nil
+	}
+	atomic.AddUint64(&s.dropped, 1)
+	logrus.Debugf("failed to write audit log to syslog %s, dropping entry: %v", s.address, err)
+}
+
+func (s *syslogSink) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: syslogDialTimeout}
+	if s.tlsConfig != nil {
+		return tls.DialWithDialer(dialer, "tcp", s.address, s.tlsConfig)
+	}
+	return dialer.Dial("tcp", s.address)
+}
+
+// frame renders msg as an RFC5424 message prefixed with its length.
+func (s *syslogSink) frame(ts time.Time, msg []byte) []byte {
+	header := fmt.Sprintf("<%d>1 %s %s %s %d %s - ", syslogPriority, ts.UTC().Format(syslogTimestamp), s.hostname, syslogAppName, s.pid, syslogMsgID)
+	return []byte(fmt.Sprintf("%d %s%s", len(header)+len(msg), header, msg))
+}
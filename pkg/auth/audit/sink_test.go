@@ -0,0 +1,296 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rancher/rancher/pkg/settings"
+)
+
+func TestWebhookSinkBatches(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		batches [][]map[string]interface{}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		var batch []map[string]interface{}
+		if err := json.NewDecoder(req.Body).Decode(&batch); err != nil {
+			t.Errorf("failed to decode webhook body: %v", err)
+		}
+		mu.Lock()
+		batches = append(batches, batch)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	sink, err := newWebhookSink(server.URL, 2, 10, 0, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := sink.Write([]byte(`{"auditID":"` + strconv.Itoa(i) + `"}` + "\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(batches) != 2 {
+		t.Fatalf("expected 2 batches, got %d", len(batches))
+	}
+	if len(batches[0]) != 2 || len(batches[1]) != 1 {
+		t.Fatalf("expected batches of 2 and 1 entries, got %d and %d", len(batches[0]), len(batches[1]))
+	}
+	if batches[1][0]["auditID"] != "2" {
+		t.Errorf("expected last entry to have auditID 2, got %v", batches[1][0]["auditID"])
+	}
+}
+
+func TestWebhookSinkRetriesServerErrors(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		attempts int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if attempts == 1 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	sink, err := newWebhookSink(server.URL, 1, 10, 3, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.send([][]byte{[]byte(`{}`)}); err != nil {
+		t.Fatalf("expected delivery to succeed after retry: %v", err)
+	}
+	sink.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if attempts != 2 {
+		t.Errorf("expected 2 delivery attempts, got %d", attempts)
+	}
+}
+
+func TestSyslogSinkFraming(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		length, err := r.ReadString(' ')
+		if err != nil {
+			return
+		}
+		n, _ := strconv.Atoi(strings.TrimSpace(length))
+		msg := make([]byte, n)
+		if _, err := io.ReadFull(r, msg); err != nil {
+			return
+		}
+		received <- string(msg)
+	}()
+
+	sink, err := newSyslogSink(listener.Addr().String(), false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	entry := `{"auditID":"1","method":"GET"}`
+	if _, err := sink.Write([]byte(entry + "\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case msg := <-received:
+		if !strings.HasPrefix(msg, "<110>1 ") {
+			t.Errorf("expected RFC5424 header with priority 110, got %q", msg)
+		}
+		if !strings.HasSuffix(msg, " rancher "+strconv.Itoa(sink.pid)+" audit - "+entry) {
+			t.Errorf("unexpected syslog message %q", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for syslog message")
+	}
+}
+
+func TestSyslogSinkDropsWhileUnavailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	sink, err := newSyslogSink(address, false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	start := time.Now()
+	if _, err := sink.Write([]byte(`{"auditID":"1"}` + "\n")); err != nil {
+		t.Fatalf("expected the entry to be queued, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected Write not to wait for the unreachable server, took %v", elapsed)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for sink.Dropped() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 1 dropped entry, got %d", sink.Dropped())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	listener, err = net.Listen("tcp", address)
+	if err != nil {
+		t.Skipf("could not listen on %s again: %v", address, err)
+	}
+	defer listener.Close()
+	received := make(chan struct{})
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if _, err := bufio.NewReader(conn).ReadString(' '); err == nil {
+			close(received)
+		}
+	}()
+
+	if _, err := sink.Write([]byte(`{"auditID":"2"}` + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for syslog message")
+	}
+	deadline = time.Now().Add(5 * time.Second)
+	for sink.Dropped() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the dropped entries to be reset once sent, got %d", sink.Dropped())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWebhookSinkCountsDrops(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		available bool
+	)
+	delivered := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !available {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		delivered <- struct{}{}
+	}))
+	defer server.Close()
+
+	sink, err := newWebhookSink(server.URL, 1, 10, 0, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	if _, err := sink.Write([]byte(`{"auditID":"1"}` + "\n")); err != nil {
+		t.Fatalf("expected the entry to be queued, got %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for sink.Dropped() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 1 dropped entry, got %d", sink.Dropped())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	mu.Lock()
+	available = true
+	mu.Unlock()
+	if _, err := sink.Write([]byte(`{"auditID":"2"}` + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-delivered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for webhook delivery")
+	}
+	deadline = time.Now().Add(5 * time.Second)
+	for sink.Dropped() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the dropped entries to be reset once delivered, got %d", sink.Dropped())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestLogWriterFollowsSinkSettings(t *testing.T) {
+	received := make(chan string, 2)
+	newServer := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			received <- name
+		}))
+	}
+	first, second := newServer("first"), newServer("second")
+	defer first.Close()
+	defer second.Close()
+
+	defer settings.AuditLogSinks.Set(settings.AuditLogSinks.Get())
+	defer settings.AuditLogWebhookURL.Set(settings.AuditLogWebhookURL.Get())
+	defer settings.AuditLogWebhookBatchSize.Set(settings.AuditLogWebhookBatchSize.Get())
+	settings.AuditLogSinks.Set(SinkWebhook)
+	settings.AuditLogWebhookBatchSize.Set("1")
+
+	// the audit-policy setting can raise the level of requests, so the writer is needed at level 0 too
+	writer := NewLogWriter("", levelNull, 0, 0, 0)
+	defer writer.Output.Close()
+
+	for name, server := range map[string]*httptest.Server{"first": first, "second": second} {
+		settings.AuditLogWebhookURL.Set(server.URL)
+		if _, err := writer.Output.Write([]byte(`{"auditID":"1"}` + "\n")); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case got := <-received:
+			if got != name {
+				t.Errorf("expected the entry to be sent to the %s webhook, got %s", name, got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for the %s webhook", name)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package audit
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultWebhookBatchSize     = 100
+	defaultWebhookQueueSize     = 10000
+	defaultWebhookMaxRetries    = 5
+	defaultWebhookFlushInterval = 5 * time.Second
+	webhookInitialBackoff       = time.Second
+	webhookMaxBackoff           = 30 * time.Second
+)
+
+var errWebhookQueueFull = errors.New("audit webhook queue is full, dropping entry")
+
+// webhookSink batches entries and POSTs them as a JSON array to an HTTP endpoint.
+// Entries are buffered in a bounded queue and sent by a single goroutine; when the
+// endpoint falls behind and the queue is full, Write drops the entry right away so that
+// API requests are never stalled by a slow receiver. Entries that can not be queued or
+// delivered are counted.
+type webhookSink struct {
+	// dropped is the number of entries dropped since the last batch was delivered, it is
+	// first for the alignment of the atomic operations
+	dropped uint64
+
+	url           string
+	client        *http.Client
+	batchSize     int
+	maxRetries    int
+	flushInterval time.Duration
+
+	queue     chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+func newWebhookSink(endpoint string, batchSize, queueSize, maxRetries int, flushInterval time.Duration) (*webhookSink, error) {
+	if endpoint == "" {
+		return nil, errors.New("audit log webhook sink requires a URL")
+	}
+	if _, err := url.ParseRequestURI(endpoint); err != nil {
+		return nil, errors.Wrap(err, "invalid audit log webhook URL")
+	}
+	if batchSize <= 0 {
+		batchSize = defaultWebhookBatchSize
+	}
+	if queueSize <= 0 {
+		queueSize = defaultWebhookQueueSize
+	}
+	if maxRetries < 0 {
+		maxRetries = defaultWebhookMaxRetries
+	}
+	if flushInterval <= 0 {
+		flushInterval = defaultWebhookFlushInterval
+	}
+
+	w := &webhookSink{
+		url:           endpoint,
+		client:        &http.Client{Timeout: 30 * time.Second},
+		batchSize:     batchSize,
+		maxRetries:    maxRetries,
+		flushInterval: flushInterval,
+		queue:         make(chan []byte, queueSize),
+		done:          make(chan struct{}),
+	}
+	w.wg.Add(1)
+	go w.run()
+	return w, nil
+}
+
+func (w *webhookSink) Write(p []byte) (int, error) {
+	entry := make([]byte, len(p))
+	copy(entry, p)
+
+	select {
+	case <-w.done:
+		return 0, errors.New("audit webhook sink is closed")
+	default:
+	}
+
+	select {
+	case w.queue <- entry:
+		return len(p), nil
+	default:
+		atomic.AddUint64(&w.dropped, 1)
+		return 0, errWebhookQueueFull
+	}
+}
+
+// Close stops accepting entries and flushes whatever is still queued.
+func (w *webhookSink) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.done)
+	})
+	w.wg.Wait()
+	return nil
+}
+
+// Dropped returns the number of entries dropped since the last batch was delivered.
+func (w *webhookSink) Dropped() uint64 {
+	return atomic.LoadUint64(&w.dropped)
+}
+
+func (w *webhookSink) run() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([][]byte, 0, w.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := w.send(batch); err != nil {
+			atomic.AddUint64(&w.dropped, uint64(len(batch)))
+			logrus.Errorf("failed to send %d audit log entries to webhook, dropping them: %v", len(batch), err)
+		} else if dropped := atomic.SwapUint64(&w.dropped, 0); dropped > 0 {
+			logrus.Warnf("dropped %d audit log entries while webhook %s was unavailable", dropped, w.url)
+		}
+		batch = make([][]byte, 0, w.batchSize)
+	}
+
+	for {
+		select {
+		case entry := <-w.queue:
+			batch = append(batch, entry)
+			if len(batch) >= w.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-w.done:
+			for {
+				select {
+				case entry := <-w.queue:
+					batch = append(batch, entry)
+					if len(batch) >= w.batchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// send POSTs a batch, retrying with exponential backoff on connection errors, 429 and
+// 5xx responses. Once the sink is closed no further backoff is attempted.
+func (w *webhookSink) send(batch [][]byte) error {
+	var body bytes.Buffer
+	body.WriteByte('[')
+	for i, entry := range batch {
+		if i > 0 {
+			body.WriteByte(',')
+		}
+		body.Write(bytes.TrimSuffix(entry, []byte("\n")))
+	}
+	body.WriteByte(']')
+
+	backoff := webhookInitialBackoff
+	for attempt := 0; ; attempt++ {
+		retry, err := w.post(body.Bytes())
+		if err == nil || !retry || attempt >= w.maxRetries {
+			return err
+		}
+
+		logrus.Debugf("audit webhook delivery attempt %d failed, retrying in %v: %v", attempt+1, backoff, err)
+		select {
+		case <-w.done:
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > webhookMaxBackoff {
+			backoff = webhookMaxBackoff
+		}
+	}
+}
+
+func (w *webhookSink) post(body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", contentTypeJSON)
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("audit webhook returned %s", resp.Status)
+	default:
+		return false, fmt.Errorf("audit webhook returned %s", resp.Status)
+	}
+}
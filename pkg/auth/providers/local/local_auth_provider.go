@@ -4,6 +4,7 @@
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 
@@ -31,12 +32,14 @@
 )
 
 type Provider struct {
+	userClient   v3.UserInterface
 	userLister   v3.UserLister
 	groupLister  v3.GroupLister
 	userIndexer  cache.Indexer
 	gmIndexer    cache.Indexer
 	groupIndexer cache.Indexer
 	tokenMGR     *tokens.Manager
+	mfa          *MFAManager
 	invalidHash  []byte
 }
 
@@ -56,12 +59,14 @@ func Configure(ctx context.Context, mgmtCtx *config.ScaledContext, tokenMGR *tok
 	invalidHash, _ := bcrypt.GenerateFromPassword([]byte("invalid"), bcrypt.DefaultCost)
 
 	l := &Provider{
+		userClient:   mgmtCtx.Management.Users(""),
 		userIndexer:  informer.GetIndexer(),
 		gmIndexer:    gmInformer.GetIndexer(),
 		groupLister:  mgmtCtx.Management.Groups("").Controller().Lister(),
 		groupIndexer: gInformer.GetIndexer(),
 		userLister:   mgmtCtx.Management.Users("").Controller().Lister(),
 		tokenMGR:     tokenMGR,
+		mfa:          NewMFAManager(mgmtCtx),
 		invalidHash:  invalidHash,
 	}
 	return l
@@ -121,19 +126,106 @@ func (l *Provider) AuthenticateUser(ctx context.Context, input interface{}) (v3.
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(pwd)); err != nil {
 		logrus.Debugf("Authentication failed for User [%s]: %v", username, err)
+		if err := recordFailedLogin(l.userClient, user); err != nil {
+			logrus.Errorf("Failed to record failed login for User [%s]: %v", username, err)
+		}
+		return v3.Principal{}, nil, "", authFailedError
+	}
+
+	if isLockedOut(user, time.Now()) {
+		logrus.Debugf("Authentication failed for User [%s]: account is locked until %s", username, user.Status.LockedUntil)
 		return v3.Principal{}, nil, "", authFailedError
 	}
 
+	// users with a second factor are only considered logged in once it has been verified
+	mfaRequired, _, err := l.mfa.Status(user)
+	if err != nil {
+		return v3.Principal{}, nil, "", errors.Wrapf(err, "failed to get multi-factor status for %v", user.Name)
+	}
+	if !mfaRequired {
+		if err := recordSuccessfulLogin(l.userClient, user); err != nil {
+			return v3.Principal{}, nil, "", errors.Wrapf(err, "failed to update login state for %v", user.Name)
+		}
+	}
+
+	userPrincipal, groupPrincipals, err := l.principalsForUser(user)
+	if err != nil {
+		return v3.Principal{}, nil, "", err
+	}
+	return userPrincipal, groupPrincipals, "", nil
+}
+
+// MFAChallenge returns a challenge if the user has to provide a second factor before a
+// login token is issued, or nil otherwise.
+func (l *Provider) MFAChallenge(user *v3.User) (*v32.MFAChallenge, error) {
+	required, enrolled, err := l.mfa.Status(user)
+	if err != nil || !required {
+		return nil, err
+	}
+
+	token, err := l.mfa.NewChallengeToken(user.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &v32.MFAChallenge{
+		ChallengeToken:     token,
+		EnrollmentRequired: !enrolled,
+	}, nil
+}
+
+// EnrollMFA starts the enrollment of a user that has to enroll before their login can complete.
+func (l *Provider) EnrollMFA(input *v32.MFAEnrollInput) (*v32.MFAEnrollOutput, error) {
+	user, err := l.mfa.UserFromChallengeToken(input.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+	return l.mfa.Enroll(user)
+}
+
+// AuthenticateMFA completes a login that was challenged for a second factor.
+func (l *Provider) AuthenticateMFA(input *v32.MFALoginInput) (*v3.User, v3.Principal, []v3.Principal, error) {
+	authFailedError := httperror.NewAPIError(httperror.Unauthorized, "authentication failed")
+
+	user, err := l.mfa.UserFromChallengeToken(input.ChallengeToken)
+	if err != nil {
+		return nil, v3.Principal{}, nil, err
+	}
+
+	ok, err := l.mfa.Verify(user, input.Code)
+	if err != nil {
+		return nil, v3.Principal{}, nil, err
+	}
+	if !ok {
+		logrus.Debugf("Multi-factor authentication failed for User [%s]", user.Username)
+		return nil, v3.Principal{}, nil, authFailedError
+	}
+
+	if err := l.mfa.EndChallenge(user.Name); err != nil {
+		return nil, v3.Principal{}, nil, errors.Wrapf(err, "failed to end multi-factor challenge for %v", user.Name)
+	}
+
+	if err := recordSuccessfulLogin(l.userClient, user); err != nil {
+		return nil, v3.Principal{}, nil, errors.Wrapf(err, "failed to update login state for %v", user.Name)
+	}
+
+	userPrincipal, groupPrincipals, err := l.principalsForUser(user)
+	if err != nil {
+		return nil, v3.Principal{}, nil, err
+	}
+	return user, userPrincipal, groupPrincipals, nil
+}
+
+func (l *Provider) principalsForUser(user *v3.User) (v3.Principal, []v3.Principal, error) {
 	principalID := getLocalPrincipalID(user)
 	userPrincipal := l.toPrincipal("user", user.DisplayName, user.Username, principalID, nil)
 	userPrincipal.Me = true
 
 	groupPrincipals, err := l.getGroupPrincipals(user)
 	if err != nil {
-		return v3.Principal{}, nil, "", errors.Wrapf(err, "failed to get groups for %v", user.Name)
+		return v3.Principal{}, nil, errors.Wrapf(err, "failed to get groups for %v", user.Name)
 	}
 
-	return userPrincipal, groupPrincipals, "", nil
+	return userPrincipal, groupPrincipals, nil
 }
 
 func getLocalPrincipalID(user *v3.User) string {
@@ -0,0 +1,509 @@
+package local
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rancher/norman/httperror"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/providers/common"
+	corev1 "github.com/rancher/rancher/pkg/generated/norman/core/v1"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	MFARequiredNone   = "none"
+	MFARequiredAdmins = "admins"
+	MFARequiredAll    = "all"
+
+	mfaIssuer            = "Rancher"
+	mfaChallengeKeyName  = "local-mfa-challenge-key"
+	mfaChallengeTTL      = 5 * time.Minute
+	mfaRecoveryCodeCount = 10
+	// mfaMaxFailedAttempts failed codes lock the second factor of a user for mfaLockoutDuration, regardless of the
+	// local-auth-lockout-threshold setting
+	mfaMaxFailedAttempts = 5
+	mfaLockoutDuration   = 15 * time.Minute
+
+	mfaSecretField         = "totpSecret"
+	mfaEnrolledField       = "enrolled"
+	mfaLastStepField       = "lastStep"
+	mfaRecoveryCodesField  = "recoveryCodes"
+	mfaFailedAttemptsField = "failedAttempts"
+	mfaLockedUntilField    = "lockedUntil"
+	mfaNonceField          = "nonce"
+)
+
+var (
+	adminGlobalRoles = map[string]bool{
+		"admin":            true,
+		"restricted-admin": true,
+	}
+	errMFAAuthFailed = httperror.NewAPIError(httperror.Unauthorized, "authentication failed")
+)
+
+// MFAManager stores TOTP secrets and recovery codes of local users in secrets in the
+// cattle-global-data namespace and issues the challenge tokens exchanged for a login
+// token once the second factor has been verified.
+type MFAManager struct {
+	secrets    corev1.SecretInterface
+	users      v3.UserInterface
+	userLister v3.UserLister
+	grbLister  v3.GlobalRoleBindingLister
+
+	challengeKeyLock sync.Mutex
+	challengeKey     []byte
+}
+
+func NewMFAManager(mgmtCtx *config.ScaledContext) *MFAManager {
+	return &MFAManager{
+		secrets:    mgmtCtx.Core.Secrets(""),
+		users:      mgmtCtx.Management.Users(""),
+		userLister: mgmtCtx.Management.Users("").Controller().Lister(),
+		grbLister:  mgmtCtx.Management.GlobalRoleBindings("").Controller().Lister(),
+	}
+}
+
+func mfaSecretName(userID string) string {
+	return fmt.Sprintf("%s-mfa-%s", Name, userID)
+}
+
+func mfaChallengeSecretName(userID string) string {
+	return fmt.Sprintf("%s-mfa-challenge-%s", Name, userID)
+}
+
+// Status returns whether the user has to pass a second factor to log in and whether
+// they have completed enrollment.
+func (m *MFAManager) Status(user *v3.User) (required bool, enrolled bool, err error) {
+	secret, err := m.getSecret(user.Name)
+	if err != nil {
+		return false, false, err
+	}
+	enrolled = secret != nil && string(secret.Data[mfaEnrolledField]) == "true"
+	if enrolled {
+		return true, true, nil
+	}
+
+	switch settings.LocalAuthMFARequired.Get() {
+	case MFARequiredAll:
+		return true, false, nil
+	case MFARequiredAdmins:
+		isAdmin, err := m.isAdmin(user.Name)
+		return isAdmin, false, err
+	}
+	return false, false, nil
+}
+
+func (m *MFAManager) isAdmin(userID string) (bool, error) {
+	grbs, err := m.grbLister.List("", labels.Everything())
+	if err != nil {
+		return false, err
+	}
+	for _, grb := range grbs {
+		if grb.UserName == userID && adminGlobalRoles[grb.GlobalRoleName] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Enroll generates a new TOTP secret and recovery codes for the user. The enrollment only
+// takes effect once a code generated from the secret has been verified.
+func (m *MFAManager) Enroll(user *v3.User) (*v32.MFAEnrollOutput, error) {
+	existing, err := m.getSecret(user.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && string(existing.Data[mfaEnrolledField]) == "true" {
+		return nil, httperror.NewAPIError(httperror.InvalidState, "multi-factor authentication is already enabled, it must be reset before enrolling again")
+	}
+
+	totpSecret, err := newTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	recoveryCodes, hashedCodes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mfaSecretName(user.Name),
+			Namespace: common.SecretsNamespace,
+		},
+		StringData: map[string]string{
+			mfaSecretField:        totpSecret,
+			mfaEnrolledField:      "false",
+			mfaLastStepField:      "0",
+			mfaRecoveryCodesField: strings.Join(hashedCodes, "\n"),
+		},
+		Type: v1.SecretTypeOpaque,
+	}
+	if existing == nil {
+		_, err = m.secrets.Create(secret)
+	} else {
+		secret.ResourceVersion = existing.ResourceVersion
+		_, err = m.secrets.Update(secret)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to store multi-factor secret for %v", user.Name)
+	}
+
+	return &v32.MFAEnrollOutput{
+		Secret:        totpSecret,
+		OTPAuthURL:    totpURL(mfaIssuer, user.Username, totpSecret),
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+// Verify checks a TOTP or recovery code for the user, completing a pending enrollment on
+// success. Failed attempts count towards the local account lockout, and mfaMaxFailedAttempts
+// of them lock the second factor of the user for mfaLockoutDuration.
+func (m *MFAManager) Verify(user *v3.User, code string) (bool, error) {
+	if isLockedOut(user, time.Now()) {
+		return false, nil
+	}
+
+	secret, err := m.getSecret(user.Name)
+	if err != nil || secret == nil {
+		return false, err
+	}
+	if mfaLockedOut(secret, time.Now()) {
+		logrus.Debugf("Multi-factor authentication of User [%s] is locked until %s", user.Username, secret.Data[mfaLockedUntilField])
+		return false, nil
+	}
+	secret = secret.DeepCopy()
+	enrolled := string(secret.Data[mfaEnrolledField]) == "true"
+	lastStep, _ := strconv.ParseUint(string(secret.Data[mfaLastStepField]), 10, 64)
+
+	valid := false
+	if step, ok := validateTOTP(string(secret.Data[mfaSecretField]), code, time.Now(), lastStep); ok {
+		valid = true
+		secret.Data[mfaLastStepField] = []byte(strconv.FormatUint(step, 10))
+		secret.Data[mfaEnrolledField] = []byte("true")
+	} else if enrolled {
+		if remaining, ok := useRecoveryCode(string(secret.Data[mfaRecoveryCodesField]), code); ok {
+			valid = true
+			secret.Data[mfaRecoveryCodesField] = []byte(remaining)
+			logrus.Infof("Recovery code used to log in User [%s]", user.Username)
+		}
+	}
+
+	if !valid {
+		if err := recordFailedLogin(m.users, user); err != nil {
+			logrus.Errorf("Failed to record failed login for User [%s]: %v", user.Username, err)
+		}
+		if err := m.recordFailedAttempt(user); err != nil {
+			// the attempt must not go uncounted
+			return false, errors.Wrapf(err, "failed to record failed multi-factor attempt for %v", user.Name)
+		}
+		return false, nil
+	}
+
+	delete(secret.Data, mfaFailedAttemptsField)
+	delete(secret.Data, mfaLockedUntilField)
+	if _, err := m.secrets.Update(secret); err != nil {
+		// the code must not be accepted if it cannot be marked as used
+		return false, errors.Wrapf(err, "failed to update multi-factor secret for %v", user.Name)
+	}
+	return true, nil
+}
+
+// recordFailedAttempt counts a failed code in the multi-factor secret of the user and locks
+// their second factor once mfaMaxFailedAttempts is reached.
+func (m *MFAManager) recordFailedAttempt(user *v3.User) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := m.getSecret(user.Name)
+		if err != nil || secret == nil {
+			return err
+		}
+		secret = secret.DeepCopy()
+		if failMFAAttempt(secret, time.Now()) {
+			logrus.Infof("Locking multi-factor authentication of User [%s] until %s after %d failed attempts", user.Username,
+				secret.Data[mfaLockedUntilField], mfaMaxFailedAttempts)
+		}
+		_, err = m.secrets.Update(secret)
+		return err
+	})
+}
+
+// failMFAAttempt counts a failed attempt in the secret, locking it once mfaMaxFailedAttempts
+// is reached. It returns true if the secret was locked.
+func failMFAAttempt(secret *v1.Secret, now time.Time) bool {
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	attempts, _ := strconv.Atoi(string(secret.Data[mfaFailedAttemptsField]))
+	attempts++
+	if attempts < mfaMaxFailedAttempts {
+		secret.Data[mfaFailedAttemptsField] = []byte(strconv.Itoa(attempts))
+		return false
+	}
+	delete(secret.Data, mfaFailedAttemptsField)
+	secret.Data[mfaLockedUntilField] = []byte(now.Add(mfaLockoutDuration).UTC().Format(time.RFC3339))
+	return true
+}
+
+// mfaLockedOut reports whether the second factor of the secret is locked at the given time.
+func mfaLockedOut(secret *v1.Secret, now time.Time) bool {
+	lockedUntil := string(secret.Data[mfaLockedUntilField])
+	if lockedUntil == "" {
+		return false
+	}
+	t, err := time.Parse(time.RFC3339, lockedUntil)
+	if err != nil {
+		// an unreadable lock must not unlock the second factor
+		return true
+	}
+	return now.Before(t)
+}
+
+// Reset removes the TOTP secret, recovery codes and pending challenge of the user.
+func (m *MFAManager) Reset(userID string) error {
+	for _, name := range []string{mfaSecretName(userID), mfaChallengeSecretName(userID)} {
+		err := m.secrets.DeleteNamespaced(common.SecretsNamespace, name, &metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MFAManager) getSecret(userID string) (*v1.Secret, error) {
+	secret, err := m.secrets.GetNamespaced(common.SecretsNamespace, mfaSecretName(userID), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return secret, err
+}
+
+// NewChallengeToken returns a short-lived token identifying a user that passed password
+// authentication but still has to provide a second factor. The token carries a nonce that is
+// stored for the user, so that issuing a new challenge or completing the login invalidates it.
+func (m *MFAManager) NewChallengeToken(userID string) (string, error) {
+	key, err := m.getChallengeKey()
+	if err != nil {
+		return "", err
+	}
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	nonce := hex.EncodeToString(raw)
+	if err := m.storeChallengeNonce(userID, nonce); err != nil {
+		return "", err
+	}
+
+	payload := userID + "." + nonce + "." + strconv.FormatInt(time.Now().Add(mfaChallengeTTL).Unix(), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + base64.RawURLEncoding.EncodeToString(sign(key, payload)), nil
+}
+
+// UserFromChallengeToken validates a challenge token and returns the user it was issued for.
+// Only the last challenge issued for the user is valid, until it is ended by EndChallenge.
+func (m *MFAManager) UserFromChallengeToken(token string) (*v3.User, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return nil, errMFAAuthFailed
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, errMFAAuthFailed
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errMFAAuthFailed
+	}
+
+	key, err := m.getChallengeKey()
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(signature, sign(key, string(payload))) {
+		return nil, errMFAAuthFailed
+	}
+
+	userID, nonce, expires, ok := parseChallengePayload(string(payload))
+	if !ok {
+		return nil, errMFAAuthFailed
+	}
+	if time.Now().Unix() > expires {
+		return nil, httperror.NewAPIError(httperror.Unauthorized, "multi-factor challenge expired")
+	}
+
+	secret, err := m.secrets.GetNamespaced(common.SecretsNamespace, mfaChallengeSecretName(userID), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, errMFAAuthFailed
+	} else if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare(secret.Data[mfaNonceField], []byte(nonce)) != 1 {
+		return nil, errMFAAuthFailed
+	}
+
+	user, err := m.userLister.Get("", userID)
+	if err != nil {
+		return nil, errMFAAuthFailed
+	}
+	return user, nil
+}
+
+// EndChallenge invalidates the pending challenge of the user, a challenge token can only be
+// used for a single login.
+func (m *MFAManager) EndChallenge(userID string) error {
+	err := m.secrets.DeleteNamespaced(common.SecretsNamespace, mfaChallengeSecretName(userID), &metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func (m *MFAManager) storeChallengeNonce(userID, nonce string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := m.secrets.GetNamespaced(common.SecretsNamespace, mfaChallengeSecretName(userID), metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			_, err = m.secrets.Create(&v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      mfaChallengeSecretName(userID),
+					Namespace: common.SecretsNamespace,
+				},
+				Data: map[string][]byte{mfaNonceField: []byte(nonce)},
+				Type: v1.SecretTypeOpaque,
+			})
+			if apierrors.IsAlreadyExists(err) {
+				// retried as a conflict, the challenge was issued concurrently
+				return apierrors.NewConflict(v1.Resource("secrets"), mfaChallengeSecretName(userID), err)
+			}
+			return err
+		} else if err != nil {
+			return err
+		}
+		secret = secret.DeepCopy()
+		secret.Data = map[string][]byte{mfaNonceField: []byte(nonce)}
+		_, err = m.secrets.Update(secret)
+		return err
+	})
+}
+
+// parseChallengePayload splits the payload of a challenge token into the user ID, nonce and
+// expiry time.
+func parseChallengePayload(payload string) (string, string, int64, bool) {
+	i := strings.LastIndex(payload, ".")
+	if i < 0 {
+		return "", "", 0, false
+	}
+	expires, err := strconv.ParseInt(payload[i+1:], 10, 64)
+	if err != nil {
+		return "", "", 0, false
+	}
+	j := strings.LastIndex(payload[:i], ".")
+	if j <= 0 || j == i-1 {
+		return "", "", 0, false
+	}
+	return payload[:j], payload[j+1 : i], expires, true
+}
+
+// getChallengeKey loads the key challenge tokens are signed with, creating it on first use
+// so that every Rancher replica shares it.
+func (m *MFAManager) getChallengeKey() ([]byte, error) {
+	m.challengeKeyLock.Lock()
+	defer m.challengeKeyLock.Unlock()
+
+	if m.challengeKey != nil {
+		return m.challengeKey, nil
+	}
+
+	secret, err := m.secrets.GetNamespaced(common.SecretsNamespace, mfaChallengeKeyName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		secret, err = m.secrets.Create(&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      mfaChallengeKeyName,
+				Namespace: common.SecretsNamespace,
+			},
+			Data: map[string][]byte{"key": key},
+			Type: v1.SecretTypeOpaque,
+		})
+		if apierrors.IsAlreadyExists(err) {
+			secret, err = m.secrets.GetNamespaced(common.SecretsNamespace, mfaChallengeKeyName, metav1.GetOptions{})
+		}
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load multi-factor challenge key")
+	}
+	if len(secret.Data["key"]) == 0 {
+		return nil, fmt.Errorf("multi-factor challenge key %s/%s is empty", common.SecretsNamespace, mfaChallengeKeyName)
+	}
+
+	m.challengeKey = secret.Data["key"]
+	return m.challengeKey, nil
+}
+
+func sign(key []byte, payload string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// newRecoveryCodes returns recovery codes along with the hashes that are stored.
+func newRecoveryCodes() ([]string, []string, error) {
+	var codes, hashes []string
+	for i := 0; i < mfaRecoveryCodeCount; i++ {
+		raw := make([]byte, 10)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, err
+		}
+		code := strings.ToLower(base32.StdEncoding.EncodeToString(raw))
+		code = code[:8] + "-" + code[8:16]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// useRecoveryCode checks code against the newline separated hashes and returns the hashes
+// that remain once it is consumed.
+func useRecoveryCode(hashes, code string) (string, bool) {
+	hashed := hashRecoveryCode(code)
+	var remaining []string
+	found := false
+	for _, h := range strings.Split(hashes, "\n") {
+		if h == "" {
+			continue
+		}
+		if !found && subtle.ConstantTimeCompare([]byte(h), []byte(hashed)) == 1 {
+			found = true
+			continue
+		}
+		remaining = append(remaining, h)
+	}
+	return strings.Join(remaining, "\n"), found
+}
@@ -0,0 +1,65 @@
+package local
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestParseChallengePayload(t *testing.T) {
+	tests := []struct {
+		payload string
+		userID  string
+		nonce   string
+		expires int64
+		ok      bool
+	}{
+		{payload: "u-abc.0123abcd.1700000000", userID: "u-abc", nonce: "0123abcd", expires: 1700000000, ok: true},
+		{payload: "u.a.b.0123abcd.1700000000", userID: "u.a.b", nonce: "0123abcd", expires: 1700000000, ok: true},
+		// tokens issued without a nonce are no longer accepted
+		{payload: "u-abc.1700000000"},
+		{payload: "u-abc..1700000000"},
+		{payload: ".0123abcd.1700000000"},
+		{payload: "u-abc.0123abcd.soon"},
+		{payload: ""},
+	}
+	for _, tt := range tests {
+		userID, nonce, expires, ok := parseChallengePayload(tt.payload)
+		if ok != tt.ok || userID != tt.userID || nonce != tt.nonce || expires != tt.expires {
+			t.Errorf("parseChallengePayload(%q) = %q, %q, %d, %v, want %q, %q, %d, %v", tt.payload,
+				userID, nonce, expires, ok, tt.userID, tt.nonce, tt.expires, tt.ok)
+		}
+	}
+}
+
+func TestFailMFAAttempt(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	secret := &v1.Secret{}
+
+	for i := 1; i < mfaMaxFailedAttempts; i++ {
+		if failMFAAttempt(secret, now) {
+			t.Fatalf("failMFAAttempt() locked after %d attempts, want %d", i, mfaMaxFailedAttempts)
+		}
+		if mfaLockedOut(secret, now) {
+			t.Fatalf("mfaLockedOut() = true after %d attempts", i)
+		}
+	}
+	if !failMFAAttempt(secret, now) {
+		t.Fatalf("failMFAAttempt() did not lock after %d attempts", mfaMaxFailedAttempts)
+	}
+	if !mfaLockedOut(secret, now) {
+		t.Error("mfaLockedOut() = false once the attempts are exhausted")
+	}
+	if mfaLockedOut(secret, now.Add(mfaLockoutDuration)) {
+		t.Error("mfaLockedOut() = true once the lockout expired")
+	}
+	if _, ok := secret.Data[mfaFailedAttemptsField]; ok {
+		t.Error("failed attempts are kept once the second factor is locked")
+	}
+
+	secret.Data[mfaLockedUntilField] = []byte("invalid")
+	if !mfaLockedOut(secret, now) {
+		t.Error("mfaLockedOut() = false for an unreadable lock")
+	}
+}
@@ -0,0 +1,80 @@
+package local
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the base32 encoding of the SHA1 test key from RFC 6238 appendix B.
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{unix: 59, code: "287082"},
+		{unix: 1111111109, code: "081804"},
+		{unix: 1234567890, code: "005924"},
+		{unix: 2000000000, code: "279037"},
+	}
+	for _, tt := range tests {
+		code, err := totpCode(rfc6238Secret, uint64(tt.unix)/totpPeriod)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code != tt.code {
+			t.Errorf("totpCode() at %d = %s, want %s", tt.unix, code, tt.code)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	current := uint64(now.Unix()) / totpPeriod
+
+	previous, err := totpCode(rfc6238Secret, current-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	step, ok := validateTOTP(rfc6238Secret, previous, now, 0)
+	if !ok || step != current-1 {
+		t.Fatalf("expected code of the previous period to be accepted, got step %d, ok %v", step, ok)
+	}
+	if _, ok := validateTOTP(rfc6238Secret, previous, now, step); ok {
+		t.Error("expected used code to be rejected")
+	}
+
+	stale, err := totpCode(rfc6238Secret, current-2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := validateTOTP(rfc6238Secret, stale, now, 0); ok {
+		t.Error("expected code outside the allowed skew to be rejected")
+	}
+	if _, ok := validateTOTP(rfc6238Secret, "12345", now, 0); ok {
+		t.Error("expected code of the wrong length to be rejected")
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != mfaRecoveryCodeCount {
+		t.Fatalf("expected %d recovery codes, got %d", mfaRecoveryCodeCount, len(codes))
+	}
+
+	remaining, ok := useRecoveryCode(strings.Join(hashes, "\n"), " "+codes[3]+" ")
+	if !ok {
+		t.Fatal("expected recovery code to be accepted")
+	}
+	if _, ok := useRecoveryCode(remaining, codes[3]); ok {
+		t.Error("expected used recovery code to be rejected")
+	}
+	if _, ok := useRecoveryCode(remaining, codes[4]); !ok {
+		t.Error("expected unused recovery code to be accepted")
+	}
+}
@@ -68,4 +68,8 @@ func authProviderSchemas(ctx context.Context, management *config.ScaledContext,
 
 func loginActionFormatter(apiContext *types.APIContext, resource *types.RawResource) {
 	resource.AddAction(apiContext, "login")
+	if resource.Type == v3public.LocalProviderType {
+		resource.AddAction(apiContext, "mfalogin")
+		resource.AddAction(apiContext, "mfaenroll")
+	}
 }
@@ -51,14 +51,42 @@ type loginHandler struct {
 	tokenMGR *tokens.Manager
 }
 
+// mfaChallengeRequired is returned when a local user passed password authentication but still
+// has to provide a second factor through the mfalogin action.
+type mfaChallengeRequired struct {
+	challenge *v32.MFAChallenge
+}
+
+func (e *mfaChallengeRequired) Error() string {
+	return "multi-factor authentication required"
+}
+
 func (h *loginHandler) login(actionName string, action *types.Action, request *types.APIContext) error {
-	if actionName != "login" {
+	var token v3.Token
+	var unhashedTokenKey, responseType string
+	var err error
+
+	switch actionName {
+	case "login":
+		token, unhashedTokenKey, responseType, err = h.createLoginToken(request)
+	case "mfalogin":
+		token, unhashedTokenKey, responseType, err = h.createMFALoginToken(request)
+	case "mfaenroll":
+		return h.enrollMFA(request)
+	default:
 		return httperror.NewAPIError(httperror.ActionNotAvailable, "")
 	}
 
 	w := request.Response
 
-	token, unhashedTokenKey, responseType, err := h.createLoginToken(request)
+	if challengeErr, ok := err.(*mfaChallengeRequired); ok {
+		request.WriteResponse(http.StatusOK, map[string]interface{}{
+			"type":               client.MFAChallengeType,
+			"challengeToken":     challengeErr.challenge.ChallengeToken,
+			"enrollmentRequired": challengeErr.challenge.EnrollmentRequired,
+		})
+		return nil
+	}
 	if err != nil {
 		// if user fails to authenticate, hide the details of the exact error. bad credentials will already be APIErrors
 		// otherwise, return a generic error message
@@ -203,6 +231,94 @@ func (h *loginHandler) createLoginToken(request *types.APIContext) (v3.Token, st
 		return v3.Token{}, "", "", httperror.NewAPIError(httperror.PermissionDenied, "Permission Denied")
 	}
 
+	if providerName == local.Name {
+		localProvider, err := getLocalProvider()
+		if err != nil {
+			return v3.Token{}, "", "", err
+		}
+		challenge, err := localProvider.MFAChallenge(currUser)
+		if err != nil {
+			return v3.Token{}, "", "", err
+		}
+		if challenge != nil {
+			return v3.Token{}, "", "", &mfaChallengeRequired{challenge: challenge}
+		}
+	}
+
+	return h.issueLoginToken(currUser, userPrincipal, groupPrincipals, providerToken, responseType, description, ttl)
+}
+
+// createMFALoginToken completes a local login that was challenged for a second factor.
+func (h *loginHandler) createMFALoginToken(request *types.APIContext) (v3.Token, string, string, error) {
+	input := &v32.MFALoginInput{}
+	if err := json.NewDecoder(request.Request.Body).Decode(input); err != nil {
+		logrus.Errorf("unmarshal failed with error: %v", err)
+		return v3.Token{}, "", "", httperror.NewAPIError(httperror.InvalidBodyContent, "")
+	}
+
+	ttl := input.TTLMillis
+	authTimeout := settings.AuthUserSessionTTLMinutes.Get()
+	if minutes, err := strconv.ParseInt(authTimeout, 10, 64); err == nil {
+		ttl = minutes * 60 * 1000
+	}
+
+	localProvider, err := getLocalProvider()
+	if err != nil {
+		return v3.Token{}, "", "", err
+	}
+	currUser, userPrincipal, groupPrincipals, err := localProvider.AuthenticateMFA(input)
+	if err != nil {
+		return v3.Token{}, "", "", err
+	}
+
+	if currUser.Enabled != nil && !*currUser.Enabled {
+		return v3.Token{}, "", "", httperror.NewAPIError(httperror.PermissionDenied, "Permission Denied")
+	}
+
+	return h.issueLoginToken(currUser, userPrincipal, groupPrincipals, "", input.ResponseType, input.Description, ttl)
+}
+
+// enrollMFA starts the enrollment of a local user that has to enroll before their login can complete.
+func (h *loginHandler) enrollMFA(request *types.APIContext) error {
+	input := &v32.MFAEnrollInput{}
+	if err := json.NewDecoder(request.Request.Body).Decode(input); err != nil {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, "")
+	}
+
+	localProvider, err := getLocalProvider()
+	if err != nil {
+		return err
+	}
+	output, err := localProvider.EnrollMFA(input)
+	if err != nil {
+		if httperror.IsAPIError(err) {
+			return err
+		}
+		return httperror.WrapAPIError(err, httperror.ServerError, "Server error while enrolling")
+	}
+
+	request.WriteResponse(http.StatusOK, map[string]interface{}{
+		"type":          client.MFAEnrollOutputType,
+		"secret":        output.Secret,
+		"otpAuthUrl":    output.OTPAuthURL,
+		"recoveryCodes": output.RecoveryCodes,
+	})
+	return nil
+}
+
+func getLocalProvider() (*local.Provider, error) {
+	provider, err := providers.GetProvider(local.Name)
+	if err != nil {
+		return nil, err
+	}
+	localProvider, ok := provider.(*local.Provider)
+	if !ok {
+		return nil, httperror.NewAPIError(httperror.ServerError, "local authentication provider is not available")
+	}
+	return localProvider, nil
+}
+
+func (h *loginHandler) issueLoginToken(currUser *v3.User, userPrincipal v3.Principal, groupPrincipals []v3.Principal, providerToken, responseType, description string, ttl int64) (v3.Token, string, string, error) {
 	if strings.HasPrefix(responseType, tokens.KubeconfigResponseType) {
 		token, tokenValue, err := tokens.GetKubeConfigToken(currUser.Name, responseType, h.userMGR)
 		if err != nil {
@@ -0,0 +1,107 @@
+package requests
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rancher/rancher/pkg/auth/tokens"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// lastUsedUpdateInterval limits how often the last-used time of a token is written, as
+// every write also triggers the controllers watching tokens.
+const lastUsedUpdateInterval = 5 * time.Minute
+
+// lastUsedRecorder records when and from where tokens last authenticated a request.
+type lastUsedRecorder struct {
+	tokenClient v3.TokenInterface
+
+	lock    sync.Mutex
+	pending map[string]bool
+}
+
+func newLastUsedRecorder(tokenClient v3.TokenInterface) *lastUsedRecorder {
+	return &lastUsedRecorder{
+		tokenClient: tokenClient,
+		pending:     map[string]bool{},
+	}
+}
+
+// use checks that the token is not idle at now and records that it was used then, so that a request is either
+// rejected as idle or refreshes the last use of the token, as of the same time.
+func (r *lastUsedRecorder) use(token *v3.Token, req *http.Request, now time.Time) error {
+	if tokens.IsIdle(*token, now) {
+		return errors.New("token has not been used within the idle timeout")
+	}
+	r.record(token, req, now)
+	return nil
+}
+
+// record updates the token in the background unless it was recorded within the update interval.
+func (r *lastUsedRecorder) record(token *v3.Token, req *http.Request, now time.Time) {
+	if !needsLastUsedUpdate(token, now) {
+		return
+	}
+
+	r.lock.Lock()
+	if r.pending[token.Name] {
+		r.lock.Unlock()
+		return
+	}
+	r.pending[token.Name] = true
+	r.lock.Unlock()
+
+	ip := sourceIP(req)
+	go func() {
+		defer func() {
+			r.lock.Lock()
+			delete(r.pending, token.Name)
+			r.lock.Unlock()
+		}()
+		if err := r.update(token.Name, ip, now); err != nil {
+			logrus.Debugf("Failed to record last use of token %s: %v", token.Name, err)
+		}
+	}()
+}
+
+func (r *lastUsedRecorder) update(tokenName, ip string, now time.Time) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		token, err := r.tokenClient.Get(tokenName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if !needsLastUsedUpdate(token, now) {
+			return nil
+		}
+
+		token.LastUsedAt = now.UTC().Format(time.RFC3339)
+		token.LastUsedFrom = ip
+		_, err = r.tokenClient.Update(token)
+		return err
+	})
+}
+
+func needsLastUsedUpdate(token *v3.Token, now time.Time) bool {
+	if token.LastUsedAt == "" {
+		return true
+	}
+	return now.Sub(tokens.LastUsed(*token)) >= lastUsedUpdateInterval
+}
+
+// sourceIP returns the address the request was received from, matching what the audit log records.
+func sourceIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
@@ -36,6 +36,10 @@ func NewAPIHandler(ctx context.Context, apiContext *config.ScaledContext, opts .
 	schema := schemas.Schema(&managementSchema.Version, client.TokenType)
 	schema.CollectionActions = map[string]types.Action{
 		"logout": {},
+		"revoke": {
+			Input:  "tokenRevokeInput",
+			Output: "tokenRevokeOutput",
+		},
 	}
 
 	schema.ActionHandler = api.tokenActionHandler
@@ -61,8 +65,11 @@ type tokenAPI struct {
 
 func (t *tokenAPI) tokenActionHandler(actionName string, action *types.Action, request *types.APIContext) error {
 	logrus.Debugf("TokenActionHandler called for action %v", actionName)
-	if actionName == "logout" {
+	switch actionName {
+	case "logout":
 		return t.mgr.logout(actionName, action, request)
+	case "revoke":
+		return t.mgr.revoke(actionName, action, request)
 	}
 	return httperror.NewAPIError(httperror.ActionNotAvailable, "")
 }
@@ -27,6 +27,7 @@
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/wait"
+	authv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
 	"k8s.io/client-go/tools/cache"
 )
 
@@ -57,28 +58,30 @@ func NewManager(ctx context.Context, apiContext *config.ScaledContext) *Manager
 	tokenInformer := apiContext.Management.Tokens("").Controller().Informer()
 
 	return &Manager{
-		ctx:                 ctx,
-		tokensClient:        apiContext.Management.Tokens(""),
-		userIndexer:         informer.GetIndexer(),
-		tokenIndexer:        tokenInformer.GetIndexer(),
-		userAttributes:      apiContext.Management.UserAttributes(""),
-		userAttributeLister: apiContext.Management.UserAttributes("").Controller().Lister(),
-		userLister:          apiContext.Management.Users("").Controller().Lister(),
-		secrets:             apiContext.Core.Secrets(""),
-		secretLister:        apiContext.Core.Secrets("").Controller().Lister(),
+		ctx:                  ctx,
+		tokensClient:         apiContext.Management.Tokens(""),
+		userIndexer:          informer.GetIndexer(),
+		tokenIndexer:         tokenInformer.GetIndexer(),
+		userAttributes:       apiContext.Management.UserAttributes(""),
+		userAttributeLister:  apiContext.Management.UserAttributes("").Controller().Lister(),
+		userLister:           apiContext.Management.Users("").Controller().Lister(),
+		secrets:              apiContext.Core.Secrets(""),
+		secretLister:         apiContext.Core.Secrets("").Controller().Lister(),
+		subjectAccessReviews: apiContext.K8sClient.AuthorizationV1().SubjectAccessReviews(),
 	}
 }
 
 type Manager struct {
-	ctx                 context.Context
-	tokensClient        v3.TokenInterface
-	userAttributes      v3.UserAttributeInterface
-	userAttributeLister v3.UserAttributeLister
-	userIndexer         cache.Indexer
-	tokenIndexer        cache.Indexer
-	userLister          v3.UserLister
-	secrets             v1.SecretInterface
-	secretLister        v1.SecretLister
+	ctx                  context.Context
+	tokensClient         v3.TokenInterface
+	userAttributes       v3.UserAttributeInterface
+	userAttributeLister  v3.UserAttributeLister
+	userIndexer          cache.Indexer
+	tokenIndexer         cache.Indexer
+	userLister           v3.UserLister
+	secrets              v1.SecretInterface
+	secretLister         v1.SecretLister
+	subjectAccessReviews authv1.SubjectAccessReviewInterface
 }
 
 func userPrincipalIndexer(obj interface{}) ([]string, error) {
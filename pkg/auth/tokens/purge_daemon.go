@@ -7,6 +7,7 @@
 	"github.com/rancher/norman/clientbase"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/namespace"
+	"github.com/rancher/rancher/pkg/settings"
 	"github.com/rancher/rancher/pkg/types/config"
 	"github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -39,20 +40,30 @@ func (p *purger) purge() {
 		logrus.Errorf("Error listing tokens during purge: %v", err)
 	}
 
-	var count int
+	var count, idleCount int
+	now := time.Now()
 	for _, token := range allTokens {
-		if IsExpired(*token) {
+		expired := IsExpired(*token)
+		idle := !expired && IsIdle(*token, now)
+		if expired || idle {
 			err = p.tokens.Delete(token.ObjectMeta.Name, &metav1.DeleteOptions{})
 			if err != nil && !clientbase.IsNotFound(err) {
 				logrus.Errorf("Error: while deleting expired token %v: %v", err, token.ObjectMeta.Name)
 				continue
 			}
-			count++
+			if idle {
+				idleCount++
+			} else {
+				count++
+			}
 		}
 	}
 	if count > 0 {
 		logrus.Infof("Purged %v expired tokens", count)
 	}
+	if idleCount > 0 {
+		logrus.Infof("Purged %v tokens unused for more than %v days", idleCount, settings.AuthTokenIdleTimeoutDays.Get())
+	}
 
 	// saml tokens store encrypted token for login request from rancher cli
 	samlTokens, err := p.samlTokensLister.List(namespace.GlobalNamespace, labels.Everything())
@@ -0,0 +1,244 @@
+package tokens
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/rancher/norman/httperror"
+	"github.com/rancher/norman/types"
+	"github.com/rancher/norman/types/convert"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/auth/util"
+	clientv3 "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/sirupsen/logrus"
+	authv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// tokenFilter selects tokens for bulk revocation. Zero values match every token.
+type tokenFilter struct {
+	authProvider     string
+	userIDs          map[string]bool
+	principalID      string
+	groupPrincipalID string
+	clusterName      string
+	createdBefore    time.Time
+	createdAfter     time.Time
+	lastUsedBefore   time.Time
+}
+
+func newTokenFilter(input *v32.TokenRevokeInput) (*tokenFilter, error) {
+	filter := &tokenFilter{
+		authProvider:     input.AuthProvider,
+		principalID:      input.PrincipalID,
+		groupPrincipalID: input.GroupPrincipalID,
+		clusterName:      input.ClusterName,
+	}
+	if input.UserID != "" {
+		filter.userIDs = map[string]bool{input.UserID: true}
+	}
+
+	for _, t := range []struct {
+		field string
+		value string
+		into  *time.Time
+	}{
+		{"createdBefore", input.CreatedBefore, &filter.createdBefore},
+		{"createdAfter", input.CreatedAfter, &filter.createdAfter},
+		{"lastUsedBefore", input.LastUsedBefore, &filter.lastUsedBefore},
+	} {
+		if t.value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, t.value)
+		if err != nil {
+			return nil, httperror.NewAPIError(httperror.InvalidFormat, fmt.Sprintf("%s must be an RFC3339 timestamp: %v", t.field, err))
+		}
+		*t.into = parsed
+	}
+
+	if filter.empty() {
+		return nil, httperror.NewAPIError(httperror.MissingRequired, "at least one token selector must be set")
+	}
+	return filter, nil
+}
+
+func (f *tokenFilter) empty() bool {
+	return f.authProvider == "" && f.userIDs == nil && f.principalID == "" && f.groupPrincipalID == "" &&
+		f.clusterName == "" && f.createdBefore.IsZero() && f.createdAfter.IsZero() && f.lastUsedBefore.IsZero()
+}
+
+// matches reports whether the token is selected by every selector of the filter except group
+// membership, which needs the user attributes and is checked by the manager.
+func (f *tokenFilter) matches(token *v3.Token) bool {
+	if f.authProvider != "" && token.AuthProvider != f.authProvider {
+		return false
+	}
+	if f.userIDs != nil && !f.userIDs[token.UserID] {
+		return false
+	}
+	if f.clusterName != "" && token.ClusterName != f.clusterName {
+		return false
+	}
+
+	created := token.CreationTimestamp.Time
+	if !f.createdBefore.IsZero() && !created.Before(f.createdBefore) {
+		return false
+	}
+	if !f.createdAfter.IsZero() && !created.After(f.createdAfter) {
+		return false
+	}
+
+	if !f.lastUsedBefore.IsZero() && !LastUsed(*token).Before(f.lastUsedBefore) {
+		return false
+	}
+	return true
+}
+
+// revoke lists or deletes every token matching the action input. Revoking tokens of other
+// users requires permission to delete tokens, which is checked with a SubjectAccessReview.
+func (m *Manager) revoke(actionName string, action *types.Action, request *types.APIContext) error {
+	tokenAuthValue := GetTokenAuthFromRequest(request.Request)
+	if tokenAuthValue == "" {
+		// no cookie or auth header, cannot authenticate
+		return httperror.NewAPIErrorLong(http.StatusUnauthorized, util.GetHTTPErrorCode(http.StatusUnauthorized), "No valid token cookie or auth header")
+	}
+
+	allowed, err := m.canRevokeTokens(request.Request)
+	if err != nil {
+		return httperror.WrapAPIError(err, httperror.ServerError, "failed to authorize token revocation")
+	}
+	if !allowed {
+		return httperror.NewAPIError(httperror.PermissionDenied, "not allowed to revoke tokens")
+	}
+
+	bytes, err := ioutil.ReadAll(request.Request.Body)
+	if err != nil {
+		return httperror.NewAPIError(httperror.InvalidBodyContent, fmt.Sprintf("%s", err))
+	}
+	input := &v32.TokenRevokeInput{}
+	if err := json.Unmarshal(bytes, input); err != nil {
+		return httperror.NewAPIError(httperror.InvalidFormat, fmt.Sprintf("%s", err))
+	}
+
+	filter, err := newTokenFilter(input)
+	if err != nil {
+		return err
+	}
+
+	currentAuthToken, _, err := m.getToken(tokenAuthValue)
+	if err != nil {
+		return err
+	}
+
+	matched, err := m.findTokens(filter)
+	if err != nil {
+		return err
+	}
+
+	output := &v32.TokenRevokeOutput{
+		DryRun: input.DryRun,
+		Tokens: []v32.TokenSummary{},
+	}
+	for _, token := range matched {
+		// the session making the request is never revoked, logout has to be used for it
+		if token.Name == currentAuthToken.Name {
+			continue
+		}
+
+		summary := summarizeToken(token)
+		if !input.DryRun {
+			if _, err := m.deleteTokenByName(token.Name); err != nil {
+				summary.Error = err.Error()
+			} else {
+				output.Revoked++
+			}
+		}
+		output.Tokens = append(output.Tokens, summary)
+	}
+
+	if !input.DryRun {
+		logrus.Infof("User [%s] revoked %d tokens", request.Request.Header.Get("Impersonate-User"), output.Revoked)
+	}
+
+	data, err := convert.EncodeToMap(output)
+	if err != nil {
+		return err
+	}
+	data["type"] = clientv3.TokenRevokeOutputType
+	request.WriteResponse(http.StatusOK, data)
+	return nil
+}
+
+// findTokens returns the tokens in the cache matching the filter, sorted by name.
+func (m *Manager) findTokens(filter *tokenFilter) ([]*v3.Token, error) {
+	if filter.principalID != "" {
+		// a principal selects the tokens of every user it is linked to
+		users, err := m.userIndexer.ByIndex(userPrincipalIndex, filter.principalID)
+		if err != nil {
+			return nil, err
+		}
+		userIDs := map[string]bool{}
+		for _, obj := range users {
+			if user, ok := obj.(*v3.User); ok && (filter.userIDs == nil || filter.userIDs[user.Name]) {
+				userIDs[user.Name] = true
+			}
+		}
+		filter.userIDs = userIDs
+	}
+
+	var matched []*v3.Token
+	for _, obj := range m.tokenIndexer.List() {
+		token, ok := obj.(*v3.Token)
+		if !ok || !filter.matches(token) {
+			continue
+		}
+		if filter.groupPrincipalID != "" && !m.IsMemberOf(*token, v3.Principal{ObjectMeta: metav1.ObjectMeta{Name: filter.groupPrincipalID}}) {
+			continue
+		}
+		matched = append(matched, token)
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].Name < matched[j].Name
+	})
+	return matched, nil
+}
+
+func (m *Manager) canRevokeTokens(req *http.Request) (bool, error) {
+	review := authv1.SubjectAccessReview{
+		Spec: authv1.SubjectAccessReviewSpec{
+			User:   req.Header.Get("Impersonate-User"),
+			Groups: req.Header["Impersonate-Group"],
+			ResourceAttributes: &authv1.ResourceAttributes{
+				Verb:     "delete",
+				Resource: "tokens",
+				Group:    v3.TokenGroupVersionKind.Group,
+			},
+		},
+	}
+
+	result, err := m.subjectAccessReviews.Create(req.Context(), &review, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return result.Status.Allowed, nil
+}
+
+func summarizeToken(token *v3.Token) v32.TokenSummary {
+	return v32.TokenSummary{
+		Name:         token.Name,
+		UserID:       token.UserID,
+		AuthProvider: token.AuthProvider,
+		ClusterName:  token.ClusterName,
+		Description:  token.Description,
+		Created:      token.CreationTimestamp.UTC().Format(time.RFC3339),
+		LastUsedAt:   token.LastUsedAt,
+		IsDerived:    token.IsDerived,
+	}
+}
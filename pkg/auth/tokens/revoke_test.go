@@ -0,0 +1,95 @@
+package tokens
+
+import (
+	"testing"
+	"time"
+
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewTokenFilter(t *testing.T) {
+	_, err := newTokenFilter(&v32.TokenRevokeInput{DryRun: true})
+	assert.Error(t, err, "expected a filter without selectors to be rejected")
+
+	_, err = newTokenFilter(&v32.TokenRevokeInput{CreatedBefore: "yesterday"})
+	assert.Error(t, err, "expected an invalid timestamp to be rejected")
+
+	filter, err := newTokenFilter(&v32.TokenRevokeInput{UserID: "u-abc", CreatedBefore: "2021-06-01T00:00:00Z"})
+	assert.NoError(t, err)
+	assert.True(t, filter.userIDs["u-abc"])
+	assert.Equal(t, time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC), filter.createdBefore.UTC())
+}
+
+func TestTokenFilterMatches(t *testing.T) {
+	created := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	token := &v3.Token{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "token-abc",
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		UserID:       "u-abc",
+		AuthProvider: "github",
+		ClusterName:  "c-abc",
+		LastUsedAt:   "2021-06-10T12:00:00Z",
+	}
+
+	tests := []struct {
+		name   string
+		filter tokenFilter
+		want   bool
+	}{
+		{
+			name:   "provider",
+			filter: tokenFilter{authProvider: "github"},
+			want:   true,
+		},
+		{
+			name:   "other provider",
+			filter: tokenFilter{authProvider: "local"},
+		},
+		{
+			name:   "user and cluster",
+			filter: tokenFilter{userIDs: map[string]bool{"u-abc": true}, clusterName: "c-abc"},
+			want:   true,
+		},
+		{
+			name:   "other cluster",
+			filter: tokenFilter{userIDs: map[string]bool{"u-abc": true}, clusterName: "c-def"},
+		},
+		{
+			name:   "no matching user",
+			filter: tokenFilter{userIDs: map[string]bool{}},
+		},
+		{
+			name:   "created before",
+			filter: tokenFilter{createdBefore: created.Add(time.Hour)},
+			want:   true,
+		},
+		{
+			name:   "created after",
+			filter: tokenFilter{createdAfter: created.Add(time.Hour)},
+		},
+		{
+			name:   "last used before",
+			filter: tokenFilter{lastUsedBefore: created.Add(30 * 24 * time.Hour)},
+			want:   true,
+		},
+		{
+			name:   "used since",
+			filter: tokenFilter{lastUsedBefore: created.Add(24 * time.Hour)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.matches(token))
+		})
+	}
+
+	neverUsed := token.DeepCopy()
+	neverUsed.LastUsedAt = ""
+	filter := tokenFilter{lastUsedBefore: created.Add(24 * time.Hour)}
+	assert.True(t, filter.matches(neverUsed), "expected a token that was never used to be measured from its creation")
+}
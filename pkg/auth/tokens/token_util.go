@@ -12,6 +12,7 @@
 	"github.com/rancher/norman/types/convert"
 	"github.com/rancher/rancher/pkg/features"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
 	"github.com/rancher/rancher/pkg/user"
 	"github.com/sirupsen/logrus"
 )
@@ -58,6 +59,45 @@ func IsExpired(token v3.Token) bool {
 	return durationElapsed.Seconds() >= ttlDuration.Seconds()
 }
 
+// systemTokenKinds are the kinds of the tokens rancher issues to its own components rather than to people.
+var systemTokenKinds = map[string]bool{
+	"agent":        true,
+	"compose":      true,
+	"drain-node":   true,
+	"helm":         true,
+	"provisioning": true,
+	"telemetry":    true,
+}
+
+// IsIdle reports whether the token has not been used at now for longer than the auth-token-idle-timeout-days
+// setting. Tokens of system principals and tokens issued to rancher components are never considered idle, nor are
+// tokens whose use was not recorded yet; their idle time starts with the first recorded use.
+func IsIdle(token v3.Token, now time.Time) bool {
+	days := settings.AuthTokenIdleTimeoutDays.GetInt()
+	if days <= 0 || isSystemToken(token) {
+		return false
+	}
+	lastUsed, err := time.Parse(time.RFC3339, token.LastUsedAt)
+	if err != nil {
+		return false
+	}
+	return now.Sub(lastUsed) >= time.Duration(days)*24*time.Hour
+}
+
+func isSystemToken(token v3.Token) bool {
+	return strings.HasPrefix(token.UserPrincipal.Name, "system://") || systemTokenKinds[token.Labels[TokenKindLabel]]
+}
+
+// LastUsed returns when the token last authenticated a request, or its creation time if it never did.
+func LastUsed(token v3.Token) time.Time {
+	if token.LastUsedAt != "" {
+		if lastUsed, err := time.Parse(time.RFC3339, token.LastUsedAt); err == nil {
+			return lastUsed
+		}
+	}
+	return token.ObjectMeta.CreationTimestamp.Time
+}
+
 func GetTokenAuthFromRequest(req *http.Request) string {
 	var tokenAuthValue string
 	authHeader := req.Header.Get(AuthHeaderName)
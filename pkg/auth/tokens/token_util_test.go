@@ -0,0 +1,60 @@
+package tokens
+
+import (
+	"testing"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsIdle(t *testing.T) {
+	defer settings.AuthTokenIdleTimeoutDays.Set(settings.AuthTokenIdleTimeoutDays.Get())
+
+	now := time.Now()
+	token := v3.Token{
+		ObjectMeta: metav1.ObjectMeta{
+			CreationTimestamp: metav1.NewTime(now.Add(-60 * 24 * time.Hour)),
+		},
+		UserID: "u-abc",
+	}
+
+	settings.AuthTokenIdleTimeoutDays.Set("0")
+	assert.False(t, IsIdle(token, now), "expected idle expiry to be disabled")
+
+	settings.AuthTokenIdleTimeoutDays.Set("30")
+	assert.False(t, IsIdle(token, now), "expected a token without recorded use not to be idle")
+
+	token.LastUsedAt = now.Add(-24 * time.Hour).UTC().Format(time.RFC3339)
+	assert.False(t, IsIdle(token, now), "expected a recently used token not to be idle")
+
+	token.LastUsedAt = now.Add(-31 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	assert.True(t, IsIdle(token, now))
+
+	// the user id says nothing about the user being a system account
+	token.UserID = "system:serviceaccount"
+	assert.True(t, IsIdle(token, now))
+
+	system := token
+	system.UserPrincipal.Name = "system://c-abcde"
+	assert.False(t, IsIdle(system, now), "expected tokens of system principals never to be idle")
+
+	agent := token
+	agent.Labels = map[string]string{TokenKindLabel: "agent"}
+	assert.False(t, IsIdle(agent, now), "expected tokens of rancher components never to be idle")
+
+	provisioning := token
+	provisioning.UserPrincipal.Name = ""
+	provisioning.Labels = map[string]string{TokenKindLabel: "provisioning"}
+	assert.False(t, IsIdle(provisioning, now), "expected provisioning tokens never to be idle")
+
+	drain := token
+	drain.Labels = map[string]string{TokenKindLabel: "drain-node"}
+	assert.False(t, IsIdle(drain, now), "expected drain-node tokens never to be idle")
+
+	kubeconfig := token
+	kubeconfig.Labels = map[string]string{TokenKindLabel: "kubeconfig"}
+	assert.True(t, IsIdle(kubeconfig, now), "expected kubeconfig tokens to be idle")
+}
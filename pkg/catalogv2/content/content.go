@@ -17,6 +17,7 @@
 	"github.com/rancher/rancher/pkg/catalogv2/git"
 	"github.com/rancher/rancher/pkg/catalogv2/helm"
 	helmhttp "github.com/rancher/rancher/pkg/catalogv2/http"
+	"github.com/rancher/rancher/pkg/catalogv2/oci"
 	catalogcontrollers "github.com/rancher/rancher/pkg/generated/controllers/catalog.cattle.io/v1"
 	"github.com/rancher/rancher/pkg/settings"
 	corecontrollers "github.com/rancher/wrangler/pkg/generated/controllers/core/v1"
@@ -121,7 +122,7 @@ func (c *Manager) Index(namespace, name string) (*repo.IndexFile, error) {
 	if cache, ok := c.IndexCache[fmt.Sprintf("%s/%s", r.status.IndexConfigMapNamespace, r.status.IndexConfigMapName)]; ok {
 		if cm.ResourceVersion == cache.revision {
 			c.lock.RUnlock()
-			return c.filterReleases(deepCopyIndex(cache.index), k8sVersion), nil
+			return c.annotateVerification(r, c.filterReleases(deepCopyIndex(cache.index), k8sVersion)), nil
 		}
 	}
 	c.lock.RUnlock()
@@ -158,7 +159,20 @@ func (c *Manager) Index(namespace, name string) (*repo.IndexFile, error) {
 	}
 	c.lock.Unlock()
 
-	return c.filterReleases(deepCopyIndex(index), k8sVersion), nil
+	return c.annotateVerification(r, c.filterReleases(deepCopyIndex(index), k8sVersion)), nil
+}
+
+func (c *Manager) annotateVerification(r repoDef, index *repo.IndexFile) *repo.IndexFile {
+	if r.spec.Verification == nil {
+		return index
+	}
+	keys, err := catalogv2.GetKeySecret(c.secrets, r.spec, r.metadata.Namespace)
+	if err != nil {
+		logrus.Errorf("failed to get the verification keys of repo %s: %v", r.metadata.Name, err)
+		keys = nil
+	}
+	annotateVerification(keys, r.spec, index)
+	return index
 }
 
 func (c *Manager) k8sVersion() (*semver.Version, error) {
@@ -262,6 +276,11 @@ func (c *Manager) Icon(namespace, name, chartName, version string) (io.ReadClose
 		return git.Icon(namespace, name, repo.status.URL, chart)
 	}
 
+	if oci.IsOCI(repo.status.URL) {
+		// registry credentials are not sent to the hosts of icons
+		return helmhttp.Icon(nil, repo.status.URL, repo.spec.CABundle, repo.spec.InsecureSkipTLSverify, chart)
+	}
+
 	secret, err := catalogv2.GetSecret(c.secrets, repo.spec, repo.metadata.Namespace)
 	if err != nil {
 		return nil, "", err
@@ -291,16 +310,24 @@ func (c *Manager) Chart(namespace, name, chartName, version string) (io.ReadClos
 		return nil, err
 	}
 
-	if repo.status.Commit != "" {
-		return git.Chart(namespace, name, repo.status.URL, chart)
+	secret, err := catalogv2.GetSecret(c.secrets, repo.spec, repo.metadata.Namespace)
+	if err != nil {
+		return nil, err
 	}
 
-	secret, err := catalogv2.GetSecret(c.secrets, repo.spec, repo.metadata.Namespace)
+	keys, err := catalogv2.GetKeySecret(c.secrets, repo.spec, repo.metadata.Namespace)
 	if err != nil {
 		return nil, err
 	}
 
-	return helmhttp.Chart(secret, repo.status.URL, repo.spec.CABundle, repo.spec.InsecureSkipTLSverify, chart)
+	s := &source{
+		namespace: namespace,
+		name:      name,
+		spec:      repo.spec,
+		status:    repo.status,
+		secret:    secret,
+	}
+	return s.verifiedChart(keys, chart)
 }
 
 func (c *Manager) Info(namespace, name, chartName, version string) (*types.ChartInfo, error) {
@@ -1,14 +1,19 @@
 package content
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 	"time"
 
+	v1 "github.com/rancher/rancher/pkg/apis/catalog.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/catalogv2/verify"
 	"github.com/rancher/rancher/pkg/settings"
 	"github.com/stretchr/testify/assert"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/repo"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestFilterReleases(t *testing.T) {
@@ -202,3 +207,60 @@ func TestFilterReleases(t *testing.T) {
 		})
 	}
 }
+
+func TestAnnotateVerification(t *testing.T) {
+	keys := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{UID: "keys", ResourceVersion: "1"}}
+	spec := &v1.RepoSpec{Verification: &v1.ChartVerification{Type: verify.Provenance}}
+
+	verifications.Add(verificationKey(keys, verify.Provenance, "verified"), verification{time: time.Now()})
+	verifications.Add(verificationKey(keys, verify.Provenance, "failed"), verification{err: errors.New("bad signature"), time: time.Now()})
+	verifications.Add(verificationKey(keys, verify.Provenance, "expired"), verification{err: errors.New("bad signature"), time: time.Now().Add(-2 * failedVerificationTTL)})
+
+	tests := []struct {
+		name     string
+		digest   string
+		expected map[string]string
+	}{
+		{
+			name:     "verified",
+			digest:   "verified",
+			expected: map[string]string{"a": "b", verify.VerifiedAnnotation: "true"},
+		},
+		{
+			name:     "failed",
+			digest:   "failed",
+			expected: map[string]string{"a": "b", verify.VerifiedAnnotation: "false", verify.VerificationErrorAnnotation: "bad signature"},
+		},
+		{
+			name:     "failure expired",
+			digest:   "expired",
+			expected: map[string]string{"a": "b"},
+		},
+		{
+			name:     "not verified yet",
+			digest:   "unknown",
+			expected: map[string]string{"a": "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			annotations := map[string]string{"a": "b", verify.VerifiedAnnotation: "true"}
+			index := &repo.IndexFile{
+				Entries: map[string]repo.ChartVersions{
+					"test-chart": {
+						{
+							Metadata: &chart.Metadata{Name: "test-chart", Version: "1.0.0", Annotations: annotations},
+							Digest:   tt.digest,
+						},
+					},
+				},
+			}
+
+			annotateVerification(keys, spec, index)
+			assert.Equal(t, tt.expected, index.Entries["test-chart"][0].Annotations)
+			// the annotations of the cached index are not changed
+			assert.Equal(t, map[string]string{"a": "b", verify.VerifiedAnnotation: "true"}, annotations)
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package content
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	v1 "github.com/rancher/rancher/pkg/apis/catalog.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/catalogv2/git"
+	helmhttp "github.com/rancher/rancher/pkg/catalogv2/http"
+	"github.com/rancher/rancher/pkg/catalogv2/oci"
+	"github.com/rancher/rancher/pkg/catalogv2/verify"
+	"github.com/rancher/wrangler/pkg/schemas/validation"
+	"helm.sh/helm/v3/pkg/repo"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	verificationCacheSize = 4096
+	// failedVerificationTTL is how long a failed verification is reported before it is tried again, the
+	// signature may have failed to download or been published after the chart
+	failedVerificationTTL = 5 * time.Minute
+)
+
+// verifications holds the results of verifying chart archives, keyed by the keys they were verified with
+// and their digest, so that unchanged charts are not verified again on every request and the index can
+// report them.
+var verifications, _ = lru.New(verificationCacheSize)
+
+type verification struct {
+	err  error
+	time time.Time
+}
+
+func verificationKey(keys *corev1.Secret, verificationType, digest string) string {
+	return fmt.Sprintf("%s/%s/%s:%s", keys.UID, keys.ResourceVersion, verificationType, digest)
+}
+
+// getVerification returns the last result of verifying the archive with the digest, false if it was not
+// verified yet or a failure expired.
+func getVerification(key string) (verification, bool) {
+	obj, ok := verifications.Get(key)
+	if !ok {
+		return verification{}, false
+	}
+	result := obj.(verification)
+	if result.err != nil && time.Since(result.time) > failedVerificationTTL {
+		return verification{}, false
+	}
+	return result, true
+}
+
+// source fetches the charts of a repo, from its git clone, OCI registry or HTTP server.
+type source struct {
+	namespace string
+	name      string
+	spec      *v1.RepoSpec
+	status    *v1.RepoStatus
+	secret    *corev1.Secret
+}
+
+func (s *source) chart(chart *repo.ChartVersion) (io.ReadCloser, error) {
+	if s.status.Commit != "" {
+		return git.Chart(s.namespace, s.name, s.status.URL, chart)
+	}
+	if oci.IsOCI(s.status.URL) {
+		return oci.Chart(s.secret, s.status.URL, s.spec.CABundle, s.spec.InsecureSkipTLSverify, chart)
+	}
+	return helmhttp.Chart(s.secret, s.status.URL, s.spec.CABundle, s.spec.InsecureSkipTLSverify, chart)
+}
+
+func (s *source) verify(verifier *verify.Verifier, chart *repo.ChartVersion, archive []byte) error {
+	if oci.IsOCI(s.status.URL) {
+		return oci.Verify(s.secret, s.status.URL, s.spec.CABundle, s.spec.InsecureSkipTLSverify, chart, archive, verifier)
+	}
+
+	var (
+		signature []byte
+		err       error
+	)
+	if s.status.Commit != "" {
+		signature, err = git.Signature(s.namespace, s.name, s.status.URL, chart, verifier.SignatureSuffix())
+	} else {
+		signature, err = helmhttp.Signature(s.secret, s.status.URL, s.spec.CABundle, s.spec.InsecureSkipTLSverify, chart, verifier.SignatureSuffix())
+	}
+	if err != nil {
+		return err
+	}
+	return verifier.Verify(chart, archive, signature)
+}
+
+// verifyArchive checks the signature of the archive unless it was verified with the same keys before.
+func (s *source) verifyArchive(keys *corev1.Secret, chart *repo.ChartVersion, archive []byte) error {
+	if keys == nil {
+		return fmt.Errorf("chart verification requires a key secret")
+	}
+
+	sum := sha256.Sum256(archive)
+	key := verificationKey(keys, s.spec.Verification.Type, hex.EncodeToString(sum[:]))
+	if result, ok := getVerification(key); ok {
+		return result.err
+	}
+
+	verifier, err := verify.New(s.spec.Verification, keys)
+	if err != nil {
+		return err
+	}
+	err = s.verify(verifier, chart, archive)
+	verifications.Add(key, verification{err: err, time: time.Now()})
+	return err
+}
+
+// verifiedChart downloads the chart and refuses to return it if its signature cannot be verified.
+func (s *source) verifiedChart(keys *corev1.Secret, chart *repo.ChartVersion) (io.ReadCloser, error) {
+	rc, err := s.chart(chart)
+	if err != nil || s.spec.Verification == nil {
+		return rc, err
+	}
+
+	archive, err := ioutil.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.verifyArchive(keys, chart, archive); err != nil {
+		return nil, fmt.Errorf("chartName %s version %s failed signature verification: %v: %w", chart.Name, chart.Version, err, validation.PermissionDenied)
+	}
+	return ioutil.NopCloser(bytes.NewReader(archive)), nil
+}
+
+// annotateVerification records the result of verifying the chart versions of the index in the
+// catalog.cattle.io/verified and catalog.cattle.io/verification-error annotations of the entries. Versions
+// are verified when they are requested, the annotations of versions not verified yet are removed so that
+// a repo can not claim them.
+func annotateVerification(keys *corev1.Secret, spec *v1.RepoSpec, index *repo.IndexFile) {
+	if spec.Verification == nil {
+		return
+	}
+	for _, versions := range index.Entries {
+		for _, version := range versions {
+			if version.Metadata == nil {
+				continue
+			}
+
+			// the annotations are shared with the cached index
+			annotations := make(map[string]string, len(version.Annotations)+2)
+			for k, v := range version.Annotations {
+				annotations[k] = v
+			}
+			delete(annotations, verify.VerifiedAnnotation)
+			delete(annotations, verify.VerificationErrorAnnotation)
+			version.Annotations = annotations
+
+			if keys == nil || version.Digest == "" {
+				continue
+			}
+			result, ok := getVerification(verificationKey(keys, spec.Verification.Type, version.Digest))
+			if !ok {
+				continue
+			}
+			annotations[verify.VerifiedAnnotation] = strconv.FormatBool(result.err == nil)
+			if result.err != nil {
+				annotations[verify.VerificationErrorAnnotation] = result.err.Error()
+			}
+		}
+	}
+}
@@ -3,6 +3,7 @@
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
@@ -54,6 +55,28 @@ func Chart(namespace, name, gitURL string, chartVersion *repo.ChartVersion) (io.
 	return archive.Open()
 }
 
+// Signature reads the signature file stored next to a packaged chart, such as its .prov file. Charts that
+// are built from a directory cannot be signed.
+func Signature(namespace, name, gitURL string, chartVersion *repo.ChartVersion, suffix string) ([]byte, error) {
+	dir := gitDir(namespace, name, gitURL)
+
+	if len(chartVersion.URLs) == 0 {
+		return nil, fmt.Errorf("failed to find chartName %s version %s: %w", chartVersion.Name, chartVersion.Version, validation.NotFound)
+	}
+
+	file, err := relative(dir, gitURL, chartVersion.URLs[0])
+	if err != nil {
+		return nil, err
+	}
+	if s, err := os.Stat(file); err != nil {
+		return nil, err
+	} else if s.IsDir() {
+		return nil, fmt.Errorf("chartName %s version %s is not packaged and cannot be signed", chartVersion.Name, chartVersion.Version)
+	}
+
+	return ioutil.ReadFile(file + suffix)
+}
+
 func relative(base, publicURL, path string) (string, error) {
 	if strings.HasPrefix(path, publicURL) {
 		path = path[len(publicURL):]
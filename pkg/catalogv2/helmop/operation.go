@@ -34,6 +34,7 @@
 	"github.com/rancher/wrangler/pkg/schemas/validation"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apiserver/pkg/authentication/user"
@@ -310,8 +311,10 @@ func (s *Operations) getUpgradeCommand(repoNamespace, repoName string, body io.R
 	upgradeArgs.Install = true
 
 	status := catalog.OperationStatus{
-		Action:    "upgrade",
-		Namespace: namespace(upgradeArgs.Namespace),
+		Action:            "upgrade",
+		Namespace:         namespace(upgradeArgs.Namespace),
+		UpgradePolicy:     upgradeArgs.UpgradePolicy,
+		UpgradeMaxHistory: upgradeArgs.MaxHistory,
 	}
 
 	for _, chartUpgrade := range upgradeArgs.Charts {
@@ -327,7 +330,22 @@ func (s *Operations) getUpgradeCommand(repoNamespace, repoName string, body io.R
 		}
 
 		status.Release = chartUpgrade.ReleaseName
+		status.UpgradeReleases = append(status.UpgradeReleases, chartUpgrade.ReleaseName)
 		commands = append(commands, cmd)
+
+		if status.UpgradePolicy != nil {
+			// a failed upgrade is rolled back to the revision deployed now
+			rel, err := s.apps.Get(status.Namespace, chartUpgrade.ReleaseName, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return status, nil, err
+			}
+			if status.RollbackRevisions == nil {
+				status.RollbackRevisions = map[string]int{}
+			}
+			status.RollbackRevisions[chartUpgrade.ReleaseName] = rel.Spec.Version
+		}
 	}
 
 	return status, commands, nil
@@ -419,6 +437,7 @@ func (c Command) renderArgs() ([]string, error) {
 	delete(dataMap, "releaseName")
 	delete(dataMap, "chartName")
 	delete(dataMap, "projectId")
+	delete(dataMap, "upgradePolicy")
 	if v, ok := dataMap["disableOpenAPIValidation"]; ok {
 		delete(dataMap, "disableOpenAPIValidation")
 		dataMap["disableOpenapiValidation"] = v
@@ -648,6 +667,8 @@ func (s *Operations) createOperation(ctx context.Context, user user.Info, status
 	}
 
 	status.Token = pod.Labels[podimpersonation.TokenLabel]
+	status.User = user.GetName()
+	status.UserGroups = user.GetGroups()
 	status.PodName = pod.Name
 	status.PodNamespace = pod.Namespace
 
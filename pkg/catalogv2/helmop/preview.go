@@ -0,0 +1,461 @@
+package helmop
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/rancher/apiserver/pkg/types"
+	types2 "github.com/rancher/rancher/pkg/api/steve/catalog/types"
+	catalog "github.com/rancher/rancher/pkg/apis/catalog.cattle.io/v1"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/restmapper"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	previewFieldManager = "rancher-helm-preview"
+
+	ChangeCreate    = "create"
+	ChangeUpdate    = "update"
+	ChangeUnchanged = "unchanged"
+	ChangeDelete    = "delete"
+)
+
+// PreviewInstall renders the charts of an install request and dry runs them against the cluster
+// without creating an operation.
+func (s *Operations) PreviewInstall(ctx context.Context, namespace, name string, options io.Reader) (*types2.ChartPreviewOutput, error) {
+	status, cmds, err := s.getInstallCommand(namespace, name, options)
+	if err != nil {
+		return nil, err
+	}
+	return s.preview(ctx, status, cmds)
+}
+
+// PreviewUpgrade renders the charts of an upgrade request and diffs them against the installed releases
+// without creating an operation.
+func (s *Operations) PreviewUpgrade(ctx context.Context, namespace, name string, options io.Reader) (*types2.ChartPreviewOutput, error) {
+	status, cmds, err := s.getUpgradeCommand(namespace, name, options)
+	if err != nil {
+		return nil, err
+	}
+	return s.preview(ctx, status, cmds)
+}
+
+type previewer struct {
+	ctx     context.Context
+	k8s     kubernetes.Interface
+	dynamic dynamic.Interface
+	mapper  meta.RESTMapper
+	caps    *chartutil.Capabilities
+}
+
+func (s *Operations) preview(ctx context.Context, status catalog.OperationStatus, cmds Commands) (*types2.ChartPreviewOutput, error) {
+	apiContext := types.GetAPIContext(ctx)
+	client, err := s.cg.K8sInterface(apiContext)
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := s.cg.DynamicClient(apiContext)
+	if err != nil {
+		return nil, err
+	}
+
+	caps, err := capabilities(client.Discovery())
+	if err != nil {
+		return nil, err
+	}
+
+	p := &previewer{
+		ctx:     ctx,
+		k8s:     client,
+		dynamic: dynamicClient,
+		mapper:  restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(client.Discovery())),
+		caps:    caps,
+	}
+
+	output := &types2.ChartPreviewOutput{}
+	for _, cmd := range cmds {
+		var current *catalog.App
+		if cmd.ReleaseName != "" {
+			current, err = s.apps.Get(status.Namespace, cmd.ReleaseName, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				current = nil
+			} else if err != nil {
+				return nil, err
+			}
+		}
+
+		chartPreview, err := p.previewChart(cmd, status.Namespace, current)
+		if err != nil {
+			return nil, err
+		}
+		output.Charts = append(output.Charts, *chartPreview)
+	}
+
+	return output, nil
+}
+
+func capabilities(client discovery.DiscoveryInterface) (*chartutil.Capabilities, error) {
+	serverVersion, err := client.ServerVersion()
+	if err != nil {
+		return nil, err
+	}
+	apiVersions, err := action.GetVersionSet(client)
+	if err != nil {
+		return nil, err
+	}
+	return &chartutil.Capabilities{
+		KubeVersion: chartutil.KubeVersion{
+			Version: serverVersion.GitVersion,
+			Major:   serverVersion.Major,
+			Minor:   serverVersion.Minor,
+		},
+		APIVersions: apiVersions,
+		HelmVersion: chartutil.DefaultCapabilities.HelmVersion,
+	}, nil
+}
+
+// previewChart renders the chart the way helm would and compares every resource, hooks excluded,
+// with the live object. Resources of the current release missing from the rendered chart are
+// reported as deleted.
+func (p *previewer) previewChart(cmd Command, releaseNamespace string, current *catalog.App) (*types2.ChartPreview, error) {
+	chart, err := loader.LoadArchive(bytes.NewReader(cmd.Chart))
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]interface{}{}
+	if len(cmd.Values) > 0 {
+		if err := json.Unmarshal(cmd.Values, &values); err != nil {
+			return nil, err
+		}
+	}
+	if err := chartutil.ProcessDependencies(chart, values); err != nil {
+		return nil, err
+	}
+
+	if chart.Metadata.KubeVersion != "" && !chartutil.IsCompatibleRange(chart.Metadata.KubeVersion, p.caps.KubeVersion.String()) {
+		return nil, fmt.Errorf("chart requires kubeVersion: %s which is incompatible with Kubernetes %s",
+			chart.Metadata.KubeVersion, p.caps.KubeVersion.String())
+	}
+
+	releaseName := cmd.ReleaseName
+	if releaseName == "" {
+		// the name is generated by helm on install, the chart name is close enough for a preview
+		releaseName = chart.Metadata.Name
+	}
+
+	options := chartutil.ReleaseOptions{
+		Name:      releaseName,
+		Namespace: releaseNamespace,
+		Revision:  1,
+		IsInstall: current == nil,
+		IsUpgrade: current != nil,
+	}
+	if current != nil {
+		options.Revision = current.Spec.Version + 1
+	}
+
+	renderValues, err := chartutil.ToRenderValues(chart, values, options, p.caps)
+	if err != nil {
+		return nil, err
+	}
+	files, err := engine.Render(chart, renderValues)
+	if err != nil {
+		return nil, err
+	}
+	for name := range files {
+		if path.Base(name) == "NOTES.txt" {
+			delete(files, name)
+		}
+	}
+
+	_, manifests, err := releaseutil.SortManifests(files, p.caps.APIVersions, releaseutil.InstallOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &types2.ChartPreview{
+		ChartName:        chart.Metadata.Name,
+		Version:          chart.Metadata.Version,
+		ReleaseName:      releaseName,
+		ReleaseNamespace: releaseNamespace,
+	}
+
+	previous := map[catalog.ReleaseResource]*unstructured.Unstructured{}
+	if current != nil {
+		previous, err = p.releaseObjects(releaseName, releaseNamespace, current.Spec.Version)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rendered := map[catalog.ReleaseResource]bool{}
+	for _, manifest := range manifests {
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(manifest.Content), &obj.Object); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", manifest.Name, err)
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+
+		resource := p.previewResource(obj, previous[objectKey(obj)], releaseName, releaseNamespace)
+		rendered[catalog.ReleaseResource{
+			APIVersion: resource.APIVersion,
+			Kind:       resource.Kind,
+			Name:       resource.Name,
+			Namespace:  resource.Namespace,
+		}] = true
+		result.Resources = append(result.Resources, resource)
+	}
+
+	if current != nil {
+		for _, resource := range current.Spec.Resources {
+			if rendered[resource] {
+				continue
+			}
+			result.Resources = append(result.Resources, types2.ResourcePreview{
+				APIVersion: resource.APIVersion,
+				Kind:       resource.Kind,
+				Namespace:  resource.Namespace,
+				Name:       resource.Name,
+				Change:     ChangeDelete,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// releaseObjects returns the objects of the manifest of a revision of the installed release.
+func (p *previewer) releaseObjects(releaseName, releaseNamespace string, revision int) (map[catalog.ReleaseResource]*unstructured.Unstructured, error) {
+	release, err := storage.Init(driver.NewSecrets(p.k8s.CoreV1().Secrets(releaseNamespace))).Get(releaseName, revision)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revision %d of release %s/%s: %w", revision, releaseNamespace, releaseName, err)
+	}
+
+	objs := map[catalog.ReleaseResource]*unstructured.Unstructured{}
+	for name, manifest := range releaseutil.SplitManifests(release.Manifest) {
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(manifest), &obj.Object); err != nil {
+			return nil, fmt.Errorf("failed to parse %s of release %s/%s: %w", name, releaseNamespace, releaseName, err)
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		objs[objectKey(obj)] = obj
+	}
+	return objs, nil
+}
+
+func objectKey(obj *unstructured.Unstructured) catalog.ReleaseResource {
+	return catalog.ReleaseResource{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Name:       obj.GetName(),
+		Namespace:  obj.GetNamespace(),
+	}
+}
+
+// previewResource validates the object with a server side dry run apply, which runs schema validation
+// and admission, and diffs the object the server would persist against the live one. Previous is the
+// object as the installed release rendered it, fields it has that the chart no longer renders are removed
+// by helm on upgrade but are kept by an apply under another field manager.
+func (p *previewer) previewResource(obj, previous *unstructured.Unstructured, releaseName, releaseNamespace string) types2.ResourcePreview {
+	gvk := obj.GroupVersionKind()
+	result := types2.ResourcePreview{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Name:       obj.GetName(),
+	}
+
+	mapping, err := p.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	var client dynamic.ResourceInterface = p.dynamic.Resource(mapping.Resource)
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace(releaseNamespace)
+		}
+		if previous != nil && previous.GetNamespace() == "" {
+			previous.SetNamespace(releaseNamespace)
+		}
+		result.Namespace = obj.GetNamespace()
+		client = p.dynamic.Resource(mapping.Resource).Namespace(obj.GetNamespace())
+	}
+	setHelmMetadata(obj, releaseName, releaseNamespace)
+
+	var removed map[string]interface{}
+	if previous != nil {
+		previous = previous.DeepCopy()
+		setHelmMetadata(previous, releaseName, releaseNamespace)
+		removed = removedFields(previous.Object, obj.Object)
+	}
+
+	live, err := client.Get(p.ctx, obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		live = nil
+		result.Change = ChangeCreate
+	} else if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	force := true
+	desired, err := client.Patch(p.ctx, obj.GetName(), k8stypes.ApplyPatchType, data, metav1.PatchOptions{
+		DryRun:       []string{metav1.DryRunAll},
+		FieldManager: previewFieldManager,
+		Force:        &force,
+	})
+	if err != nil {
+		if result.Change == "" {
+			result.Change = ChangeUpdate
+		}
+		result.Error = err.Error()
+		return result
+	}
+
+	from := map[string]interface{}{}
+	if live != nil {
+		from = normalize(live)
+	}
+	diff, err := diffObjects(from, normalize(desired), removed)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	switch {
+	case live == nil:
+		result.Diff = diff
+	case diff == "{}":
+		result.Change = ChangeUnchanged
+	default:
+		result.Change = ChangeUpdate
+		result.Diff = diff
+	}
+	return result
+}
+
+// setHelmMetadata adds the ownership metadata helm sets on every resource of a release, so that it
+// does not show up as a change.
+func setHelmMetadata(obj *unstructured.Unstructured, releaseName, releaseNamespace string) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels["app.kubernetes.io/managed-by"] = "Helm"
+	obj.SetLabels(labels)
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations["meta.helm.sh/release-name"] = releaseName
+	annotations["meta.helm.sh/release-namespace"] = releaseNamespace
+	obj.SetAnnotations(annotations)
+}
+
+// normalize drops the fields the server manages and that would otherwise show up in every diff.
+func normalize(obj *unstructured.Unstructured) map[string]interface{} {
+	obj = obj.DeepCopy()
+	unstructured.RemoveNestedField(obj.Object, "status")
+	for _, field := range []string{"managedFields", "resourceVersion", "generation", "uid", "creationTimestamp", "selfLink"} {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+	return obj.Object
+}
+
+// removedFields returns a merge patch deleting the fields of the previous object that are not in the
+// rendered one. Lists are replaced as a whole and are not descended into.
+func removedFields(previous, rendered map[string]interface{}) map[string]interface{} {
+	removed := map[string]interface{}{}
+	for key, value := range previous {
+		renderedValue, ok := rendered[key]
+		if !ok {
+			removed[key] = nil
+			continue
+		}
+		previousMap, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		renderedMap, ok := renderedValue.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if nested := removedFields(previousMap, renderedMap); len(nested) > 0 {
+			removed[key] = nested
+		}
+	}
+	return removed
+}
+
+// diffObjects returns the merge patch from the live object to the desired one, after deleting the
+// removed fields from the desired object.
+func diffObjects(live, desired, removed map[string]interface{}) (string, error) {
+	if len(removed) > 0 {
+		desiredData, err := json.Marshal(desired)
+		if err != nil {
+			return "", err
+		}
+		removedData, err := json.Marshal(removed)
+		if err != nil {
+			return "", err
+		}
+		desiredData, err = jsonpatch.MergePatch(desiredData, removedData)
+		if err != nil {
+			return "", err
+		}
+		desired = map[string]interface{}{}
+		if err := json.Unmarshal(desiredData, &desired); err != nil {
+			return "", err
+		}
+	}
+	return mergePatch(live, desired)
+}
+
+func mergePatch(from, to map[string]interface{}) (string, error) {
+	fromData, err := json.Marshal(from)
+	if err != nil {
+		return "", err
+	}
+	toData, err := json.Marshal(to)
+	if err != nil {
+		return "", err
+	}
+	patch, err := jsonpatch.CreateMergePatch(fromData, toData)
+	if err != nil {
+		return "", err
+	}
+	return string(patch), nil
+}
@@ -0,0 +1,106 @@
+package helmop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemovedFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous map[string]interface{}
+		rendered map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{
+			name:     "nothing removed",
+			previous: map[string]interface{}{"data": map[string]interface{}{"a": "1"}},
+			rendered: map[string]interface{}{"data": map[string]interface{}{"a": "2", "b": "1"}},
+			expected: map[string]interface{}{},
+		},
+		{
+			name:     "top level field removed",
+			previous: map[string]interface{}{"data": map[string]interface{}{"a": "1"}, "immutable": true},
+			rendered: map[string]interface{}{"data": map[string]interface{}{"a": "1"}},
+			expected: map[string]interface{}{"immutable": nil},
+		},
+		{
+			name: "nested field removed",
+			previous: map[string]interface{}{"spec": map[string]interface{}{
+				"replicas": 1,
+				"template": map[string]interface{}{"metadata": map[string]interface{}{"labels": map[string]interface{}{"a": "1", "b": "2"}}},
+			}},
+			rendered: map[string]interface{}{"spec": map[string]interface{}{
+				"template": map[string]interface{}{"metadata": map[string]interface{}{"labels": map[string]interface{}{"a": "1"}}},
+			}},
+			expected: map[string]interface{}{"spec": map[string]interface{}{
+				"replicas": nil,
+				"template": map[string]interface{}{"metadata": map[string]interface{}{"labels": map[string]interface{}{"b": nil}}},
+			}},
+		},
+		{
+			name:     "list is not descended into",
+			previous: map[string]interface{}{"args": []interface{}{"--a", "--b"}},
+			rendered: map[string]interface{}{"args": []interface{}{"--a"}},
+			expected: map[string]interface{}{},
+		},
+		{
+			name:     "map replaced by a scalar",
+			previous: map[string]interface{}{"value": map[string]interface{}{"a": "1"}},
+			rendered: map[string]interface{}{"value": "a"},
+			expected: map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, removedFields(tt.previous, tt.rendered))
+		})
+	}
+}
+
+func TestDiffObjects(t *testing.T) {
+	tests := []struct {
+		name     string
+		live     map[string]interface{}
+		desired  map[string]interface{}
+		removed  map[string]interface{}
+		expected string
+	}{
+		{
+			name:     "unchanged",
+			live:     map[string]interface{}{"data": map[string]interface{}{"a": "1"}},
+			desired:  map[string]interface{}{"data": map[string]interface{}{"a": "1"}},
+			expected: `{}`,
+		},
+		{
+			name:     "changed",
+			live:     map[string]interface{}{"data": map[string]interface{}{"a": "1"}},
+			desired:  map[string]interface{}{"data": map[string]interface{}{"a": "2"}},
+			expected: `{"data":{"a":"2"}}`,
+		},
+		{
+			name:     "field removed from the chart is kept by the apply",
+			live:     map[string]interface{}{"data": map[string]interface{}{"a": "1", "b": "2"}},
+			desired:  map[string]interface{}{"data": map[string]interface{}{"a": "1", "b": "2"}},
+			removed:  map[string]interface{}{"data": map[string]interface{}{"b": nil}},
+			expected: `{"data":{"b":null}}`,
+		},
+		{
+			name:     "removed field already gone",
+			live:     map[string]interface{}{"data": map[string]interface{}{"a": "1"}},
+			desired:  map[string]interface{}{"data": map[string]interface{}{"a": "2"}},
+			removed:  map[string]interface{}{"data": map[string]interface{}{"b": nil}},
+			expected: `{"data":{"a":"2"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := diffObjects(tt.live, tt.desired, tt.removed)
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.expected, diff)
+		})
+	}
+}
@@ -0,0 +1,395 @@
+package oci
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	helmhttp "github.com/rancher/rancher/pkg/catalogv2/http"
+	"github.com/rancher/wrangler/pkg/schemas/validation"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	manifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	pageSize          = 1000
+)
+
+// client is a minimal client of the OCI distribution API, supporting basic and bearer token authentication.
+type client struct {
+	httpClient *http.Client
+	host       string
+	username   string
+	password   string
+
+	lock   sync.Mutex
+	tokens map[string]string
+	// basic is set once the registry answered with a basic challenge, credentials are not sent before
+	basic bool
+}
+
+type descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type manifest struct {
+	MediaType   string            `json:"mediaType,omitempty"`
+	Config      descriptor        `json:"config"`
+	Layers      []descriptor      `json:"layers"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+func newClient(secret *corev1.Secret, host string, caBundle []byte, insecureSkipTLSVerify bool) (*client, error) {
+	c := &client{
+		host:   host,
+		tokens: map[string]string{},
+	}
+
+	// Only client certificates are left to the helm client. Credentials are exchanged for bearer tokens, or
+	// sent as basic auth once the registry asks for it, and are not sent to registries that did not ask.
+	var tlsSecret *corev1.Secret
+	if secret != nil {
+		switch secret.Type {
+		case corev1.SecretTypeBasicAuth:
+			c.username = string(secret.Data[corev1.BasicAuthUsernameKey])
+			c.password = string(secret.Data[corev1.BasicAuthPasswordKey])
+		case corev1.SecretTypeDockerConfigJson:
+			username, password, err := dockerConfigCredentials(secret.Data[corev1.DockerConfigJsonKey], host)
+			if err != nil {
+				return nil, err
+			}
+			c.username, c.password = username, password
+		case corev1.SecretTypeTLS:
+			tlsSecret = secret
+		}
+	}
+
+	httpClient, err := helmhttp.HelmClient(tlsSecret, caBundle, insecureSkipTLSVerify)
+	if err != nil {
+		return nil, err
+	}
+	c.httpClient = httpClient
+	return c, nil
+}
+
+func dockerConfigCredentials(data []byte, host string) (string, string, error) {
+	config := struct {
+		Auths map[string]struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+			Auth     string `json:"auth"`
+		} `json:"auths"`
+	}{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return "", "", fmt.Errorf("failed to parse docker config: %w", err)
+	}
+
+	for registry, auth := range config.Auths {
+		if registry != host && strings.TrimPrefix(strings.TrimPrefix(registry, "https://"), "http://") != host {
+			continue
+		}
+		if auth.Auth == "" {
+			return auth.Username, auth.Password, nil
+		}
+		decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to decode docker config auth for %s: %w", registry, err)
+		}
+		parts := strings.SplitN(string(decoded), ":", 2)
+		if len(parts) != 2 {
+			return "", "", fmt.Errorf("invalid docker config auth for %s", registry)
+		}
+		return parts[0], parts[1], nil
+	}
+	return "", "", nil
+}
+
+func (c *client) close() {
+	c.httpClient.CloseIdleConnections()
+}
+
+// repositories lists the repositories of the registry below the prefix. Registries not exposing
+// the catalog API return an error.
+func (c *client) repositories(prefix string) ([]string, error) {
+	var (
+		result []string
+		next   = fmt.Sprintf("/v2/_catalog?n=%d", pageSize)
+	)
+	for next != "" {
+		page := struct {
+			Repositories []string `json:"repositories"`
+		}{}
+		link, err := c.getJSON(next, "registry:catalog:*", "", &page)
+		if err != nil {
+			return nil, err
+		}
+		for _, repository := range page.Repositories {
+			if prefix == "" || strings.HasPrefix(repository, prefix+"/") {
+				result = append(result, repository)
+			}
+		}
+		next = link
+	}
+	return result, nil
+}
+
+func (c *client) tags(repository string) ([]string, error) {
+	var (
+		result []string
+		next   = fmt.Sprintf("/v2/%s/tags/list?n=%d", repository, pageSize)
+	)
+	for next != "" {
+		page := struct {
+			Tags []string `json:"tags"`
+		}{}
+		link, err := c.getJSON(next, pullScope(repository), "", &page)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, page.Tags...)
+		next = link
+	}
+	return result, nil
+}
+
+func (c *client) manifest(repository, reference string) (*manifest, error) {
+	m, _, err := c.manifestWithDigest(repository, reference)
+	return m, err
+}
+
+// manifestWithDigest also returns the digest of the manifest, which is what signatures refer to.
+func (c *client) manifestWithDigest(repository, reference string) (*manifest, string, error) {
+	path := fmt.Sprintf("/v2/%s/manifests/%s", repository, reference)
+	resp, err := c.get(path, pullScope(repository), manifestMediaType)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	m := &manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, "", fmt.Errorf("failed to parse response from %s%s: %w", c.host, path, err)
+	}
+	sum := sha256.Sum256(data)
+	return m, "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+func (c *client) blob(repository string, desc descriptor) ([]byte, error) {
+	resp, err := c.get(fmt.Sprintf("/v2/%s/blobs/%s", repository, desc.Digest), pullScope(repository), "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, desc.Size+1))
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyDigest(desc, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// getJSON decodes the response into target and returns the path of the next page, if any.
+func (c *client) getJSON(path, scope, accept string, target interface{}) (string, error) {
+	resp, err := c.get(path, scope, accept)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return "", fmt.Errorf("failed to parse response from %s%s: %w", c.host, path, err)
+	}
+	return nextPage(resp.Header.Get("Link")), nil
+}
+
+func (c *client) get(path, scope, accept string) (*http.Response, error) {
+	resp, err := c.do(path, scope, accept)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		drain(resp)
+		if err := c.authenticate(challenge, scope); err != nil {
+			return nil, err
+		}
+		if resp, err = c.do(path, scope, accept); err != nil {
+			return nil, err
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		drain(resp)
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%s%s: %w", c.host, path, validation.NotFound)
+		}
+		return nil, validation.ErrorCode{
+			Status: resp.StatusCode,
+		}
+	}
+	return resp, nil
+}
+
+func (c *client) do(path, scope, accept string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, "https://"+c.host+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	c.lock.Lock()
+	token, basic := c.tokens[scope], c.basic
+	c.lock.Unlock()
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	} else if basic {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	return c.httpClient.Do(req)
+}
+
+// authenticate answers a bearer challenge by requesting a token for the scope from the realm of the challenge.
+// After a basic challenge the credentials are sent with every request.
+func (c *client) authenticate(challenge, scope string) error {
+	scheme, params := parseChallenge(challenge)
+	if strings.EqualFold(scheme, "basic") {
+		if c.username == "" && c.password == "" {
+			return validation.Unauthorized
+		}
+		c.lock.Lock()
+		c.basic = true
+		c.lock.Unlock()
+		return nil
+	}
+	if !strings.EqualFold(scheme, "bearer") || params["realm"] == "" {
+		return validation.Unauthorized
+	}
+
+	realm, err := url.Parse(params["realm"])
+	if err != nil {
+		return err
+	}
+	query := realm.Query()
+	if params["service"] != "" {
+		query.Set("service", params["service"])
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return err
+	}
+	if c.username != "" || c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return validation.Unauthorized
+	}
+
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return err
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	if token.Token == "" {
+		return validation.Unauthorized
+	}
+
+	c.lock.Lock()
+	c.tokens[scope] = token.Token
+	c.lock.Unlock()
+	return nil
+}
+
+// parseChallenge parses a WWW-Authenticate header such as
+// Bearer realm="https://auth.example.com/token",service="registry.example.com"
+func parseChallenge(header string) (string, map[string]string) {
+	params := map[string]string{}
+	parts := strings.SplitN(strings.TrimSpace(header), " ", 2)
+	if len(parts) < 2 {
+		return parts[0], params
+	}
+
+	rest := parts[1]
+	for rest != "" {
+		eq := strings.Index(rest, "=")
+		if eq < 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(rest[:eq]))
+		rest = strings.TrimSpace(rest[eq+1:])
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else if comma := strings.Index(rest, ","); comma >= 0 {
+			value, rest = rest[:comma], rest[comma:]
+		} else {
+			value, rest = rest, ""
+		}
+		params[key] = value
+		rest = strings.TrimPrefix(strings.TrimSpace(rest), ",")
+	}
+	return parts[0], params
+}
+
+// nextPage returns the path of a Link header such as </v2/_catalog?last=a&n=100>; rel="next"
+func nextPage(link string) string {
+	start, end := strings.Index(link, "<"), strings.Index(link, ">")
+	if start < 0 || end < start || !strings.Contains(link[end:], `rel="next"`) {
+		return ""
+	}
+	u, err := url.Parse(link[start+1 : end])
+	if err != nil {
+		return ""
+	}
+	return u.RequestURI()
+}
+
+func pullScope(repository string) string {
+	return "repository:" + repository + ":pull"
+}
+
+func drain(resp *http.Response) {
+	_, _ = ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+}
@@ -0,0 +1,218 @@
+package oci
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/rancher/wrangler/pkg/schemas/validation"
+	"github.com/sirupsen/logrus"
+	helmchart "helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/repo"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	Scheme = "oci"
+
+	configMediaType           = "application/vnd.cncf.helm.config.v1+json"
+	chartLayerMediaType       = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+	legacyChartLayerMediaType = "application/tar+gzip"
+	createdAnnotation         = "org.opencontainers.image.created"
+)
+
+// IsOCI reports whether the repo URL points to an OCI registry.
+func IsOCI(repoURL string) bool {
+	u, err := url.Parse(repoURL)
+	return err == nil && u.Scheme == Scheme
+}
+
+// DownloadIndex builds an index of the charts stored in the registry below the path of the repo URL. Every
+// repository is a chart and every tag that is a semantic version one of its versions. If the registry does not
+// expose its catalog, the repo URL is expected to point to a single chart repository.
+func DownloadIndex(secret *corev1.Secret, repoURL string, caBundle []byte, insecureSkipTLSVerify bool) (*repo.IndexFile, error) {
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return nil, err
+	}
+	prefix := strings.Trim(u.Path, "/")
+
+	c, err := newClient(secret, u.Host, caBundle, insecureSkipTLSVerify)
+	if err != nil {
+		return nil, err
+	}
+	defer c.close()
+
+	logrus.Infof("Building repo index from %s", repoURL)
+
+	repositories, err := c.repositories(prefix)
+	if err != nil || len(repositories) == 0 {
+		if prefix == "" {
+			return nil, fmt.Errorf("failed to list repositories of %s: %v", u.Host, err)
+		}
+		logrus.Debugf("Listing repositories of %s failed, using %s as chart repository: %v", u.Host, prefix, err)
+		repositories = []string{prefix}
+	}
+
+	index := repo.NewIndexFile()
+	for _, repository := range repositories {
+		versions, err := c.chartVersions(u.Host, repository)
+		if err != nil {
+			return nil, fmt.Errorf("failed to index %s/%s: %w", u.Host, repository, err)
+		}
+		for _, version := range versions {
+			index.Entries[version.Name] = append(index.Entries[version.Name], version)
+		}
+	}
+
+	return index, nil
+}
+
+func (c *client) chartVersions(host, repository string) ([]*repo.ChartVersion, error) {
+	tags, err := c.tags(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*repo.ChartVersion
+	for _, tag := range tags {
+		// helm stores the build metadata of a version as _ since + is not allowed in tags
+		if _, err := semver.NewVersion(strings.ReplaceAll(tag, "_", "+")); err != nil {
+			continue
+		}
+
+		m, err := c.manifest(repository, tag)
+		if err != nil {
+			return nil, err
+		}
+		if m.Config.MediaType != configMediaType {
+			continue
+		}
+		layer, ok := chartLayer(m)
+		if !ok {
+			continue
+		}
+
+		config, err := c.blob(repository, m.Config)
+		if err != nil {
+			return nil, err
+		}
+		metadata := &helmchart.Metadata{}
+		if err := json.Unmarshal(config, metadata); err != nil {
+			return nil, fmt.Errorf("failed to parse chart metadata of %s:%s: %w", repository, tag, err)
+		}
+		if metadata.Name == "" {
+			metadata.Name = path.Base(repository)
+		}
+
+		version := &repo.ChartVersion{
+			Metadata: metadata,
+			URLs:     []string{fmt.Sprintf("%s://%s/%s:%s", Scheme, host, repository, tag)},
+			Digest:   strings.TrimPrefix(layer.Digest, "sha256:"),
+		}
+		if created, err := time.Parse(time.RFC3339, m.Annotations[createdAnnotation]); err == nil {
+			version.Created = created
+		}
+		result = append(result, version)
+	}
+
+	return result, nil
+}
+
+// Chart pulls the chart layer of the chart version. The credentials of the repo are only ever sent to
+// the registry of the repo URL.
+func Chart(secret *corev1.Secret, repoURL string, caBundle []byte, insecureSkipTLSVerify bool, chart *repo.ChartVersion) (io.ReadCloser, error) {
+	if len(chart.URLs) == 0 {
+		return nil, fmt.Errorf("failed to find chartName %s version %s: %w", chart.Name, chart.Version, validation.NotFound)
+	}
+
+	host, repository, tag, err := chartReference(repoURL, chart)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := newClient(secret, host, caBundle, insecureSkipTLSVerify)
+	if err != nil {
+		return nil, err
+	}
+	defer c.close()
+
+	m, err := c.manifest(repository, tag)
+	if err != nil {
+		return nil, err
+	}
+	layer, ok := chartLayer(m)
+	if !ok {
+		return nil, fmt.Errorf("%s has no chart layer: %w", chart.URLs[0], validation.NotFound)
+	}
+
+	data, err := c.blob(repository, layer)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+func chartLayer(m *manifest) (descriptor, bool) {
+	for _, layer := range m.Layers {
+		if layer.MediaType == chartLayerMediaType || layer.MediaType == legacyChartLayerMediaType {
+			return layer, true
+		}
+	}
+	return descriptor{}, false
+}
+
+// chartReference returns the location of the chart version, which must be stored in the registry of the repo.
+func chartReference(repoURL string, chart *repo.ChartVersion) (string, string, string, error) {
+	host, repository, tag, err := parseReference(chart.URLs[0])
+	if err != nil {
+		return "", "", "", err
+	}
+	if u, err := url.Parse(repoURL); err != nil {
+		return "", "", "", err
+	} else if u.Host != host {
+		return "", "", "", fmt.Errorf("chart %s is not stored in registry %s", chart.URLs[0], u.Host)
+	}
+	return host, repository, tag, nil
+}
+
+// parseReference splits oci://host/repository:tag.
+func parseReference(ref string) (string, string, string, error) {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return "", "", "", err
+	}
+	if u.Scheme != Scheme {
+		return "", "", "", fmt.Errorf("invalid chart reference %s", ref)
+	}
+
+	repository := strings.Trim(u.Path, "/")
+	i := strings.LastIndex(repository, ":")
+	if i < 0 || strings.Contains(repository[i:], "/") {
+		return "", "", "", fmt.Errorf("chart reference %s has no tag", ref)
+	}
+	return u.Host, repository[:i], repository[i+1:], nil
+}
+
+func verifyDigest(desc descriptor, data []byte) error {
+	if int64(len(data)) != desc.Size {
+		return fmt.Errorf("size of %s does not match, expected %d bytes, got %d", desc.Digest, desc.Size, len(data))
+	}
+	if !strings.HasPrefix(desc.Digest, "sha256:") {
+		return fmt.Errorf("unsupported digest %s", desc.Digest)
+	}
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != strings.TrimPrefix(desc.Digest, "sha256:") {
+		return fmt.Errorf("digest of %s does not match", desc.Digest)
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package oci
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/repo"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type testRegistry struct {
+	*httptest.Server
+	blobs     map[string][]byte
+	manifests map[string][]byte
+	tags      map[string][]string
+	catalog   bool
+	// basic makes the registry ask for basic auth instead of bearer tokens
+	basic bool
+	// leaked counts the requests that sent credentials to a registry asking for bearer tokens
+	leaked int
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func newTestRegistry(t *testing.T, catalog bool) *testRegistry {
+	r := &testRegistry{
+		blobs:     map[string][]byte{},
+		manifests: map[string][]byte{},
+		tags:      map[string][]string{},
+		catalog:   catalog,
+	}
+	r.Server = httptest.NewTLSServer(http.HandlerFunc(r.serve))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *testRegistry) host() string {
+	return strings.TrimPrefix(r.URL, "https://")
+}
+
+func (r *testRegistry) push(t *testing.T, repository, tag string, metadata map[string]interface{}, chart []byte) {
+	config, err := json.Marshal(metadata)
+	require.NoError(t, err)
+	r.blobs[digest(config)] = config
+	r.blobs[digest(chart)] = chart
+
+	m, err := json.Marshal(manifest{
+		MediaType: manifestMediaType,
+		Config:    descriptor{MediaType: configMediaType, Digest: digest(config), Size: int64(len(config))},
+		Layers: []descriptor{
+			{MediaType: chartLayerMediaType, Digest: digest(chart), Size: int64(len(chart))},
+		},
+	})
+	require.NoError(t, err)
+	r.manifests[repository+":"+tag] = m
+	r.tags[repository] = append(r.tags[repository], tag)
+}
+
+func (r *testRegistry) serve(rw http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/token" {
+		user, password, ok := req.BasicAuth()
+		if !ok || user != "user" || password != "secret" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(rw).Encode(map[string]string{"token": "token-" + req.URL.Query().Get("scope")})
+		return
+	}
+
+	path := strings.TrimPrefix(req.URL.Path, "/v2/")
+	scope := "registry:catalog:*"
+	if path != "_catalog" {
+		for _, sep := range []string{"/tags/", "/manifests/", "/blobs/"} {
+			if i := strings.Index(path, sep); i >= 0 {
+				scope = pullScope(path[:i])
+			}
+		}
+	}
+	user, password, ok := req.BasicAuth()
+	if r.basic {
+		if !ok || user != "user" || password != "secret" {
+			rw.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	} else {
+		if ok {
+			r.leaked++
+		}
+		if req.Header.Get("Authorization") != "Bearer token-"+scope {
+			rw.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, r.URL))
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	}
+
+	switch {
+	case path == "_catalog" && r.catalog:
+		var repositories []string
+		for repository := range r.tags {
+			repositories = append(repositories, repository)
+		}
+		_ = json.NewEncoder(rw).Encode(map[string][]string{"repositories": repositories})
+	case strings.HasSuffix(path, "/tags/list"):
+		_ = json.NewEncoder(rw).Encode(map[string][]string{"tags": r.tags[strings.TrimSuffix(path, "/tags/list")]})
+	case strings.Contains(path, "/manifests/"):
+		m, ok := r.manifests[strings.Replace(path, "/manifests/", ":", 1)]
+		if !ok {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = rw.Write(m)
+	case strings.Contains(path, "/blobs/"):
+		blob, ok := r.blobs[path[strings.Index(path, "/blobs/")+len("/blobs/"):]]
+		if !ok {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = rw.Write(blob)
+	default:
+		rw.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func basicAuthSecret() *corev1.Secret {
+	return &corev1.Secret{
+		Type: corev1.SecretTypeBasicAuth,
+		Data: map[string][]byte{
+			corev1.BasicAuthUsernameKey: []byte("user"),
+			corev1.BasicAuthPasswordKey: []byte("secret"),
+		},
+	}
+}
+
+func TestDownloadIndexAndChart(t *testing.T) {
+	registry := newTestRegistry(t, true)
+	registry.push(t, "charts/app", "1.0.0", map[string]interface{}{"name": "app", "version": "1.0.0", "apiVersion": "v2"}, []byte("app-1.0.0"))
+	registry.push(t, "charts/app", "1.1.0_build.1", map[string]interface{}{"name": "app", "version": "1.1.0+build.1", "apiVersion": "v2"}, []byte("app-1.1.0"))
+	registry.push(t, "charts/app", "latest", map[string]interface{}{"name": "app", "version": "1.1.0", "apiVersion": "v2"}, []byte("app-latest"))
+	registry.push(t, "other/tool", "2.0.0", map[string]interface{}{"name": "tool", "version": "2.0.0", "apiVersion": "v2"}, []byte("tool"))
+
+	repoURL := "oci://" + registry.host() + "/charts"
+	index, err := DownloadIndex(basicAuthSecret(), repoURL, nil, true)
+	require.NoError(t, err)
+
+	assert.Len(t, index.Entries, 1, "expected only the repositories below the repo path to be indexed")
+	versions := index.Entries["app"]
+	require.Len(t, versions, 2, "expected tags that are not versions to be skipped")
+	assert.Equal(t, "oci://"+registry.host()+"/charts/app:1.0.0", versions[0].URLs[0])
+	assert.Equal(t, "1.1.0+build.1", versions[1].Version)
+
+	chart, err := Chart(basicAuthSecret(), repoURL, nil, true, versions[1])
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(chart)
+	require.NoError(t, err)
+	assert.Equal(t, "app-1.1.0", string(data))
+
+	_, err = DownloadIndex(nil, repoURL, nil, true)
+	assert.Error(t, err, "expected the registry to refuse anonymous access")
+	assert.Zero(t, registry.leaked, "expected credentials to only be sent to the token realm")
+}
+
+func TestDownloadIndexBasicAuth(t *testing.T) {
+	registry := newTestRegistry(t, true)
+	registry.basic = true
+	registry.push(t, "charts/app", "1.0.0", map[string]interface{}{"name": "app", "version": "1.0.0", "apiVersion": "v2"}, []byte("app-1.0.0"))
+
+	repoURL := "oci://" + registry.host() + "/charts"
+	index, err := DownloadIndex(basicAuthSecret(), repoURL, nil, true)
+	require.NoError(t, err)
+	assert.Len(t, index.Entries["app"], 1)
+
+	_, err = DownloadIndex(nil, repoURL, nil, true)
+	assert.Error(t, err, "expected the registry to refuse anonymous access")
+}
+
+func TestDownloadIndexWithoutCatalog(t *testing.T) {
+	registry := newTestRegistry(t, false)
+	registry.push(t, "charts/app", "1.0.0", map[string]interface{}{"name": "app", "version": "1.0.0", "apiVersion": "v2"}, []byte("app-1.0.0"))
+
+	index, err := DownloadIndex(basicAuthSecret(), "oci://"+registry.host()+"/charts/app", nil, true)
+	require.NoError(t, err)
+	assert.Len(t, index.Entries["app"], 1)
+}
+
+func TestChartRejectsOtherRegistries(t *testing.T) {
+	version := &repo.ChartVersion{URLs: []string{"oci://evil.example.com/charts/app:1.0.0"}}
+	_, err := Chart(basicAuthSecret(), "oci://registry.example.com/charts", nil, true, version)
+	assert.Error(t, err)
+}
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:a/b:pull"`)
+	assert.Equal(t, "Bearer", scheme)
+	assert.Equal(t, map[string]string{
+		"realm":   "https://auth.example.com/token",
+		"service": "registry.example.com",
+		"scope":   "repository:a/b:pull",
+	}, params)
+}
+
+func TestNextPage(t *testing.T) {
+	assert.Equal(t, "/v2/_catalog?last=b&n=2", nextPage(`</v2/_catalog?last=b&n=2>; rel="next"`))
+	assert.Equal(t, "", nextPage(""))
+}
@@ -0,0 +1,103 @@
+package oci
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/rancher/rancher/pkg/catalogv2/verify"
+	"github.com/rancher/wrangler/pkg/schemas/validation"
+	"helm.sh/helm/v3/pkg/repo"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	provenanceLayerMediaType  = "application/vnd.cncf.helm.chart.provenance.v1.prov"
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+)
+
+// Verify checks the signature of a chart archive pulled from the registry. Provenance files are expected
+// as a layer of the chart manifest and cosign signatures in the signature image cosign pushes next to it.
+func Verify(secret *corev1.Secret, repoURL string, caBundle []byte, insecureSkipTLSVerify bool, chart *repo.ChartVersion, archive []byte, verifier *verify.Verifier) error {
+	if len(chart.URLs) == 0 {
+		return fmt.Errorf("failed to find chartName %s version %s: %w", chart.Name, chart.Version, validation.NotFound)
+	}
+
+	host, repository, tag, err := chartReference(repoURL, chart)
+	if err != nil {
+		return err
+	}
+
+	c, err := newClient(secret, host, caBundle, insecureSkipTLSVerify)
+	if err != nil {
+		return err
+	}
+	defer c.close()
+
+	m, digest, err := c.manifestWithDigest(repository, tag)
+	if err != nil {
+		return err
+	}
+	layer, ok := chartLayer(m)
+	if !ok {
+		return fmt.Errorf("%s has no chart layer: %w", chart.URLs[0], validation.NotFound)
+	}
+	// the signatures cover the manifest, so the archive has to be the one it references
+	if err := verifyDigest(layer, archive); err != nil {
+		return fmt.Errorf("chart archive does not match %s: %w", chart.URLs[0], err)
+	}
+
+	if verifier.Type == verify.Provenance {
+		for _, layer := range m.Layers {
+			if layer.MediaType != provenanceLayerMediaType {
+				continue
+			}
+			prov, err := c.blob(repository, layer)
+			if err != nil {
+				return err
+			}
+			return verifier.VerifyProvenance(chart, archive, prov)
+		}
+		return fmt.Errorf("%s has no provenance layer", chart.URLs[0])
+	}
+
+	return c.verifyCosign(repository, digest, verifier)
+}
+
+// verifyCosign looks for a simple signing payload signed with the key of the verifier that refers to the manifest digest.
+func (c *client) verifyCosign(repository, digest string, verifier *verify.Verifier) error {
+	signatures, err := c.manifest(repository, strings.Replace(digest, ":", "-", 1)+".sig")
+	if err != nil {
+		return fmt.Errorf("failed to find cosign signature of %s: %w", digest, err)
+	}
+
+	for _, layer := range signatures.Layers {
+		signature := layer.Annotations[cosignSignatureAnnotation]
+		if signature == "" {
+			continue
+		}
+		payload, err := c.blob(repository, layer)
+		if err != nil {
+			return err
+		}
+		if err := verifier.VerifySignature(payload, []byte(signature)); err != nil {
+			continue
+		}
+
+		simpleSigning := struct {
+			Critical struct {
+				Image struct {
+					DockerManifestDigest string `json:"docker-manifest-digest"`
+				} `json:"image"`
+			} `json:"critical"`
+		}{}
+		if err := json.Unmarshal(payload, &simpleSigning); err != nil {
+			continue
+		}
+		if simpleSigning.Critical.Image.DockerManifestDigest == digest {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("no valid cosign signature of %s", digest)
+}
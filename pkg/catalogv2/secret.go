@@ -17,3 +17,16 @@ func GetSecret(secrets corev1controllers.SecretCache, repoSpec *v1.RepoSpec, rep
 
 	return secrets.Get(ns, repoSpec.ClientSecret.Name)
 }
+
+// GetKeySecret returns the secret holding the public keys chart signatures of the repo are verified with.
+func GetKeySecret(secrets corev1controllers.SecretCache, repoSpec *v1.RepoSpec, repoNamespace string) (*corev1.Secret, error) {
+	if repoSpec.Verification == nil || repoSpec.Verification.KeySecret == nil {
+		return nil, nil
+	}
+	ns := repoSpec.Verification.KeySecret.Namespace
+	if repoNamespace != "" {
+		ns = repoNamespace
+	}
+
+	return secrets.Get(ns, repoSpec.Verification.KeySecret.Name)
+}
@@ -0,0 +1,170 @@
+package verify
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	v1 "github.com/rancher/rancher/pkg/apis/catalog.cattle.io/v1"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/clearsign"
+	helmchart "helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/repo"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	Provenance = "provenance"
+	Cosign     = "cosign"
+
+	KeyringKey         = "keyring"
+	CosignPublicKeyKey = "cosign.pub"
+
+	VerifiedAnnotation          = "catalog.cattle.io/verified"
+	VerificationErrorAnnotation = "catalog.cattle.io/verification-error"
+)
+
+// Verifier checks the signatures of chart archives against the keys configured on a repo.
+type Verifier struct {
+	Type      string
+	keyring   openpgp.EntityList
+	publicKey crypto.PublicKey
+}
+
+func New(verification *v1.ChartVerification, keys *corev1.Secret) (*Verifier, error) {
+	if keys == nil {
+		return nil, fmt.Errorf("chart verification requires a key secret")
+	}
+
+	v := &Verifier{
+		Type: verification.Type,
+	}
+	switch verification.Type {
+	case Provenance:
+		keyring, err := readKeyring(keys.Data[KeyringKey])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read keyring from secret %s/%s: %w", keys.Namespace, keys.Name, err)
+		}
+		v.keyring = keyring
+	case Cosign:
+		publicKey, err := readPublicKey(keys.Data[CosignPublicKeyKey])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read public key from secret %s/%s: %w", keys.Namespace, keys.Name, err)
+		}
+		v.publicKey = publicKey
+	default:
+		return nil, fmt.Errorf("unknown chart verification type %q", verification.Type)
+	}
+	return v, nil
+}
+
+func readKeyring(data []byte) (openpgp.EntityList, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("key %s is empty", KeyringKey)
+	}
+	if bytes.Contains(data, []byte("-----BEGIN PGP")) {
+		return openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+	}
+	return openpgp.ReadKeyRing(bytes.NewReader(data))
+}
+
+func readPublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key %s is not PEM encoded", CosignPublicKeyKey)
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+// SignatureSuffix is the suffix of the file stored next to a chart archive that holds its signature.
+func (v *Verifier) SignatureSuffix() string {
+	if v.Type == Provenance {
+		return ".prov"
+	}
+	return ".sig"
+}
+
+// Verify checks the signature file of a chart archive.
+func (v *Verifier) Verify(chart *repo.ChartVersion, archive, signature []byte) error {
+	if v.Type == Provenance {
+		return v.VerifyProvenance(chart, archive, signature)
+	}
+	return v.VerifySignature(archive, signature)
+}
+
+// VerifyProvenance checks a Helm provenance file, which is a PGP clear signed document holding the chart
+// metadata and the digest of the chart archive.
+func (v *Verifier) VerifyProvenance(chart *repo.ChartVersion, archive, prov []byte) error {
+	block, _ := clearsign.Decode(prov)
+	if block == nil {
+		return fmt.Errorf("provenance file is not PGP signed")
+	}
+	if _, err := openpgp.CheckDetachedSignature(v.keyring, bytes.NewReader(block.Bytes), block.ArmoredSignature.Body); err != nil {
+		return fmt.Errorf("invalid provenance signature: %w", err)
+	}
+
+	parts := bytes.SplitN(block.Plaintext, []byte("\n...\n"), 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid provenance file")
+	}
+
+	metadata := &helmchart.Metadata{}
+	if err := yaml.Unmarshal(parts[0], metadata); err != nil {
+		return fmt.Errorf("invalid chart metadata in provenance file: %w", err)
+	}
+	if metadata.Name != chart.Name || metadata.Version != chart.Version {
+		return fmt.Errorf("provenance file is for chart %s version %s", metadata.Name, metadata.Version)
+	}
+
+	sums := struct {
+		Files map[string]string `json:"files"`
+	}{}
+	if err := yaml.Unmarshal(parts[1], &sums); err != nil {
+		return fmt.Errorf("invalid file digests in provenance file: %w", err)
+	}
+	digest := sha256.Sum256(archive)
+	sum := "sha256:" + hex.EncodeToString(digest[:])
+	for _, fileSum := range sums.Files {
+		if fileSum == sum {
+			return nil
+		}
+	}
+	return fmt.Errorf("digest %s of the chart archive is not signed", sum)
+}
+
+// VerifySignature checks a signature of the payload as created by cosign sign-blob. The signature may be
+// base64 encoded.
+func (v *Verifier) VerifySignature(payload, signature []byte) error {
+	if decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature))); err == nil {
+		signature = decoded
+	}
+	digest := sha256.Sum256(payload)
+
+	switch key := v.publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if ecdsa.VerifyASN1(key, digest[:], signature) {
+			return nil
+		}
+	case *rsa.PublicKey:
+		if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil {
+			return nil
+		}
+	case ed25519.PublicKey:
+		if ed25519.Verify(key, payload, signature) {
+			return nil
+		}
+	default:
+		return fmt.Errorf("unsupported public key type %T", v.publicKey)
+	}
+	return fmt.Errorf("invalid signature")
+}
@@ -0,0 +1,92 @@
+package verify
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"testing"
+
+	v1 "github.com/rancher/rancher/pkg/apis/catalog.cattle.io/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/clearsign"
+	helmchart "helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/repo"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	archive = []byte("app-1.0.0.tgz")
+	chart   = &repo.ChartVersion{Metadata: &helmchart.Metadata{Name: "app", Version: "1.0.0"}}
+)
+
+func TestVerifySignature(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	verifier, err := New(&v1.ChartVerification{Type: Cosign}, &corev1.Secret{
+		Data: map[string][]byte{
+			CosignPublicKeyKey: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey}),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ".sig", verifier.SignatureSuffix())
+
+	digest := sha256.Sum256(archive)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+
+	assert.NoError(t, verifier.Verify(chart, archive, []byte(base64.StdEncoding.EncodeToString(signature))))
+	assert.Error(t, verifier.Verify(chart, []byte("tampered"), signature))
+}
+
+func TestVerifyProvenance(t *testing.T) {
+	entity, err := openpgp.NewEntity("charts", "", "charts@example.com", nil)
+	require.NoError(t, err)
+	keyring := &bytes.Buffer{}
+	require.NoError(t, entity.Serialize(keyring))
+
+	verifier, err := New(&v1.ChartVerification{Type: Provenance}, &corev1.Secret{
+		Data: map[string][]byte{
+			KeyringKey: keyring.Bytes(),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ".prov", verifier.SignatureSuffix())
+
+	sign := func(name string, data []byte) []byte {
+		digest := sha256.Sum256(data)
+		prov := &bytes.Buffer{}
+		w, err := clearsign.Encode(prov, entity.PrivateKey, nil)
+		require.NoError(t, err)
+		_, err = fmt.Fprintf(w, "apiVersion: v2\nname: %s\nversion: 1.0.0\n\n...\nfiles:\n  app-1.0.0.tgz: sha256:%s\n", name, hex.EncodeToString(digest[:]))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		return prov.Bytes()
+	}
+
+	assert.NoError(t, verifier.Verify(chart, archive, sign("app", archive)))
+	assert.Error(t, verifier.Verify(chart, []byte("tampered"), sign("app", archive)), "expected a changed archive to be rejected")
+	assert.Error(t, verifier.Verify(chart, archive, sign("other", archive)), "expected the provenance of another chart to be rejected")
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(&v1.ChartVerification{Type: Cosign}, nil)
+	assert.Error(t, err)
+
+	_, err = New(&v1.ChartVerification{Type: "unknown"}, &corev1.Secret{})
+	assert.Error(t, err)
+
+	_, err = New(&v1.ChartVerification{Type: Cosign}, &corev1.Secret{Data: map[string][]byte{CosignPublicKeyKey: []byte("not a key")}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,156 @@
+package client
+
+import (
+	"github.com/rancher/norman/types"
+)
+
+const (
+	AlertSilenceType                      = "alertSilence"
+	AlertSilenceFieldAnnotations          = "annotations"
+	AlertSilenceFieldClusterID            = "clusterId"
+	AlertSilenceFieldCreated              = "created"
+	AlertSilenceFieldCreatorID            = "creatorId"
+	AlertSilenceFieldDescription          = "description"
+	AlertSilenceFieldDurationMinutes      = "durationMinutes"
+	AlertSilenceFieldEndTime              = "endTime"
+	AlertSilenceFieldEndsAt               = "endsAt"
+	AlertSilenceFieldGroupNames           = "groupNames"
+	AlertSilenceFieldLabels               = "labels"
+	AlertSilenceFieldMatchers             = "matchers"
+	AlertSilenceFieldMessage              = "message"
+	AlertSilenceFieldName                 = "name"
+	AlertSilenceFieldNamespaceId          = "namespaceId"
+	AlertSilenceFieldOwnerReferences      = "ownerReferences"
+	AlertSilenceFieldProjectName          = "projectName"
+	AlertSilenceFieldRemoved              = "removed"
+	AlertSilenceFieldRuleNames            = "ruleNames"
+	AlertSilenceFieldSchedule             = "schedule"
+	AlertSilenceFieldSilenceState         = "silenceState"
+	AlertSilenceFieldStartTime            = "startTime"
+	AlertSilenceFieldStartsAt             = "startsAt"
+	AlertSilenceFieldState                = "state"
+	AlertSilenceFieldTimeZone             = "timeZone"
+	AlertSilenceFieldTransitioning        = "transitioning"
+	AlertSilenceFieldTransitioningMessage = "transitioningMessage"
+	AlertSilenceFieldUUID                 = "uuid"
+)
+
+type AlertSilence struct {
+	types.Resource
+	Annotations          map[string]string     `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	ClusterID            string                `json:"clusterId,omitempty" yaml:"clusterId,omitempty"`
+	Created              string                `json:"created,omitempty" yaml:"created,omitempty"`
+	CreatorID            string                `json:"creatorId,omitempty" yaml:"creatorId,omitempty"`
+	Description          string                `json:"description,omitempty" yaml:"description,omitempty"`
+	DurationMinutes      int64                 `json:"durationMinutes,omitempty" yaml:"durationMinutes,omitempty"`
+	EndTime              string                `json:"endTime,omitempty" yaml:"endTime,omitempty"`
+	EndsAt               string                `json:"endsAt,omitempty" yaml:"endsAt,omitempty"`
+	GroupNames           []string              `json:"groupNames,omitempty" yaml:"groupNames,omitempty"`
+	Labels               map[string]string     `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Matchers             []AlertSilenceMatcher `json:"matchers,omitempty" yaml:"matchers,omitempty"`
+	Message              string                `json:"message,omitempty" yaml:"message,omitempty"`
+	Name                 string                `json:"name,omitempty" yaml:"name,omitempty"`
+	NamespaceId          string                `json:"namespaceId,omitempty" yaml:"namespaceId,omitempty"`
+	OwnerReferences      []OwnerReference      `json:"ownerReferences,omitempty" yaml:"ownerReferences,omitempty"`
+	ProjectName          string                `json:"projectName,omitempty" yaml:"projectName,omitempty"`
+	Removed              string                `json:"removed,omitempty" yaml:"removed,omitempty"`
+	RuleNames            []string              `json:"ruleNames,omitempty" yaml:"ruleNames,omitempty"`
+	Schedule             string                `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+	SilenceState         string                `json:"silenceState,omitempty" yaml:"silenceState,omitempty"`
+	StartTime            string                `json:"startTime,omitempty" yaml:"startTime,omitempty"`
+	StartsAt             string                `json:"startsAt,omitempty" yaml:"startsAt,omitempty"`
+	State                string                `json:"state,omitempty" yaml:"state,omitempty"`
+	TimeZone             string                `json:"timeZone,omitempty" yaml:"timeZone,omitempty"`
+	Transitioning        string                `json:"transitioning,omitempty" yaml:"transitioning,omitempty"`
+	TransitioningMessage string                `json:"transitioningMessage,omitempty" yaml:"transitioningMessage,omitempty"`
+	UUID                 string                `json:"uuid,omitempty" yaml:"uuid,omitempty"`
+}
+
+type AlertSilenceCollection struct {
+	types.Collection
+	Data   []AlertSilence `json:"data,omitempty"`
+	client *AlertSilenceClient
+}
+
+type AlertSilenceClient struct {
+	apiClient *Client
+}
+
+type AlertSilenceOperations interface {
+	List(opts *types.ListOpts) (*AlertSilenceCollection, error)
+	ListAll(opts *types.ListOpts) (*AlertSilenceCollection, error)
+	Create(opts *AlertSilence) (*AlertSilence, error)
+	Update(existing *AlertSilence, updates interface{}) (*AlertSilence, error)
+	Replace(existing *AlertSilence) (*AlertSilence, error)
+	ByID(id string) (*AlertSilence, error)
+	Delete(container *AlertSilence) error
+}
+
+func newAlertSilenceClient(apiClient *Client) *AlertSilenceClient {
+	return &AlertSilenceClient{
+		apiClient: apiClient,
+	}
+}
+
+func (c *AlertSilenceClient) Create(container *AlertSilence) (*AlertSilence, error) {
+	resp := &AlertSilence{}
+	err := c.apiClient.Ops.DoCreate(AlertSilenceType, container, resp)
+	return resp, err
+}
+
+func (c *AlertSilenceClient) Update(existing *AlertSilence, updates interface{}) (*AlertSilence, error) {
+	resp := &AlertSilence{}
+	err := c.apiClient.Ops.DoUpdate(AlertSilenceType, &existing.Resource, updates, resp)
+	return resp, err
+}
+
+func (c *AlertSilenceClient) Replace(obj *AlertSilence) (*AlertSilence, error) {
+	resp := &AlertSilence{}
+	err := c.apiClient.Ops.DoReplace(AlertSilenceType, &obj.Resource, obj, resp)
+	return resp, err
+}
+
+func (c *AlertSilenceClient) List(opts *types.ListOpts) (*AlertSilenceCollection, error) {
+	resp := &AlertSilenceCollection{}
+	err := c.apiClient.Ops.DoList(AlertSilenceType, opts, resp)
+	resp.client = c
+	return resp, err
+}
+
+func (c *AlertSilenceClient) ListAll(opts *types.ListOpts) (*AlertSilenceCollection, error) {
+	resp := &AlertSilenceCollection{}
+	resp, err := c.List(opts)
+	if err != nil {
+		return resp, err
+	}
+	data := resp.Data
+	for next, err := resp.Next(); next != nil && err == nil; next, err = next.Next() {
+		data = append(data, next.Data...)
+		resp = next
+		resp.Data = data
+	}
+	if err != nil {
+		return resp, err
+	}
+	return resp, err
+}
+
+func (cc *AlertSilenceCollection) Next() (*AlertSilenceCollection, error) {
+	if cc != nil && cc.Pagination != nil && cc.Pagination.Next != "" {
+		resp := &AlertSilenceCollection{}
+		err := cc.client.apiClient.Ops.DoNext(cc.Pagination.Next, resp)
+		resp.client = cc.client
+		return resp, err
+	}
+	return nil, nil
+}
+
+func (c *AlertSilenceClient) ByID(id string) (*AlertSilence, error) {
+	resp := &AlertSilence{}
+	err := c.apiClient.Ops.DoByID(AlertSilenceType, id, resp)
+	return resp, err
+}
+
+func (c *AlertSilenceClient) Delete(container *AlertSilence) error {
+	return c.apiClient.Ops.DoResourceDelete(AlertSilenceType, &container.Resource)
+}
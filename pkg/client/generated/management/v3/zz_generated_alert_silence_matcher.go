@@ -0,0 +1,14 @@
+package client
+
+const (
+	AlertSilenceMatcherType         = "alertSilenceMatcher"
+	AlertSilenceMatcherFieldIsRegex = "isRegex"
+	AlertSilenceMatcherFieldName    = "name"
+	AlertSilenceMatcherFieldValue   = "value"
+)
+
+type AlertSilenceMatcher struct {
+	IsRegex bool   `json:"isRegex,omitempty" yaml:"isRegex,omitempty"`
+	Name    string `json:"name,omitempty" yaml:"name,omitempty"`
+	Value   string `json:"value,omitempty" yaml:"value,omitempty"`
+}
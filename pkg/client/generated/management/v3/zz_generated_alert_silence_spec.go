@@ -0,0 +1,32 @@
+package client
+
+const (
+	AlertSilenceSpecType                 = "alertSilenceSpec"
+	AlertSilenceSpecFieldClusterID       = "clusterId"
+	AlertSilenceSpecFieldDescription     = "description"
+	AlertSilenceSpecFieldDisplayName     = "displayName"
+	AlertSilenceSpecFieldDurationMinutes = "durationMinutes"
+	AlertSilenceSpecFieldEndsAt          = "endsAt"
+	AlertSilenceSpecFieldGroupNames      = "groupNames"
+	AlertSilenceSpecFieldMatchers        = "matchers"
+	AlertSilenceSpecFieldProjectName     = "projectName"
+	AlertSilenceSpecFieldRuleNames       = "ruleNames"
+	AlertSilenceSpecFieldSchedule        = "schedule"
+	AlertSilenceSpecFieldStartsAt        = "startsAt"
+	AlertSilenceSpecFieldTimeZone        = "timeZone"
+)
+
+type AlertSilenceSpec struct {
+	ClusterID       string                `json:"clusterId,omitempty" yaml:"clusterId,omitempty"`
+	Description     string                `json:"description,omitempty" yaml:"description,omitempty"`
+	DisplayName     string                `json:"displayName,omitempty" yaml:"displayName,omitempty"`
+	DurationMinutes int64                 `json:"durationMinutes,omitempty" yaml:"durationMinutes,omitempty"`
+	EndsAt          string                `json:"endsAt,omitempty" yaml:"endsAt,omitempty"`
+	GroupNames      []string              `json:"groupNames,omitempty" yaml:"groupNames,omitempty"`
+	Matchers        []AlertSilenceMatcher `json:"matchers,omitempty" yaml:"matchers,omitempty"`
+	ProjectName     string                `json:"projectName,omitempty" yaml:"projectName,omitempty"`
+	RuleNames       []string              `json:"ruleNames,omitempty" yaml:"ruleNames,omitempty"`
+	Schedule        string                `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+	StartsAt        string                `json:"startsAt,omitempty" yaml:"startsAt,omitempty"`
+	TimeZone        string                `json:"timeZone,omitempty" yaml:"timeZone,omitempty"`
+}
@@ -0,0 +1,16 @@
+package client
+
+const (
+	AlertSilenceStatusType              = "alertSilenceStatus"
+	AlertSilenceStatusFieldEndTime      = "endTime"
+	AlertSilenceStatusFieldMessage      = "message"
+	AlertSilenceStatusFieldSilenceState = "silenceState"
+	AlertSilenceStatusFieldStartTime    = "startTime"
+)
+
+type AlertSilenceStatus struct {
+	EndTime      string `json:"endTime,omitempty" yaml:"endTime,omitempty"`
+	Message      string `json:"message,omitempty" yaml:"message,omitempty"`
+	SilenceState string `json:"silenceState,omitempty" yaml:"silenceState,omitempty"`
+	StartTime    string `json:"startTime,omitempty" yaml:"startTime,omitempty"`
+}
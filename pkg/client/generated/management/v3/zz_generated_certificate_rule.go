@@ -0,0 +1,16 @@
+package client
+
+const (
+	CertificateRuleType                  = "certificateRule"
+	CertificateRuleFieldCertificateID    = "certificateId"
+	CertificateRuleFieldDaysBeforeExpiry = "daysBeforeExpiry"
+	CertificateRuleFieldIngressOnly      = "ingressOnly"
+	CertificateRuleFieldSelector         = "selector"
+)
+
+type CertificateRule struct {
+	CertificateID    string            `json:"certificateId,omitempty" yaml:"certificateId,omitempty"`
+	DaysBeforeExpiry int64             `json:"daysBeforeExpiry,omitempty" yaml:"daysBeforeExpiry,omitempty"`
+	IngressOnly      bool              `json:"ingressOnly,omitempty" yaml:"ingressOnly,omitempty"`
+	Selector         map[string]string `json:"selector,omitempty" yaml:"selector,omitempty"`
+}
@@ -49,6 +49,7 @@ type Client struct {
 	ProjectAlertGroup                       ProjectAlertGroupOperations
 	ClusterAlertRule                        ClusterAlertRuleOperations
 	ProjectAlertRule                        ProjectAlertRuleOperations
+	AlertSilence                            AlertSilenceOperations
 	ComposeConfig                           ComposeConfigOperations
 	ProjectCatalog                          ProjectCatalogOperations
 	ClusterCatalog                          ClusterCatalogOperations
@@ -126,6 +127,7 @@ func NewClient(opts *clientbase.ClientOpts) (*Client, error) {
 	client.ProjectAlertGroup = newProjectAlertGroupClient(client)
 	client.ClusterAlertRule = newClusterAlertRuleClient(client)
 	client.ProjectAlertRule = newProjectAlertRuleClient(client)
+	client.AlertSilence = newAlertSilenceClient(client)
 	client.ComposeConfig = newComposeConfigClient(client)
 	client.ProjectCatalog = newProjectCatalogClient(client)
 	client.ClusterCatalog = newClusterCatalogClient(client)
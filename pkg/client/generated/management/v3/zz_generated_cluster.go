@@ -45,6 +45,7 @@
 	ClusterFieldEnableClusterAlerting                = "enableClusterAlerting"
 	ClusterFieldEnableClusterMonitoring              = "enableClusterMonitoring"
 	ClusterFieldEnableNetworkPolicy                  = "enableNetworkPolicy"
+	ClusterFieldEtcdBackupEncryption                 = "etcdBackupEncryption"
 	ClusterFieldFailedSpec                           = "failedSpec"
 	ClusterFieldFleetWorkspaceName                   = "fleetWorkspaceName"
 	ClusterFieldGKEConfig                            = "gkeConfig"
@@ -117,6 +118,7 @@ type Cluster struct {
 	EnableClusterAlerting                bool                           `json:"enableClusterAlerting,omitempty" yaml:"enableClusterAlerting,omitempty"`
 	EnableClusterMonitoring              bool                           `json:"enableClusterMonitoring,omitempty" yaml:"enableClusterMonitoring,omitempty"`
 	EnableNetworkPolicy                  *bool                          `json:"enableNetworkPolicy,omitempty" yaml:"enableNetworkPolicy,omitempty"`
+	EtcdBackupEncryption                 *EtcdBackupEncryptionConfig    `json:"etcdBackupEncryption,omitempty" yaml:"etcdBackupEncryption,omitempty"`
 	FailedSpec                           *ClusterSpec                   `json:"failedSpec,omitempty" yaml:"failedSpec,omitempty"`
 	FleetWorkspaceName                   string                         `json:"fleetWorkspaceName,omitempty" yaml:"fleetWorkspaceName,omitempty"`
 	GKEConfig                            *GKEClusterConfigSpec          `json:"gkeConfig,omitempty" yaml:"gkeConfig,omitempty"`
@@ -192,6 +194,8 @@ type ClusterOperations interface {
 	ActionSaveAsTemplate(resource *Cluster, input *SaveAsTemplateInput) (*SaveAsTemplateOutput, error)
 
 	ActionViewMonitoring(resource *Cluster) (*MonitoringOutput, error)
+
+	CollectionActionExpiringCertificates(resource *ClusterCollection, input *ExpiringCertificatesInput) (*ExpiringCertificatesOutput, error)
 }
 
 func newClusterClient(apiClient *Client) *ClusterClient {
@@ -334,3 +338,9 @@ func (c *ClusterClient) ActionViewMonitoring(resource *Cluster) (*MonitoringOutp
 	err := c.apiClient.Ops.DoAction(ClusterType, "viewMonitoring", &resource.Resource, nil, resp)
 	return resp, err
 }
+
+func (c *ClusterClient) CollectionActionExpiringCertificates(resource *ClusterCollection, input *ExpiringCertificatesInput) (*ExpiringCertificatesOutput, error) {
+	resp := &ExpiringCertificatesOutput{}
+	err := c.apiClient.Ops.DoCollectionAction(ClusterType, "expiringCertificates", &resource.Collection, input, resp)
+	return resp, err
+}
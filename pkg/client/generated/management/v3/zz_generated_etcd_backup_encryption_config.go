@@ -0,0 +1,12 @@
+package client
+
+const (
+	EtcdBackupEncryptionConfigType            = "etcdBackupEncryptionConfig"
+	EtcdBackupEncryptionConfigFieldKeyID      = "keyId"
+	EtcdBackupEncryptionConfigFieldSecretName = "secretName"
+)
+
+type EtcdBackupEncryptionConfig struct {
+	KeyID      string `json:"keyId,omitempty" yaml:"keyId,omitempty"`
+	SecretName string `json:"secretName,omitempty" yaml:"secretName,omitempty"`
+}
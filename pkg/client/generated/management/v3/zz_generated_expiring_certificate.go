@@ -0,0 +1,18 @@
+package client
+
+const (
+	ExpiringCertificateType                = "expiringCertificate"
+	ExpiringCertificateFieldClusterID      = "clusterId"
+	ExpiringCertificateFieldClusterName    = "clusterName"
+	ExpiringCertificateFieldExpirationDate = "expirationDate"
+	ExpiringCertificateFieldName           = "name"
+	ExpiringCertificateFieldSeverity       = "severity"
+)
+
+type ExpiringCertificate struct {
+	ClusterID      string `json:"clusterId,omitempty" yaml:"clusterId,omitempty"`
+	ClusterName    string `json:"clusterName,omitempty" yaml:"clusterName,omitempty"`
+	ExpirationDate string `json:"expirationDate,omitempty" yaml:"expirationDate,omitempty"`
+	Name           string `json:"name,omitempty" yaml:"name,omitempty"`
+	Severity       string `json:"severity,omitempty" yaml:"severity,omitempty"`
+}
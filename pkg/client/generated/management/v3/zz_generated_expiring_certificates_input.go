@@ -0,0 +1,10 @@
+package client
+
+const (
+	ExpiringCertificatesInputType            = "expiringCertificatesInput"
+	ExpiringCertificatesInputFieldWithinDays = "withinDays"
+)
+
+type ExpiringCertificatesInput struct {
+	WithinDays int64 `json:"withinDays,omitempty" yaml:"withinDays,omitempty"`
+}
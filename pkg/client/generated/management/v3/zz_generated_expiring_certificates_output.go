@@ -0,0 +1,10 @@
+package client
+
+const (
+	ExpiringCertificatesOutputType              = "expiringCertificatesOutput"
+	ExpiringCertificatesOutputFieldCertificates = "certificates"
+)
+
+type ExpiringCertificatesOutput struct {
+	Certificates []ExpiringCertificate `json:"certificates,omitempty" yaml:"certificates,omitempty"`
+}
@@ -0,0 +1,12 @@
+package client
+
+const (
+	JobRuleType            = "jobRule"
+	JobRuleFieldSelector   = "selector"
+	JobRuleFieldWorkloadID = "workloadId"
+)
+
+type JobRule struct {
+	Selector   map[string]string `json:"selector,omitempty" yaml:"selector,omitempty"`
+	WorkloadID string            `json:"workloadId,omitempty" yaml:"workloadId,omitempty"`
+}
@@ -0,0 +1,14 @@
+package client
+
+const (
+	MFAEnrollOutputType               = "mfaEnrollOutput"
+	MFAEnrollOutputFieldOTPAuthURL    = "otpAuthUrl"
+	MFAEnrollOutputFieldRecoveryCodes = "recoveryCodes"
+	MFAEnrollOutputFieldSecret        = "secret"
+)
+
+type MFAEnrollOutput struct {
+	OTPAuthURL    string   `json:"otpAuthUrl,omitempty" yaml:"otpAuthUrl,omitempty"`
+	RecoveryCodes []string `json:"recoveryCodes,omitempty" yaml:"recoveryCodes,omitempty"`
+	Secret        string   `json:"secret,omitempty" yaml:"secret,omitempty"`
+}
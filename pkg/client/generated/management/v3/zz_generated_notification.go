@@ -1,24 +1,32 @@
 package client
 
 const (
-	NotificationType                 = "notification"
-	NotificationFieldDingtalkConfig  = "dingtalkConfig"
-	NotificationFieldMSTeamsConfig   = "msteamsConfig"
-	NotificationFieldMessage         = "message"
-	NotificationFieldPagerdutyConfig = "pagerdutyConfig"
-	NotificationFieldSMTPConfig      = "smtpConfig"
-	NotificationFieldSlackConfig     = "slackConfig"
-	NotificationFieldWebhookConfig   = "webhookConfig"
-	NotificationFieldWechatConfig    = "wechatConfig"
+	NotificationType                  = "notification"
+	NotificationFieldDingtalkConfig   = "dingtalkConfig"
+	NotificationFieldGoogleChatConfig = "googleChatConfig"
+	NotificationFieldMSTeamsConfig    = "msteamsConfig"
+	NotificationFieldMattermostConfig = "mattermostConfig"
+	NotificationFieldMessage          = "message"
+	NotificationFieldOpsgenieConfig   = "opsgenieConfig"
+	NotificationFieldPagerdutyConfig  = "pagerdutyConfig"
+	NotificationFieldSMTPConfig       = "smtpConfig"
+	NotificationFieldSlackConfig      = "slackConfig"
+	NotificationFieldTelegramConfig   = "telegramConfig"
+	NotificationFieldWebhookConfig    = "webhookConfig"
+	NotificationFieldWechatConfig     = "wechatConfig"
 )
 
 type Notification struct {
-	DingtalkConfig  *DingtalkConfig  `json:"dingtalkConfig,omitempty" yaml:"dingtalkConfig,omitempty"`
-	MSTeamsConfig   *MSTeamsConfig   `json:"msteamsConfig,omitempty" yaml:"msteamsConfig,omitempty"`
-	Message         string           `json:"message,omitempty" yaml:"message,omitempty"`
-	PagerdutyConfig *PagerdutyConfig `json:"pagerdutyConfig,omitempty" yaml:"pagerdutyConfig,omitempty"`
-	SMTPConfig      *SMTPConfig      `json:"smtpConfig,omitempty" yaml:"smtpConfig,omitempty"`
-	SlackConfig     *SlackConfig     `json:"slackConfig,omitempty" yaml:"slackConfig,omitempty"`
-	WebhookConfig   *WebhookConfig   `json:"webhookConfig,omitempty" yaml:"webhookConfig,omitempty"`
-	WechatConfig    *WechatConfig    `json:"wechatConfig,omitempty" yaml:"wechatConfig,omitempty"`
+	DingtalkConfig   *DingtalkConfig   `json:"dingtalkConfig,omitempty" yaml:"dingtalkConfig,omitempty"`
+	GoogleChatConfig *GoogleChatConfig `json:"googleChatConfig,omitempty" yaml:"googleChatConfig,omitempty"`
+	MSTeamsConfig    *MSTeamsConfig    `json:"msteamsConfig,omitempty" yaml:"msteamsConfig,omitempty"`
+	MattermostConfig *MattermostConfig `json:"mattermostConfig,omitempty" yaml:"mattermostConfig,omitempty"`
+	Message          string            `json:"message,omitempty" yaml:"message,omitempty"`
+	OpsgenieConfig   *OpsgenieConfig   `json:"opsgenieConfig,omitempty" yaml:"opsgenieConfig,omitempty"`
+	PagerdutyConfig  *PagerdutyConfig  `json:"pagerdutyConfig,omitempty" yaml:"pagerdutyConfig,omitempty"`
+	SMTPConfig       *SMTPConfig       `json:"smtpConfig,omitempty" yaml:"smtpConfig,omitempty"`
+	SlackConfig      *SlackConfig      `json:"slackConfig,omitempty" yaml:"slackConfig,omitempty"`
+	TelegramConfig   *TelegramConfig   `json:"telegramConfig,omitempty" yaml:"telegramConfig,omitempty"`
+	WebhookConfig    *WebhookConfig    `json:"webhookConfig,omitempty" yaml:"webhookConfig,omitempty"`
+	WechatConfig     *WechatConfig     `json:"wechatConfig,omitempty" yaml:"wechatConfig,omitempty"`
 }
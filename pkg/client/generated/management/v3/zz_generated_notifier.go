@@ -12,10 +12,13 @@
 	NotifierFieldCreatorID            = "creatorId"
 	NotifierFieldDescription          = "description"
 	NotifierFieldDingtalkConfig       = "dingtalkConfig"
+	NotifierFieldGoogleChatConfig     = "googleChatConfig"
 	NotifierFieldLabels               = "labels"
 	NotifierFieldMSTeamsConfig        = "msteamsConfig"
+	NotifierFieldMattermostConfig     = "mattermostConfig"
 	NotifierFieldName                 = "name"
 	NotifierFieldNamespaceId          = "namespaceId"
+	NotifierFieldOpsgenieConfig       = "opsgenieConfig"
 	NotifierFieldOwnerReferences      = "ownerReferences"
 	NotifierFieldPagerdutyConfig      = "pagerdutyConfig"
 	NotifierFieldRemoved              = "removed"
@@ -24,6 +27,8 @@
 	NotifierFieldSlackConfig          = "slackConfig"
 	NotifierFieldState                = "state"
 	NotifierFieldStatus               = "status"
+	NotifierFieldTelegramConfig       = "telegramConfig"
+	NotifierFieldTemplate             = "template"
 	NotifierFieldTransitioning        = "transitioning"
 	NotifierFieldTransitioningMessage = "transitioningMessage"
 	NotifierFieldUUID                 = "uuid"
@@ -39,10 +44,13 @@ type Notifier struct {
 	CreatorID            string            `json:"creatorId,omitempty" yaml:"creatorId,omitempty"`
 	Description          string            `json:"description,omitempty" yaml:"description,omitempty"`
 	DingtalkConfig       *DingtalkConfig   `json:"dingtalkConfig,omitempty" yaml:"dingtalkConfig,omitempty"`
+	GoogleChatConfig     *GoogleChatConfig `json:"googleChatConfig,omitempty" yaml:"googleChatConfig,omitempty"`
 	Labels               map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
 	MSTeamsConfig        *MSTeamsConfig    `json:"msteamsConfig,omitempty" yaml:"msteamsConfig,omitempty"`
+	MattermostConfig     *MattermostConfig `json:"mattermostConfig,omitempty" yaml:"mattermostConfig,omitempty"`
 	Name                 string            `json:"name,omitempty" yaml:"name,omitempty"`
 	NamespaceId          string            `json:"namespaceId,omitempty" yaml:"namespaceId,omitempty"`
+	OpsgenieConfig       *OpsgenieConfig   `json:"opsgenieConfig,omitempty" yaml:"opsgenieConfig,omitempty"`
 	OwnerReferences      []OwnerReference  `json:"ownerReferences,omitempty" yaml:"ownerReferences,omitempty"`
 	PagerdutyConfig      *PagerdutyConfig  `json:"pagerdutyConfig,omitempty" yaml:"pagerdutyConfig,omitempty"`
 	Removed              string            `json:"removed,omitempty" yaml:"removed,omitempty"`
@@ -51,6 +59,8 @@ type Notifier struct {
 	SlackConfig          *SlackConfig      `json:"slackConfig,omitempty" yaml:"slackConfig,omitempty"`
 	State                string            `json:"state,omitempty" yaml:"state,omitempty"`
 	Status               *NotifierStatus   `json:"status,omitempty" yaml:"status,omitempty"`
+	TelegramConfig       *TelegramConfig   `json:"telegramConfig,omitempty" yaml:"telegramConfig,omitempty"`
+	Template             *NotifierTemplate `json:"template,omitempty" yaml:"template,omitempty"`
 	Transitioning        string            `json:"transitioning,omitempty" yaml:"transitioning,omitempty"`
 	TransitioningMessage string            `json:"transitioningMessage,omitempty" yaml:"transitioningMessage,omitempty"`
 	UUID                 string            `json:"uuid,omitempty" yaml:"uuid,omitempty"`
@@ -77,6 +87,8 @@ type NotifierOperations interface {
 	ByID(id string) (*Notifier, error)
 	Delete(container *Notifier) error
 
+	ActionResend(resource *Notifier, input *NotifierResendInput) error
+
 	ActionSend(resource *Notifier, input *Notification) error
 
 	CollectionActionSend(resource *NotifierCollection, input *Notification) error
@@ -151,6 +163,11 @@ func (c *NotifierClient) Delete(container *Notifier) error {
 	return c.apiClient.Ops.DoResourceDelete(NotifierType, &container.Resource)
 }
 
+func (c *NotifierClient) ActionResend(resource *Notifier, input *NotifierResendInput) error {
+	err := c.apiClient.Ops.DoAction(NotifierType, "resend", &resource.Resource, input, nil)
+	return err
+}
+
 func (c *NotifierClient) ActionSend(resource *Notifier, input *Notification) error {
 	err := c.apiClient.Ops.DoAction(NotifierType, "send", &resource.Resource, input, nil)
 	return err
@@ -0,0 +1,22 @@
+package client
+
+const (
+	NotifierDeadLetterType             = "notifierDeadLetter"
+	NotifierDeadLetterFieldAlertName   = "alertName"
+	NotifierDeadLetterFieldAttempts    = "attempts"
+	NotifierDeadLetterFieldCreatedTime = "createdTime"
+	NotifierDeadLetterFieldID          = "id"
+	NotifierDeadLetterFieldLastError   = "lastError"
+	NotifierDeadLetterFieldRecipient   = "recipient"
+	NotifierDeadLetterFieldTitle       = "title"
+)
+
+type NotifierDeadLetter struct {
+	AlertName   string `json:"alertName,omitempty" yaml:"alertName,omitempty"`
+	Attempts    int64  `json:"attempts,omitempty" yaml:"attempts,omitempty"`
+	CreatedTime string `json:"createdTime,omitempty" yaml:"createdTime,omitempty"`
+	ID          string `json:"id,omitempty" yaml:"id,omitempty"`
+	LastError   string `json:"lastError,omitempty" yaml:"lastError,omitempty"`
+	Recipient   string `json:"recipient,omitempty" yaml:"recipient,omitempty"`
+	Title       string `json:"title,omitempty" yaml:"title,omitempty"`
+}
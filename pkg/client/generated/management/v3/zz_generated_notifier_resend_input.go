@@ -0,0 +1,10 @@
+package client
+
+const (
+	NotifierResendInputType               = "notifierResendInput"
+	NotifierResendInputFieldDeadLetterIDs = "deadLetterIds"
+)
+
+type NotifierResendInput struct {
+	DeadLetterIDs []string `json:"deadLetterIds,omitempty" yaml:"deadLetterIds,omitempty"`
+}
@@ -1,8 +1,18 @@
 package client
 
 const (
-	NotifierStatusType = "notifierStatus"
+	NotifierStatusType                  = "notifierStatus"
+	NotifierStatusFieldDeadLetters      = "deadLetters"
+	NotifierStatusFieldLastDeliveryTime = "lastDeliveryTime"
+	NotifierStatusFieldLastError        = "lastError"
+	NotifierStatusFieldLastFailureTime  = "lastFailureTime"
+	NotifierStatusFieldPending          = "pending"
 )
 
 type NotifierStatus struct {
+	DeadLetters      []NotifierDeadLetter `json:"deadLetters,omitempty" yaml:"deadLetters,omitempty"`
+	LastDeliveryTime string               `json:"lastDeliveryTime,omitempty" yaml:"lastDeliveryTime,omitempty"`
+	LastError        string               `json:"lastError,omitempty" yaml:"lastError,omitempty"`
+	LastFailureTime  string               `json:"lastFailureTime,omitempty" yaml:"lastFailureTime,omitempty"`
+	Pending          int64                `json:"pending,omitempty" yaml:"pending,omitempty"`
 }
@@ -0,0 +1,14 @@
+package client
+
+const (
+	NotifierTemplateType         = "notifierTemplate"
+	NotifierTemplateFieldPayload = "payload"
+	NotifierTemplateFieldText    = "text"
+	NotifierTemplateFieldTitle   = "title"
+)
+
+type NotifierTemplate struct {
+	Payload string `json:"payload,omitempty" yaml:"payload,omitempty"`
+	Text    string `json:"text,omitempty" yaml:"text,omitempty"`
+	Title   string `json:"title,omitempty" yaml:"title,omitempty"`
+}
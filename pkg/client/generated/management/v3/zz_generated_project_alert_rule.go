@@ -8,12 +8,14 @@
 	ProjectAlertRuleType                       = "projectAlertRule"
 	ProjectAlertRuleFieldAlertState            = "alertState"
 	ProjectAlertRuleFieldAnnotations           = "annotations"
+	ProjectAlertRuleFieldCertificateRule       = "certificateRule"
 	ProjectAlertRuleFieldCreated               = "created"
 	ProjectAlertRuleFieldCreatorID             = "creatorId"
 	ProjectAlertRuleFieldGroupID               = "groupId"
 	ProjectAlertRuleFieldGroupIntervalSeconds  = "groupIntervalSeconds"
 	ProjectAlertRuleFieldGroupWaitSeconds      = "groupWaitSeconds"
 	ProjectAlertRuleFieldInherited             = "inherited"
+	ProjectAlertRuleFieldJobRule               = "jobRule"
 	ProjectAlertRuleFieldLabels                = "labels"
 	ProjectAlertRuleFieldMetricRule            = "metricRule"
 	ProjectAlertRuleFieldName                  = "name"
@@ -28,6 +30,7 @@
 	ProjectAlertRuleFieldTransitioning         = "transitioning"
 	ProjectAlertRuleFieldTransitioningMessage  = "transitioningMessage"
 	ProjectAlertRuleFieldUUID                  = "uuid"
+	ProjectAlertRuleFieldVolumeRule            = "volumeRule"
 	ProjectAlertRuleFieldWorkloadRule          = "workloadRule"
 )
 
@@ -35,12 +38,14 @@ type ProjectAlertRule struct {
 	types.Resource
 	AlertState            string            `json:"alertState,omitempty" yaml:"alertState,omitempty"`
 	Annotations           map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	CertificateRule       *CertificateRule  `json:"certificateRule,omitempty" yaml:"certificateRule,omitempty"`
 	Created               string            `json:"created,omitempty" yaml:"created,omitempty"`
 	CreatorID             string            `json:"creatorId,omitempty" yaml:"creatorId,omitempty"`
 	GroupID               string            `json:"groupId,omitempty" yaml:"groupId,omitempty"`
 	GroupIntervalSeconds  int64             `json:"groupIntervalSeconds,omitempty" yaml:"groupIntervalSeconds,omitempty"`
 	GroupWaitSeconds      int64             `json:"groupWaitSeconds,omitempty" yaml:"groupWaitSeconds,omitempty"`
 	Inherited             *bool             `json:"inherited,omitempty" yaml:"inherited,omitempty"`
+	JobRule               *JobRule          `json:"jobRule,omitempty" yaml:"jobRule,omitempty"`
 	Labels                map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
 	MetricRule            *MetricRule       `json:"metricRule,omitempty" yaml:"metricRule,omitempty"`
 	Name                  string            `json:"name,omitempty" yaml:"name,omitempty"`
@@ -55,6 +60,7 @@ type ProjectAlertRule struct {
 	Transitioning         string            `json:"transitioning,omitempty" yaml:"transitioning,omitempty"`
 	TransitioningMessage  string            `json:"transitioningMessage,omitempty" yaml:"transitioningMessage,omitempty"`
 	UUID                  string            `json:"uuid,omitempty" yaml:"uuid,omitempty"`
+	VolumeRule            *VolumeRule       `json:"volumeRule,omitempty" yaml:"volumeRule,omitempty"`
 	WorkloadRule          *WorkloadRule     `json:"workloadRule,omitempty" yaml:"workloadRule,omitempty"`
 }
 
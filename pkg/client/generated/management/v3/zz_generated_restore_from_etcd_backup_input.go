@@ -2,11 +2,13 @@
 
 const (
 	RestoreFromEtcdBackupInputType                  = "restoreFromEtcdBackupInput"
+	RestoreFromEtcdBackupInputFieldDryRun           = "dryRun"
 	RestoreFromEtcdBackupInputFieldEtcdBackupID     = "etcdBackupId"
 	RestoreFromEtcdBackupInputFieldRestoreRkeConfig = "restoreRkeConfig"
 )
 
 type RestoreFromEtcdBackupInput struct {
+	DryRun           bool   `json:"dryRun,omitempty" yaml:"dryRun,omitempty"`
 	EtcdBackupID     string `json:"etcdBackupId,omitempty" yaml:"etcdBackupId,omitempty"`
 	RestoreRkeConfig string `json:"restoreRkeConfig,omitempty" yaml:"restoreRkeConfig,omitempty"`
 }
@@ -0,0 +1,12 @@
+package client
+
+const (
+	RestoreFromEtcdBackupOutputType          = "restoreFromEtcdBackupOutput"
+	RestoreFromEtcdBackupOutputFieldMessages = "messages"
+	RestoreFromEtcdBackupOutputFieldValid    = "valid"
+)
+
+type RestoreFromEtcdBackupOutput struct {
+	Messages []string `json:"messages,omitempty" yaml:"messages,omitempty"`
+	Valid    bool     `json:"valid,omitempty" yaml:"valid,omitempty"`
+}
@@ -20,6 +20,8 @@
 	TokenFieldIsDerived       = "isDerived"
 	TokenFieldLabels          = "labels"
 	TokenFieldLastUpdateTime  = "lastUpdateTime"
+	TokenFieldLastUsedAt      = "lastUsedAt"
+	TokenFieldLastUsedFrom    = "lastUsedFrom"
 	TokenFieldName            = "name"
 	TokenFieldOwnerReferences = "ownerReferences"
 	TokenFieldProviderInfo    = "providerInfo"
@@ -47,6 +49,8 @@ type Token struct {
 	IsDerived       bool              `json:"isDerived,omitempty" yaml:"isDerived,omitempty"`
 	Labels          map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
 	LastUpdateTime  string            `json:"lastUpdateTime,omitempty" yaml:"lastUpdateTime,omitempty"`
+	LastUsedAt      string            `json:"lastUsedAt,omitempty" yaml:"lastUsedAt,omitempty"`
+	LastUsedFrom    string            `json:"lastUsedFrom,omitempty" yaml:"lastUsedFrom,omitempty"`
 	Name            string            `json:"name,omitempty" yaml:"name,omitempty"`
 	OwnerReferences []OwnerReference  `json:"ownerReferences,omitempty" yaml:"ownerReferences,omitempty"`
 	ProviderInfo    map[string]string `json:"providerInfo,omitempty" yaml:"providerInfo,omitempty"`
@@ -78,6 +82,8 @@ type TokenOperations interface {
 	Delete(container *Token) error
 
 	CollectionActionLogout(resource *TokenCollection) error
+
+	CollectionActionRevoke(resource *TokenCollection, input *TokenRevokeInput) (*TokenRevokeOutput, error)
 }
 
 func newTokenClient(apiClient *Client) *TokenClient {
@@ -153,3 +159,9 @@ func (c *TokenClient) CollectionActionLogout(resource *TokenCollection) error {
 	err := c.apiClient.Ops.DoCollectionAction(TokenType, "logout", &resource.Collection, nil, nil)
 	return err
 }
+
+func (c *TokenClient) CollectionActionRevoke(resource *TokenCollection, input *TokenRevokeInput) (*TokenRevokeOutput, error) {
+	resp := &TokenRevokeOutput{}
+	err := c.apiClient.Ops.DoCollectionAction(TokenType, "revoke", &resource.Collection, input, resp)
+	return resp, err
+}
@@ -0,0 +1,26 @@
+package client
+
+const (
+	TokenRevokeInputType                  = "tokenRevokeInput"
+	TokenRevokeInputFieldAuthProvider     = "authProvider"
+	TokenRevokeInputFieldClusterName      = "clusterName"
+	TokenRevokeInputFieldCreatedAfter     = "createdAfter"
+	TokenRevokeInputFieldCreatedBefore    = "createdBefore"
+	TokenRevokeInputFieldDryRun           = "dryRun"
+	TokenRevokeInputFieldGroupPrincipalID = "groupPrincipalId"
+	TokenRevokeInputFieldLastUsedBefore   = "lastUsedBefore"
+	TokenRevokeInputFieldPrincipalID      = "principalId"
+	TokenRevokeInputFieldUserID           = "userId"
+)
+
+type TokenRevokeInput struct {
+	AuthProvider     string `json:"authProvider,omitempty" yaml:"authProvider,omitempty"`
+	ClusterName      string `json:"clusterName,omitempty" yaml:"clusterName,omitempty"`
+	CreatedAfter     string `json:"createdAfter,omitempty" yaml:"createdAfter,omitempty"`
+	CreatedBefore    string `json:"createdBefore,omitempty" yaml:"createdBefore,omitempty"`
+	DryRun           bool   `json:"dryRun,omitempty" yaml:"dryRun,omitempty"`
+	GroupPrincipalID string `json:"groupPrincipalId,omitempty" yaml:"groupPrincipalId,omitempty"`
+	LastUsedBefore   string `json:"lastUsedBefore,omitempty" yaml:"lastUsedBefore,omitempty"`
+	PrincipalID      string `json:"principalId,omitempty" yaml:"principalId,omitempty"`
+	UserID           string `json:"userId,omitempty" yaml:"userId,omitempty"`
+}
@@ -0,0 +1,14 @@
+package client
+
+const (
+	TokenRevokeOutputType         = "tokenRevokeOutput"
+	TokenRevokeOutputFieldDryRun  = "dryRun"
+	TokenRevokeOutputFieldRevoked = "revoked"
+	TokenRevokeOutputFieldTokens  = "tokens"
+)
+
+type TokenRevokeOutput struct {
+	DryRun  bool           `json:"dryRun,omitempty" yaml:"dryRun,omitempty"`
+	Revoked int64          `json:"revoked,omitempty" yaml:"revoked,omitempty"`
+	Tokens  []TokenSummary `json:"tokens,omitempty" yaml:"tokens,omitempty"`
+}
@@ -0,0 +1,26 @@
+package client
+
+const (
+	TokenSummaryType              = "tokenSummary"
+	TokenSummaryFieldAuthProvider = "authProvider"
+	TokenSummaryFieldClusterName  = "clusterName"
+	TokenSummaryFieldCreated      = "created"
+	TokenSummaryFieldDescription  = "description"
+	TokenSummaryFieldError        = "error"
+	TokenSummaryFieldIsDerived    = "isDerived"
+	TokenSummaryFieldLastUsedAt   = "lastUsedAt"
+	TokenSummaryFieldName         = "name"
+	TokenSummaryFieldUserID       = "userId"
+)
+
+type TokenSummary struct {
+	AuthProvider string `json:"authProvider,omitempty" yaml:"authProvider,omitempty"`
+	ClusterName  string `json:"clusterName,omitempty" yaml:"clusterName,omitempty"`
+	Created      string `json:"created,omitempty" yaml:"created,omitempty"`
+	Description  string `json:"description,omitempty" yaml:"description,omitempty"`
+	Error        string `json:"error,omitempty" yaml:"error,omitempty"`
+	IsDerived    bool   `json:"isDerived,omitempty" yaml:"isDerived,omitempty"`
+	LastUsedAt   string `json:"lastUsedAt,omitempty" yaml:"lastUsedAt,omitempty"`
+	Name         string `json:"name,omitempty" yaml:"name,omitempty"`
+	UserID       string `json:"userId,omitempty" yaml:"userId,omitempty"`
+}
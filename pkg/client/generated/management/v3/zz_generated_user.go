@@ -12,12 +12,16 @@
 	UserFieldCreatorID            = "creatorId"
 	UserFieldDescription          = "description"
 	UserFieldEnabled              = "enabled"
+	UserFieldFailedLoginAttempts  = "failedLoginAttempts"
 	UserFieldLabels               = "labels"
+	UserFieldLockedUntil          = "lockedUntil"
 	UserFieldMe                   = "me"
 	UserFieldMustChangePassword   = "mustChangePassword"
 	UserFieldName                 = "name"
 	UserFieldOwnerReferences      = "ownerReferences"
 	UserFieldPassword             = "password"
+	UserFieldPasswordChangedAt    = "passwordChangedAt"
+	UserFieldPasswordHistory      = "passwordHistory"
 	UserFieldPrincipalIDs         = "principalIds"
 	UserFieldRemoved              = "removed"
 	UserFieldState                = "state"
@@ -35,12 +39,16 @@ type User struct {
 	CreatorID            string            `json:"creatorId,omitempty" yaml:"creatorId,omitempty"`
 	Description          string            `json:"description,omitempty" yaml:"description,omitempty"`
 	Enabled              *bool             `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	FailedLoginAttempts  int64             `json:"failedLoginAttempts,omitempty" yaml:"failedLoginAttempts,omitempty"`
 	Labels               map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	LockedUntil          string            `json:"lockedUntil,omitempty" yaml:"lockedUntil,omitempty"`
 	Me                   bool              `json:"me,omitempty" yaml:"me,omitempty"`
 	MustChangePassword   bool              `json:"mustChangePassword,omitempty" yaml:"mustChangePassword,omitempty"`
 	Name                 string            `json:"name,omitempty" yaml:"name,omitempty"`
 	OwnerReferences      []OwnerReference  `json:"ownerReferences,omitempty" yaml:"ownerReferences,omitempty"`
 	Password             string            `json:"password,omitempty" yaml:"password,omitempty"`
+	PasswordChangedAt    string            `json:"passwordChangedAt,omitempty" yaml:"passwordChangedAt,omitempty"`
+	PasswordHistory      []string          `json:"passwordHistory,omitempty" yaml:"passwordHistory,omitempty"`
 	PrincipalIDs         []string          `json:"principalIds,omitempty" yaml:"principalIds,omitempty"`
 	Removed              string            `json:"removed,omitempty" yaml:"removed,omitempty"`
 	State                string            `json:"state,omitempty" yaml:"state,omitempty"`
@@ -71,11 +79,19 @@ type UserOperations interface {
 
 	ActionRefreshauthprovideraccess(resource *User) error
 
+	ActionResetmfa(resource *User) error
+
 	ActionSetpassword(resource *User, input *SetPasswordInput) (*User, error)
 
+	ActionUnlock(resource *User) (*User, error)
+
 	CollectionActionChangepassword(resource *UserCollection, input *ChangePasswordInput) error
 
+	CollectionActionEnablemfa(resource *UserCollection) (*MFAEnrollOutput, error)
+
 	CollectionActionRefreshauthprovideraccess(resource *UserCollection) error
+
+	CollectionActionVerifymfa(resource *UserCollection, input *VerifyMFAInput) error
 }
 
 func newUserClient(apiClient *Client) *UserClient {
@@ -152,18 +168,40 @@ func (c *UserClient) ActionRefreshauthprovideraccess(resource *User) error {
 	return err
 }
 
+func (c *UserClient) ActionResetmfa(resource *User) error {
+	err := c.apiClient.Ops.DoAction(UserType, "resetmfa", &resource.Resource, nil, nil)
+	return err
+}
+
 func (c *UserClient) ActionSetpassword(resource *User, input *SetPasswordInput) (*User, error) {
 	resp := &User{}
 	err := c.apiClient.Ops.DoAction(UserType, "setpassword", &resource.Resource, input, resp)
 	return resp, err
 }
 
+func (c *UserClient) ActionUnlock(resource *User) (*User, error) {
+	resp := &User{}
+	err := c.apiClient.Ops.DoAction(UserType, "unlock", &resource.Resource, nil, resp)
+	return resp, err
+}
+
 func (c *UserClient) CollectionActionChangepassword(resource *UserCollection, input *ChangePasswordInput) error {
 	err := c.apiClient.Ops.DoCollectionAction(UserType, "changepassword", &resource.Collection, input, nil)
 	return err
 }
 
+func (c *UserClient) CollectionActionEnablemfa(resource *UserCollection) (*MFAEnrollOutput, error) {
+	resp := &MFAEnrollOutput{}
+	err := c.apiClient.Ops.DoCollectionAction(UserType, "enablemfa", &resource.Collection, nil, resp)
+	return resp, err
+}
+
 func (c *UserClient) CollectionActionRefreshauthprovideraccess(resource *UserCollection) error {
 	err := c.apiClient.Ops.DoCollectionAction(UserType, "refreshauthprovideraccess", &resource.Collection, nil, nil)
 	return err
 }
+
+func (c *UserClient) CollectionActionVerifymfa(resource *UserCollection, input *VerifyMFAInput) error {
+	err := c.apiClient.Ops.DoCollectionAction(UserType, "verifymfa", &resource.Collection, input, nil)
+	return err
+}
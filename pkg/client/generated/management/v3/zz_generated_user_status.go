@@ -1,10 +1,16 @@
 package client
 
 const (
-	UserStatusType            = "userStatus"
-	UserStatusFieldConditions = "conditions"
+	UserStatusType                     = "userStatus"
+	UserStatusFieldConditions          = "conditions"
+	UserStatusFieldFailedLoginAttempts = "failedLoginAttempts"
+	UserStatusFieldLockedUntil         = "lockedUntil"
+	UserStatusFieldPasswordChangedAt   = "passwordChangedAt"
 )
 
 type UserStatus struct {
-	Conditions []UserCondition `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+	Conditions          []UserCondition `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+	FailedLoginAttempts int64           `json:"failedLoginAttempts,omitempty" yaml:"failedLoginAttempts,omitempty"`
+	LockedUntil         string          `json:"lockedUntil,omitempty" yaml:"lockedUntil,omitempty"`
+	PasswordChangedAt   string          `json:"passwordChangedAt,omitempty" yaml:"passwordChangedAt,omitempty"`
 }
@@ -0,0 +1,10 @@
+package client
+
+const (
+	VerifyMFAInputType      = "verifyMFAInput"
+	VerifyMFAInputFieldCode = "code"
+)
+
+type VerifyMFAInput struct {
+	Code string `json:"code,omitempty" yaml:"code,omitempty"`
+}
@@ -0,0 +1,14 @@
+package client
+
+const (
+	VolumeRuleType                         = "volumeRule"
+	VolumeRuleFieldPersistentVolumeClaimID = "persistentVolumeClaimId"
+	VolumeRuleFieldSelector                = "selector"
+	VolumeRuleFieldUsedPercentage          = "usedPercentage"
+)
+
+type VolumeRule struct {
+	PersistentVolumeClaimID string            `json:"persistentVolumeClaimId,omitempty" yaml:"persistentVolumeClaimId,omitempty"`
+	Selector                map[string]string `json:"selector,omitempty" yaml:"selector,omitempty"`
+	UsedPercentage          int64             `json:"usedPercentage,omitempty" yaml:"usedPercentage,omitempty"`
+}
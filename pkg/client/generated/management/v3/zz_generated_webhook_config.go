@@ -3,10 +3,12 @@
 const (
 	WebhookConfigType          = "webhookConfig"
 	WebhookConfigFieldProxyURL = "proxyUrl"
+	WebhookConfigFieldSecret   = "secret"
 	WebhookConfigFieldURL      = "url"
 )
 
 type WebhookConfig struct {
 	ProxyURL string `json:"proxyUrl,omitempty" yaml:"proxyUrl,omitempty"`
+	Secret   string `json:"secret,omitempty" yaml:"secret,omitempty"`
 	URL      string `json:"url,omitempty" yaml:"url,omitempty"`
 }
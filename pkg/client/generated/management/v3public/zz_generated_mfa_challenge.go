@@ -0,0 +1,12 @@
+package client
+
+const (
+	MFAChallengeType                    = "mfaChallenge"
+	MFAChallengeFieldChallengeToken     = "challengeToken"
+	MFAChallengeFieldEnrollmentRequired = "enrollmentRequired"
+)
+
+type MFAChallenge struct {
+	ChallengeToken     string `json:"challengeToken,omitempty" yaml:"challengeToken,omitempty"`
+	EnrollmentRequired bool   `json:"enrollmentRequired,omitempty" yaml:"enrollmentRequired,omitempty"`
+}
@@ -0,0 +1,10 @@
+package client
+
+const (
+	MFAEnrollInputType                = "mfaEnrollInput"
+	MFAEnrollInputFieldChallengeToken = "challengeToken"
+)
+
+type MFAEnrollInput struct {
+	ChallengeToken string `json:"challengeToken,omitempty" yaml:"challengeToken,omitempty"`
+}
@@ -0,0 +1,18 @@
+package client
+
+const (
+	MFALoginInputType                = "mfaLoginInput"
+	MFALoginInputFieldChallengeToken = "challengeToken"
+	MFALoginInputFieldCode           = "code"
+	MFALoginInputFieldDescription    = "description"
+	MFALoginInputFieldResponseType   = "responseType"
+	MFALoginInputFieldTTLMillis      = "ttl"
+)
+
+type MFALoginInput struct {
+	ChallengeToken string `json:"challengeToken,omitempty" yaml:"challengeToken,omitempty"`
+	Code           string `json:"code,omitempty" yaml:"code,omitempty"`
+	Description    string `json:"description,omitempty" yaml:"description,omitempty"`
+	ResponseType   string `json:"responseType,omitempty" yaml:"responseType,omitempty"`
+	TTLMillis      int64  `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+}
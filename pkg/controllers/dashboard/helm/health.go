@@ -0,0 +1,291 @@
+package helm
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	catalog "github.com/rancher/rancher/pkg/apis/catalog.cattle.io/v1"
+	"github.com/rancher/wrangler/pkg/condition"
+	"github.com/rancher/wrangler/pkg/kstatus"
+	"github.com/rancher/wrangler/pkg/yaml"
+	"github.com/sirupsen/logrus"
+	"helm.sh/helm/v3/pkg/action"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta2 "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	defaultHealthTimeout = 5 * time.Minute
+	healthCheckInterval  = 5 * time.Second
+)
+
+var (
+	operationCompleted  = condition.Cond(catalog.OperationCompleted)
+	operationHealthy    = condition.Cond(catalog.OperationHealthy)
+	operationRolledBack = condition.Cond(catalog.OperationRolledBack)
+)
+
+// checkUpgradeHealth waits for the workloads of every upgraded release to become ready and, if they do not
+// within the timeout of the upgrade policy, rolls each unhealthy release back to its revision from when the upgrade
+// was started.
+func (o *operationHandler) checkUpgradeHealth(operation *catalog.Operation, status catalog.OperationStatus, finished time.Time) (catalog.OperationStatus, error) {
+	if operationHealthy.IsTrue(&status) {
+		kstatus.SetActive(&status)
+		return status, nil
+	} else if operationHealthy.IsFalse(&status) {
+		// the result is final, it was already acted on
+		return status, nil
+	}
+
+	helmcfg := &action.Configuration{}
+	if err := helmcfg.Init(o.restClientGetter, status.Namespace, "", logrus.Infof); err != nil {
+		return status, err
+	}
+
+	releases := status.UpgradeReleases
+	if len(releases) == 0 {
+		releases = []string{status.Release}
+	}
+
+	// copy the revisions, the map is shared with the cached operation
+	revisions := make(map[string]int, len(releases))
+	for name, revision := range status.UpgradeRevisions {
+		revisions[name] = revision
+	}
+	status.UpgradeRevisions = revisions
+
+	var (
+		notReady  []string
+		unhealthy []string
+	)
+	for _, name := range releases {
+		release, err := action.NewGet(helmcfg).Run(name)
+		if err != nil {
+			return status, err
+		}
+
+		if revision, ok := revisions[name]; !ok {
+			revisions[name] = release.Version
+		} else if release.Version != revision {
+			operationHealthy.Unknown(&status)
+			operationHealthy.Message(&status, fmt.Sprintf("revision %d of release %s was superseded by revision %d", revision, name, release.Version))
+			kstatus.SetActive(&status)
+			return status, nil
+		}
+
+		workloads, err := o.notReadyWorkloads(status.Namespace, release.Manifest)
+		if err != nil {
+			return status, err
+		}
+		if len(workloads) > 0 {
+			notReady = append(notReady, workloads...)
+			unhealthy = append(unhealthy, name)
+		}
+	}
+
+	if len(notReady) == 0 {
+		operationHealthy.True(&status)
+		operationHealthy.Message(&status, "")
+		kstatus.SetActive(&status)
+		return status, nil
+	}
+
+	timeout := defaultHealthTimeout
+	if status.UpgradePolicy.HealthTimeout != nil {
+		timeout = status.UpgradePolicy.HealthTimeout.Duration
+	}
+	if time.Now().Before(finished.Add(timeout)) {
+		kstatus.SetTransitioning(&status, fmt.Sprintf("waiting for %s to become ready", strings.Join(notReady, ", ")))
+		o.operations.EnqueueAfter(operation.Namespace, operation.Name, healthCheckInterval)
+		return status, nil
+	}
+
+	reason := fmt.Sprintf("%s not ready after %s", strings.Join(notReady, ", "), timeout)
+	operationHealthy.False(&status)
+	operationHealthy.Message(&status, reason)
+
+	if !status.UpgradePolicy.RollbackOnFailure {
+		kstatus.SetError(&status, reason)
+		return status, nil
+	}
+
+	var rolledBack, failed []string
+	for _, name := range unhealthy {
+		revision := status.RollbackRevisions[name]
+		if err := o.rollback(&status, name, revision); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		logrus.Infof("Rolled back release %s/%s to revision %d: %s", status.Namespace, name, revision, reason)
+		rolledBack = append(rolledBack, fmt.Sprintf("%s to revision %d", name, revision))
+	}
+
+	if len(failed) > 0 {
+		operationRolledBack.False(&status)
+		operationRolledBack.Message(&status, strings.Join(failed, "; "))
+		kstatus.SetError(&status, fmt.Sprintf("%s, rollback failed: %s", reason, strings.Join(failed, "; ")))
+		return status, nil
+	}
+
+	operationRolledBack.True(&status)
+	operationRolledBack.Message(&status, fmt.Sprintf("rolled back %s: %s", strings.Join(rolledBack, ", "), reason))
+	kstatus.SetError(&status, reason)
+	return status, nil
+}
+
+// rollback rolls the release back to the revision from before the upgrade. It runs as the user of the operation, so
+// that the rollback can not change anything the upgrade could not.
+func (o *operationHandler) rollback(status *catalog.OperationStatus, releaseName string, revision int) error {
+	if revision < 1 {
+		return fmt.Errorf("no revision from before the upgrade to roll back to")
+	}
+	if status.User == "" {
+		return fmt.Errorf("operation does not record the user to roll back as")
+	}
+
+	helmcfg := &action.Configuration{}
+	getter := &impersonatingRESTClientGetter{
+		RESTClientGetter: o.restClientGetter,
+		user:             status.User,
+		groups:           status.UserGroups,
+	}
+	if err := helmcfg.Init(getter, status.Namespace, "", logrus.Infof); err != nil {
+		return err
+	}
+
+	rollback := action.NewRollback(helmcfg)
+	rollback.Version = revision
+	rollback.MaxHistory = status.UpgradeMaxHistory
+	return rollback.Run(releaseName)
+}
+
+// impersonatingRESTClientGetter makes the requests of helm as the given user.
+type impersonatingRESTClientGetter struct {
+	genericclioptions.RESTClientGetter
+	user   string
+	groups []string
+}
+
+func (i *impersonatingRESTClientGetter) ToRESTConfig() (*rest.Config, error) {
+	config, err := i.RESTClientGetter.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	config = rest.CopyConfig(config)
+	config.Impersonate = rest.ImpersonationConfig{
+		UserName: i.user,
+		Groups:   i.groups,
+	}
+	return config, nil
+}
+
+// notReadyWorkloads returns the Deployments, StatefulSets and DaemonSets of the manifest that are not ready.
+func (o *operationHandler) notReadyWorkloads(namespace, manifest string) ([]string, error) {
+	objs, err := yaml.ToObjects(bytes.NewReader([]byte(manifest)))
+	if err != nil {
+		return nil, err
+	}
+
+	var notReady []string
+	for _, obj := range objs {
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		if gvk.Group != appsv1.GroupName {
+			continue
+		}
+		meta, err := meta2.Accessor(obj)
+		if err != nil {
+			return nil, err
+		}
+		ns := meta.GetNamespace()
+		if ns == "" {
+			ns = namespace
+		}
+
+		var ready bool
+		switch gvk.Kind {
+		case "Deployment":
+			deployment, err := o.k8s.AppsV1().Deployments(ns).Get(o.ctx, meta.GetName(), metav1.GetOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+			ready = err == nil && deploymentReady(deployment)
+		case "StatefulSet":
+			statefulSet, err := o.k8s.AppsV1().StatefulSets(ns).Get(o.ctx, meta.GetName(), metav1.GetOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+			ready = err == nil && statefulSetReady(statefulSet)
+		case "DaemonSet":
+			daemonSet, err := o.k8s.AppsV1().DaemonSets(ns).Get(o.ctx, meta.GetName(), metav1.GetOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+			ready = err == nil && daemonSetReady(daemonSet)
+		default:
+			continue
+		}
+
+		if !ready {
+			notReady = append(notReady, fmt.Sprintf("%s %s/%s", strings.ToLower(gvk.Kind), ns, meta.GetName()))
+		}
+	}
+
+	return notReady, nil
+}
+
+// deploymentReady follows the checks of kubectl rollout status.
+func deploymentReady(deployment *appsv1.Deployment) bool {
+	if deployment.Spec.Paused {
+		return true
+	}
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		return false
+	}
+
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	return deployment.Status.UpdatedReplicas >= replicas &&
+		deployment.Status.Replicas <= deployment.Status.UpdatedReplicas &&
+		deployment.Status.AvailableReplicas >= deployment.Status.UpdatedReplicas
+}
+
+func statefulSetReady(statefulSet *appsv1.StatefulSet) bool {
+	if statefulSet.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+		return true
+	}
+	if statefulSet.Status.ObservedGeneration < statefulSet.Generation {
+		return false
+	}
+
+	replicas := int32(1)
+	if statefulSet.Spec.Replicas != nil {
+		replicas = *statefulSet.Spec.Replicas
+	}
+	if statefulSet.Status.ReadyReplicas < replicas {
+		return false
+	}
+
+	if rollingUpdate := statefulSet.Spec.UpdateStrategy.RollingUpdate; rollingUpdate != nil && rollingUpdate.Partition != nil {
+		return statefulSet.Status.UpdatedReplicas >= replicas-*rollingUpdate.Partition
+	}
+	return statefulSet.Status.UpdateRevision == statefulSet.Status.CurrentRevision
+}
+
+func daemonSetReady(daemonSet *appsv1.DaemonSet) bool {
+	if daemonSet.Spec.UpdateStrategy.Type == appsv1.OnDeleteDaemonSetStrategyType {
+		return true
+	}
+	if daemonSet.Status.ObservedGeneration < daemonSet.Generation {
+		return false
+	}
+	return daemonSet.Status.UpdatedNumberScheduled >= daemonSet.Status.DesiredNumberScheduled &&
+		daemonSet.Status.NumberAvailable >= daemonSet.Status.DesiredNumberScheduled
+}
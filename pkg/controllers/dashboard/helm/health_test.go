@@ -0,0 +1,160 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+)
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func TestDeploymentReady(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     appsv1.DeploymentSpec
+		status   appsv1.DeploymentStatus
+		expected bool
+	}{
+		{
+			"rolled out",
+			appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+			appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2},
+			true,
+		},
+		{
+			"not observed",
+			appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+			appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2},
+			false,
+		},
+		{
+			"old replicas pending termination",
+			appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+			appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 2, AvailableReplicas: 3},
+			false,
+		},
+		{
+			"updated replicas unavailable",
+			appsv1.DeploymentSpec{},
+			appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 1, UpdatedReplicas: 1},
+			false,
+		},
+		{
+			"paused",
+			appsv1.DeploymentSpec{Paused: true},
+			appsv1.DeploymentStatus{},
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       tt.spec,
+				Status:     tt.status,
+			}
+			assert.Equal(t, tt.expected, deploymentReady(deployment))
+		})
+	}
+}
+
+func TestStatefulSetReady(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     appsv1.StatefulSetSpec
+		status   appsv1.StatefulSetStatus
+		expected bool
+	}{
+		{
+			"rolled out",
+			appsv1.StatefulSetSpec{Replicas: int32Ptr(3)},
+			appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 3, CurrentRevision: "r2", UpdateRevision: "r2"},
+			true,
+		},
+		{
+			"revision not rolled out",
+			appsv1.StatefulSetSpec{Replicas: int32Ptr(3)},
+			appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 3, CurrentRevision: "r1", UpdateRevision: "r2"},
+			false,
+		},
+		{
+			"partition rolled out",
+			appsv1.StatefulSetSpec{
+				Replicas: int32Ptr(3),
+				UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+					Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+					RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: int32Ptr(2)},
+				},
+			},
+			appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 3, UpdatedReplicas: 1, CurrentRevision: "r1", UpdateRevision: "r2"},
+			true,
+		},
+		{
+			"not ready",
+			appsv1.StatefulSetSpec{Replicas: int32Ptr(3)},
+			appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 2, CurrentRevision: "r2", UpdateRevision: "r2"},
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statefulSet := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       tt.spec,
+				Status:     tt.status,
+			}
+			assert.Equal(t, tt.expected, statefulSetReady(statefulSet))
+		})
+	}
+}
+
+func TestDaemonSetReady(t *testing.T) {
+	daemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Generation: 2},
+		Status: appsv1.DaemonSetStatus{
+			ObservedGeneration:     2,
+			DesiredNumberScheduled: 3,
+			UpdatedNumberScheduled: 3,
+			NumberAvailable:        2,
+		},
+	}
+	assert.False(t, daemonSetReady(daemonSet))
+
+	daemonSet.Status.NumberAvailable = 3
+	assert.True(t, daemonSetReady(daemonSet))
+
+	daemonSet.Generation = 3
+	assert.False(t, daemonSetReady(daemonSet))
+}
+
+type fakeRESTClientGetter struct {
+	genericclioptions.RESTClientGetter
+	config *rest.Config
+}
+
+func (f *fakeRESTClientGetter) ToRESTConfig() (*rest.Config, error) {
+	return f.config, nil
+}
+
+func TestImpersonatingRESTClientGetter(t *testing.T) {
+	config := &rest.Config{Host: "https://127.0.0.1:6443"}
+	getter := &impersonatingRESTClientGetter{
+		RESTClientGetter: &fakeRESTClientGetter{config: config},
+		user:             "u-abcde",
+		groups:           []string{"system:authenticated"},
+	}
+
+	impersonated, err := getter.ToRESTConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, config.Host, impersonated.Host)
+	assert.Equal(t, rest.ImpersonationConfig{UserName: "u-abcde", Groups: []string{"system:authenticated"}}, impersonated.Impersonate)
+	assert.Empty(t, config.Impersonate.UserName, "expected the shared config not to be changed")
+}
@@ -3,6 +3,7 @@
 import (
 	"context"
 	"fmt"
+	"time"
 
 	catalog "github.com/rancher/rancher/pkg/apis/catalog.cattle.io/v1"
 	catalogcontrollers "github.com/rancher/rancher/pkg/generated/controllers/catalog.cattle.io/v1"
@@ -12,6 +13,7 @@
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 )
@@ -21,22 +23,27 @@
 )
 
 type operationHandler struct {
-	ctx             context.Context
-	pods            corecontrollers.PodCache
-	k8s             kubernetes.Interface
-	operationsCache catalogcontrollers.OperationCache
+	ctx              context.Context
+	pods             corecontrollers.PodCache
+	k8s              kubernetes.Interface
+	restClientGetter genericclioptions.RESTClientGetter
+	operations       catalogcontrollers.OperationController
+	operationsCache  catalogcontrollers.OperationCache
 }
 
 func RegisterOperations(ctx context.Context,
 	k8s kubernetes.Interface,
+	restClientGetter genericclioptions.RESTClientGetter,
 	pods corecontrollers.PodController,
 	operations catalogcontrollers.OperationController) {
 
 	o := operationHandler{
-		ctx:             ctx,
-		k8s:             k8s,
-		pods:            pods.Cache(),
-		operationsCache: operations.Cache(),
+		ctx:              ctx,
+		k8s:              k8s,
+		restClientGetter: restClientGetter,
+		pods:             pods.Cache(),
+		operations:       operations,
+		operationsCache:  operations.Cache(),
 	}
 
 	operations.Cache().AddIndexer(podIndex, indexOperationsByPod)
@@ -69,8 +76,9 @@ func (o *operationHandler) onOperationChange(operation *catalog.Operation, statu
 
 	pod, err := o.pods.Get(status.PodNamespace, status.PodName)
 	if apierrors.IsNotFound(err) {
-		kstatus.SetActive(&status)
-		return status, nil
+		return o.onPodRemoved(operation, status)
+	} else if err != nil {
+		return status, err
 	}
 
 	for _, container := range pod.Status.ContainerStatuses {
@@ -84,6 +92,20 @@ func (o *operationHandler) onOperationChange(operation *catalog.Operation, statu
 		} else if container.State.Terminated != nil {
 			status.PodCreated = true
 			if container.State.Terminated.ExitCode == 0 {
+				operationCompleted.True(&status)
+				operationCompleted.Message(&status, "")
+				operationCompleted.LastUpdated(&status, container.State.Terminated.FinishedAt.UTC().Format(time.RFC3339))
+			} else {
+				operationCompleted.False(&status)
+				operationCompleted.Message(&status, container.State.Terminated.Message)
+			}
+
+			if container.State.Terminated.ExitCode == 0 && status.Action == "upgrade" && status.UpgradePolicy != nil {
+				status, err = o.checkUpgradeHealth(operation, status, container.State.Terminated.FinishedAt.Time)
+				if err != nil {
+					return status, err
+				}
+			} else if container.State.Terminated.ExitCode == 0 {
 				kstatus.SetActive(&status)
 			} else {
 				kstatus.SetError(&status,
@@ -104,6 +126,31 @@ func (o *operationHandler) onOperationChange(operation *catalog.Operation, statu
 	return status, nil
 }
 
+// onPodRemoved settles an operation whose pod is gone. A pending health check of an upgrade continues from the
+// time helm finished, an operation whose pod was removed before helm finished fails.
+func (o *operationHandler) onPodRemoved(operation *catalog.Operation, status catalog.OperationStatus) (catalog.OperationStatus, error) {
+	switch {
+	case !status.PodCreated:
+		// the pod is not in the cache yet, the operation is enqueued again once it is
+		return status, nil
+	case operationCompleted.IsFalse(&status):
+		// helm failed, the error is already recorded
+		return status, nil
+	case operationCompleted.IsTrue(&status):
+		if status.Action == "upgrade" && status.UpgradePolicy != nil {
+			finished, err := time.Parse(time.RFC3339, operationCompleted.GetLastUpdated(&status))
+			if err != nil {
+				return status, fmt.Errorf("parsing completion time of operation %s/%s: %w", operation.Namespace, operation.Name, err)
+			}
+			return o.checkUpgradeHealth(operation, status, finished)
+		}
+		kstatus.SetActive(&status)
+	case kstatus.Reconciling.IsTrue(&status):
+		kstatus.SetError(&status, fmt.Sprintf("pod %s/%s was removed before the operation completed", status.PodNamespace, status.PodName))
+	}
+	return status, nil
+}
+
 func (o *operationHandler) cleanup(pod *corev1.Pod) error {
 	running := false
 	success := false
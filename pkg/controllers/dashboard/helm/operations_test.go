@@ -0,0 +1,74 @@
+package helm
+
+import (
+	"testing"
+
+	catalog "github.com/rancher/rancher/pkg/apis/catalog.cattle.io/v1"
+	"github.com/rancher/wrangler/pkg/kstatus"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestOnPodRemoved(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      func(status *catalog.OperationStatus)
+		stalled     bool
+		reconciling bool
+	}{
+		{
+			name: "pod not observed yet",
+			status: func(status *catalog.OperationStatus) {
+				status.PodCreated = false
+				kstatus.SetTransitioning(status, "waiting to run operation")
+			},
+			reconciling: true,
+		},
+		{
+			name: "removed while running",
+			status: func(status *catalog.OperationStatus) {
+				kstatus.SetTransitioning(status, "running operation")
+			},
+			stalled: true,
+		},
+		{
+			name: "helm failed",
+			status: func(status *catalog.OperationStatus) {
+				operationCompleted.False(status)
+				kstatus.SetError(status, "failed exit code: 1")
+			},
+			stalled: true,
+		},
+		{
+			name: "helm succeeded",
+			status: func(status *catalog.OperationStatus) {
+				operationCompleted.True(status)
+				kstatus.SetTransitioning(status, "running operation")
+			},
+		},
+		{
+			name: "finished before completion was recorded",
+			status: func(status *catalog.OperationStatus) {
+				kstatus.SetActive(status)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &operationHandler{}
+			status := catalog.OperationStatus{
+				Action:       "install",
+				PodName:      "helm-operation-abcde",
+				PodNamespace: "cattle-system",
+				PodCreated:   true,
+			}
+			tt.status(&status)
+
+			status, err := o.onPodRemoved(&catalog.Operation{ObjectMeta: metav1.ObjectMeta{Name: "op"}}, status)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.stalled, kstatus.Stalled.IsTrue(&status))
+			assert.Equal(t, tt.reconciling, kstatus.Reconciling.IsTrue(&status))
+		})
+	}
+}
@@ -20,6 +20,7 @@ func Register(ctx context.Context, wrangler *wrangler.Context) {
 		wrangler.Catalog.App())
 	RegisterOperations(ctx,
 		wrangler.K8s,
+		wrangler.RESTClientGetter,
 		wrangler.Core.Pod(),
 		wrangler.Catalog.Operation())
 }
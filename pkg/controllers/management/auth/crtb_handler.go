@@ -37,6 +37,7 @@
 )
 
 var clusterManagmentPlaneResources = map[string]string{
+	"alertsilences":               "management.cattle.io",
 	"clusterscans":                "management.cattle.io",
 	"catalogtemplates":            "management.cattle.io",
 	"catalogtemplateversions":     "management.cattle.io",
@@ -2,83 +2,171 @@
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"reflect"
 	"time"
 
-	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
-
-	v1 "github.com/rancher/rancher/pkg/generated/norman/core/v1"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/metrics"
 	"github.com/rancher/rancher/pkg/rkecerts"
 	"github.com/rancher/rancher/pkg/types/config"
-	rkeCluster "github.com/rancher/rke/cluster"
+	"github.com/rancher/wrangler/pkg/ticker"
 	"github.com/sirupsen/logrus"
-	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	resyncInterval = time.Hour
+
+	// reportedAnnotation holds the severity last reported for each certificate of the cluster, so that an event is
+	// only recorded when a certificate crosses a threshold, also across restarts of rancher
+	reportedAnnotation = "management.cattle.io/reported-certificate-expirations"
+
+	reasonCertificateExpired  = "CertificateExpired"
+	reasonCertificateExpiring = "CertificateExpiring"
+	reasonCertificateRenewed  = "CertificateRenewed"
 )
 
-// This controller handles cert expiration for local cluster only
+// This controller reports the expiring certificates of every cluster as events and metrics. The expiration
+// dates are collected into the cluster status by the certificate expiration controller of each user cluster.
 func Register(ctx context.Context, management *config.ManagementContext) {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: management.K8sClient.CoreV1().Events("")})
+	go func() {
+		<-ctx.Done()
+		broadcaster.Shutdown()
+	}()
+
 	c := &certsExpiration{
-		clusters:        management.Management.Clusters(""),
-		configMapLister: management.Core.ConfigMaps("kube-system").Controller().Lister(),
+		clusters:      management.Management.Clusters(""),
+		clusterLister: management.Management.Clusters("").Controller().Lister(),
+		recorder:      broadcaster.NewRecorder(management.Scheme, corev1.EventSource{Component: "rancher-certificate-expiration"}),
 	}
-	management.Management.Clusters("").AddHandler(ctx, "certificate-expiration", c.sync)
+	management.Management.Clusters("").AddHandler(ctx, "certificate-expiration-monitor", c.sync)
+	go c.resync(ctx, resyncInterval)
 }
 
 type certsExpiration struct {
-	clusters        v3.ClusterInterface
-	configMapLister v1.ConfigMapLister
+	clusters      v3.ClusterInterface
+	clusterLister v3.ClusterLister
+	recorder      record.EventRecorder
 }
 
 func (c *certsExpiration) sync(key string, cluster *v3.Cluster) (runtime.Object, error) {
-	if cluster == nil || cluster.Name != "local" {
-		return cluster, nil // We are only checking local cluster
-	}
-	cm, err := c.configMapLister.Get("kube-system", rkeCluster.FullStateConfigMapName)
-	if err != nil {
-		if k8sErrors.IsNotFound(err) {
-			return cluster, nil // not an rke cluster, nothing we can do
-		}
-		return cluster, err
+	if cluster == nil || cluster.DeletionTimestamp != nil {
+		metrics.UnsetCertificateExpirations(key)
+		return cluster, nil
 	}
-	certBundle, err := rkecerts.CertBundleFromConfig(cm)
-	if err != nil {
-		return cluster, err
-	}
-	rkecerts.CleanCertificateBundle(certBundle)
+	return c.check(cluster)
+}
 
-	certsExpInfo := map[string]v32.CertExpiration{}
-	for certName, certObj := range certBundle {
-		info, err := rkecerts.GetCertExpiration(certObj.CertificatePEM)
+// resync checks the certificates of every cluster periodically, thresholds are crossed with time passing, not with
+// the cluster changing.
+func (c *certsExpiration) resync(ctx context.Context, interval time.Duration) {
+	for range ticker.Context(ctx, interval) {
+		clusters, err := c.clusterLister.List("", labels.Everything())
 		if err != nil {
-			logrus.Debugf("failed to get expiration date for certificate [%s] for local cluster: %v", certName, err)
+			logrus.Errorf("failed to list clusters for certificate expiration: %v", err)
 			continue
 		}
-		certsExpInfo[certName] = info
-		err = logCertExpirationWarning(certName, info)
+		for _, cluster := range clusters {
+			if cluster.DeletionTimestamp != nil {
+				continue
+			}
+			if _, err := c.check(cluster); err != nil && !apierrors.IsConflict(err) {
+				logrus.Errorf("failed to check certificate expiration of cluster [%s]: %v", cluster.Name, err)
+			}
+		}
+	}
+}
+
+func (c *certsExpiration) check(cluster *v3.Cluster) (*v3.Cluster, error) {
+	critical, warning := rkecerts.ExpirationThresholds()
+	now := time.Now().UTC()
+
+	expirations := map[string]time.Time{}
+	severities := map[string]string{}
+	for certName, certExp := range cluster.Status.CertificatesExpiration {
+		date, err := time.Parse(time.RFC3339, certExp.ExpirationDate)
 		if err != nil {
-			logrus.Warnf("certificate [%s] from local cluster has or will expire and date is corrupted: %v", certName, err)
+			logrus.Warnf("certificate [%s] from cluster [%s] has or will expire and date is corrupted: %v", certName, cluster.Name, err)
 			continue
 		}
+		expirations[certName] = date
+		severities[certName] = rkecerts.ExpirationSeverity(date, now, critical, warning)
 	}
-	// Update certExpiration on cluster obj in order for it to display in API, and the UI if expiring
-	if !reflect.DeepEqual(cluster.Status.CertificatesExpiration, certsExpInfo) {
-		cluster.Status.CertificatesExpiration = certsExpInfo
-		return c.clusters.Update(cluster)
+
+	metrics.SetCertificateExpirations(cluster.Name, expirations)
+
+	reported := reportedSeverities(cluster)
+	if reflect.DeepEqual(reported, severities) {
+		return cluster, nil
 	}
-	return cluster, nil
-}
 
-func logCertExpirationWarning(name string, certExp v32.CertExpiration) error {
-	date, err := time.Parse(time.RFC3339, certExp.ExpirationDate)
+	data, err := json.Marshal(severities)
+	if err != nil {
+		return cluster, err
+	}
+	updated := cluster.DeepCopy()
+	if updated.Annotations == nil {
+		updated.Annotations = map[string]string{}
+	}
+	updated.Annotations[reportedAnnotation] = string(data)
+	// the events are recorded once the severities are stored, so that a failed update does not report them twice
+	updated, err = c.clusters.Update(updated)
 	if err != nil {
-		return err
+		return cluster, err
 	}
-	if time.Now().UTC().After(date) { // warn if expired
-		logrus.Warnf("Certificate from local cluster has expired: %s", name)
-	} else if time.Now().UTC().AddDate(0, 1, 0).After(date) { // warn if within a month
-		logrus.Warnf("Certificate from local cluster will expire soon: %s", name)
+	c.report(updated, reported, expirations, severities)
+	return updated, nil
+}
+
+// reportedSeverities returns the severities last reported for the certificates of the cluster.
+func reportedSeverities(cluster *v3.Cluster) map[string]string {
+	reported := map[string]string{}
+	if data := cluster.Annotations[reportedAnnotation]; data != "" {
+		if err := json.Unmarshal([]byte(data), &reported); err != nil {
+			logrus.Debugf("failed to parse reported certificate expirations of cluster [%s]: %v", cluster.Name, err)
+			return map[string]string{}
+		}
+	}
+	return reported
+}
+
+func (c *certsExpiration) report(cluster *v3.Cluster, reported map[string]string, expirations map[string]time.Time, severities map[string]string) {
+	ref := &corev1.ObjectReference{
+		APIVersion: v3.ClusterGroupVersionKind.GroupVersion().String(),
+		Kind:       v3.ClusterGroupVersionKind.Kind,
+		Name:       cluster.Name,
+		UID:        cluster.UID,
+	}
+
+	for certName, severity := range severities {
+		if reported[certName] == severity {
+			continue
+		}
+
+		date := expirations[certName].Format(time.RFC3339)
+		switch severity {
+		case rkecerts.CertificateExpired:
+			logrus.Warnf("Certificate [%s] from cluster [%s] has expired", certName, cluster.Name)
+			c.recorder.Event(ref, corev1.EventTypeWarning, reasonCertificateExpired,
+				fmt.Sprintf("Certificate %s expired on %s", certName, date))
+		case rkecerts.CertificateCritical, rkecerts.CertificateWarning:
+			logrus.Warnf("Certificate [%s] from cluster [%s] will expire soon", certName, cluster.Name)
+			c.recorder.Event(ref, corev1.EventTypeWarning, reasonCertificateExpiring,
+				fmt.Sprintf("Certificate %s expires on %s (%s)", certName, date, severity))
+		default:
+			if reported[certName] != "" {
+				c.recorder.Event(ref, corev1.EventTypeNormal, reasonCertificateRenewed,
+					fmt.Sprintf("Certificate %s was renewed and expires on %s", certName, date))
+			}
+		}
 	}
-	return nil
 }
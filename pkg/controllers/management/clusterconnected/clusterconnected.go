@@ -9,6 +9,7 @@
 	"github.com/rancher/rancher/pkg/api/steve/proxy"
 	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	managementcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/metrics/tunnel"
 	"github.com/rancher/rancher/pkg/wrangler"
 	"github.com/rancher/remotedialer"
 	"github.com/rancher/wrangler/pkg/condition"
@@ -27,6 +28,7 @@ func Register(ctx context.Context, wrangler *wrangler.Context) {
 	c := checker{
 		clusterCache: wrangler.Mgmt.Cluster().Cache(),
 		clusters:     wrangler.Mgmt.Cluster(),
+		nodeCache:    wrangler.Mgmt.Node().Cache(),
 		tunnelServer: wrangler.TunnelServer,
 	}
 
@@ -42,6 +44,7 @@ func Register(ctx context.Context, wrangler *wrangler.Context) {
 type checker struct {
 	clusterCache managementcontrollers.ClusterCache
 	clusters     managementcontrollers.ClusterClient
+	nodeCache    managementcontrollers.NodeCache
 	tunnelServer *remotedialer.Server
 }
 
@@ -59,11 +62,13 @@ func (c *checker) check() error {
 	return nil
 }
 
-func (c *checker) hasSession(cluster *v3.Cluster) bool {
+// hasSession returns whether the cluster agent is connected and answers a ping through its tunnel, and the round trip
+// time of the ping.
+func (c *checker) hasSession(cluster *v3.Cluster) (bool, time.Duration) {
 	clientKey := proxy.Prefix + cluster.Name
 	hasSession := c.tunnelServer.HasSession(clientKey)
 	if !hasSession {
-		return false
+		return false, 0
 	}
 
 	dialer := c.tunnelServer.Dialer(clientKey)
@@ -74,15 +79,32 @@ func (c *checker) hasSession(cluster *v3.Cluster) bool {
 	client := &http.Client{
 		Transport: transport,
 	}
+	start := time.Now()
 	resp, err := client.Get("http://not-used/ping")
 	if err != nil {
-		return false
+		return false, 0
 	}
+	rtt := time.Since(start)
 	defer func() {
 		ioutil.ReadAll(resp.Body)
 		resp.Body.Close()
 	}()
-	return resp.StatusCode == http.StatusOK
+	return resp.StatusCode == http.StatusOK, rtt
+}
+
+// countNodeAgents records the number of connected node agents of the cluster.
+func (c *checker) countNodeAgents(cluster *v3.Cluster) {
+	nodes, err := c.nodeCache.List(cluster.Name, labels.Everything())
+	if err != nil {
+		return
+	}
+	connected := 0
+	for _, node := range nodes {
+		if c.tunnelServer.HasSession(cluster.Name + ":" + node.Name) {
+			connected++
+		}
+	}
+	tunnel.SetNodeAgentsConnected(cluster.Name, connected)
 }
 
 func (c *checker) checkCluster(cluster *v3.Cluster) error {
@@ -90,7 +112,9 @@ func (c *checker) checkCluster(cluster *v3.Cluster) error {
 		return nil
 	}
 
-	hasSession := c.hasSession(cluster)
+	hasSession, rtt := c.hasSession(cluster)
+	tunnel.ObserveClusterAgent(cluster.Name, hasSession, rtt)
+	c.countNodeAgents(cluster)
 	// The simpler condition of hasSession == Connected.IsTrue(cluster) is not
 	// used because it treat a non-existent conditions as False
 	if hasSession && Connected.IsTrue(cluster) {
@@ -0,0 +1,62 @@
+package clusterstats
+
+import (
+	"context"
+	"time"
+
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/statshistory"
+	"github.com/rancher/wrangler/pkg/ticker"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// historyCheckInterval is how often the clusters are checked for a due sample, the samples are taken at the interval
+// of the stats-history-interval-minutes setting.
+const historyCheckInterval = time.Minute
+
+// historyRecorder samples the totals the StatsAggregator keeps on the status of the clusters into their stats history.
+type historyRecorder struct {
+	clusterLister v3.ClusterLister
+	recorder      *statshistory.Recorder
+}
+
+func (h *historyRecorder) run(ctx context.Context) {
+	for range ticker.Context(ctx, historyCheckInterval) {
+		if err := h.record(time.Now()); err != nil {
+			logrus.Errorf("failed to record cluster stats history: %v", err)
+		}
+	}
+}
+
+func (h *historyRecorder) record(now time.Time) error {
+	clusters, err := h.clusterLister.List("", labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	for _, cluster := range clusters {
+		// clusters that never reported their nodes have no stats
+		if cluster.DeletionTimestamp != nil || cluster.Status.Capacity == nil {
+			continue
+		}
+		sample := statshistory.Sample{
+			Time:        now,
+			Capacity:    statshistory.ResourcesFromList(cluster.Status.Capacity),
+			Allocatable: statshistory.ResourcesFromList(cluster.Status.Allocatable),
+			Requested:   statshistory.ResourcesFromList(cluster.Status.Requested),
+			Limits:      statshistory.ResourcesFromList(cluster.Status.Limits),
+		}
+		owner := metav1.OwnerReference{
+			APIVersion: "management.cattle.io/v3",
+			Kind:       "Cluster",
+			Name:       cluster.Name,
+			UID:        cluster.UID,
+		}
+		if err := h.recorder.Record(owner, cluster.Name, statshistory.ClusterConfigMapName(), sample); err != nil {
+			logrus.Debugf("failed to record stats history of cluster [%s]: %v", cluster.Name, err)
+		}
+	}
+	return nil
+}
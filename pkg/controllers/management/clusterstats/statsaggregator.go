@@ -12,6 +12,7 @@
 	"github.com/pkg/errors"
 	"github.com/rancher/rancher/pkg/clustermanager"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/statshistory"
 	"github.com/rancher/rancher/pkg/types/config"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -52,6 +53,12 @@ func Register(ctx context.Context, management *config.ManagementContext, cluster
 
 	clustersClient.AddHandler(ctx, "cluster-stats", s.sync)
 	machinesClient.AddHandler(ctx, "cluster-stats", s.machineChanged)
+
+	history := &historyRecorder{
+		clusterLister: clustersClient.Controller().Lister(),
+		recorder:      statshistory.NewRecorder(management.Core.ConfigMaps("")),
+	}
+	go history.run(ctx)
 }
 
 func (s *StatsAggregator) sync(key string, cluster *v3.Cluster) (runtime.Object, error) {
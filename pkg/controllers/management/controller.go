@@ -66,6 +66,7 @@ func Register(ctx context.Context, management *config.ManagementContext, manager
 	// registration
 	management.Management.ClusterAlertGroups("").Controller()
 	management.Management.ClusterAlertRules("").Controller()
+	management.Management.AlertSilences("").Controller()
 
 	// Register last
 	auth.RegisterLate(ctx, management)
@@ -0,0 +1,145 @@
+package etcdbackup
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/rancher/rancher/pkg/etcdsnapshot"
+	corev1 "github.com/rancher/rancher/pkg/generated/norman/core/v1"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/namespace"
+	"github.com/rancher/rancher/pkg/ref"
+	"github.com/rancher/rancher/pkg/types/config/dialer"
+	"github.com/sirupsen/logrus"
+)
+
+// EncryptionKeyAnnotation holds the ID of the key the snapshot is encrypted with, it is not set for cleartext snapshots
+const EncryptionKeyAnnotation = "etcdbackup.cattle.io/encryption-key-id"
+
+// KeyRing returns the keys the snapshots of the cluster are encrypted with, or nil if encryption is not enabled.
+func KeyRing(secretLister corev1.SecretLister, cluster *v3.Cluster) (*etcdsnapshot.KeyRing, error) {
+	config := cluster.Spec.EtcdBackupEncryption
+	if config == nil || config.SecretName == "" {
+		return nil, nil
+	}
+	ns, name := ref.Parse(config.SecretName)
+	if ns == "" {
+		ns = namespace.GlobalNamespace
+	}
+	secret, err := secretLister.Get(ns, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get etcd backup encryption keys of cluster [%s]: %w", cluster.Name, err)
+	}
+	return etcdsnapshot.KeyRingFromSecret(secret, config.KeyID)
+}
+
+// Encrypt rewrites the snapshot of the backup in S3 encrypted with the active key of ring, decrypting it first if it
+// is encrypted with another key. The returned copy of the backup records the key and the checksum of the new snapshot.
+func Encrypt(ctx context.Context, dialerFactory dialer.Factory, b *v3.EtcdBackup, ring *etcdsnapshot.KeyRing) (*v3.EtcdBackup, error) {
+	return rewriteSnapshot(ctx, dialerFactory, b, ring, true)
+}
+
+// Decrypt rewrites the snapshot of the backup in S3 in cleartext, so that RKE can restore it. The returned copy of the
+// backup records the checksum of the new snapshot.
+func Decrypt(ctx context.Context, dialerFactory dialer.Factory, b *v3.EtcdBackup, ring *etcdsnapshot.KeyRing) (*v3.EtcdBackup, error) {
+	return rewriteSnapshot(ctx, dialerFactory, b, ring, false)
+}
+
+func rewriteSnapshot(ctx context.Context, dialerFactory dialer.Factory, b *v3.EtcdBackup, ring *etcdsnapshot.KeyRing, encrypt bool) (*v3.EtcdBackup, error) {
+	if encrypt && ring == nil {
+		return b, fmt.Errorf("no encryption keys for snapshot of backup [%s]", b.Name)
+	}
+	t, err := backupTarget(ctx, dialerFactory, b)
+	if err != nil {
+		return b, err
+	}
+	if t == nil {
+		return b, fmt.Errorf("backup [%s] is not stored in a backup target", b.Name)
+	}
+	name := snapshotFilename(b)
+	rc, err := t.Download(ctx, name)
+	if err != nil {
+		return b, err
+	}
+	defer rc.Close()
+
+	var (
+		br                 = bufio.NewReader(rc)
+		snapshot io.Reader = br
+		keyID    string
+		newKeyID string
+	)
+	if etcdsnapshot.IsEncrypted(br) {
+		snapshot, keyID, err = etcdsnapshot.Decrypt(br, ring)
+		if err != nil {
+			return b, err
+		}
+	}
+	if encrypt {
+		newKeyID = ring.Active
+	}
+	if keyID == newKeyID {
+		return setEncryptionKey(b, keyID, ""), nil
+	}
+
+	// the new snapshot is staged on disk, it must not replace the stored one unless it was read completely
+	f, err := ioutil.TempFile("", "etcd-snapshot-")
+	if err != nil {
+		return b, err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	h := sha256.New()
+	out := io.MultiWriter(f, h)
+	if encrypt {
+		w, err := etcdsnapshot.Encrypt(out, ring)
+		if err != nil {
+			return b, err
+		}
+		if _, err := io.Copy(w, snapshot); err != nil {
+			return b, err
+		}
+		if err := w.Close(); err != nil {
+			return b, err
+		}
+	} else if _, err := io.Copy(out, snapshot); err != nil {
+		return b, err
+	}
+
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return b, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return b, err
+	}
+	if err := t.Upload(ctx, name, f, size); err != nil {
+		return b, err
+	}
+
+	logrus.Infof("[etcd-backup] rewrote snapshot of backup [%s] with encryption key [%s], was [%s]", b.Name, newKeyID, keyID)
+	return setEncryptionKey(b, newKeyID, hex.EncodeToString(h.Sum(nil))), nil
+}
+
+func setEncryptionKey(b *v3.EtcdBackup, keyID, checksum string) *v3.EtcdBackup {
+	b = b.DeepCopy()
+	if b.Annotations == nil {
+		b.Annotations = map[string]string{}
+	}
+	if keyID == "" {
+		delete(b.Annotations, EncryptionKeyAnnotation)
+	} else {
+		b.Annotations[EncryptionKeyAnnotation] = keyID
+	}
+	if checksum != "" {
+		b.Annotations[ChecksumAnnotation] = checksum
+	}
+	return b
+}
@@ -4,13 +4,10 @@
 	"bytes"
 	"compress/gzip"
 	"context"
-	"crypto/tls"
-	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
 	"strings"
 	"time"
 
@@ -19,8 +16,9 @@
 	rketypes "github.com/rancher/rke/types"
 
 	minio "github.com/minio/minio-go/v7"
-	"github.com/minio/minio-go/v7/pkg/credentials"
 	"github.com/rancher/rancher/pkg/controllers/management/clusterprovisioner"
+	"github.com/rancher/rancher/pkg/etcdsnapshot/target"
+	corev1 "github.com/rancher/rancher/pkg/generated/norman/core/v1"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/kontainer-engine/drivers/rke"
 	"github.com/rancher/rancher/pkg/kontainer-engine/service"
@@ -39,7 +37,6 @@
 const (
 	clusterBackupCheckInterval = 5 * time.Minute
 	compressedExtension        = "zip"
-	s3Endpoint                 = "s3.amazonaws.com"
 )
 
 type Controller struct {
@@ -50,6 +47,8 @@ type Controller struct {
 	backupLister          v3.EtcdBackupLister
 	backupDriver          *service.EngineService
 	KontainerDriverLister v3.KontainerDriverLister
+	secretLister          corev1.SecretLister
+	dialer                dialer.Factory
 }
 
 func Register(ctx context.Context, management *config.ManagementContext) {
@@ -61,6 +60,8 @@ func Register(ctx context.Context, management *config.ManagementContext) {
 		backupLister:          management.Management.EtcdBackups("").Controller().Lister(),
 		backupDriver:          service.NewEngineService(clusterprovisioner.NewPersistentStore(management.Core.Namespaces(""), management.Core)),
 		KontainerDriverLister: management.Management.KontainerDrivers("").Controller().Lister(),
+		secretLister:          management.Core.Secrets("").Controller().Lister(),
+		dialer:                management.Dialer,
 	}
 
 	local := &rkedialerfactory.RKEDialerFactory{
@@ -80,6 +81,7 @@ func Register(ctx context.Context, management *config.ManagementContext) {
 
 	c.backupClient.AddLifecycle(ctx, "etcdbackup-controller", c)
 	go c.clusterBackupSync(ctx, clusterBackupCheckInterval)
+	go c.backupVerifySync(ctx, clusterBackupCheckInterval)
 }
 
 func (c *Controller) Create(b *v3.EtcdBackup) (runtime.Object, error) {
@@ -108,6 +110,11 @@ func (c *Controller) Create(b *v3.EtcdBackup) (runtime.Object, error) {
 		}
 	}
 	bObj, saveErr := c.etcdSaveWithBackoff(b)
+	verify := saveErr == nil && canVerify(b)
+	if verify {
+		v32.EtcdBackupConditionVerified.Unknown(bObj)
+		v32.EtcdBackupConditionVerified.Message(bObj, "verifying snapshot")
+	}
 	b, err = c.backupClient.Update(bObj.(*v3.EtcdBackup))
 	if err != nil {
 		return b, err
@@ -116,6 +123,10 @@ func (c *Controller) Create(b *v3.EtcdBackup) (runtime.Object, error) {
 	if saveErr != nil {
 		return b, fmt.Errorf("[etcd-backup] failed to perform etcd backup: %v", saveErr)
 	}
+	if verify {
+		// the whole snapshot is downloaded to encrypt and verify it, so it must not hold up the handler
+		go c.verifyNewBackup(cluster, b)
+	}
 	return b, nil
 }
 
@@ -124,6 +135,9 @@ func (c *Controller) Remove(b *v3.EtcdBackup) (runtime.Object, error) {
 	if err := c.etcdRemoveSnapshotWithBackoff(b); err != nil {
 		logrus.Errorf("[etcd-backup] giving up on deleting backup [%s]: %v", b.Name, err)
 	}
+	if err := c.deleteFromTarget(b); err != nil {
+		logrus.Errorf("[etcd-backup] failed to delete snapshot of backup [%s] from the backup target: %v", b.Name, err)
+	}
 	return b, nil
 }
 
@@ -252,9 +266,14 @@ func (c *Controller) etcdRemoveSnapshotWithBackoff(b *v3.EtcdBackup) error {
 	if err != nil {
 		return err
 	}
+	// the snapshot is deleted from the backup target by deleteFromTarget, rke only removes the copies of the etcd nodes
+	spec := cluster.Spec.DeepCopy()
+	if rkeConfig := spec.RancherKubernetesEngineConfig; rkeConfig != nil && rkeConfig.Services.Etcd.BackupConfig != nil {
+		rkeConfig.Services.Etcd.BackupConfig.S3BackupConfig = nil
+	}
 	snapshotName := clusterprovisioner.GetBackupFilename(b)
 	return wait.ExponentialBackoff(backoff, func() (bool, error) {
-		if inErr := c.backupDriver.ETCDRemoveSnapshot(c.ctx, cluster.Name, kontainerDriver, cluster.Spec, snapshotName); inErr != nil {
+		if inErr := c.backupDriver.ETCDRemoveSnapshot(c.ctx, cluster.Name, kontainerDriver, *spec, snapshotName); inErr != nil {
 			logrus.Warnf("%v", inErr)
 			return false, nil
 		}
@@ -262,6 +281,8 @@ func (c *Controller) etcdRemoveSnapshotWithBackoff(b *v3.EtcdBackup) error {
 	})
 }
 
+// rotateExpiredBackups deletes the expired recurring backups, Remove deletes their snapshots from the etcd nodes and
+// the backup target.
 func (c *Controller) rotateExpiredBackups(cluster *v3.Cluster, clusterBackups []*v3.EtcdBackup) error {
 	retention := cluster.Spec.RancherKubernetesEngineConfig.Services.Etcd.BackupConfig.Retention
 	intervalHours := cluster.Spec.RancherKubernetesEngineConfig.Services.Etcd.BackupConfig.IntervalHours
@@ -393,49 +414,34 @@ func generateBackupFilename(snapshotName string, backupConfig *rketypes.BackupCo
 
 }
 
+// GetS3Client returns a client for the S3 backup target of an RKE cluster.
 func GetS3Client(sbc *rketypes.S3BackupConfig, timeout int, dialer dialer.Dialer) (*minio.Client, error) {
 	if sbc == nil {
 		return nil, fmt.Errorf("Can't find S3 backup target configuration")
 	}
-	var creds *credentials.Credentials
-	var tr http.RoundTripper = &http.Transport{
-		Proxy:                 http.ProxyFromEnvironment,
-		DialContext:           dialer,
-		MaxIdleConns:          100,
-		IdleConnTimeout:       90 * time.Second,
-		TLSHandshakeTimeout:   10 * time.Second,
-		ExpectContinueTimeout: 1 * time.Second,
-	}
-	endpoint := sbc.Endpoint
-	// no access credentials, we assume IAM roles
-	if sbc.AccessKey == "" ||
-		sbc.SecretKey == "" {
-		creds = credentials.NewIAM("")
-		if sbc.Endpoint == "" {
-			endpoint = s3Endpoint
-		}
-	} else {
-		accessKey := sbc.AccessKey
-		secretKey := sbc.SecretKey
-		creds = credentials.NewStatic(accessKey, secretKey, "", credentials.SignatureDefault)
-	}
+	config := S3TargetConfig(sbc)
+	config.Dialer = dialer
+	return target.NewS3Client(config)
+}
 
-	bucketLookup := getBucketLookupType(endpoint)
-	opt := minio.Options{
-		Creds:        creds,
-		Region:       sbc.Region,
-		Secure:       true,
-		BucketLookup: bucketLookup,
-		Transport:    tr,
-	}
-	if sbc.CustomCA != "" {
-		opt.Transport = getCustomCATransport(tr, sbc.CustomCA)
-	}
-	s3Client, err := minio.New(endpoint, &opt)
-	if err != nil {
-		return nil, err
+// S3TargetConfig returns the target config of the S3 backup config of an RKE cluster. RKE uploads the snapshots
+// itself and only supports S3, the other targets are only available to RKE2 and K3s clusters.
+func S3TargetConfig(sbc *rketypes.S3BackupConfig) *target.Config {
+	config := &target.Config{
+		Type:       target.TypeS3,
+		Bucket:     sbc.BucketName,
+		Folder:     sbc.Folder,
+		Endpoint:   sbc.Endpoint,
+		EndpointCA: sbc.CustomCA,
+		Region:     sbc.Region,
+	}
+	if sbc.AccessKey != "" && sbc.SecretKey != "" {
+		config.Credentials = map[string]string{
+			target.CredentialAccessKey: sbc.AccessKey,
+			target.CredentialSecretKey: sbc.SecretKey,
+		}
 	}
-	return s3Client, nil
+	return config
 }
 
 func (c *Controller) getRecuringBackupsList(cluster *v3.Cluster) ([]*v3.EtcdBackup, error) {
@@ -452,16 +458,6 @@ func (c *Controller) getRecuringBackupsList(cluster *v3.Cluster) ([]*v3.EtcdBack
 	return retList, nil
 }
 
-func getBucketLookupType(endpoint string) minio.BucketLookupType {
-	if endpoint == "" {
-		return minio.BucketLookupAuto
-	}
-	if strings.Contains(endpoint, "aliyun") {
-		return minio.BucketLookupDNS
-	}
-	return minio.BucketLookupAuto
-}
-
 func getBackupCompletedTime(o runtime.Object) time.Time {
 	t, _ := time.Parse(time.RFC3339, rketypes.BackupConditionCompleted.GetLastUpdated(o))
 	return t
@@ -518,12 +514,3 @@ func isBackupSet(rkeConfig *rketypes.RancherKubernetesEngineConfig) bool {
 func isRecurringBackupEnabled(rkeConfig *rketypes.RancherKubernetesEngineConfig) bool {
 	return isBackupSet(rkeConfig) && rkeConfig.Services.Etcd.BackupConfig.Enabled != nil && *rkeConfig.Services.Etcd.BackupConfig.Enabled
 }
-
-func getCustomCATransport(tr http.RoundTripper, ca string) http.RoundTripper {
-	certPool := x509.NewCertPool()
-	certPool.AppendCertsFromPEM([]byte(ca))
-	tr.(*http.Transport).TLSClientConfig = &tls.Config{
-		RootCAs: certPool,
-	}
-	return tr
-}
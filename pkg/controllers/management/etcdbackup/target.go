@@ -0,0 +1,38 @@
+package etcdbackup
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rancher/rancher/pkg/etcdsnapshot/target"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/types/config/dialer"
+)
+
+// backupTarget returns the target holding the snapshot of the backup, or nil if the snapshot is only stored on the
+// etcd nodes. The backup configs of rke describe S3 targets only, which rke uploads the snapshots to from the etcd nodes.
+func backupTarget(ctx context.Context, dialerFactory dialer.Factory, b *v3.EtcdBackup) (target.Target, error) {
+	sbc := b.Spec.BackupConfig.S3BackupConfig
+	if sbc == nil {
+		return nil, nil
+	}
+	clusterDialer, err := dialerFactory.ClusterDialer(b.Spec.ClusterID)
+	if err != nil {
+		return nil, err
+	}
+	config := S3TargetConfig(sbc)
+	config.Dialer = clusterDialer
+	return target.New(ctx, config)
+}
+
+// deleteFromTarget deletes the snapshot of the backup from its target, snapshots which are already gone are ignored.
+func (c *Controller) deleteFromTarget(b *v3.EtcdBackup) error {
+	t, err := backupTarget(c.ctx, c.dialer, b)
+	if err != nil || t == nil {
+		return err
+	}
+	if err := t.Delete(c.ctx, snapshotFilename(b)); err != nil && !errors.Is(err, target.ErrNotFound) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package etcdbackup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"time"
+
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/controllers/management/clusterprovisioner"
+	"github.com/rancher/rancher/pkg/etcdsnapshot"
+	"github.com/rancher/rancher/pkg/etcdsnapshot/target"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	rketypes "github.com/rancher/rke/types"
+	"github.com/rancher/wrangler/pkg/ticker"
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// ChecksumAnnotation holds the sha256 of the snapshot as it was uploaded
+	ChecksumAnnotation = "etcdbackup.cattle.io/checksum"
+	// KeyCountAnnotation holds the number of key revisions found in the snapshot when it was last verified
+	KeyCountAnnotation = "etcdbackup.cattle.io/key-count"
+)
+
+// backupVerifySync periodically verifies the stored snapshots of all clusters, so that corrupt or missing uploads
+// are found before they are needed for a restore, and keeps them encrypted with the active key of their cluster.
+func (c *Controller) backupVerifySync(ctx context.Context, interval time.Duration) {
+	for range ticker.Context(ctx, interval) {
+		verifyInterval := verifyInterval()
+		clusters, err := c.clusterLister.List("", labels.NewSelector())
+		if err != nil {
+			logrus.Errorf("[etcd-backup] error while listing clusters: %v", err)
+			continue
+		}
+		for _, cluster := range clusters {
+			if err := c.doBackupVerifySync(cluster, verifyInterval); err != nil && !apierrors.IsConflict(err) {
+				logrus.Errorf("[etcd-backup] error while verifying backups for cluster [%s]: %v", cluster.Name, err)
+			}
+		}
+	}
+}
+
+func (c *Controller) doBackupVerifySync(cluster *v3.Cluster, verifyInterval time.Duration) error {
+	if cluster.DeletionTimestamp != nil || !v32.ClusterConditionReady.IsTrue(cluster) {
+		return nil
+	}
+
+	ring, err := KeyRing(c.secretLister, cluster)
+	if err != nil {
+		return err
+	}
+	backups, err := c.backupLister.List(cluster.Name, labels.NewSelector())
+	if err != nil {
+		return err
+	}
+	for _, backup := range backups {
+		if !canVerify(backup) || !rketypes.BackupConditionCompleted.IsTrue(backup) {
+			continue
+		}
+		if time.Since(getBackupCompletedTime(backup)) < clusterBackupCheckInterval {
+			// new backups are encrypted and verified by verifyNewBackup
+			continue
+		}
+		if ring != nil && backup.Annotations[EncryptionKeyAnnotation] != ring.Active && !isRestoring(cluster) {
+			// encrypt snapshots that failed to be encrypted or were decrypted for a restore, and re-encrypt the ones
+			// encrypted with a key that was rotated
+			encrypted, err := Encrypt(c.ctx, c.dialer, backup, ring)
+			if err != nil {
+				logrus.Errorf("[etcd-backup] failed to encrypt snapshot of backup [%s]: %v", backup.Name, err)
+				continue
+			}
+			if _, err := c.backupClient.Update(c.verifyBackup(encrypted, ring)); err != nil {
+				return err
+			}
+			continue
+		}
+		if verifyInterval == 0 || lastVerified(backup).Add(verifyInterval).After(time.Now()) {
+			continue
+		}
+		logrus.Debugf("[etcd-backup] verifying backup [%s] of cluster [%s]", backup.Name, cluster.Name)
+		if _, err := c.backupClient.Update(c.verifyBackup(backup, ring)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyNewBackup encrypts and verifies a backup that was just saved and records the result on the stored backup.
+func (c *Controller) verifyNewBackup(cluster *v3.Cluster, b *v3.EtcdBackup) {
+	verified := c.encryptAndVerifyBackup(cluster, b)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current, err := c.backupClient.GetNamespaced(b.Namespace, b.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		current = current.DeepCopy()
+		if current.Annotations == nil {
+			current.Annotations = map[string]string{}
+		}
+		for _, key := range []string{ChecksumAnnotation, KeyCountAnnotation, EncryptionKeyAnnotation} {
+			if value, ok := verified.Annotations[key]; ok {
+				current.Annotations[key] = value
+			} else {
+				delete(current.Annotations, key)
+			}
+		}
+		setVerifiedCondition(current, verified)
+		_, err = c.backupClient.Update(current)
+		return err
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		logrus.Errorf("[etcd-backup] failed to record verification of backup [%s]: %v", b.Name, err)
+	}
+}
+
+// encryptAndVerifyBackup encrypts the uploaded snapshot if encryption is enabled for the cluster, and records the
+// checksum of the stored snapshot, later verifications compare against it. A snapshot that failed to be encrypted
+// is left to the periodic verification, which retries it.
+func (c *Controller) encryptAndVerifyBackup(cluster *v3.Cluster, b *v3.EtcdBackup) *v3.EtcdBackup {
+	ring, err := KeyRing(c.secretLister, cluster)
+	if err != nil {
+		logrus.Errorf("[etcd-backup] failed to encrypt snapshot of backup [%s]: %v", b.Name, err)
+	} else if ring != nil {
+		if encrypted, err := Encrypt(c.ctx, c.dialer, b, ring); err != nil {
+			logrus.Errorf("[etcd-backup] failed to encrypt snapshot of backup [%s]: %v", b.Name, err)
+		} else {
+			b = encrypted
+		}
+	}
+	return c.verifyBackup(b, ring)
+}
+
+// setVerifiedCondition copies the Verified condition of src to dst.
+func setVerifiedCondition(dst, src *v3.EtcdBackup) {
+	for _, cond := range src.Status.Conditions {
+		if string(cond.Type) != string(v32.EtcdBackupConditionVerified) {
+			continue
+		}
+		for i := range dst.Status.Conditions {
+			if dst.Status.Conditions[i].Type == cond.Type {
+				dst.Status.Conditions[i] = cond
+				return
+			}
+		}
+		dst.Status.Conditions = append(dst.Status.Conditions, cond)
+		return
+	}
+}
+
+// isRestoring returns true while a snapshot is restored, it must not be rewritten until RKE has read it.
+func isRestoring(cluster *v3.Cluster) bool {
+	return cluster.Spec.RancherKubernetesEngineConfig != nil && cluster.Spec.RancherKubernetesEngineConfig.Restore.Restore
+}
+
+// verifyBackup downloads the snapshot of the backup and checks that it matches the checksum recorded when it was
+// saved and that its etcd database can be opened, decrypting it with the keys of ring. The result is recorded in the
+// Verified condition of the returned copy.
+func (c *Controller) verifyBackup(b *v3.EtcdBackup, ring *etcdsnapshot.KeyRing) *v3.EtcdBackup {
+	b = b.DeepCopy()
+	defer v32.EtcdBackupConditionVerified.LastUpdated(b, time.Now().Format(time.RFC3339))
+
+	object, err := c.openSnapshot(b)
+	if err != nil {
+		if errors.Is(err, target.ErrNotFound) {
+			v32.EtcdBackupConditionVerified.False(b)
+			v32.EtcdBackupConditionVerified.Reason(b, "Missing")
+			v32.EtcdBackupConditionVerified.Message(b, "snapshot was not found in the backup target")
+			return b
+		}
+		// the snapshot could not be checked, this says nothing about its integrity
+		logrus.Warnf("[etcd-backup] failed to download snapshot of backup [%s]: %v", b.Name, err)
+		v32.EtcdBackupConditionVerified.Unknown(b)
+		v32.EtcdBackupConditionVerified.Message(b, fmt.Sprintf("failed to download snapshot: %v", err))
+		return b
+	}
+	defer object.Close()
+
+	info, err := etcdsnapshot.Verify(object, ring)
+	if err == nil {
+		if checksum := b.Annotations[ChecksumAnnotation]; checksum != "" && checksum != info.Checksum {
+			err = fmt.Errorf("snapshot checksum %s does not match checksum %s recorded when it was saved", info.Checksum, checksum)
+		}
+	}
+	if err != nil {
+		logrus.Errorf("[etcd-backup] snapshot of backup [%s] failed verification: %v", b.Name, err)
+		v32.EtcdBackupConditionVerified.False(b)
+		v32.EtcdBackupConditionVerified.ReasonAndMessageFromError(b, err)
+		return b
+	}
+
+	if b.Annotations == nil {
+		b.Annotations = map[string]string{}
+	}
+	b.Annotations[ChecksumAnnotation] = info.Checksum
+	b.Annotations[KeyCountAnnotation] = strconv.Itoa(info.Keys)
+	if info.KeyID == "" {
+		delete(b.Annotations, EncryptionKeyAnnotation)
+	} else {
+		b.Annotations[EncryptionKeyAnnotation] = info.KeyID
+	}
+	v32.EtcdBackupConditionVerified.True(b)
+	v32.EtcdBackupConditionVerified.Reason(b, "")
+	v32.EtcdBackupConditionVerified.Message(b, fmt.Sprintf("snapshot holds %d keys", info.Keys))
+	return b
+}
+
+func (c *Controller) openSnapshot(b *v3.EtcdBackup) (io.ReadCloser, error) {
+	t, err := backupTarget(c.ctx, c.dialer, b)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, fmt.Errorf("backup [%s] is not stored in a backup target", b.Name)
+	}
+	return t.Download(c.ctx, snapshotFilename(b))
+}
+
+func snapshotFilename(b *v3.EtcdBackup) string {
+	filename, err := clusterprovisioner.GetBackupFilenameFromURL(b.Spec.Filename)
+	if err != nil {
+		filename = path.Base(b.Spec.Filename)
+	}
+	return filename
+}
+
+// canVerify returns true if rancher can read the snapshot, local snapshots only exist on the etcd nodes.
+func canVerify(b *v3.EtcdBackup) bool {
+	return b.Spec.BackupConfig.S3BackupConfig != nil && b.Spec.Filename != ""
+}
+
+func lastVerified(b *v3.EtcdBackup) time.Time {
+	t, _ := time.Parse(time.RFC3339, v32.EtcdBackupConditionVerified.GetLastUpdated(b))
+	return t
+}
+
+func verifyInterval() time.Duration {
+	hours, err := strconv.Atoi(settings.EtcdSnapshotVerifyIntervalHours.Get())
+	if err != nil || hours < 0 {
+		return 0
+	}
+	return time.Duration(hours) * time.Hour
+}
@@ -0,0 +1,191 @@
+package notifierdelivery
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	managementcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/notifiers"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/rancher/rancher/pkg/types/config/dialer"
+	"github.com/rancher/rancher/pkg/wrangler"
+	corecontrollers "github.com/rancher/wrangler/pkg/generated/controllers/core/v1"
+	"github.com/rancher/wrangler/pkg/ticker"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierror "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const deliveryInterval = 10 * time.Second
+
+// Register starts delivering the messages queued for the notifiers, retrying the failed deliveries and reporting the
+// state of the queues in the status of the notifiers.
+func Register(ctx context.Context, wrangler *wrangler.Context, management *config.ManagementContext) {
+	d := &deliverer{
+		notifierCache:  wrangler.Mgmt.Notifier().Cache(),
+		notifiers:      wrangler.Mgmt.Notifier(),
+		configMapCache: wrangler.Core.ConfigMap().Cache(),
+		configMaps:     wrangler.Core.ConfigMap(),
+		dialerFactory:  management.Dialer,
+		limiter:        &rateLimiter{sent: map[string][]time.Time{}},
+	}
+
+	go func() {
+		for range ticker.Context(ctx, deliveryInterval) {
+			if err := d.deliverAll(ctx); err != nil {
+				logrus.Errorf("failed to deliver notifications: %v", err)
+			}
+		}
+	}()
+}
+
+type deliverer struct {
+	notifierCache  managementcontrollers.NotifierCache
+	notifiers      managementcontrollers.NotifierClient
+	configMapCache corecontrollers.ConfigMapCache
+	configMaps     corecontrollers.ConfigMapClient
+	dialerFactory  dialer.Factory
+	limiter        *rateLimiter
+}
+
+func (d *deliverer) deliverAll(ctx context.Context) error {
+	selector, err := labels.Parse(notifiers.QueueLabel)
+	if err != nil {
+		return err
+	}
+	queues, err := d.configMapCache.List("", selector)
+	if err != nil {
+		return err
+	}
+
+	// the notifiers are independent, so a slow receiver does not hold up the others
+	var wg sync.WaitGroup
+	for _, cm := range queues {
+		wg.Add(1)
+		go func(cm *corev1.ConfigMap) {
+			defer wg.Done()
+			if err := d.deliver(ctx, cm); err != nil {
+				logrus.Errorf("failed to deliver notifications of notifier [%s/%s]: %v", cm.Namespace, cm.Labels[notifiers.QueueLabel], err)
+			}
+		}(cm)
+	}
+	wg.Wait()
+	return nil
+}
+
+func (d *deliverer) deliver(ctx context.Context, cm *corev1.ConfigMap) error {
+	notifier, err := d.notifierCache.Get(cm.Namespace, cm.Labels[notifiers.QueueLabel])
+	if apierror.IsNotFound(err) {
+		// the queue is garbage collected with its notifier
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	q, err := notifiers.LoadQueue(cm)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	results := map[string]error{}
+	if due := q.Due(now); len(due) > 0 {
+		// the namespace of a notifier is the name of its cluster
+		clusterDialer, dialerErr := d.dialerFactory.ClusterDialer(notifier.Namespace)
+		for _, delivery := range due {
+			if !d.limiter.allow(notifier.Namespace+"/"+notifier.Name, now, settings.NotifierRateLimitPerMinute.GetInt()) {
+				break
+			}
+			if dialerErr != nil {
+				results[delivery.ID] = dialerErr
+				continue
+			}
+			results[delivery.ID] = notifiers.SendMessage(ctx, notifier, delivery.Recipient, delivery.Message(), clusterDialer)
+		}
+	}
+
+	if len(results) > 0 {
+		dedupWindow := notifiers.DedupWindow()
+		q, err = notifiers.UpdateQueue(d.configMaps, notifier, func(q *notifiers.Queue) bool {
+			for id, err := range results {
+				q.Done(id, err, now, dedupWindow)
+			}
+			return true
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return d.updateStatus(notifier, q)
+}
+
+func (d *deliverer) updateStatus(notifier *v32.Notifier, q *notifiers.Queue) error {
+	status := v32.NotifierStatus{
+		Pending:   len(q.Pending),
+		LastError: q.LastError,
+	}
+	if !q.LastDelivered.IsZero() {
+		status.LastDeliveryTime = q.LastDelivered.UTC().Format(time.RFC3339)
+	}
+	if !q.LastFailed.IsZero() {
+		status.LastFailureTime = q.LastFailed.UTC().Format(time.RFC3339)
+	}
+	for _, deadLetter := range q.DeadLetters {
+		letter := v32.NotifierDeadLetter{
+			ID:          deadLetter.ID,
+			Title:       deadLetter.Title,
+			Recipient:   deadLetter.Recipient,
+			Attempts:    deadLetter.Attempts,
+			CreatedTime: deadLetter.Created.UTC().Format(time.RFC3339),
+			LastError:   deadLetter.LastError,
+		}
+		if deadLetter.Alert != nil {
+			letter.AlertName = deadLetter.Alert.Name
+		}
+		status.DeadLetters = append(status.DeadLetters, letter)
+	}
+
+	if equality.Semantic.DeepEqual(notifier.Status, status) {
+		return nil
+	}
+	notifier = notifier.DeepCopy()
+	notifier.Status = status
+	_, err := d.notifiers.Update(notifier)
+	return err
+}
+
+// rateLimiter bounds the number of deliveries of each notifier per minute, so that a burst of alerts does not get the
+// notifier throttled or banned by the receiver.
+type rateLimiter struct {
+	sync.Mutex
+	sent map[string][]time.Time
+}
+
+// allow returns whether a delivery of the notifier is allowed and records it, a limit of 0 disables the rate limit.
+func (r *rateLimiter) allow(key string, now time.Time, limit int) bool {
+	if limit <= 0 {
+		return true
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	var recent []time.Time
+	for _, sent := range r.sent[key] {
+		if now.Sub(sent) < time.Minute {
+			recent = append(recent, sent)
+		}
+	}
+	if len(recent) >= limit {
+		r.sent[key] = recent
+		return false
+	}
+	r.sent[key] = append(recent, now)
+	return true
+}
@@ -12,6 +12,7 @@
 	"github.com/rancher/rancher/pkg/controllers/management/feature"
 	"github.com/rancher/rancher/pkg/controllers/management/gke"
 	"github.com/rancher/rancher/pkg/controllers/management/k3sbasedupgrade"
+	"github.com/rancher/rancher/pkg/controllers/management/notifierdelivery"
 	"github.com/rancher/rancher/pkg/features"
 	"github.com/rancher/rancher/pkg/types/config"
 	"github.com/rancher/rancher/pkg/wrangler"
@@ -26,6 +27,7 @@ func RegisterWrangler(ctx context.Context, wranglerContext *wrangler.Context, ma
 
 	feature.Register(ctx, wranglerContext)
 	clusterconnected.Register(ctx, wranglerContext)
+	notifierdelivery.Register(ctx, wranglerContext, management)
 
 	if features.ProvisioningV2.Enabled() {
 		if err := authprovisioningv2.Register(ctx, wranglerContext); err != nil {
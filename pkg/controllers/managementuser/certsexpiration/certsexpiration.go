@@ -2,9 +2,11 @@
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"reflect"
 	"strings"
+	"time"
 
 	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 
@@ -20,18 +22,26 @@
 	"github.com/rancher/rke/hosts"
 	"github.com/rancher/rke/pki"
 	"github.com/rancher/rke/services"
+	"github.com/rancher/wrangler/pkg/ticker"
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+const resyncInterval = 12 * time.Hour
+
+var servingCertSecrets = []string{"rke2-serving", "k3s-serving"}
+
 type Controller struct {
 	ClusterName   string
 	ClusterLister v3.ClusterLister
 	ClusterClient v3.ClusterInterface
 	ClusterStore  cluster.PersistentStore
 	SecretLister  v1.SecretLister
+	ConfigMaps    v1.ConfigMapInterface
 }
 
 func Register(ctx context.Context, userContext *config.UserContext) {
@@ -41,9 +51,27 @@ func Register(ctx context.Context, userContext *config.UserContext) {
 		ClusterClient: userContext.Management.Management.Clusters(""),
 		ClusterStore:  clusterprovisioner.NewPersistentStore(userContext.Management.Core.Namespaces(""), userContext.Management.Core),
 		SecretLister:  userContext.Core.Secrets("").Controller().Lister(),
+		ConfigMaps:    userContext.Core.ConfigMaps("kube-system"),
 	}
 
 	userContext.Management.Management.Clusters("").AddHandler(ctx, "certificate-expiration", c.sync)
+	go c.resync(ctx, resyncInterval)
+}
+
+// resync checks the certificates of the cluster periodically, certificates are renewed without the cluster changing.
+func (c Controller) resync(ctx context.Context, interval time.Duration) {
+	for range ticker.Context(ctx, interval) {
+		cluster, err := c.ClusterLister.Get("", c.ClusterName)
+		if apierrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			logrus.Errorf("failed to get cluster [%s] for certificate expiration: %v", c.ClusterName, err)
+			continue
+		}
+		if _, err := c.sync(cluster.Name, cluster); err != nil && !apierrors.IsConflict(err) {
+			logrus.Errorf("failed to check certificate expiration of cluster [%s]: %v", c.ClusterName, err)
+		}
+	}
 }
 
 func (c Controller) sync(key string, cluster *v3.Cluster) (runtime.Object, error) {
@@ -51,10 +79,8 @@ func (c Controller) sync(key string, cluster *v3.Cluster) (runtime.Object, error
 		return cluster, nil
 	}
 
-	if cluster.Spec.RancherKubernetesEngineConfig == nil {
-		return cluster, nil
-	}
-	if cluster.Status.AppliedSpec.RancherKubernetesEngineConfig == nil {
+	rkeConfig := cluster.Status.AppliedSpec.RancherKubernetesEngineConfig
+	if cluster.Spec.RancherKubernetesEngineConfig != nil && rkeConfig == nil {
 		return cluster, nil
 	}
 	certsExpInfo := map[string]v32.CertExpiration{}
@@ -63,7 +89,13 @@ func (c Controller) sync(key string, cluster *v3.Cluster) (runtime.Object, error
 	if err != nil {
 		return cluster, err
 	}
-	certBundle, err := c.getClusterCertificateBundle(cluster.Name)
+
+	var certBundle map[string]pki.CertificatePKI
+	if rkeConfig != nil {
+		certBundle, err = c.getClusterCertificateBundle(cluster.Name)
+	} else {
+		certBundle, err = c.getDownstreamCertificateBundle(cluster)
+	}
 	if err != nil {
 		return cluster, err
 	}
@@ -75,8 +107,11 @@ func (c Controller) sync(key string, cluster *v3.Cluster) (runtime.Object, error
 		}
 		certsExpInfo[certName] = info
 	}
-	logrus.Debugf("Checking and deleting unused certificates for cluster %s", cluster.Name)
-	deleteUnusedCerts(certsExpInfo, cluster.Status.AppliedSpec.RancherKubernetesEngineConfig)
+	if rkeConfig != nil {
+		logrus.Debugf("Checking and deleting unused certificates for cluster %s", cluster.Name)
+		deleteUnusedCerts(certsExpInfo, rkeConfig)
+	}
+
 	if !reflect.DeepEqual(cluster.Status.CertificatesExpiration, certsExpInfo) {
 		toUpdate := cluster.DeepCopy()
 		toUpdate.Status.CertificatesExpiration = certsExpInfo
@@ -123,6 +158,49 @@ func getRKECurrentStateFromStore(store cluster.PersistentStore, clusterName stri
 	return &fullState.CurrentState, nil
 }
 
+// getDownstreamCertificateBundle collects the certificates of clusters that are not provisioned by rancher with RKE.
+// Clusters installed with the RKE CLI have a full state configmap, RKE2 and K3s clusters store the serving certificate of
+// the API server in a secret. The CA of the cluster is known for every cluster.
+func (c Controller) getDownstreamCertificateBundle(cluster *v3.Cluster) (map[string]pki.CertificatePKI, error) {
+	certs := map[string]pki.CertificatePKI{}
+
+	cm, err := c.ConfigMaps.Get(rkecluster.FullStateConfigMapName, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	} else if err == nil {
+		bundle, err := rkecerts.CertBundleFromConfig(cm)
+		if err != nil {
+			return nil, err
+		}
+		rkecerts.CleanCertificateBundle(bundle)
+		for name, cert := range bundle {
+			certs[name] = cert
+		}
+	}
+
+	for _, name := range servingCertSecrets {
+		secret, err := c.SecretLister.Get("kube-system", name)
+		if apierrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if cert, ok := secret.Data[corev1.TLSCertKey]; ok {
+			certs[name] = pki.CertificatePKI{CertificatePEM: string(cert)}
+		}
+	}
+
+	if _, ok := certs[pki.CACertName]; !ok && cluster.Status.CACert != "" {
+		caCert, err := base64.StdEncoding.DecodeString(cluster.Status.CACert)
+		if err != nil {
+			logrus.Debugf("failed to decode CA certificate of cluster [%s]: %v", cluster.Name, err)
+		} else {
+			certs[pki.CACertName] = pki.CertificatePKI{CertificatePEM: string(caCert)}
+		}
+	}
+	return certs, nil
+}
+
 func (c Controller) getCertsFromUserCluster() (map[string]pki.CertificatePKI, error) {
 	certs := map[string]pki.CertificatePKI{}
 	secrets, err := c.SecretLister.List("kube-system", labels.Everything())
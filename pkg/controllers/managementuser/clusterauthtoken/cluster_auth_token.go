@@ -0,0 +1,44 @@
+package clusterauthtoken
+
+import (
+	"time"
+
+	clusterv3 "github.com/rancher/rancher/pkg/generated/norman/cluster.cattle.io/v3"
+	managementv3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type clusterAuthTokenHandler struct {
+	token       managementv3.TokenInterface
+	tokenLister managementv3.TokenLister
+}
+
+// Sync copies the last use of clusterAuthTokens through the authorized cluster endpoint to their tokens, so that
+// the idle timeout of tokens also counts requests that never reach rancher.
+func (h *clusterAuthTokenHandler) Sync(key string, clusterAuthToken *clusterv3.ClusterAuthToken) (runtime.Object, error) {
+	if clusterAuthToken == nil || clusterAuthToken.DeletionTimestamp != nil || clusterAuthToken.LastUsedAt == "" {
+		return nil, nil
+	}
+
+	lastUsed, err := time.Parse(time.RFC3339, clusterAuthToken.LastUsedAt)
+	if err != nil {
+		return nil, nil
+	}
+
+	token, err := h.tokenLister.Get("", clusterAuthToken.Name)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if tokenLastUsed, err := time.Parse(time.RFC3339, token.LastUsedAt); err == nil && !tokenLastUsed.Before(lastUsed) {
+		return nil, nil
+	}
+
+	token = token.DeepCopy()
+	token.LastUsedAt = clusterAuthToken.LastUsedAt
+	_, err = h.token.Update(token)
+	return nil, err
+}
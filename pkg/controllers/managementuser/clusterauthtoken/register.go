@@ -71,6 +71,11 @@ func Register(ctx context.Context, cluster *config.UserContext) {
 		clusterUserAttributeLister,
 	}).Sync)
 
+	cluster.Cluster.ClusterAuthTokens(namespace).AddHandler(ctx, "cat-cluster-auth-token-controller", (&clusterAuthTokenHandler{
+		cluster.Management.Management.Tokens(""),
+		cluster.Management.Management.Tokens("").Controller().Lister(),
+	}).Sync)
+
 	cluster.Cluster.ClusterUserAttributes(namespace).AddHandler(ctx, "cat-cluster-user-attribute-controller", (&clusterUserAttributeHandler{
 		userAttribute,
 		userAttributeLister,
@@ -11,12 +11,14 @@
 	"github.com/rancher/rancher/pkg/controllers/managementuser/networkpolicy"
 	"github.com/rancher/rancher/pkg/controllers/managementuser/nodesyncer"
 	"github.com/rancher/rancher/pkg/controllers/managementuser/nsserviceaccount"
+	"github.com/rancher/rancher/pkg/controllers/managementuser/projectstats"
 	"github.com/rancher/rancher/pkg/controllers/managementuser/pspdelete"
 	"github.com/rancher/rancher/pkg/controllers/managementuser/rbac"
 	"github.com/rancher/rancher/pkg/controllers/managementuser/rbac/podsecuritypolicy"
 	"github.com/rancher/rancher/pkg/controllers/managementuser/resourcequota"
 	"github.com/rancher/rancher/pkg/controllers/managementuser/secret"
 	"github.com/rancher/rancher/pkg/controllers/managementuser/settings"
+	"github.com/rancher/rancher/pkg/controllers/managementuser/showback"
 	"github.com/rancher/rancher/pkg/controllers/managementuser/snapshotbackpopulate"
 	"github.com/rancher/rancher/pkg/controllers/managementuser/windows"
 	"github.com/rancher/rancher/pkg/controllers/managementuserlegacy"
@@ -30,6 +32,8 @@ func Register(ctx context.Context, cluster *config.UserContext, clusterRec *mana
 	healthsyncer.Register(ctx, cluster)
 	networkpolicy.Register(ctx, cluster)
 	nodesyncer.Register(ctx, cluster, kubeConfigGetter)
+	projectstats.Register(ctx, cluster)
+	showback.Register(ctx, cluster)
 	podsecuritypolicy.RegisterCluster(ctx, cluster)
 	podsecuritypolicy.RegisterClusterRole(ctx, cluster)
 	podsecuritypolicy.RegisterBindings(ctx, cluster)
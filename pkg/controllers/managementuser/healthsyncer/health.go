@@ -0,0 +1,300 @@
+package healthsyncer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rancher/norman/condition"
+	"github.com/rancher/norman/types/slice"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	etcdRoleLabel = "node-role.kubernetes.io/etcd"
+	// leaseGracePeriod tolerates clock skew between the cluster and rancher and slow renewals of the leader leases
+	leaseGracePeriod = 30 * time.Second
+	// maxListedNodes limits the nodes that are listed by name in the messages of the conditions
+	maxListedNodes = 5
+	// probeTimeout limits each request of the health checks, so that one slow endpoint does not fail the others
+	probeTimeout = 5 * time.Second
+)
+
+// healthCheck is the result of a check of a component, it is reported as the condition of the component on the
+// cluster.
+type healthCheck struct {
+	status  v1.ConditionStatus
+	reason  string
+	message string
+}
+
+func healthy(message string) healthCheck {
+	return healthCheck{status: v1.ConditionTrue, message: message}
+}
+
+func unhealthy(reason, message string) healthCheck {
+	return healthCheck{status: v1.ConditionFalse, reason: reason, message: message}
+}
+
+func unknown(reason, message string) healthCheck {
+	return healthCheck{status: v1.ConditionUnknown, reason: reason, message: message}
+}
+
+func (c healthCheck) apply(cond condition.Cond, cluster *v3.Cluster) {
+	cond.SetStatus(cluster, string(c.status))
+	cond.Reason(cluster, c.reason)
+	cond.Message(cluster, c.message)
+}
+
+// componentStatus returns the component status of the component as it is reported by the check.
+func (c healthCheck) componentStatus(name string) v32.ClusterComponentStatus {
+	message := c.message
+	if message == "" {
+		message = "ok"
+	}
+	return v32.ClusterComponentStatus{
+		Name: name,
+		Conditions: []v1.ComponentCondition{{
+			Type:    v1.ComponentHealthy,
+			Status:  c.status,
+			Message: message,
+			Error:   c.reason,
+		}},
+	}
+}
+
+// componentCheck is the check of a component that is reported as the condition on the cluster and, if the component
+// is set, as its component status.
+type componentCheck struct {
+	cond      condition.Cond
+	component string
+	check     healthCheck
+}
+
+// checkHealth checks the health of the components of the cluster, sets their conditions and overrides the component
+// statuses of the components that could be checked. Unhealthy components only show in their conditions, the Ready
+// condition of the cluster depends on the reachability of its API alone.
+func (h *HealthSyncer) checkHealth(cluster *v3.Cluster, excludedComponents []string) error {
+	nodes, err := h.nodeLister.List("", labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	apiServer, etcd := h.checkAPIServer()
+	checks := []componentCheck{
+		{cond: v32.ClusterConditionAPIServerHealthy, check: apiServer},
+		{cond: v32.ClusterConditionEtcdHealthy, component: "etcd", check: checkEtcdNodes(etcd, nodes)},
+	}
+	// the control plane of hosted clusters is managed by the provider
+	if !slice.ContainsString(excludedComponents, "scheduler") {
+		checks = append(checks, componentCheck{
+			cond:      v32.ClusterConditionSchedulerHealthy,
+			component: "scheduler",
+			check:     h.checkLease("kube-scheduler", time.Now()),
+		})
+	}
+	if !slice.ContainsString(excludedComponents, "controller-manager") {
+		checks = append(checks, componentCheck{
+			cond:      v32.ClusterConditionControllerManagerHealthy,
+			component: "controller-manager",
+			check:     h.checkLease("kube-controller-manager", time.Now()),
+		})
+	}
+
+	for _, c := range checks {
+		c.check.apply(c.cond, cluster)
+		if c.component != "" && c.check.status != v1.ConditionUnknown {
+			cluster.Status.ComponentStatuses = overrideComponentStatus(cluster.Status.ComponentStatuses, c.check.componentStatus(c.component))
+		}
+	}
+	checkNodes(nodes).apply(v32.ClusterConditionNodesReady, cluster)
+	return nil
+}
+
+// checkAPIServer returns the health of the kube-apiserver and of etcd as seen by the kube-apiserver, from the verbose
+// output of its readyz and livez endpoints.
+func (h *HealthSyncer) checkAPIServer() (healthCheck, healthCheck) {
+	var apiFailures, etcdFailures []string
+	for _, path := range []string{"/readyz", "/livez"} {
+		ctx, cancel := context.WithTimeout(h.ctx, probeTimeout)
+		body, err := h.k8s.Discovery().RESTClient().Get().AbsPath(path).Param("verbose", "").DoRaw(ctx)
+		cancel()
+		if apierrors.IsNotFound(err) {
+			// the endpoints were added in Kubernetes 1.16, reachability is all that can be checked before
+			continue
+		}
+		checks, ok := parseVerboseHealth(body)
+		if !ok {
+			if err == nil {
+				err = fmt.Errorf("unexpected response")
+			}
+			return unhealthy("APIServerUnreachable", fmt.Sprintf("%s: %v", path, err)), unknown("APIServerUnreachable", "")
+		}
+		for _, check := range checks {
+			if strings.HasPrefix(check, "etcd") {
+				etcdFailures = appendUnique(etcdFailures, check)
+			} else {
+				apiFailures = appendUnique(apiFailures, check)
+			}
+		}
+	}
+
+	apiServer := healthy("")
+	if len(apiFailures) > 0 {
+		apiServer = unhealthy("APIServerCheckFailed", "failed checks: "+strings.Join(apiFailures, ", "))
+	}
+	etcd := healthy("")
+	if len(etcdFailures) > 0 {
+		etcd = unhealthy("EtcdCheckFailed", "failed checks: "+strings.Join(etcdFailures, ", "))
+	}
+	return apiServer, etcd
+}
+
+// parseVerboseHealth returns the failed checks of the verbose output of a health endpoint of the kube-apiserver, which
+// lists a check per line as "[+]name ok" or "[-]name failed: reason". It returns false if the output is not a list of
+// checks.
+func parseVerboseHealth(body []byte) ([]string, bool) {
+	var failed []string
+	found := false
+	for _, line := range strings.Split(string(body), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "[+]"):
+			found = true
+		case strings.HasPrefix(line, "[-]"):
+			found = true
+			name := strings.Fields(strings.TrimPrefix(line, "[-]"))
+			if len(name) > 0 {
+				failed = append(failed, name[0])
+			}
+		}
+	}
+	return failed, found
+}
+
+// checkEtcdNodes adds the readiness of the etcd nodes to the etcd health, etcd is unhealthy without a ready quorum.
+// Clusters with an external or hosted etcd have no etcd nodes.
+func checkEtcdNodes(etcd healthCheck, nodes []*v1.Node) healthCheck {
+	var total, ready int
+	for _, node := range nodes {
+		if node.Labels[etcdRoleLabel] != "true" {
+			continue
+		}
+		total++
+		if isNodeReady(node) {
+			ready++
+		}
+	}
+	if total == 0 || ready == total || etcd.status == v1.ConditionFalse {
+		return etcd
+	}
+
+	message := fmt.Sprintf("%d/%d etcd nodes ready", ready, total)
+	if ready < total/2+1 {
+		return unhealthy("EtcdQuorumLost", message)
+	}
+	if etcd.status == v1.ConditionTrue {
+		etcd.message = message
+	}
+	return etcd
+}
+
+// checkLease returns the health of a control plane component from the renewal of its leader lease in kube-system.
+func (h *HealthSyncer) checkLease(name string, now time.Time) healthCheck {
+	ctx, cancel := context.WithTimeout(h.ctx, probeTimeout)
+	defer cancel()
+
+	lease, err := h.k8s.CoordinationV1().Leases("kube-system").Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return unknown("LeaseNotFound", fmt.Sprintf("no leader lease %s", name))
+	} else if err != nil {
+		return unknown("LeaseFetchingFailure", err.Error())
+	}
+	return leaseHealth(lease, now)
+}
+
+func leaseHealth(lease *coordinationv1.Lease, now time.Time) healthCheck {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return unknown("LeaseNotFound", fmt.Sprintf("leader lease %s was never acquired", lease.Name))
+	}
+
+	holder := ""
+	if lease.Spec.HolderIdentity != nil {
+		holder = *lease.Spec.HolderIdentity
+	}
+	expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds)*time.Second + leaseGracePeriod)
+	if now.After(expiry) {
+		return unhealthy("LeaseExpired", fmt.Sprintf("leader lease %s of %s was last renewed at %s", lease.Name, holder,
+			lease.Spec.RenewTime.UTC().Format(time.RFC3339)))
+	}
+	return healthy("")
+}
+
+// checkNodes returns the readiness of the nodes of the cluster, the nodes are unhealthy if less than half of them are
+// ready.
+func checkNodes(nodes []*v1.Node) healthCheck {
+	if len(nodes) == 0 {
+		return unknown("NoNodes", "the cluster has no nodes")
+	}
+	var notReady []string
+	for _, node := range nodes {
+		if !isNodeReady(node) {
+			notReady = append(notReady, node.Name)
+		}
+	}
+	if len(notReady) == 0 {
+		return healthy("")
+	}
+
+	sort.Strings(notReady)
+	listed := notReady
+	if len(listed) > maxListedNodes {
+		listed = listed[:maxListedNodes]
+	}
+	message := fmt.Sprintf("%d/%d nodes ready, not ready: %s", len(nodes)-len(notReady), len(nodes), strings.Join(listed, ", "))
+	if len(notReady) > len(listed) {
+		message += fmt.Sprintf(" and %d more", len(notReady)-len(listed))
+	}
+	if 2*len(notReady) > len(nodes) {
+		return unhealthy("NodesNotReady", message)
+	}
+	return healthCheck{status: v1.ConditionTrue, reason: "NodesNotReady", message: message}
+}
+
+func isNodeReady(node *v1.Node) bool {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == v1.NodeReady {
+			return cond.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// overrideComponentStatus replaces the statuses of the component, and of its members like etcd-0, with the status.
+func overrideComponentStatus(statuses []v32.ClusterComponentStatus, status v32.ClusterComponentStatus) []v32.ClusterComponentStatus {
+	result := []v32.ClusterComponentStatus{status}
+	for _, cs := range statuses {
+		if cs.Name != status.Name && !strings.HasPrefix(cs.Name, status.Name+"-") {
+			result = append(result, cs)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func appendUnique(values []string, value string) []string {
+	if slice.ContainsString(values, value) {
+		return values
+	}
+	return append(values, value)
+}
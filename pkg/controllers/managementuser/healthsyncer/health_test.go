@@ -0,0 +1,113 @@
+package healthsyncer
+
+import (
+	"testing"
+	"time"
+
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/stretchr/testify/assert"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseVerboseHealth(t *testing.T) {
+	failed, ok := parseVerboseHealth([]byte("[+]ping ok\n[+]log ok\n[-]etcd failed: reason withheld\n[+]informer-sync ok\n" +
+		"[-]poststarthook/start-apiextensions-controllers failed: reason withheld\nreadyz check failed\n"))
+	assert.True(t, ok)
+	assert.Equal(t, []string{"etcd", "poststarthook/start-apiextensions-controllers"}, failed)
+
+	failed, ok = parseVerboseHealth([]byte("[+]ping ok\nreadyz check passed\n"))
+	assert.True(t, ok)
+	assert.Empty(t, failed)
+
+	_, ok = parseVerboseHealth([]byte("<html>bad gateway</html>"))
+	assert.False(t, ok)
+}
+
+func node(name string, ready bool, etcd bool) *v1.Node {
+	status := v1.ConditionFalse
+	if ready {
+		status = v1.ConditionTrue
+	}
+	n := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{},
+		},
+		Status: v1.NodeStatus{
+			Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: status}},
+		},
+	}
+	if etcd {
+		n.Labels[etcdRoleLabel] = "true"
+	}
+	return n
+}
+
+func TestCheckEtcdNodes(t *testing.T) {
+	check := checkEtcdNodes(healthy(""), []*v1.Node{node("a", true, true), node("b", false, true), node("c", true, true), node("w", false, false)})
+	assert.Equal(t, v1.ConditionTrue, check.status)
+	assert.Equal(t, "2/3 etcd nodes ready", check.message)
+
+	check = checkEtcdNodes(healthy(""), []*v1.Node{node("a", true, true), node("b", false, true), node("c", false, true)})
+	assert.Equal(t, unhealthy("EtcdQuorumLost", "1/3 etcd nodes ready"), check)
+
+	check = checkEtcdNodes(healthy(""), []*v1.Node{node("w", false, false)})
+	assert.Equal(t, healthy(""), check, "expected clusters without etcd nodes to rely on the apiserver")
+
+	failed := unhealthy("EtcdCheckFailed", "failed checks: etcd")
+	assert.Equal(t, failed, checkEtcdNodes(failed, []*v1.Node{node("a", true, true), node("b", false, true)}))
+}
+
+func TestLeaseHealth(t *testing.T) {
+	now := time.Now()
+	duration := int32(15)
+	holder := "cp-1_1234"
+	lease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: "kube-scheduler"},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &holder,
+			LeaseDurationSeconds: &duration,
+			RenewTime:            &metav1.MicroTime{Time: now.Add(-10 * time.Second)},
+		},
+	}
+	assert.Equal(t, v1.ConditionTrue, leaseHealth(lease, now).status)
+
+	lease.Spec.RenewTime = &metav1.MicroTime{Time: now.Add(-time.Minute)}
+	check := leaseHealth(lease, now)
+	assert.Equal(t, v1.ConditionFalse, check.status)
+	assert.Equal(t, "LeaseExpired", check.reason)
+	assert.Contains(t, check.message, holder)
+
+	lease.Spec.RenewTime = nil
+	assert.Equal(t, v1.ConditionUnknown, leaseHealth(lease, now).status)
+}
+
+func TestCheckNodes(t *testing.T) {
+	assert.Equal(t, v1.ConditionUnknown, checkNodes(nil).status)
+	assert.Equal(t, healthy(""), checkNodes([]*v1.Node{node("a", true, false)}))
+
+	check := checkNodes([]*v1.Node{node("a", true, false), node("b", false, false), node("c", true, false)})
+	assert.Equal(t, v1.ConditionTrue, check.status)
+	assert.Equal(t, "2/3 nodes ready, not ready: b", check.message)
+
+	var nodes []*v1.Node
+	for _, name := range []string{"h", "g", "f", "e", "d", "c", "b"} {
+		nodes = append(nodes, node(name, false, false))
+	}
+	nodes = append(nodes, node("a", true, false))
+	check = checkNodes(nodes)
+	assert.Equal(t, v1.ConditionFalse, check.status)
+	assert.Equal(t, "1/8 nodes ready, not ready: b, c, d, e, f and 2 more", check.message)
+}
+
+func TestOverrideComponentStatus(t *testing.T) {
+	statuses := []v32.ClusterComponentStatus{{Name: "controller-manager"}, {Name: "etcd-0"}, {Name: "etcd-1"}, {Name: "scheduler"}}
+	result := overrideComponentStatus(statuses, unhealthy("EtcdQuorumLost", "1/3 etcd nodes ready").componentStatus("etcd"))
+	assert.Len(t, result, 3)
+	assert.Equal(t, "controller-manager", result[0].Name)
+	assert.Equal(t, "etcd", result[1].Name)
+	assert.Equal(t, v1.ConditionFalse, result[1].Conditions[0].Status)
+	assert.Equal(t, "scheduler", result[2].Name)
+}
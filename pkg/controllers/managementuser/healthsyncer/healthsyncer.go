@@ -43,6 +43,7 @@ type HealthSyncer struct {
 	clusters          v3.ClusterInterface
 	componentStatuses corev1.ComponentStatusInterface
 	namespaces        corev1.NamespaceInterface
+	nodeLister        corev1.NodeLister
 	k8s               kubernetes.Interface
 }
 
@@ -54,6 +55,7 @@ func Register(ctx context.Context, workload *config.UserContext) {
 		clusters:          workload.Management.Management.Clusters(""),
 		componentStatuses: workload.Core.ComponentStatuses(""),
 		namespaces:        workload.Core.Namespaces(""),
+		nodeLister:        workload.Core.Nodes("").Controller().Lister(),
 		k8s:               workload.K8sClient,
 	}
 
@@ -70,7 +72,7 @@ func (h *HealthSyncer) syncHealth(ctx context.Context, syncHealth time.Duration)
 }
 
 func (h *HealthSyncer) getComponentStatus(cluster *v3.Cluster) error {
-	ctx, cancel := context.WithTimeout(h.ctx, 5*time.Second)
+	ctx, cancel := context.WithTimeout(h.ctx, probeTimeout)
 	defer cancel()
 
 	// Prior to k8s v1.14, we only needed to list the ComponentStatuses from the user cluster.
@@ -101,7 +103,9 @@ func (h *HealthSyncer) getComponentStatus(cluster *v3.Cluster) error {
 		return cluster.Status.ComponentStatuses[i].Name < cluster.Status.ComponentStatuses[j].Name
 	})
 
-	return nil
+	// The ComponentStatus API is deprecated and empty or misleading on RKE2, k3s and hosted clusters, so the health
+	// of the components is checked directly as well.
+	return h.checkHealth(cluster, excludedComponents)
 }
 
 func (h *HealthSyncer) updateClusterHealth() error {
@@ -701,7 +701,7 @@ func (m *nodesSyncer) convertNodeToMachine(node *corev1.Node, existing *v3.Node,
 		machine.Status.InternalNodeStatus = *node.Status.DeepCopy()
 	}
 
-	requests, limits := aggregateRequestAndLimitsForNode(pods[node.Name])
+	requests, limits := AggregateRequestsAndLimits(pods[node.Name])
 	if machine.Status.Requested == nil {
 		machine.Status.Requested = corev1.ResourceList{}
 	}
@@ -755,7 +755,8 @@ func (m *nodesSyncer) getNonTerminatedPods() (map[string][]*corev1.Pod, error) {
 	return pods, nil
 }
 
-func aggregateRequestAndLimitsForNode(pods []*corev1.Pod) (map[corev1.ResourceName]resource.Quantity, map[corev1.ResourceName]resource.Quantity) {
+// AggregateRequestsAndLimits returns the total resource requests and limits of the pods, and their number as requested pods.
+func AggregateRequestsAndLimits(pods []*corev1.Pod) (map[corev1.ResourceName]resource.Quantity, map[corev1.ResourceName]resource.Quantity) {
 	requests, limits := map[corev1.ResourceName]resource.Quantity{}, map[corev1.ResourceName]resource.Quantity{}
 	for _, pod := range pods {
 		podRequests, podLimits := getPodData(pod)
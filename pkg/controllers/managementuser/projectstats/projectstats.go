@@ -0,0 +1,125 @@
+package projectstats
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/rancher/rancher/pkg/controllers/managementuser/nodesyncer"
+	v1 "github.com/rancher/rancher/pkg/generated/norman/core/v1"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/statshistory"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/rancher/wrangler/pkg/ticker"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	projectIDAnnotation = "field.cattle.io/projectId"
+	// checkInterval is how often the projects are checked for a due sample, the samples are taken at the interval of
+	// the stats-history-interval-minutes setting.
+	checkInterval = time.Minute
+)
+
+// statsRecorder samples the requests and limits of the pods of every project into the stats history of the project.
+type statsRecorder struct {
+	clusterName     string
+	projectLister   v3.ProjectLister
+	namespaceLister v1.NamespaceLister
+	podLister       v1.PodLister
+	recorder        *statshistory.Recorder
+}
+
+func Register(ctx context.Context, cluster *config.UserContext) {
+	s := &statsRecorder{
+		clusterName:     cluster.ClusterName,
+		projectLister:   cluster.Management.Management.Projects(cluster.ClusterName).Controller().Lister(),
+		namespaceLister: cluster.Core.Namespaces("").Controller().Lister(),
+		podLister:       cluster.Core.Pods("").Controller().Lister(),
+		recorder:        statshistory.NewRecorder(cluster.Management.Core.ConfigMaps("")),
+	}
+	go s.run(ctx)
+}
+
+func (s *statsRecorder) run(ctx context.Context) {
+	for range ticker.Context(ctx, checkInterval) {
+		if err := s.record(time.Now()); err != nil {
+			logrus.Errorf("failed to record project stats history of cluster [%s]: %v", s.clusterName, err)
+		}
+	}
+}
+
+func (s *statsRecorder) record(now time.Time) error {
+	projects, err := s.projectLister.List(s.clusterName, labels.Everything())
+	if err != nil {
+		return err
+	}
+	pods, err := s.projectPods()
+	if err != nil {
+		return err
+	}
+
+	for _, project := range projects {
+		if project.DeletionTimestamp != nil {
+			continue
+		}
+		requests, limits := nodesyncer.AggregateRequestsAndLimits(pods[project.Name])
+		sample := statshistory.Sample{
+			Time:      now,
+			Requested: statshistory.ResourcesFromList(requests),
+			Limits:    statshistory.ResourcesFromList(limits),
+		}
+		owner := metav1.OwnerReference{
+			APIVersion: "management.cattle.io/v3",
+			Kind:       "Project",
+			Name:       project.Name,
+			UID:        project.UID,
+		}
+		if err := s.recorder.Record(owner, s.clusterName, statshistory.ProjectConfigMapName(project.Name), sample); err != nil {
+			logrus.Debugf("failed to record stats history of project [%s:%s]: %v", s.clusterName, project.Name, err)
+		}
+	}
+	return nil
+}
+
+// projectPods returns the scheduled and running pods of the cluster by the name of their project.
+func (s *statsRecorder) projectPods() (map[string][]*corev1.Pod, error) {
+	namespaces, err := s.namespaceLister.List("", labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	projectOfNamespace := map[string]string{}
+	for _, ns := range namespaces {
+		clusterName, projectName := splitProjectID(ns.Annotations[projectIDAnnotation])
+		if clusterName == s.clusterName {
+			projectOfNamespace[ns.Name] = projectName
+		}
+	}
+
+	pods, err := s.podLister.List("", labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	result := map[string][]*corev1.Pod{}
+	for _, pod := range pods {
+		// like the requests of the nodes, only pods that occupy resources are counted
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if projectName := projectOfNamespace[pod.Namespace]; projectName != "" {
+			result[projectName] = append(result[projectName], pod)
+		}
+	}
+	return result, nil
+}
+
+func splitProjectID(projectID string) (string, string) {
+	parts := strings.SplitN(projectID, ":", 2)
+	if len(parts) != 2 {
+		return "", ""
+	}
+	return parts[0], parts[1]
+}
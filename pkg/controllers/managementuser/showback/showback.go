@@ -0,0 +1,212 @@
+package showback
+
+import (
+	"context"
+	"time"
+
+	"github.com/rancher/rancher/pkg/controllers/managementuser/nodesyncer"
+	v1 "github.com/rancher/rancher/pkg/generated/norman/core/v1"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/ref"
+	"github.com/rancher/rancher/pkg/showback"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/rancher/wrangler/pkg/ticker"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	projectIDAnnotation = "field.cattle.io/projectId"
+	sampleInterval      = 5 * time.Minute
+)
+
+// sampler accounts the resources requested by the namespaces of the cluster since the previous sample to the usage
+// of the day.
+type sampler struct {
+	clusterName     string
+	clusterLister   v3.ClusterLister
+	namespaceLister v1.NamespaceLister
+	podLister       v1.PodLister
+	pvcLister       v1.PersistentVolumeClaimLister
+	configMaps      v1.ConfigMapInterface
+	last            time.Time
+	lastPruned      string
+}
+
+func Register(ctx context.Context, cluster *config.UserContext) {
+	s := &sampler{
+		clusterName:     cluster.ClusterName,
+		clusterLister:   cluster.Management.Management.Clusters("").Controller().Lister(),
+		namespaceLister: cluster.Core.Namespaces("").Controller().Lister(),
+		podLister:       cluster.Core.Pods("").Controller().Lister(),
+		pvcLister:       cluster.Core.PersistentVolumeClaims("").Controller().Lister(),
+		configMaps:      cluster.Management.Core.ConfigMaps(""),
+	}
+	go s.run(ctx)
+}
+
+func (s *sampler) run(ctx context.Context) {
+	for range ticker.Context(ctx, sampleInterval) {
+		if err := s.sample(time.Now()); err != nil {
+			logrus.Errorf("failed to sample showback usage of cluster [%s]: %v", s.clusterName, err)
+		}
+	}
+}
+
+func (s *sampler) sample(now time.Time) error {
+	retention := showback.Retention()
+	if retention == 0 {
+		s.last = time.Time{}
+		return nil
+	}
+	if s.last.IsZero() {
+		s.last = now
+		return nil
+	}
+	// the requests are unknown while rancher or the cluster was unavailable, the gap is not accounted
+	elapsed := now.Sub(s.last)
+	if elapsed > 2*sampleInterval {
+		elapsed = sampleInterval
+	}
+
+	usage, err := s.namespaceUsage(elapsed)
+	if err != nil {
+		return err
+	}
+	if err := s.store(now, usage); err != nil {
+		return err
+	}
+	s.last = now
+
+	if day := showback.Day(now); day != s.lastPruned {
+		if err := s.prune(now.Add(-retention)); err != nil {
+			return err
+		}
+		s.lastPruned = day
+	}
+	return nil
+}
+
+// namespaceUsage returns the usage of the requests of the scheduled and running pods and the bound volume claims of
+// every namespace for the duration, and the projects of the namespaces.
+func (s *sampler) namespaceUsage(d time.Duration) (showback.DayUsage, error) {
+	pods, err := s.podLister.List("", labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	podsByNamespace := map[string][]*corev1.Pod{}
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		podsByNamespace[pod.Namespace] = append(podsByNamespace[pod.Namespace], pod)
+	}
+
+	pvcs, err := s.pvcLister.List("", labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	storageByNamespace := map[string]int64{}
+	for _, pvc := range pvcs {
+		if pvc.Status.Phase != corev1.ClaimBound {
+			continue
+		}
+		storageByNamespace[pvc.Namespace] += pvc.Status.Capacity.Storage().Value()
+	}
+
+	namespaces, err := s.namespaceLister.List("", labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	usage := showback.DayUsage{}
+	for _, ns := range namespaces {
+		pods, storage := podsByNamespace[ns.Name], storageByNamespace[ns.Name]
+		if len(pods) == 0 && storage == 0 {
+			continue
+		}
+		var project string
+		if clusterName, projectName := ref.Parse(ns.Annotations[projectIDAnnotation]); clusterName == s.clusterName {
+			project = projectName
+		}
+		requests, _ := nodesyncer.AggregateRequestsAndLimits(pods)
+		usage.Add(ns.Name, project, showback.UsageOf(requests, storage, d))
+	}
+	return usage, nil
+}
+
+// store adds the usage to the usage of the day in the config map of the day.
+func (s *sampler) store(now time.Time, usage showback.DayUsage) error {
+	cluster, err := s.clusterLister.Get("", s.clusterName)
+	if err != nil {
+		return err
+	}
+
+	name := showback.ConfigMapName(now)
+	cm, err := s.configMaps.GetNamespaced(s.clusterName, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: s.clusterName,
+				Labels: map[string]string{
+					showback.Label: "true",
+				},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "management.cattle.io/v3",
+					Kind:       "Cluster",
+					Name:       cluster.Name,
+					UID:        cluster.UID,
+				}},
+			},
+		}
+	} else if err != nil {
+		return err
+	}
+
+	cm = cm.DeepCopy()
+	if cm.BinaryData == nil {
+		cm.BinaryData = map[string][]byte{}
+	}
+	dayUsage, err := showback.LoadDay(cm.BinaryData)
+	if err != nil {
+		logrus.Warnf("discarding showback usage of cluster [%s]: %v", s.clusterName, err)
+		dayUsage = showback.DayUsage{}
+	}
+	for namespace, nsUsage := range usage {
+		dayUsage.Add(namespace, nsUsage.Project, nsUsage.Usage)
+	}
+	if err := showback.StoreDay(cm.BinaryData, dayUsage); err != nil {
+		return err
+	}
+
+	if cm.ResourceVersion == "" {
+		_, err = s.configMaps.Create(cm)
+	} else {
+		_, err = s.configMaps.Update(cm)
+	}
+	return err
+}
+
+// prune deletes the config maps of the days before the cutoff.
+func (s *sampler) prune(cutoff time.Time) error {
+	cms, err := s.configMaps.ListNamespaced(s.clusterName, metav1.ListOptions{
+		LabelSelector: showback.Label + "=true",
+	})
+	if err != nil {
+		return err
+	}
+	// config map names sort like their days
+	oldest := showback.ConfigMapName(cutoff)
+	for _, cm := range cms.Items {
+		if cm.Name >= oldest {
+			continue
+		}
+		if err := s.configMaps.DeleteNamespaced(cm.Namespace, cm.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -9,8 +9,10 @@
 	"github.com/rancher/lasso/pkg/cache"
 	"github.com/rancher/lasso/pkg/client"
 	"github.com/rancher/lasso/pkg/controller"
+	provv1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
 	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
 	cluster2 "github.com/rancher/rancher/pkg/controllers/provisioningv2/cluster"
+	"github.com/rancher/rancher/pkg/etcdsnapshot"
 	provisioningcontrollers "github.com/rancher/rancher/pkg/generated/controllers/provisioning.cattle.io/v1"
 	"github.com/rancher/rancher/pkg/types/config"
 	corecontrollers "github.com/rancher/wrangler/pkg/generated/controllers/core/v1"
@@ -31,6 +33,7 @@ type handler struct {
 	clusterName  string
 	clusterCache provisioningcontrollers.ClusterCache
 	clusters     provisioningcontrollers.ClusterClient
+	secretCache  corecontrollers.SecretCache
 }
 
 func Register(ctx context.Context, userContext *config.UserContext) error {
@@ -38,6 +41,7 @@ func Register(ctx context.Context, userContext *config.UserContext) error {
 		clusterName:  userContext.ClusterName,
 		clusterCache: userContext.Management.Wrangler.Provisioning.Cluster().Cache(),
 		clusters:     userContext.Management.Wrangler.Provisioning.Cluster(),
+		secretCache:  userContext.Management.Wrangler.Core.Secret().Cache(),
 	}
 
 	// We want to watch two specific objects, not all config maps.  So we setup a custom controller
@@ -84,6 +88,9 @@ func (h *handler) OnChange(key string, configMap *corev1.ConfigMap) (*corev1.Con
 	if err != nil {
 		return configMap, err
 	}
+	if err := h.setEncryptionKeyIDs(cluster[0], fromConfigMap); err != nil {
+		return configMap, err
+	}
 
 	if !equality.Semantic.DeepEqual(cluster[0].Status.ETCDSnapshots, fromConfigMap) {
 		cluster := cluster[0].DeepCopy()
@@ -126,6 +133,41 @@ func (h *handler) configMapToSnapshots(configMap *corev1.ConfigMap) (result []rk
 	return result, nil
 }
 
+// setEncryptionKeyIDs records the key the agent encrypts the uploaded copies of the snapshots with. The agent is only
+// given the active key of the cluster, new snapshots are uploaded with it, the others keep the key recorded before.
+func (h *handler) setEncryptionKeyIDs(cluster *provv1.Cluster, snapshots []rkev1.ETCDSnapshot) error {
+	if cluster.Spec.RKEConfig == nil || cluster.Spec.RKEConfig.ETCD == nil || cluster.Spec.RKEConfig.ETCD.Encryption == nil {
+		return nil
+	}
+
+	recorded := map[string]string{}
+	for _, snapshot := range cluster.Status.ETCDSnapshots {
+		recorded[snapshot.NodeName+"/"+snapshot.Name] = snapshot.EncryptionKeyID
+	}
+
+	var active string
+	for i := range snapshots {
+		if keyID := recorded[snapshots[i].NodeName+"/"+snapshots[i].Name]; keyID != "" {
+			snapshots[i].EncryptionKeyID = keyID
+			continue
+		}
+		if active == "" {
+			encryption := cluster.Spec.RKEConfig.ETCD.Encryption
+			secret, err := h.secretCache.Get(cluster.Namespace, encryption.SecretName)
+			if err != nil {
+				return err
+			}
+			ring, err := etcdsnapshot.KeyRingFromSecret(secret, encryption.KeyID)
+			if err != nil {
+				return err
+			}
+			active = ring.Active
+		}
+		snapshots[i].EncryptionKeyID = active
+	}
+	return nil
+}
+
 type s3Config struct {
 	Endpoint      string `json:"endpoint,omitempty"`
 	EndpointCA    string `json:"endpointCA,omitempty"`
@@ -5,8 +5,10 @@
 	"context"
 	"fmt"
 	"net/url"
+	"regexp"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
@@ -18,6 +20,7 @@
 	alertconfig "github.com/rancher/rancher/pkg/controllers/managementuserlegacy/alert/config"
 	"github.com/rancher/rancher/pkg/controllers/managementuserlegacy/alert/deployer"
 	"github.com/rancher/rancher/pkg/controllers/managementuserlegacy/alert/manager"
+	"github.com/rancher/rancher/pkg/controllers/managementuserlegacy/alert/silence"
 	v1 "github.com/rancher/rancher/pkg/generated/norman/core/v1"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	projectv3 "github.com/rancher/rancher/pkg/generated/norman/project.cattle.io/v3"
@@ -42,6 +45,7 @@
 	webhookReceiverURL  = "http://webhook-receiver.cattle-prometheus.svc:9094/"
 	DingTalk            = "DINGTALK"
 	MicrosoftTeams      = "MICROSOFT_TEAMS"
+	silencedReceiver    = "silenced"
 )
 
 type WebhookReceiverConfig struct {
@@ -75,6 +79,7 @@ func NewConfigSyncer(ctx context.Context, cluster *config.UserContext, alertMana
 		clusterName:             cluster.ClusterName,
 		alertManager:            alertManager,
 		operatorCRDManager:      operatorCRDManager,
+		silencer:                silence.NewSilencer(cluster),
 	}
 }
 
@@ -92,6 +97,7 @@ type ConfigSyncer struct {
 	clusterName             string
 	alertManager            *manager.AlertManager
 	operatorCRDManager      *manager.PromOperatorCRDManager
+	silencer                *silence.Silencer
 }
 
 func (d *ConfigSyncer) ProjectGroupSync(key string, alert *v3.ProjectAlertGroup) (runtime.Object, error) {
@@ -114,6 +120,10 @@ func (d *ConfigSyncer) NotifierSync(key string, alert *v3.Notifier) (runtime.Obj
 	return nil, d.sync()
 }
 
+func (d *ConfigSyncer) SilenceSync(key string, obj *v3.AlertSilence) (runtime.Object, error) {
+	return nil, d.sync()
+}
+
 //sync: update the secret which store the configuration of alertmanager given the latest configured notifiers and alerts rules.
 //For each alert, it will generate a route and a receiver in the alertmanager's configuration file, for metric rules it will update operator crd also.
 func (d *ConfigSyncer) sync() error {
@@ -228,6 +238,14 @@ func (d *ConfigSyncer) sync() error {
 		return err
 	}
 
+	silences, err := d.silencer.Active(time.Now())
+	if err != nil {
+		return errors.Wrapf(err, "List alert silences")
+	}
+	if err = d.addSilence2Config(config, silences); err != nil {
+		return err
+	}
+
 	data, err := yaml.Marshal(config)
 	if err != nil {
 		return errors.Wrapf(err, "Marshal secrets")
@@ -373,7 +391,8 @@ func (d *ConfigSyncer) addProjectAlert2Config(config *alertconfig.Config, projec
 
 					groupBy := getProjectAlertGroupBy(alert.Spec)
 
-					if alert.Spec.PodRule != nil || alert.Spec.WorkloadRule != nil || alert.Spec.MetricRule != nil {
+					if alert.Spec.PodRule != nil || alert.Spec.WorkloadRule != nil || alert.Spec.MetricRule != nil ||
+						alert.Spec.VolumeRule != nil || alert.Spec.CertificateRule != nil || alert.Spec.JobRule != nil {
 						ruleID := common.GetRuleID(groupID, alert.Name)
 						d.addRule(ruleID, r1, alert.Spec.CommonRuleField, groupBy)
 					}
@@ -432,6 +451,43 @@ func (d *ConfigSyncer) addClusterAlert2Config(config *alertconfig.Config, alerts
 	return nil
 }
 
+// addSilence2Config routes the alerts muted by the active silences to a receiver without notifiers, ahead of the routes
+// of the alert groups.
+func (d *ConfigSyncer) addSilence2Config(config *alertconfig.Config, silences []silence.Active) error {
+	if len(silences) == 0 {
+		return nil
+	}
+
+	var routes []*alertconfig.Route
+	for _, s := range silences {
+		route := &alertconfig.Route{
+			Receiver: silencedReceiver,
+		}
+		for _, m := range s.Matchers {
+			if !m.IsRegex {
+				if route.Match == nil {
+					route.Match = map[string]string{}
+				}
+				route.Match[m.Name] = m.Value
+				continue
+			}
+			re, err := regexp.Compile(m.Value)
+			if err != nil {
+				return errors.Wrapf(err, "Invalid matcher %s of alert silence %s", m.Name, s.Name)
+			}
+			if route.MatchRE == nil {
+				route.MatchRE = map[string]alertconfig.Regexp{}
+			}
+			route.MatchRE[m.Name] = alertconfig.Regexp{Regexp: re}
+		}
+		routes = append(routes, route)
+	}
+
+	config.Receivers = append(config.Receivers, &alertconfig.Receiver{Name: silencedReceiver})
+	config.Route.Routes = append(routes, config.Route.Routes...)
+	return nil
+}
+
 func (d *ConfigSyncer) addRule(ruleID string, route *alertconfig.Route, comm v32.CommonRuleField, groupBy []model.LabelName) {
 	inherited := true
 	if comm.Inherited != nil {
@@ -486,6 +542,11 @@ func (d *ConfigSyncer) addRecipients(notifiers []*v3.Notifier, receiver *alertco
 				logrus.Debugf("Can not find the notifier %s", r.NotifierName)
 				continue
 			}
+			if notifierutil.DeliveredByRancher(notifier) {
+				// the alerts routed to the receiver are queued for the notifier by the alert dispatcher
+				receiverExist = true
+				continue
+			}
 			commonNotifierConfig := alertconfig.NotifierConfig{
 				VSendResolved: notifier.Spec.SendResolved,
 			}
@@ -618,6 +679,66 @@ func (d *ConfigSyncer) addRecipients(notifiers []*v3.Notifier, receiver *alertco
 				receiver.SlackConfigs = append(receiver.SlackConfigs, slack)
 				receiverExist = true
 
+			} else if notifier.Spec.MattermostConfig != nil {
+				// incoming webhooks of Mattermost accept the payloads of Slack
+				mattermost := &alertconfig.SlackConfig{
+					NotifierConfig: commonNotifierConfig,
+					APIURL:         alertconfig.Secret(notifier.Spec.MattermostConfig.URL),
+					Channel:        notifier.Spec.MattermostConfig.DefaultRecipient,
+					Username:       notifier.Spec.MattermostConfig.Username,
+					Text:           `{{ template "slack.text" . }}`,
+					Title:          `{{ template "rancher.title" . }}`,
+					Color:          `{{ if eq (index .Alerts 0).Labels.severity "critical" }}danger{{ else if eq (index .Alerts 0).Labels.severity "warning" }}warning{{ else }}good{{ end }}`,
+				}
+				if r.Recipient != "" {
+					mattermost.Channel = r.Recipient
+				}
+
+				if notifierutil.IsHTTPClientConfigSet(notifier.Spec.MattermostConfig.HTTPClientConfig) {
+					url, err := toAlertManagerURL(notifier.Spec.MattermostConfig.HTTPClientConfig.ProxyURL)
+					if err != nil {
+						logrus.Errorf("Failed to parse mattermost proxy url %s, %v", notifier.Spec.MattermostConfig.HTTPClientConfig.ProxyURL, err)
+						continue
+					}
+					mattermost.HTTPConfig = &alertconfig.HTTPClientConfig{
+						ProxyURL: *url,
+					}
+				}
+				receiver.SlackConfigs = append(receiver.SlackConfigs, mattermost)
+				receiverExist = true
+
+			} else if notifier.Spec.OpsgenieConfig != nil {
+				apiHost := notifier.Spec.OpsgenieConfig.APIURL
+				if apiHost == "" {
+					apiHost = "https://api.opsgenie.com"
+				}
+				opsgenie := &alertconfig.OpsGenieConfig{
+					NotifierConfig: commonNotifierConfig,
+					APIKey:         alertconfig.Secret(notifier.Spec.OpsgenieConfig.APIKey),
+					APIHost:        strings.TrimSuffix(apiHost, "/") + "/",
+					Message:        `{{ template "rancher.title" . }}`,
+					Description:    `{{ template "slack.text" . }}`,
+					Source:         "rancher",
+					Teams:          notifier.Spec.OpsgenieConfig.DefaultRecipient,
+					Tags:           strings.Join(notifier.Spec.OpsgenieConfig.Tags, ","),
+				}
+				if r.Recipient != "" {
+					opsgenie.Teams = r.Recipient
+				}
+
+				if notifierutil.IsHTTPClientConfigSet(notifier.Spec.OpsgenieConfig.HTTPClientConfig) {
+					url, err := toAlertManagerURL(notifier.Spec.OpsgenieConfig.HTTPClientConfig.ProxyURL)
+					if err != nil {
+						logrus.Errorf("Failed to parse opsgenie proxy url %s, %v", notifier.Spec.OpsgenieConfig.HTTPClientConfig.ProxyURL, err)
+						continue
+					}
+					opsgenie.HTTPConfig = &alertconfig.HTTPClientConfig{
+						ProxyURL: *url,
+					}
+				}
+				receiver.OpsGenieConfigs = append(receiver.OpsGenieConfigs, opsgenie)
+				receiverExist = true
+
 			} else if notifier.Spec.SMTPConfig != nil {
 				header := map[string]string{}
 				header["Subject"] = `{{ template "rancher.title" . }}`
@@ -698,6 +819,12 @@ func getProjectAlertGroupBy(spec v32.ProjectAlertRuleSpec) []model.LabelName {
 		return []model.LabelName{"rule_id", "workload_namespace", "workload_name", "workload_kind"}
 	} else if spec.MetricRule != nil {
 		return []model.LabelName{"rule_id"}
+	} else if spec.VolumeRule != nil {
+		return []model.LabelName{"rule_id", "namespace", "volume_name"}
+	} else if spec.CertificateRule != nil {
+		return []model.LabelName{"rule_id", "namespace", "certificate_name"}
+	} else if spec.JobRule != nil {
+		return []model.LabelName{"rule_id", "workload_namespace", "workload_name", "workload_kind"}
 	}
 
 	return nil
@@ -739,6 +866,9 @@ func (d *ConfigSyncer) syncWebhookConfig(notifiers []*v3.Notifier, cAlertGroupsM
 				logrus.Debugf("Can not find the notifier %s", r.NotifierName)
 				continue
 			}
+			if notifierutil.DeliveredByRancher(notifier) {
+				continue
+			}
 			if notifier.Spec.DingtalkConfig != nil {
 				provider := &Provider{
 					Type:       DingTalk,
@@ -9,6 +9,7 @@
 	"github.com/prometheus/common/model"
 	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/controllers/managementuserlegacy/alert/manager"
+	"github.com/rancher/rancher/pkg/controllers/managementuserlegacy/alert/silence"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -70,6 +71,41 @@ func TestAddClusterAlert2Config(t *testing.T) {
 
 }
 
+func TestAddSilence2Config(t *testing.T) {
+	config := manager.GetAlertManagerDefaultConfig()
+	configSyncer := ConfigSyncer{
+		clusterName: clusterName,
+	}
+
+	if err := configSyncer.addClusterAlert2Config(config, nodeRulesMap, []string{groupID}, clusterGroupMap, notifiers); err != nil {
+		t.Fatal(err)
+	}
+	silences := []silence.Active{{
+		Name: "maintenance",
+		Matchers: []v32.AlertSilenceMatcher{
+			{Name: "group_id", Value: groupID},
+			{Name: "node_name", Value: "worker-.*", IsRegex: true},
+		},
+	}}
+	if err := configSyncer.addSilence2Config(config, silences); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(config.Route.Routes) != 2 {
+		t.Fatalf("expect 2 routes, actual %d", len(config.Route.Routes))
+	}
+	route := config.Route.Routes[0]
+	if route.Receiver != silencedReceiver || route.Continue {
+		t.Errorf("expect the silence route first, actual receiver %s", route.Receiver)
+	}
+	if route.Match["group_id"] != groupID || route.MatchRE["node_name"].String() != "worker-.*" {
+		t.Errorf("unexpected silence route matchers %v %v", route.Match, route.MatchRE)
+	}
+	if receiver := config.Receivers[len(config.Receivers)-1]; receiver.Name != silencedReceiver {
+		t.Errorf("expect the silenced receiver, actual %s", receiver.Name)
+	}
+}
+
 var (
 	projectAlertTests = []struct {
 		caseName     string
@@ -10,7 +10,9 @@
 	"github.com/rancher/norman/controller"
 	"github.com/rancher/rancher/pkg/controllers/managementuserlegacy/alert/configsyncer"
 	"github.com/rancher/rancher/pkg/controllers/managementuserlegacy/alert/deployer"
+	"github.com/rancher/rancher/pkg/controllers/managementuserlegacy/alert/dispatcher"
 	"github.com/rancher/rancher/pkg/controllers/managementuserlegacy/alert/manager"
+	"github.com/rancher/rancher/pkg/controllers/managementuserlegacy/alert/silence"
 	"github.com/rancher/rancher/pkg/controllers/managementuserlegacy/alert/statesyncer"
 	"github.com/rancher/rancher/pkg/controllers/managementuserlegacy/alert/watcher"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
@@ -45,6 +47,7 @@ func Register(ctx context.Context, cluster *config.UserContext) {
 	projectAlertGroups := cluster.Management.Management.ProjectAlertGroups("")
 
 	notifiers := cluster.Management.Management.Notifiers(cluster.ClusterName)
+	silences := cluster.Management.Management.AlertSilences(cluster.ClusterName)
 
 	deploy := deployer.NewDeployer(cluster, alertmanager)
 	clusterAlertGroups.AddClusterScopedHandler(ctx, "cluster-alert-group-deployer", cluster.ClusterName, deploy.ClusterGroupSync)
@@ -60,6 +63,8 @@ func Register(ctx context.Context, cluster *config.UserContext) {
 	clusterAlertRules.AddClusterScopedHandler(ctx, "cluster-alert-rule-controller", cluster.ClusterName, configSyncer.ClusterRuleSync)
 	projectAlertRules.AddClusterScopedHandler(ctx, "project-alert-rule-controller", cluster.ClusterName, configSyncer.ProjectRuleSync)
 	notifiers.AddClusterScopedHandler(ctx, "notifier-config-syncer", cluster.ClusterName, configSyncer.NotifierSync)
+	silences.AddClusterScopedHandler(ctx, "alert-silence-config-syncer", cluster.ClusterName, configSyncer.SilenceSync)
+	silence.Register(ctx, cluster)
 
 	cleaner := &alertGroupCleaner{
 		clusterName:        cluster.ClusterName,
@@ -84,6 +89,7 @@ func Register(ctx context.Context, cluster *config.UserContext) {
 	projects.AddClusterScopedLifecycle(ctx, "project-precan-alert-controller", cluster.ClusterName, projectLifecycle)
 
 	statesyncer.StartStateSyncer(ctx, cluster, alertmanager)
+	dispatcher.StartDispatcher(ctx, cluster, alertmanager)
 
 	i := &initClusterAlerts{
 		clusterAlertGroups:      clusterAlertGroups,
@@ -107,6 +113,9 @@ func Register(ctx context.Context, cluster *config.UserContext) {
 	watcher.StartWorkloadWatcher(ctx, cluster, alertmanager)
 	watcher.StartNodeWatcher(ctx, cluster, alertmanager)
 	watcher.StartClusterScanWatcher(ctx, cluster, alertmanager)
+	watcher.StartVolumeWatcher(ctx, cluster, alertmanager)
+	watcher.StartCertificateWatcher(ctx, cluster, alertmanager)
+	watcher.StartJobWatcher(ctx, cluster, alertmanager)
 
 }
 
@@ -33,6 +33,15 @@
 {{- else if eq .CommonLabels.alert_type "workload" -}}
 The workload {{ if .GroupLabels.workload_namespace}}{{.GroupLabels.workload_namespace}}:{{end}}{{.GroupLabels.workload_name}} has available replicas less than {{ .CommonLabels.available_percentage}}%
 
+{{- else if eq .CommonLabels.alert_type "volumeUsage" -}}
+The volume {{ if .GroupLabels.namespace}}{{.GroupLabels.namespace}}:{{end}}{{.GroupLabels.volume_name}} has used over {{ .CommonLabels.used_percentage}}% of its capacity
+
+{{- else if eq .CommonLabels.alert_type "certificateExpiry" -}}
+The certificate {{ if .GroupLabels.namespace}}{{.GroupLabels.namespace}}:{{end}}{{.GroupLabels.certificate_name}} expires in less than {{ .CommonLabels.days_before_expiry}} days
+
+{{- else if eq .CommonLabels.alert_type "jobFailed" -}}
+The {{ .GroupLabels.workload_kind}} {{ if .GroupLabels.workload_namespace}}{{.GroupLabels.workload_namespace}}:{{end}}{{.GroupLabels.workload_name}} failed
+
 {{- else if eq .CommonLabels.alert_type "metric" -}}
 The metric {{ .CommonLabels.alert_name}} crossed the threshold 
 {{ end -}}
@@ -106,6 +115,18 @@
 Project Name: {{ .Labels.project_name}}
 Available Replicas: {{ .Labels.available_replicas}}
 Desired Replicas: {{ .Labels.desired_replicas}}
+{{- else if eq .Labels.alert_type "volumeUsage" }}
+Project Name: {{ .Labels.project_name}}
+Used Space: {{ .Labels.used_space}}
+Capacity: {{ .Labels.capacity}}
+{{- else if eq .Labels.alert_type "certificateExpiry" }}
+Project Name: {{ .Labels.project_name}}
+Expiry Time: {{ .Labels.expiry_time}}
+{{- if .Labels.ingress_names }}
+Ingress Names: {{ .Labels.ingress_names}}{{ end }}
+{{- else if eq .Labels.alert_type "jobFailed" }}
+Project Name: {{ .Labels.project_name}}
+Job Name: {{ .Labels.job_name}}
 {{- else if eq .Labels.alert_type "metric" }}
 {{- if .Labels.namespace }}
 Namespace: {{ .Labels.namespace}}{{ end }}
@@ -185,6 +206,19 @@
 Project Name: {{.Labels.project_name}}<br>
 Available Replicas: {{ .Labels.available_replicas}}<br>
 Desired Replicas: {{ .Labels.desired_replicas}}<br>
+{{- else if eq .Labels.alert_type "volumeUsage" }}
+Project Name: {{.Labels.project_name}}<br>
+Used Space: {{ .Labels.used_space}}<br>
+Capacity: {{ .Labels.capacity}}<br>
+{{- else if eq .Labels.alert_type "certificateExpiry" }}
+Project Name: {{.Labels.project_name}}<br>
+Expiry Time: {{ .Labels.expiry_time}}<br>
+{{- if .Labels.ingress_names }}
+Ingress Names: {{.Labels.ingress_names}}<br>
+{{ end -}}
+{{- else if eq .Labels.alert_type "jobFailed" }}
+Project Name: {{.Labels.project_name}}<br>
+Job Name: {{ .Labels.job_name}}<br>
 {{- else if eq .Labels.alert_type "metric" }}
 {{- if .Labels.project_name }}
 Project Name: {{.Labels.project_name}}<br>
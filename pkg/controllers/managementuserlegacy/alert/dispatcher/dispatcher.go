@@ -0,0 +1,232 @@
+package dispatcher
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/rancher/norman/controller"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/controllers/managementuserlegacy/alert/common"
+	"github.com/rancher/rancher/pkg/controllers/managementuserlegacy/alert/deployer"
+	"github.com/rancher/rancher/pkg/controllers/managementuserlegacy/alert/manager"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/notifiers"
+	"github.com/rancher/rancher/pkg/types/config"
+	corecontrollers "github.com/rancher/wrangler/pkg/generated/controllers/core/v1"
+	"github.com/rancher/wrangler/pkg/ticker"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const syncInterval = 30 * time.Second
+
+// notificationTmpl renders the messages of the alerts like alertmanager does for the other notifiers.
+var notificationTmpl = template.Must(template.New("notification").Parse(deployer.NotificationTmpl))
+
+// StartDispatcher starts queueing the active alerts of alertmanager for the notifiers alertmanager can not deliver to,
+// the notifier delivery controller then delivers them with the templates of the notifiers.
+func StartDispatcher(ctx context.Context, cluster *config.UserContext, manager *manager.AlertManager) {
+	d := &Dispatcher{
+		clusterName:             cluster.ClusterName,
+		alertManager:            manager,
+		clusterAlertGroupLister: cluster.Management.Management.ClusterAlertGroups(cluster.ClusterName).Controller().Lister(),
+		projectAlertGroupLister: cluster.Management.Management.ProjectAlertGroups("").Controller().Lister(),
+		notifierLister:          cluster.Management.Management.Notifiers(cluster.ClusterName).Controller().Lister(),
+		configMaps:              cluster.Management.Wrangler.Core.ConfigMap(),
+	}
+	go d.watch(ctx, syncInterval)
+}
+
+type Dispatcher struct {
+	clusterName             string
+	alertManager            *manager.AlertManager
+	clusterAlertGroupLister v3.ClusterAlertGroupLister
+	projectAlertGroupLister v3.ProjectAlertGroupLister
+	notifierLister          v3.NotifierLister
+	configMaps              corecontrollers.ConfigMapClient
+}
+
+func (d *Dispatcher) watch(ctx context.Context, interval time.Duration) {
+	for range ticker.Context(ctx, interval) {
+		if err := d.dispatch(time.Now()); err != nil {
+			logrus.Warnf("Failed to dispatch the alerts of cluster %s, %v", d.clusterName, err)
+		}
+	}
+}
+
+func (d *Dispatcher) dispatch(now time.Time) error {
+	if !d.alertManager.IsDeploy {
+		return nil
+	}
+
+	notifierList, err := d.notifierLister.List(d.clusterName, labels.NewSelector())
+	if err != nil {
+		return err
+	}
+	var rancherNotifiers []*v3.Notifier
+	for _, notifier := range notifierList {
+		if notifiers.DeliveredByRancher(notifier) {
+			rancherNotifiers = append(rancherNotifiers, notifier)
+		}
+	}
+	if len(rancherNotifiers) == 0 {
+		return nil
+	}
+
+	routes, err := d.routes()
+	if err != nil {
+		return err
+	}
+	apiAlerts, err := d.alertManager.GetAlertList()
+	if err != nil {
+		return err
+	}
+	active := activeAlerts(apiAlerts)
+
+	for _, notifier := range rancherNotifiers {
+		var notifierRoutes []notifiers.AlertRoute
+		for _, route := range routes[d.clusterName+":"+notifier.Name] {
+			route.Alerts = active[route.GroupID]
+			notifierRoutes = append(notifierRoutes, route)
+		}
+		sendResolved := notifier.Spec.SendResolved
+		if _, err := notifiers.UpdateQueue(d.configMaps, notifier, func(q *notifiers.Queue) bool {
+			return q.SyncAlerts(notifierRoutes, sendResolved, alertMessage, now)
+		}); err != nil {
+			logrus.Warnf("Failed to queue the alerts of notifier %s:%s, %v", notifier.Namespace, notifier.Name, err)
+		}
+	}
+	return nil
+}
+
+// routes returns the routes of the alert groups of the cluster by the notifiers of their recipients, as in
+// c-xxxxx:n-xxxxx.
+func (d *Dispatcher) routes() (map[string][]notifiers.AlertRoute, error) {
+	routes := map[string][]notifiers.AlertRoute{}
+	add := func(groupID string, recipients []v32.Recipient, timing v32.TimingField) {
+		for _, r := range recipients {
+			if r.NotifierName == "" {
+				continue
+			}
+			routes[r.NotifierName] = append(routes[r.NotifierName], notifiers.AlertRoute{
+				GroupID:        groupID,
+				Recipient:      r.Recipient,
+				RepeatInterval: time.Duration(timing.RepeatIntervalSeconds) * time.Second,
+			})
+		}
+	}
+
+	clusterGroups, err := d.clusterAlertGroupLister.List(d.clusterName, labels.NewSelector())
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range clusterGroups {
+		add(common.GetGroupID(group.Namespace, group.Name), group.Spec.Recipients, group.Spec.TimingField)
+	}
+
+	projectGroups, err := d.projectAlertGroupLister.List(metav1.NamespaceAll, labels.NewSelector())
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range projectGroups {
+		if controller.ObjectInCluster(d.clusterName, group) {
+			add(common.GetGroupID(group.Namespace, group.Name), group.Spec.Recipients, group.Spec.TimingField)
+		}
+	}
+	return routes, nil
+}
+
+// activeAlerts returns the active alerts by the receivers they are routed to, the receivers of the alert groups are
+// named by their group id.
+func activeAlerts(apiAlerts []*manager.APIAlert) map[string][]*notifiers.Alert {
+	active := map[string][]*notifiers.Alert{}
+	for _, a := range apiAlerts {
+		if a.Alert == nil || a.Status.State != manager.AlertStateActive {
+			continue
+		}
+		lbs := map[string]string{}
+		for k, v := range a.Labels {
+			lbs[string(k)] = string(v)
+		}
+		annotations := map[string]string{}
+		for k, v := range a.Annotations {
+			annotations[string(k)] = string(v)
+		}
+		alert := notifiers.AlertFromLabels(lbs, annotations)
+		alert.Fingerprint = a.Fingerprint
+		alert.StartsAt = a.StartsAt
+		for _, receiver := range a.Receivers {
+			active[receiver] = append(active[receiver], alert)
+		}
+	}
+	return active
+}
+
+// templateData is the part of the data of the alertmanager notification templates used by the rancher templates.
+type templateData struct {
+	Status       string
+	Alerts       templateAlerts
+	GroupLabels  map[string]string
+	CommonLabels map[string]string
+}
+
+type templateAlert struct {
+	Status      string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+type templateAlerts []templateAlert
+
+func (as templateAlerts) Firing() []templateAlert {
+	return as.withStatus(notifiers.AlertStatusFiring)
+}
+
+func (as templateAlerts) Resolved() []templateAlert {
+	return as.withStatus(notifiers.AlertStatusResolved)
+}
+
+func (as templateAlerts) withStatus(status string) []templateAlert {
+	var res []templateAlert
+	for _, a := range as {
+		if a.Status == status {
+			res = append(res, a)
+		}
+	}
+	return res
+}
+
+// alertMessage returns the message of the alert, with the title and text alertmanager sends for the alert.
+func alertMessage(alert *notifiers.Alert) *notifiers.Message {
+	data := templateData{
+		Status: alert.Status,
+		Alerts: templateAlerts{{
+			Status:      alert.Status,
+			Labels:      alert.Labels,
+			Annotations: alert.Annotations,
+		}},
+		GroupLabels:  alert.Labels,
+		CommonLabels: alert.Labels,
+	}
+	msg := &notifiers.Message{
+		Title:   strings.TrimSpace(execute("rancher.title", data)),
+		Content: strings.TrimSpace(execute("slack.text", data)),
+		Alert:   alert,
+	}
+	if msg.Title == "" {
+		msg.Title = alert.Name
+	}
+	return msg
+}
+
+func execute(name string, data templateData) string {
+	var buf bytes.Buffer
+	if err := notificationTmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		logrus.Debugf("Failed to render the %s template of alert, %v", name, err)
+	}
+	return buf.String()
+}
@@ -0,0 +1,97 @@
+package dispatcher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/controllers/managementuserlegacy/alert/manager"
+	"github.com/rancher/rancher/pkg/notifiers"
+	"github.com/stretchr/testify/assert"
+)
+
+func testAPIAlerts() []*manager.APIAlert {
+	return []*manager.APIAlert{
+		{
+			Alert: &model.Alert{
+				Labels: model.LabelSet{
+					"alert_name":       "High restarts",
+					"alert_type":       "podRestarts",
+					"severity":         "warning",
+					"cluster_name":     "prod",
+					"project_name":     "web",
+					"group_id":         "p-12345:g-abcde",
+					"rule_id":          "p-12345:g-abcde_r-1",
+					"namespace":        "web",
+					"pod_name":         "web-0",
+					"restart_times":    "3",
+					"restart_interval": "300",
+				},
+				Annotations: model.LabelSet{"runbook_url": "https://runbooks.example.com/restarts"},
+				StartsAt:    time.Unix(1700000000, 0),
+			},
+			Status:      manager.AlertStatus{State: manager.AlertStateActive},
+			Receivers:   []string{"p-12345:g-abcde"},
+			Fingerprint: "2f1e0c5a8b7d6e43",
+		},
+		{
+			Alert: &model.Alert{
+				Labels: model.LabelSet{"alert_name": "Muted", "group_id": "p-12345:g-abcde"},
+			},
+			Status:      manager.AlertStatus{State: manager.AlertStateSuppressed},
+			Receivers:   []string{"silenced"},
+			Fingerprint: "0000000000000000",
+		},
+	}
+}
+
+func TestActiveAlerts(t *testing.T) {
+	active := activeAlerts(testAPIAlerts())
+	assert.Empty(t, active["silenced"], "expected suppressed alerts to be skipped")
+	if !assert.Len(t, active["p-12345:g-abcde"], 1) {
+		return
+	}
+	alert := active["p-12345:g-abcde"][0]
+	assert.Equal(t, "2f1e0c5a8b7d6e43", alert.Fingerprint)
+	assert.Equal(t, "p-12345:g-abcde_r-1", alert.RuleID)
+	assert.Equal(t, "https://runbooks.example.com/restarts", alert.RunbookURL)
+	assert.Equal(t, time.Unix(1700000000, 0), alert.StartsAt)
+}
+
+func TestAlertMessage(t *testing.T) {
+	alert := activeAlerts(testAPIAlerts())["p-12345:g-abcde"][0]
+
+	msg := alertMessage(alert)
+	assert.Equal(t, "The Pod web:web-0 restarts 3 times in 300 sec", msg.Title)
+	assert.Contains(t, msg.Content, "Alert Name: High restarts")
+	assert.Contains(t, msg.Content, "Namespace: web")
+
+	resolved := *alert
+	resolved.Status = notifiers.AlertStatusResolved
+	msg = alertMessage(&resolved)
+	assert.Contains(t, msg.Title, "[Resolved]")
+	assert.Contains(t, msg.Content, "Alert Name: High restarts", "expected the resolved alert to be listed")
+}
+
+func TestRenderQueuedAlert(t *testing.T) {
+	q := &notifiers.Queue{Delivered: map[string]time.Time{}, Firing: map[string]*notifiers.FiringAlert{}}
+	route := notifiers.AlertRoute{
+		GroupID:        "p-12345:g-abcde",
+		Recipient:      "#alerts",
+		RepeatInterval: time.Hour,
+		Alerts:         activeAlerts(testAPIAlerts())["p-12345:g-abcde"],
+	}
+	assert.True(t, q.SyncAlerts([]notifiers.AlertRoute{route}, true, alertMessage, time.Now()))
+	if !assert.Len(t, q.Pending, 1) {
+		return
+	}
+
+	rendered, payload, err := notifiers.Render(&v32.NotifierTemplate{
+		Title:   `[{{ .Alert.Severity | upper }}] {{ .Alert.Name }}`,
+		Payload: `{"rule": {{ .Alert.RuleID | quote }}, "project": {{ .Alert.ProjectName | quote }}, "runbook": {{ .Alert.RunbookURL | quote }}}`,
+	}, q.Pending[0].Message())
+	assert.NoError(t, err)
+	assert.Equal(t, "[WARNING] High restarts", rendered.Title)
+	assert.JSONEq(t, `{"rule": "p-12345:g-abcde_r-1", "project": "web", "runbook": "https://runbooks.example.com/restarts"}`, string(payload))
+}
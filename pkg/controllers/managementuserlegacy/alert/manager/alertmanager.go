@@ -11,6 +11,7 @@
 
 	"github.com/prometheus/common/model"
 	alertconfig "github.com/rancher/rancher/pkg/controllers/managementuserlegacy/alert/config"
+	"github.com/rancher/rancher/pkg/controllers/managementuserlegacy/alert/silence"
 	monitorutil "github.com/rancher/rancher/pkg/monitoring"
 
 	v1 "github.com/rancher/rancher/pkg/generated/norman/core/v1"
@@ -85,6 +86,7 @@ type AlertManager struct {
 	dialer      dialer.Factory
 	clusterName string
 	client      *http.Client
+	silencer    *silence.Silencer
 	IsDeploy    bool
 }
 
@@ -104,6 +106,7 @@ func NewAlertManager(cluster *config.UserContext) *AlertManager {
 		svcLister:   cluster.Core.Services("").Controller().Lister(),
 		client:      client,
 		clusterName: cluster.ClusterName,
+		silencer:    silence.NewSilencer(cluster),
 	}
 }
 
@@ -320,6 +323,17 @@ func (m *AlertManager) RemoveSilenceRule(matcherName, matcherValue string) error
 }
 
 func (m *AlertManager) SendAlert(labels map[string]string) error {
+	// the alerts muted by a silence are not sent, so that they do not fire again once the silence ends if they are
+	// resolved by then
+	silenceName, err := m.silencer.Silenced(labels, time.Now())
+	if err != nil {
+		return err
+	}
+	if silenceName != "" {
+		logrus.Debugf("Alert %s is muted by silence %s", labels["rule_id"], silenceName)
+		return nil
+	}
+
 	url, err := m.GetAlertManagerEndpoint()
 	if err != nil {
 		return err
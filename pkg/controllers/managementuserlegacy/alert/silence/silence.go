@@ -0,0 +1,231 @@
+package silence
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/ref"
+	"github.com/robfig/cron"
+)
+
+const (
+	StatePending = "pending"
+	StateActive  = "active"
+	StateExpired = "expired"
+
+	groupIDLabel = "group_id"
+	ruleIDLabel  = "rule_id"
+)
+
+// Validate returns an error if the silence has an invalid window or matchers.
+func Validate(spec *v32.AlertSilenceSpec) error {
+	startsAt, endsAt, err := bounds(spec)
+	if err != nil {
+		return err
+	}
+	if !startsAt.IsZero() && !endsAt.IsZero() && !endsAt.After(startsAt) {
+		return fmt.Errorf("endsAt must be after startsAt")
+	}
+
+	if spec.Schedule == "" {
+		if endsAt.IsZero() {
+			return fmt.Errorf("endsAt is required without a schedule")
+		}
+		if spec.DurationMinutes != 0 || spec.TimeZone != "" {
+			return fmt.Errorf("durationMinutes and timeZone require a schedule")
+		}
+	} else {
+		if _, _, err := schedule(spec); err != nil {
+			return err
+		}
+		if spec.DurationMinutes <= 0 {
+			return fmt.Errorf("durationMinutes is required with a schedule")
+		}
+	}
+
+	names := map[string]bool{}
+	for _, m := range spec.Matchers {
+		if m.Name == groupIDLabel || m.Name == ruleIDLabel {
+			return fmt.Errorf("matcher %s is not allowed, use groupNames or ruleNames", m.Name)
+		}
+		if names[m.Name] {
+			return fmt.Errorf("duplicate matcher %s", m.Name)
+		}
+		names[m.Name] = true
+		if m.IsRegex {
+			if _, err := regexp.Compile(m.Value); err != nil {
+				return fmt.Errorf("invalid regex of matcher %s: %v", m.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Window returns the window of the silence that is active at now, or else its next window. It returns false if the
+// silence has no window left.
+func Window(spec *v32.AlertSilenceSpec, now time.Time) (time.Time, time.Time, bool, error) {
+	startsAt, endsAt, err := bounds(spec)
+	if err != nil {
+		return time.Time{}, time.Time{}, false, err
+	}
+
+	if spec.Schedule == "" {
+		if endsAt.IsZero() || !endsAt.After(now) {
+			return time.Time{}, time.Time{}, false, nil
+		}
+		return startsAt, endsAt, true, nil
+	}
+
+	sched, loc, err := schedule(spec)
+	if err != nil {
+		return time.Time{}, time.Time{}, false, err
+	}
+	duration := time.Duration(spec.DurationMinutes) * time.Minute
+
+	// the first window starting after now-duration is either active or the next one
+	from := now.Add(-duration)
+	if !startsAt.IsZero() && from.Before(startsAt) {
+		from = startsAt.Add(-time.Nanosecond)
+	}
+	start := sched.Next(from.In(loc))
+	if start.IsZero() {
+		return time.Time{}, time.Time{}, false, nil
+	}
+	end := start.Add(duration)
+	if !endsAt.IsZero() {
+		if !start.Before(endsAt) {
+			return time.Time{}, time.Time{}, false, nil
+		}
+		if end.After(endsAt) {
+			end = endsAt
+		}
+	}
+	if !end.After(now) {
+		return time.Time{}, time.Time{}, false, nil
+	}
+	return start, end, true, nil
+}
+
+// Status returns the status of the silence at now, and when it changes next. The time is zero if it does not change
+// anymore.
+func Status(spec *v32.AlertSilenceSpec, now time.Time) (v32.AlertSilenceStatus, time.Time) {
+	start, end, ok, err := Window(spec, now)
+	if err != nil {
+		return v32.AlertSilenceStatus{Message: err.Error()}, time.Time{}
+	}
+	if !ok {
+		return v32.AlertSilenceStatus{SilenceState: StateExpired}, time.Time{}
+	}
+
+	status := v32.AlertSilenceStatus{
+		SilenceState: StatePending,
+		EndTime:      end.UTC().Format(time.RFC3339),
+	}
+	next := start
+	if !start.IsZero() {
+		status.StartTime = start.UTC().Format(time.RFC3339)
+	}
+	if !start.After(now) {
+		status.SilenceState = StateActive
+		next = end
+	}
+	return status, next
+}
+
+// Matchers returns the matchers of the labels of the alerts muted by the silence, ruleIDs are the rule_id labels of
+// the rules of the silence. It returns false if the silence cannot match any alert, e.g. if its rules do not exist.
+func Matchers(spec *v32.AlertSilenceSpec, ruleIDs []string) ([]v32.AlertSilenceMatcher, bool) {
+	var matchers []v32.AlertSilenceMatcher
+
+	_, projectID := ref.Parse(spec.ProjectName)
+	if len(spec.GroupNames) > 0 {
+		var groupIDs []string
+		for _, groupID := range spec.GroupNames {
+			if projectID == "" || strings.HasPrefix(groupID, projectID+":") {
+				groupIDs = append(groupIDs, groupID)
+			}
+		}
+		if len(groupIDs) == 0 {
+			return nil, false
+		}
+		matchers = append(matchers, anyOf(groupIDLabel, groupIDs))
+	} else if projectID != "" {
+		// the alerts of a project are grouped by the project alert groups, which are in the namespace of the project
+		matchers = append(matchers, v32.AlertSilenceMatcher{
+			Name:    groupIDLabel,
+			Value:   regexp.QuoteMeta(projectID+":") + ".*",
+			IsRegex: true,
+		})
+	}
+
+	if len(spec.RuleNames) > 0 {
+		if len(ruleIDs) == 0 {
+			return nil, false
+		}
+		matchers = append(matchers, anyOf(ruleIDLabel, ruleIDs))
+	}
+
+	return append(matchers, spec.Matchers...), true
+}
+
+// Match returns whether the labels of an alert match all the matchers. Regexes are anchored, as in alertmanager.
+func Match(matchers []v32.AlertSilenceMatcher, labels map[string]string) bool {
+	for _, m := range matchers {
+		value := labels[m.Name]
+		if !m.IsRegex {
+			if value != m.Value {
+				return false
+			}
+			continue
+		}
+		re, err := regexp.Compile("^(?:" + m.Value + ")$")
+		if err != nil || !re.MatchString(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func anyOf(name string, values []string) v32.AlertSilenceMatcher {
+	if len(values) == 1 {
+		return v32.AlertSilenceMatcher{Name: name, Value: values[0]}
+	}
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = regexp.QuoteMeta(v)
+	}
+	return v32.AlertSilenceMatcher{Name: name, Value: strings.Join(quoted, "|"), IsRegex: true}
+}
+
+func bounds(spec *v32.AlertSilenceSpec) (time.Time, time.Time, error) {
+	var startsAt, endsAt time.Time
+	var err error
+	if spec.StartsAt != "" {
+		if startsAt, err = time.Parse(time.RFC3339, spec.StartsAt); err != nil {
+			return startsAt, endsAt, fmt.Errorf("invalid startsAt: %v", err)
+		}
+	}
+	if spec.EndsAt != "" {
+		if endsAt, err = time.Parse(time.RFC3339, spec.EndsAt); err != nil {
+			return startsAt, endsAt, fmt.Errorf("invalid endsAt: %v", err)
+		}
+	}
+	return startsAt, endsAt, nil
+}
+
+func schedule(spec *v32.AlertSilenceSpec) (cron.Schedule, *time.Location, error) {
+	sched, err := cron.ParseStandard(spec.Schedule)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid schedule: %v", err)
+	}
+	loc := time.UTC
+	if spec.TimeZone != "" {
+		if loc, err = time.LoadLocation(spec.TimeZone); err != nil {
+			return nil, nil, fmt.Errorf("invalid timeZone: %v", err)
+		}
+	}
+	return sched, loc, nil
+}
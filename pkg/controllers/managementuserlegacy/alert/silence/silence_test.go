@@ -0,0 +1,125 @@
+package silence
+
+import (
+	"testing"
+	"time"
+
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func mustParse(t *testing.T, value string) time.Time {
+	parsed, err := time.Parse(time.RFC3339, value)
+	assert.NoError(t, err)
+	return parsed
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate(&v32.AlertSilenceSpec{EndsAt: "2021-08-01T10:00:00Z"}))
+	assert.NoError(t, Validate(&v32.AlertSilenceSpec{Schedule: "0 22 * * 6", DurationMinutes: 120, TimeZone: "Europe/Berlin"}))
+
+	assert.Error(t, Validate(&v32.AlertSilenceSpec{}), "expected a window to be required")
+	assert.Error(t, Validate(&v32.AlertSilenceSpec{StartsAt: "2021-08-01T10:00:00Z", EndsAt: "2021-08-01T09:00:00Z"}))
+	assert.Error(t, Validate(&v32.AlertSilenceSpec{EndsAt: "tomorrow"}))
+	assert.Error(t, Validate(&v32.AlertSilenceSpec{Schedule: "0 22 * * 6"}), "expected a duration to be required")
+	assert.Error(t, Validate(&v32.AlertSilenceSpec{Schedule: "every night", DurationMinutes: 60}))
+	assert.Error(t, Validate(&v32.AlertSilenceSpec{Schedule: "0 22 * * 6", DurationMinutes: 60, TimeZone: "Mars/Olympus"}))
+	assert.Error(t, Validate(&v32.AlertSilenceSpec{
+		EndsAt:   "2021-08-01T10:00:00Z",
+		Matchers: []v32.AlertSilenceMatcher{{Name: "node_name", Value: "worker-(", IsRegex: true}},
+	}))
+	assert.Error(t, Validate(&v32.AlertSilenceSpec{
+		EndsAt:   "2021-08-01T10:00:00Z",
+		Matchers: []v32.AlertSilenceMatcher{{Name: "rule_id", Value: "c-abcde:g-12345_r-1"}},
+	}))
+}
+
+func TestWindow(t *testing.T) {
+	now := mustParse(t, "2021-08-07T23:00:00Z")
+
+	oneOff := &v32.AlertSilenceSpec{StartsAt: "2021-08-08T00:00:00Z", EndsAt: "2021-08-08T02:00:00Z"}
+	start, end, ok, err := Window(oneOff, now)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, mustParse(t, "2021-08-08T00:00:00Z"), start)
+	assert.Equal(t, mustParse(t, "2021-08-08T02:00:00Z"), end)
+
+	_, _, ok, err = Window(oneOff, now.Add(3*time.Hour))
+	assert.NoError(t, err)
+	assert.False(t, ok, "expected the window to be over")
+
+	// every saturday from 22:00 to 00:00
+	weekly := &v32.AlertSilenceSpec{Schedule: "0 22 * * 6", DurationMinutes: 120}
+	start, end, ok, err = Window(weekly, now)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, mustParse(t, "2021-08-07T22:00:00Z"), start)
+	assert.Equal(t, mustParse(t, "2021-08-08T00:00:00Z"), end)
+
+	start, _, ok, err = Window(weekly, now.Add(2*time.Hour))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, mustParse(t, "2021-08-14T22:00:00Z"), start, "expected the next window")
+
+	weekly.TimeZone = "Europe/Berlin"
+	start, _, ok, err = Window(weekly, now)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, mustParse(t, "2021-08-14T20:00:00Z"), start.UTC())
+
+	weekly.TimeZone = ""
+	weekly.EndsAt = "2021-08-07T23:30:00Z"
+	_, end, ok, err = Window(weekly, now)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, mustParse(t, "2021-08-07T23:30:00Z"), end, "expected the window to end with the silence")
+	_, _, ok, err = Window(weekly, now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestStatus(t *testing.T) {
+	now := mustParse(t, "2021-08-07T23:00:00Z")
+	spec := &v32.AlertSilenceSpec{StartsAt: "2021-08-08T00:00:00Z", EndsAt: "2021-08-08T02:00:00Z"}
+
+	status, next := Status(spec, now)
+	assert.Equal(t, StatePending, status.SilenceState)
+	assert.Equal(t, "2021-08-08T00:00:00Z", status.StartTime)
+	assert.Equal(t, mustParse(t, "2021-08-08T00:00:00Z"), next)
+
+	status, next = Status(spec, now.Add(time.Hour))
+	assert.Equal(t, StateActive, status.SilenceState)
+	assert.Equal(t, mustParse(t, "2021-08-08T02:00:00Z"), next)
+
+	status, next = Status(spec, now.Add(3*time.Hour))
+	assert.Equal(t, StateExpired, status.SilenceState)
+	assert.True(t, next.IsZero())
+}
+
+func TestMatchers(t *testing.T) {
+	matchers, ok := Matchers(&v32.AlertSilenceSpec{}, nil)
+	assert.True(t, ok)
+	assert.True(t, Match(matchers, map[string]string{"rule_id": "c-abcde:g-12345_r-1"}), "expected all alerts to match")
+
+	matchers, ok = Matchers(&v32.AlertSilenceSpec{
+		ProjectName: "c-abcde:p-12345",
+		Matchers:    []v32.AlertSilenceMatcher{{Name: "workload_name", Value: "web-.*", IsRegex: true}},
+	}, nil)
+	assert.True(t, ok)
+	assert.True(t, Match(matchers, map[string]string{"group_id": "p-12345:g-1", "workload_name": "web-1"}))
+	assert.False(t, Match(matchers, map[string]string{"group_id": "p-67890:g-1", "workload_name": "web-1"}))
+	assert.False(t, Match(matchers, map[string]string{"group_id": "p-12345:g-1", "workload_name": "api-web-1"}), "expected the regex to be anchored")
+
+	matchers, ok = Matchers(&v32.AlertSilenceSpec{
+		GroupNames: []string{"c-abcde:g-1", "c-abcde:g-2"},
+		RuleNames:  []string{"c-abcde:r-1"},
+	}, []string{"c-abcde:g-1_r-1"})
+	assert.True(t, ok)
+	assert.True(t, Match(matchers, map[string]string{"group_id": "c-abcde:g-1", "rule_id": "c-abcde:g-1_r-1"}))
+	assert.False(t, Match(matchers, map[string]string{"group_id": "c-abcde:g-2", "rule_id": "c-abcde:g-2_r-2"}))
+
+	_, ok = Matchers(&v32.AlertSilenceSpec{RuleNames: []string{"c-abcde:r-1"}}, nil)
+	assert.False(t, ok, "expected a silence of rules that do not exist to match nothing")
+	_, ok = Matchers(&v32.AlertSilenceSpec{ProjectName: "c-abcde:p-12345", GroupNames: []string{"c-abcde:g-1"}}, nil)
+	assert.False(t, ok, "expected a silence of groups outside of its project to match nothing")
+}
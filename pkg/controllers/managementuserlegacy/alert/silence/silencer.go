@@ -0,0 +1,127 @@
+package silence
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/controllers/managementuserlegacy/alert/common"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/ref"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Active is a silence in its window and the matchers of the alerts it mutes.
+type Active struct {
+	Name     string
+	Matchers []v32.AlertSilenceMatcher
+}
+
+// Silencer finds the silences of a cluster muting its alerts.
+type Silencer struct {
+	clusterName            string
+	silenceLister          v3.AlertSilenceLister
+	clusterAlertRuleLister v3.ClusterAlertRuleLister
+	projectAlertRuleLister v3.ProjectAlertRuleLister
+}
+
+func NewSilencer(cluster *config.UserContext) *Silencer {
+	return &Silencer{
+		clusterName:            cluster.ClusterName,
+		silenceLister:          cluster.Management.Management.AlertSilences(cluster.ClusterName).Controller().Lister(),
+		clusterAlertRuleLister: cluster.Management.Management.ClusterAlertRules(cluster.ClusterName).Controller().Lister(),
+		projectAlertRuleLister: cluster.Management.Management.ProjectAlertRules("").Controller().Lister(),
+	}
+}
+
+// Active returns the silences of the cluster in their window at now, sorted by name.
+func (s *Silencer) Active(now time.Time) ([]Active, error) {
+	silences, err := s.silenceLister.List(s.clusterName, labels.NewSelector())
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(silences, func(i, j int) bool {
+		return silences[i].Name < silences[j].Name
+	})
+
+	var active []Active
+	for _, silence := range silences {
+		start, _, ok, err := Window(&silence.Spec, now)
+		if err != nil {
+			logrus.Warnf("Invalid alert silence %s/%s: %v", silence.Namespace, silence.Name, err)
+			continue
+		}
+		if !ok || start.After(now) {
+			continue
+		}
+		if matchers, ok := Matchers(&silence.Spec, s.ruleIDs(silence.Spec.RuleNames)); ok {
+			active = append(active, Active{Name: silence.Name, Matchers: matchers})
+		}
+	}
+	return active, nil
+}
+
+// Silenced returns the name of a silence muting the alert with the labels at now, or an empty string.
+func (s *Silencer) Silenced(alertLabels map[string]string, now time.Time) (string, error) {
+	active, err := s.Active(now)
+	if err != nil {
+		return "", err
+	}
+	for _, a := range active {
+		if Match(a.Matchers, alertLabels) {
+			return a.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// ruleIDs returns the rule_id labels of the alerts of the cluster and project alert rules.
+func (s *Silencer) ruleIDs(ruleNames []string) []string {
+	var ruleIDs []string
+	for _, ruleName := range ruleNames {
+		namespace, name := ref.Parse(ruleName)
+		if rule, err := s.clusterAlertRuleLister.Get(namespace, name); err == nil {
+			ruleIDs = append(ruleIDs, common.GetRuleID(rule.Spec.GroupName, rule.Name))
+		} else if rule, err := s.projectAlertRuleLister.Get(namespace, name); err == nil {
+			ruleIDs = append(ruleIDs, common.GetRuleID(rule.Spec.GroupName, rule.Name))
+		}
+	}
+	return ruleIDs
+}
+
+// Register keeps the status of the silences of the cluster up to date, and requeues them when their windows start and
+// end so that the handlers of the silences, e.g. the alertmanager config syncer, catch up.
+func Register(ctx context.Context, cluster *config.UserContext) {
+	silences := cluster.Management.Management.AlertSilences(cluster.ClusterName)
+	s := &statusSyncer{
+		silences: silences,
+	}
+	silences.AddClusterScopedHandler(ctx, "alert-silence-status", cluster.ClusterName, s.sync)
+}
+
+type statusSyncer struct {
+	silences v3.AlertSilenceInterface
+}
+
+func (s *statusSyncer) sync(key string, silence *v3.AlertSilence) (runtime.Object, error) {
+	if silence == nil || silence.DeletionTimestamp != nil {
+		return silence, nil
+	}
+
+	now := time.Now()
+	status, next := Status(&silence.Spec, now)
+	if !next.IsZero() {
+		s.silences.Controller().EnqueueAfter(silence.Namespace, silence.Name, next.Sub(now)+time.Second)
+	}
+	if status == silence.Status {
+		return silence, nil
+	}
+
+	silence = silence.DeepCopy()
+	silence.Status = status
+	return s.silences.Update(silence)
+}
@@ -0,0 +1,198 @@
+package watcher
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rancher/norman/controller"
+	"github.com/rancher/rancher/pkg/controllers/managementuserlegacy/alert/common"
+	"github.com/rancher/rancher/pkg/controllers/managementuserlegacy/alert/manager"
+	v1 "github.com/rancher/rancher/pkg/generated/norman/core/v1"
+	extv1beta1 "github.com/rancher/rancher/pkg/generated/norman/extensions/v1beta1"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/rancher/wrangler/pkg/ticker"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/cert"
+)
+
+type CertificateWatcher struct {
+	secretLister           v1.SecretLister
+	ingressLister          extv1beta1.IngressLister
+	namespaceIndexer       cache.Indexer
+	projectAlertPolicies   v3.ProjectAlertRuleInterface
+	projectAlertRuleLister v3.ProjectAlertRuleLister
+	alertManager           *manager.AlertManager
+	clusterName            string
+	clusterLister          v3.ClusterLister
+	projectLister          v3.ProjectLister
+}
+
+func StartCertificateWatcher(ctx context.Context, cluster *config.UserContext, manager *manager.AlertManager) {
+	projectAlerts := cluster.Management.Management.ProjectAlertRules("")
+	c := &CertificateWatcher{
+		secretLister:           cluster.Core.Secrets("").Controller().Lister(),
+		ingressLister:          cluster.Extensions.Ingresses("").Controller().Lister(),
+		namespaceIndexer:       projectNamespaceIndexer(cluster),
+		projectAlertPolicies:   projectAlerts,
+		projectAlertRuleLister: projectAlerts.Controller().Lister(),
+		alertManager:           manager,
+		clusterName:            cluster.ClusterName,
+		clusterLister:          cluster.Management.Management.Clusters("").Controller().Lister(),
+		projectLister:          cluster.Management.Management.Projects(cluster.ClusterName).Controller().Lister(),
+	}
+	go c.watch(ctx, syncInterval)
+}
+
+func (w *CertificateWatcher) watch(ctx context.Context, interval time.Duration) {
+	for range ticker.Context(ctx, interval) {
+		err := w.watchRule()
+		if err != nil {
+			logrus.Infof("Failed to watch certificate, error: %v", err)
+		}
+	}
+}
+
+func (w *CertificateWatcher) watchRule() error {
+	if w.alertManager.IsDeploy == false {
+		return nil
+	}
+
+	projectAlerts, err := w.projectAlertRuleLister.List("", labels.NewSelector())
+	if err != nil {
+		return err
+	}
+
+	pAlerts := []*v3.ProjectAlertRule{}
+	for _, alert := range projectAlerts {
+		if controller.ObjectInCluster(w.clusterName, alert) {
+			pAlerts = append(pAlerts, alert)
+		}
+	}
+
+	for _, alert := range pAlerts {
+		if alert.Status.AlertState == "inactive" || alert.Spec.CertificateRule == nil {
+			continue
+		}
+
+		if alert.Spec.CertificateRule.CertificateID != "" {
+			parts := strings.Split(alert.Spec.CertificateRule.CertificateID, ":")
+			if len(parts) != 2 {
+				continue
+			}
+			secret, err := w.secretLister.Get(parts[0], parts[1])
+			if err != nil {
+				if kerrors.IsNotFound(err) {
+					if err = w.projectAlertPolicies.DeleteNamespaced(alert.Namespace, alert.Name, &metav1.DeleteOptions{}); err != nil {
+						return err
+					}
+				}
+				logrus.Debugf("Failed to get certificate %s: %v", alert.Spec.CertificateRule.CertificateID, err)
+				continue
+			}
+			ingressNames, err := w.getIngressNames(secret.Namespace)
+			if err != nil {
+				return err
+			}
+			w.checkCertificateExpiry(secret, ingressNames[secret.Name], alert)
+			continue
+		}
+
+		namespaces, err := projectNamespaces(w.namespaceIndexer, alert.Spec.ProjectName)
+		if err != nil {
+			return err
+		}
+		for _, namespace := range namespaces {
+			secrets, err := w.secretLister.List(namespace, labels.SelectorFromSet(alert.Spec.CertificateRule.Selector))
+			if err != nil {
+				logrus.Warnf("Fail to list secret: %v", err)
+				continue
+			}
+			ingressNames, err := w.getIngressNames(namespace)
+			if err != nil {
+				return err
+			}
+			for _, secret := range secrets {
+				if alert.Spec.CertificateRule.IngressOnly && len(ingressNames[secret.Name]) == 0 {
+					continue
+				}
+				w.checkCertificateExpiry(secret, ingressNames[secret.Name], alert)
+			}
+		}
+	}
+
+	return nil
+}
+
+// getIngressNames returns the names of the ingresses of the namespace using the TLS secrets, by secret name.
+func (w *CertificateWatcher) getIngressNames(namespace string) (map[string][]string, error) {
+	ingresses, err := w.ingressLister.List(namespace, labels.NewSelector())
+	if err != nil {
+		return nil, err
+	}
+
+	ingressNames := map[string][]string{}
+	for _, ingress := range ingresses {
+		for _, tls := range ingress.Spec.TLS {
+			if tls.SecretName != "" {
+				ingressNames[tls.SecretName] = append(ingressNames[tls.SecretName], ingress.Name)
+			}
+		}
+	}
+	for _, names := range ingressNames {
+		sort.Strings(names)
+	}
+	return ingressNames, nil
+}
+
+func (w *CertificateWatcher) checkCertificateExpiry(secret *corev1.Secret, ingressNames []string, alert *v3.ProjectAlertRule) {
+	if secret.Type != corev1.SecretTypeTLS {
+		return
+	}
+
+	// the first certificate of the chain is the one of the server
+	certs, err := cert.ParseCertsPEM(secret.Data[corev1.TLSCertKey])
+	if err != nil || len(certs) == 0 {
+		logrus.Debugf("Failed to parse certificate %s:%s: %v", secret.Namespace, secret.Name, err)
+		return
+	}
+	expiry := certs[0].NotAfter
+
+	days := alert.Spec.CertificateRule.DaysBeforeExpiry
+	if time.Until(expiry) > time.Duration(days)*24*time.Hour {
+		return
+	}
+
+	ruleID := common.GetRuleID(alert.Spec.GroupName, alert.Name)
+
+	clusterDisplayName := common.GetClusterDisplayName(w.clusterName, w.clusterLister)
+	projectDisplayName := common.GetProjectDisplayName(alert.Spec.ProjectName, w.projectLister)
+
+	data := map[string]string{}
+	data["rule_id"] = ruleID
+	data["group_id"] = alert.Spec.GroupName
+	data["alert_type"] = "certificateExpiry"
+	data["alert_name"] = alert.Spec.DisplayName
+	data["severity"] = alert.Spec.Severity
+	data["cluster_name"] = clusterDisplayName
+	data["project_name"] = projectDisplayName
+	data["namespace"] = secret.Namespace
+	data["certificate_name"] = secret.Name
+	data["days_before_expiry"] = strconv.Itoa(days)
+	data["expiry_time"] = expiry.UTC().Format(time.RFC3339)
+	if len(ingressNames) > 0 {
+		data["ingress_names"] = strings.Join(ingressNames, ",")
+	}
+
+	if err := w.alertManager.SendAlert(data); err != nil {
+		logrus.Errorf("Failed to send alert: %v", err)
+	}
+}
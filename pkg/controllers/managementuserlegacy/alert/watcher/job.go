@@ -0,0 +1,254 @@
+package watcher
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/rancher/norman/controller"
+	"github.com/rancher/rancher/pkg/controllers/managementagent/workload"
+	"github.com/rancher/rancher/pkg/controllers/managementuserlegacy/alert/common"
+	"github.com/rancher/rancher/pkg/controllers/managementuserlegacy/alert/manager"
+	batchv1 "github.com/rancher/rancher/pkg/generated/norman/batch/v1"
+	batchv1beta1 "github.com/rancher/rancher/pkg/generated/norman/batch/v1beta1"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/rancher/wrangler/pkg/ticker"
+	"github.com/sirupsen/logrus"
+	corebatchv1 "k8s.io/api/batch/v1"
+	corebatchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// failedJobAlertPeriod is how long the alert of a failed job that is not run by a cron job keeps firing. A job
+// stays failed until it is deleted, the alert of a cron job is resolved by its next successful run instead.
+const failedJobAlertPeriod = 24 * time.Hour
+
+type JobWatcher struct {
+	jobLister              batchv1.JobLister
+	cronJobLister          batchv1beta1.CronJobLister
+	namespaceIndexer       cache.Indexer
+	projectAlertPolicies   v3.ProjectAlertRuleInterface
+	projectAlertRuleLister v3.ProjectAlertRuleLister
+	alertManager           *manager.AlertManager
+	clusterName            string
+	clusterLister          v3.ClusterLister
+	projectLister          v3.ProjectLister
+}
+
+func StartJobWatcher(ctx context.Context, cluster *config.UserContext, manager *manager.AlertManager) {
+	projectAlerts := cluster.Management.Management.ProjectAlertRules("")
+	j := &JobWatcher{
+		jobLister:              cluster.BatchV1.Jobs("").Controller().Lister(),
+		cronJobLister:          cluster.BatchV1Beta1.CronJobs("").Controller().Lister(),
+		namespaceIndexer:       projectNamespaceIndexer(cluster),
+		projectAlertPolicies:   projectAlerts,
+		projectAlertRuleLister: projectAlerts.Controller().Lister(),
+		alertManager:           manager,
+		clusterName:            cluster.ClusterName,
+		clusterLister:          cluster.Management.Management.Clusters("").Controller().Lister(),
+		projectLister:          cluster.Management.Management.Projects(cluster.ClusterName).Controller().Lister(),
+	}
+	go j.watch(ctx, syncInterval)
+}
+
+func (w *JobWatcher) watch(ctx context.Context, interval time.Duration) {
+	for range ticker.Context(ctx, interval) {
+		err := w.watchRule()
+		if err != nil {
+			logrus.Infof("Failed to watch job, error: %v", err)
+		}
+	}
+}
+
+func (w *JobWatcher) watchRule() error {
+	if w.alertManager.IsDeploy == false {
+		return nil
+	}
+
+	projectAlerts, err := w.projectAlertRuleLister.List("", labels.NewSelector())
+	if err != nil {
+		return err
+	}
+
+	pAlerts := []*v3.ProjectAlertRule{}
+	for _, alert := range projectAlerts {
+		if controller.ObjectInCluster(w.clusterName, alert) {
+			pAlerts = append(pAlerts, alert)
+		}
+	}
+
+	for _, alert := range pAlerts {
+		if alert.Status.AlertState == "inactive" || alert.Spec.JobRule == nil {
+			continue
+		}
+
+		if alert.Spec.JobRule.WorkloadID != "" {
+			if err := w.checkWorkload(alert); err != nil {
+				if kerrors.IsNotFound(err) {
+					if err = w.projectAlertPolicies.DeleteNamespaced(alert.Namespace, alert.Name, &metav1.DeleteOptions{}); err != nil {
+						return err
+					}
+				}
+				logrus.Debugf("Failed to get job for %s: %v", alert.Spec.JobRule.WorkloadID, err)
+			}
+			continue
+		}
+
+		namespaces, err := projectNamespaces(w.namespaceIndexer, alert.Spec.ProjectName)
+		if err != nil {
+			return err
+		}
+		selector := labels.SelectorFromSet(alert.Spec.JobRule.Selector)
+		for _, namespace := range namespaces {
+			jobs, err := w.jobLister.List(namespace, selector)
+			if err != nil {
+				logrus.Warnf("Fail to list job: %v", err)
+				continue
+			}
+			for _, job := range jobs {
+				// the jobs of the cron jobs are checked with their cron job
+				if getCronJobName(job) == "" {
+					w.checkStandaloneJob(job, alert)
+				}
+			}
+
+			cronJobs, err := w.cronJobLister.List(namespace, selector)
+			if err != nil {
+				logrus.Warnf("Fail to list cron job: %v", err)
+				continue
+			}
+			for _, cronJob := range cronJobs {
+				if err := w.checkCronJob(cronJob, alert); err != nil {
+					logrus.Warnf("Fail to list jobs of cron job %s:%s: %v", cronJob.Namespace, cronJob.Name, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkWorkload checks the job or cron job of the rule, its workload id is the kind, namespace and name of the job,
+// e.g. cronjob:default:backup.
+func (w *JobWatcher) checkWorkload(alert *v3.ProjectAlertRule) error {
+	parts := strings.Split(alert.Spec.JobRule.WorkloadID, ":")
+	if len(parts) != 3 {
+		return nil
+	}
+	kind, namespace, name := strings.ToLower(parts[0]), parts[1], parts[2]
+
+	switch kind {
+	case workload.JobType:
+		job, err := w.jobLister.Get(namespace, name)
+		if err != nil {
+			return err
+		}
+		w.checkStandaloneJob(job, alert)
+	case workload.CronJobType:
+		cronJob, err := w.cronJobLister.Get(namespace, name)
+		if err != nil {
+			return err
+		}
+		return w.checkCronJob(cronJob, alert)
+	}
+	return nil
+}
+
+// checkCronJob checks the last run of the cron job, so that the alert is resolved once a later run succeeds.
+func (w *JobWatcher) checkCronJob(cronJob *corebatchv1beta1.CronJob, alert *v3.ProjectAlertRule) error {
+	jobs, err := w.jobLister.List(cronJob.Namespace, labels.NewSelector())
+	if err != nil {
+		return err
+	}
+
+	var last *corebatchv1.Job
+	var lastFinished time.Time
+	for _, job := range jobs {
+		if getCronJobName(job) != cronJob.Name {
+			continue
+		}
+		finished, ok := getJobFinishedTime(job)
+		if ok && finished.After(lastFinished) {
+			last, lastFinished = job, finished
+		}
+	}
+	if last != nil {
+		w.checkJobFailed(workload.CronJobType, cronJob.Namespace, cronJob.Name, last, alert)
+	}
+	return nil
+}
+
+// checkStandaloneJob checks a job that is not run by a cron job, until failedJobAlertPeriod after it failed.
+func (w *JobWatcher) checkStandaloneJob(job *corebatchv1.Job, alert *v3.ProjectAlertRule) {
+	if finished, ok := getJobFinishedTime(job); ok && time.Since(finished) > failedJobAlertPeriod {
+		return
+	}
+	w.checkJobFailed(workload.JobType, job.Namespace, job.Name, job, alert)
+}
+
+// checkJobFailed sends the alert of a failed job. It is sent again on every sync while the job is failed, which keeps
+// it firing in alertmanager, and alertmanager notifies the receivers again after the repeat interval of the group.
+func (w *JobWatcher) checkJobFailed(kind, namespace, name string, job *corebatchv1.Job, alert *v3.ProjectAlertRule) {
+	failed := getJobCondition(job, corebatchv1.JobFailed)
+	if failed == nil {
+		return
+	}
+
+	ruleID := common.GetRuleID(alert.Spec.GroupName, alert.Name)
+
+	clusterDisplayName := common.GetClusterDisplayName(w.clusterName, w.clusterLister)
+	projectDisplayName := common.GetProjectDisplayName(alert.Spec.ProjectName, w.projectLister)
+
+	data := map[string]string{}
+	data["rule_id"] = ruleID
+	data["group_id"] = alert.Spec.GroupName
+	data["alert_type"] = "jobFailed"
+	data["alert_name"] = alert.Spec.DisplayName
+	data["severity"] = alert.Spec.Severity
+	data["cluster_name"] = clusterDisplayName
+	data["project_name"] = projectDisplayName
+	data["workload_name"] = name
+	data["workload_namespace"] = namespace
+	data["workload_kind"] = kind
+	data["job_name"] = job.Name
+
+	if failed.Message != "" {
+		data["logs"] = failed.Message
+	}
+
+	if err := w.alertManager.SendAlert(data); err != nil {
+		logrus.Errorf("Failed to send alert: %v", err)
+	}
+}
+
+// getCronJobName returns the name of the cron job that created the job, or an empty string.
+func getCronJobName(job *corebatchv1.Job) string {
+	if owner := metav1.GetControllerOf(job); owner != nil && owner.Kind == "CronJob" {
+		return owner.Name
+	}
+	return ""
+}
+
+// getJobFinishedTime returns when the job completed or failed, and false if it is still running.
+func getJobFinishedTime(job *corebatchv1.Job) (time.Time, bool) {
+	for _, conditionType := range []corebatchv1.JobConditionType{corebatchv1.JobComplete, corebatchv1.JobFailed} {
+		if cond := getJobCondition(job, conditionType); cond != nil {
+			return cond.LastTransitionTime.Time, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func getJobCondition(job *corebatchv1.Job, conditionType corebatchv1.JobConditionType) *corebatchv1.JobCondition {
+	for i, cond := range job.Status.Conditions {
+		if cond.Type == conditionType && cond.Status == corev1.ConditionTrue {
+			return &job.Status.Conditions[i]
+		}
+	}
+	return nil
+}
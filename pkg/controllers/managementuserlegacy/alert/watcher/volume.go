@@ -0,0 +1,256 @@
+package watcher
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rancher/norman/controller"
+	"github.com/rancher/rancher/pkg/controllers/managementuserlegacy/alert/common"
+	"github.com/rancher/rancher/pkg/controllers/managementuserlegacy/alert/manager"
+	v1 "github.com/rancher/rancher/pkg/generated/norman/core/v1"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/types/config"
+	"github.com/rancher/wrangler/pkg/ticker"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	volumeStatsTimeout = 10 * time.Second
+	// volumeStatsWorkers is how many kubelets are asked for their stats summary at once, so that a few unreachable
+	// nodes do not make a pass over a large cluster overrun the sync interval
+	volumeStatsWorkers = 10
+)
+
+type VolumeWatcher struct {
+	k8sClient              kubernetes.Interface
+	nodeLister             v1.NodeLister
+	pvcLister              v1.PersistentVolumeClaimLister
+	namespaceIndexer       cache.Indexer
+	projectAlertPolicies   v3.ProjectAlertRuleInterface
+	projectAlertRuleLister v3.ProjectAlertRuleLister
+	alertManager           *manager.AlertManager
+	clusterName            string
+	clusterLister          v3.ClusterLister
+	projectLister          v3.ProjectLister
+}
+
+// statsSummary is the part of the stats summary of a kubelet with the usage of the volumes of the pods.
+type statsSummary struct {
+	Pods []struct {
+		VolumeStats []volumeStats `json:"volume,omitempty"`
+	} `json:"pods"`
+}
+
+type volumeStats struct {
+	CapacityBytes *uint64 `json:"capacityBytes,omitempty"`
+	UsedBytes     *uint64 `json:"usedBytes,omitempty"`
+	PVCRef        *struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"pvcRef,omitempty"`
+}
+
+func StartVolumeWatcher(ctx context.Context, cluster *config.UserContext, manager *manager.AlertManager) {
+	projectAlerts := cluster.Management.Management.ProjectAlertRules("")
+	v := &VolumeWatcher{
+		k8sClient:              cluster.K8sClient,
+		nodeLister:             cluster.Core.Nodes("").Controller().Lister(),
+		pvcLister:              cluster.Core.PersistentVolumeClaims("").Controller().Lister(),
+		namespaceIndexer:       projectNamespaceIndexer(cluster),
+		projectAlertPolicies:   projectAlerts,
+		projectAlertRuleLister: projectAlerts.Controller().Lister(),
+		alertManager:           manager,
+		clusterName:            cluster.ClusterName,
+		clusterLister:          cluster.Management.Management.Clusters("").Controller().Lister(),
+		projectLister:          cluster.Management.Management.Projects(cluster.ClusterName).Controller().Lister(),
+	}
+	go v.watch(ctx, syncInterval)
+}
+
+func (w *VolumeWatcher) watch(ctx context.Context, interval time.Duration) {
+	for range ticker.Context(ctx, interval) {
+		err := w.watchRule(ctx)
+		if err != nil {
+			logrus.Infof("Failed to watch volume, error: %v", err)
+		}
+	}
+}
+
+func (w *VolumeWatcher) watchRule(ctx context.Context) error {
+	if w.alertManager.IsDeploy == false {
+		return nil
+	}
+
+	projectAlerts, err := w.projectAlertRuleLister.List("", labels.NewSelector())
+	if err != nil {
+		return err
+	}
+
+	pAlerts := []*v3.ProjectAlertRule{}
+	for _, alert := range projectAlerts {
+		if controller.ObjectInCluster(w.clusterName, alert) && alert.Status.AlertState != "inactive" && alert.Spec.VolumeRule != nil {
+			pAlerts = append(pAlerts, alert)
+		}
+	}
+	// the kubelets are only asked for the stats of the volumes if there are rules to check
+	if len(pAlerts) == 0 {
+		return nil
+	}
+
+	stats, err := w.getVolumeStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, alert := range pAlerts {
+		if alert.Spec.VolumeRule.PersistentVolumeClaimID != "" {
+			parts := strings.Split(alert.Spec.VolumeRule.PersistentVolumeClaimID, ":")
+			if len(parts) != 2 {
+				continue
+			}
+			pvc, err := w.pvcLister.Get(parts[0], parts[1])
+			if err != nil {
+				if kerrors.IsNotFound(err) {
+					if err = w.projectAlertPolicies.DeleteNamespaced(alert.Namespace, alert.Name, &metav1.DeleteOptions{}); err != nil {
+						return err
+					}
+				}
+				logrus.Debugf("Failed to get persistent volume claim %s: %v", alert.Spec.VolumeRule.PersistentVolumeClaimID, err)
+				continue
+			}
+			w.checkVolumeUsage(pvc, stats, alert)
+			continue
+		}
+
+		namespaces, err := projectNamespaces(w.namespaceIndexer, alert.Spec.ProjectName)
+		if err != nil {
+			return err
+		}
+		for _, namespace := range namespaces {
+			pvcs, err := w.pvcLister.List(namespace, labels.SelectorFromSet(alert.Spec.VolumeRule.Selector))
+			if err != nil {
+				logrus.Warnf("Fail to list persistent volume claim: %v", err)
+				continue
+			}
+			for _, pvc := range pvcs {
+				w.checkVolumeUsage(pvc, stats, alert)
+			}
+		}
+	}
+
+	return nil
+}
+
+// getVolumeStats returns the usage of the persistent volume claims mounted by pods, by namespace:name, from the stats
+// summary of the kubelets, which are asked by volumeStatsWorkers at once. The nodes whose kubelet cannot be reached
+// are skipped.
+func (w *VolumeWatcher) getVolumeStats(ctx context.Context) (map[string]volumeStats, error) {
+	nodes, err := w.nodeLister.List("", labels.NewSelector())
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		stats     = map[string]volumeStats{}
+		statsLock sync.Mutex
+		wg        sync.WaitGroup
+		nodeNames = make(chan string)
+	)
+	for i := 0; i < volumeStatsWorkers && i < len(nodes); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for nodeName := range nodeNames {
+				summary, err := w.getStatsSummary(ctx, nodeName)
+				if err != nil {
+					logrus.Debugf("Failed to get stats summary of node %s: %v", nodeName, err)
+					continue
+				}
+
+				statsLock.Lock()
+				for _, pod := range summary.Pods {
+					for _, volume := range pod.VolumeStats {
+						if volume.PVCRef == nil || volume.CapacityBytes == nil || volume.UsedBytes == nil {
+							continue
+						}
+						stats[volume.PVCRef.Namespace+":"+volume.PVCRef.Name] = volume
+					}
+				}
+				statsLock.Unlock()
+			}
+		}()
+	}
+
+	for _, node := range nodes {
+		nodeNames <- node.Name
+	}
+	close(nodeNames)
+	wg.Wait()
+
+	return stats, nil
+}
+
+func (w *VolumeWatcher) getStatsSummary(ctx context.Context, nodeName string) (*statsSummary, error) {
+	ctx, cancel := context.WithTimeout(ctx, volumeStatsTimeout)
+	defer cancel()
+
+	data, err := w.k8sClient.CoreV1().RESTClient().Get().
+		Resource("nodes").
+		Name(nodeName).
+		SubResource("proxy").
+		Suffix("stats/summary").
+		DoRaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &statsSummary{}
+	return summary, json.Unmarshal(data, summary)
+}
+
+func (w *VolumeWatcher) checkVolumeUsage(pvc *corev1.PersistentVolumeClaim, stats map[string]volumeStats, alert *v3.ProjectAlertRule) {
+	// the volumes that are not mounted have no stats
+	volume, ok := stats[pvc.Namespace+":"+pvc.Name]
+	if !ok || *volume.CapacityBytes == 0 {
+		return
+	}
+
+	percentage := alert.Spec.VolumeRule.UsedPercentage
+	if *volume.UsedBytes*100 < uint64(percentage)**volume.CapacityBytes {
+		return
+	}
+
+	ruleID := common.GetRuleID(alert.Spec.GroupName, alert.Name)
+
+	clusterDisplayName := common.GetClusterDisplayName(w.clusterName, w.clusterLister)
+	projectDisplayName := common.GetProjectDisplayName(alert.Spec.ProjectName, w.projectLister)
+
+	data := map[string]string{}
+	data["rule_id"] = ruleID
+	data["group_id"] = alert.Spec.GroupName
+	data["alert_type"] = "volumeUsage"
+	data["alert_name"] = alert.Spec.DisplayName
+	data["severity"] = alert.Spec.Severity
+	data["cluster_name"] = clusterDisplayName
+	data["project_name"] = projectDisplayName
+	data["namespace"] = pvc.Namespace
+	data["volume_name"] = pvc.Name
+	data["used_percentage"] = strconv.Itoa(percentage)
+	data["used_space"] = resource.NewQuantity(int64(*volume.UsedBytes), resource.BinarySI).String()
+	data["capacity"] = resource.NewQuantity(int64(*volume.CapacityBytes), resource.BinarySI).String()
+
+	if err := w.alertManager.SendAlert(data); err != nil {
+		logrus.Errorf("Failed to send alert: %v", err)
+	}
+}
@@ -50,11 +50,6 @@ type WorkloadWatcher struct {
 }
 
 func StartWorkloadWatcher(ctx context.Context, cluster *config.UserContext, manager *manager.AlertManager) {
-	nsInformer := cluster.Core.Namespaces("").Controller().Informer()
-	nsIndexers := map[string]cache.IndexFunc{
-		nsByProjectIndex: nsutils.NsByProjectID,
-	}
-	nsInformer.AddIndexers(nsIndexers)
 	projectAlerts := cluster.Management.Management.ProjectAlertRules("")
 	d := &WorkloadWatcher{
 		projectAlertPolicies:        projectAlerts,
@@ -64,7 +59,7 @@ func StartWorkloadWatcher(ctx context.Context, cluster *config.UserContext, mana
 		clusterName:                 cluster.ClusterName,
 		clusterLister:               cluster.Management.Management.Clusters("").Controller().Lister(),
 		projectLister:               cluster.Management.Management.Projects(cluster.ClusterName).Controller().Lister(),
-		namespaceIndexer:            nsInformer.GetIndexer(),
+		namespaceIndexer:            projectNamespaceIndexer(cluster),
 		replicationControllerLister: cluster.Core.ReplicationControllers(metav1.NamespaceAll).Controller().Lister(),
 		replicaSetLister:            cluster.Apps.ReplicaSets(metav1.NamespaceAll).Controller().Lister(),
 		daemonsetLister:             cluster.Apps.DaemonSets(metav1.NamespaceAll).Controller().Lister(),
@@ -75,6 +70,33 @@ func StartWorkloadWatcher(ctx context.Context, cluster *config.UserContext, mana
 	go d.watch(ctx, syncInterval)
 }
 
+// projectNamespaceIndexer returns the indexer of the namespaces of the cluster by project, which is shared by the
+// watchers of the project alert rules.
+func projectNamespaceIndexer(cluster *config.UserContext) cache.Indexer {
+	nsInformer := cluster.Core.Namespaces("").Controller().Informer()
+	if _, ok := nsInformer.GetIndexer().GetIndexers()[nsByProjectIndex]; !ok {
+		nsInformer.AddIndexers(map[string]cache.IndexFunc{
+			nsByProjectIndex: nsutils.NsByProjectID,
+		})
+	}
+	return nsInformer.GetIndexer()
+}
+
+// projectNamespaces returns the names of the namespaces of the project, e.g. c-xxxxx:p-xxxxx.
+func projectNamespaces(namespaceIndexer cache.Indexer, projectName string) ([]string, error) {
+	namespaces, err := namespaceIndexer.ByIndex(nsByProjectIndex, projectName)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, n := range namespaces {
+		if namespace, ok := n.(*corev1.Namespace); ok {
+			names = append(names, namespace.Name)
+		}
+	}
+	return names, nil
+}
+
 func (w *WorkloadWatcher) watch(ctx context.Context, interval time.Duration) {
 	for range ticker.Context(ctx, interval) {
 		err := w.watchRule()
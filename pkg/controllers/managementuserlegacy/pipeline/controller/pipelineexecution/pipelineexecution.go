@@ -27,9 +27,8 @@
 	"github.com/rancher/rancher/pkg/settings"
 	"github.com/rancher/rancher/pkg/systemaccount"
 	"github.com/rancher/rancher/pkg/types/config"
-	"github.com/rancher/rancher/pkg/types/config/dialer"
+	corecontrollers "github.com/rancher/wrangler/pkg/generated/controllers/core/v1"
 	"github.com/sirupsen/logrus"
-	"golang.org/x/sync/errgroup"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -99,7 +98,8 @@ type Lifecycle struct {
 	pipelineEngine             engine.PipelineEngine
 	sourceCodeCredentialLister v3.SourceCodeCredentialLister
 
-	DialerFactory dialer.Factory
+	// notifierQueues holds the delivery queues of the notifiers in the management cluster
+	notifierQueues corecontrollers.ConfigMapClient
 }
 
 func Register(ctx context.Context, cluster *config.UserContext) {
@@ -163,7 +163,7 @@ func Register(ctx context.Context, cluster *config.UserContext) {
 		notifierLister:             notifierLister,
 		tokenLister:                tokenLister,
 
-		DialerFactory: cluster.Management.Dialer,
+		notifierQueues: cluster.Management.Wrangler.Core.ConfigMap(),
 	}
 	stateSyncer := &ExecutionStateSyncer{
 		clusterName:             clusterName,
@@ -486,15 +486,9 @@ func (l *Lifecycle) doNotify(obj *v3.PipelineExecution) (runtime.Object, error)
 	if err != nil {
 		return obj, err
 	}
-	clusterName, _ := ref.Parse(obj.Spec.ProjectName)
-	clusterDialer, err := l.DialerFactory.ClusterDialer(clusterName)
-	if err != nil {
-		return nil, errors.Wrap(err, "error getting dialer")
-	}
 	if obj.Spec.PipelineConfig.Notification.Message != "" {
 		message = obj.Spec.PipelineConfig.Notification.Message
 	}
-	var g errgroup.Group
 	for i := range toSendRecipients {
 		toSendRecipient := toSendRecipients[i]
 		notifierMessage := &notifiers.Message{
@@ -505,11 +499,11 @@ func (l *Lifecycle) doNotify(obj *v3.PipelineExecution) (runtime.Object, error)
 			notifierMessage.Title = fmt.Sprintf("Notification From Rancher: Pipeline #%d build for %s repo %s", obj.Spec.Run, repoName, obj.Status.ExecutionState)
 			notifierMessage.Content = strings.Replace(message, "\n", "<br>\n", -1)
 		}
-		g.Go(func() error {
-			return notifiers.SendMessage(l.ctx, toSendRecipient.Notifier, toSendRecipient.Recipient, notifierMessage, clusterDialer)
-		})
+		if err := notifiers.Enqueue(l.notifierQueues, toSendRecipient.Notifier, toSendRecipient.Recipient, notifierMessage); err != nil {
+			return obj, errors.Wrap(err, "error queueing notification")
+		}
 	}
-	return obj, g.Wait()
+	return obj, nil
 }
 
 func (l *Lifecycle) getToSendRecipients(obj *v3.PipelineExecution) ([]notifierRecipient, error) {
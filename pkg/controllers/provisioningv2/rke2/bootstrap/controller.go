@@ -169,6 +169,16 @@ func (h *handler) assignPlanSecret(machine *capi.Machine, obj *rkev1.RKEBootstra
 			},
 		},
 	}
+	if machine.Labels[planner.EtcdRoleLabel] == "true" {
+		// the agent transferring the etcd snapshots reads the target credentials and encryption keys from the etcd
+		// snapshot secret
+		role.Rules = append(role.Rules, rbacv1.PolicyRule{
+			Verbs:         []string{"get"},
+			APIGroups:     []string{""},
+			Resources:     []string{"secrets"},
+			ResourceNames: []string{planner.ETCDSnapshotSecretName(machine.Spec.ClusterName)},
+		})
+	}
 	rolebinding := &rbacv1.RoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      secretName,
@@ -375,6 +375,8 @@ func rkeControlPlane(cluster *rancherv1.Cluster) *rkev1.RKEControlPlane {
 			RKEClusterSpecCommon:  *cluster.Spec.RKEConfig.RKEClusterSpecCommon.DeepCopy(),
 			ETCDSnapshotRestore:   cluster.Spec.RKEConfig.ETCDSnapshotRestore.DeepCopy(),
 			ETCDSnapshotCreate:    cluster.Spec.RKEConfig.ETCDSnapshotCreate.DeepCopy(),
+			ETCDSnapshotSource:    cluster.Spec.RKEConfig.ETCDSnapshotSource.DeepCopy(),
+			RotateCertificates:    cluster.Spec.RKEConfig.RotateCertificates.DeepCopy(),
 			KubernetesVersion:     cluster.Spec.KubernetesVersion,
 			ManagementClusterName: cluster.Status.ClusterName,
 			AgentEnvVars:          cluster.Spec.AgentEnvVars,
@@ -13,6 +13,7 @@
 	"github.com/rancher/norman/types/slice"
 	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/metrics/tunnel"
 	"github.com/rancher/rancher/pkg/types/config"
 	"github.com/rancher/rancher/pkg/types/config/dialer"
 	"github.com/rancher/rancher/pkg/wrangler"
@@ -50,9 +51,19 @@ func (f *Factory) ClusterDialer(clusterName string) (dialer.Dialer, error) {
 		d, err := f.clusterDialer(clusterName, address)
 		if err != nil {
 			logrus.Debugf(WaitForAgentError, clusterName)
+			if errors.Is(err, ErrAgentDisconnected) {
+				tunnel.CountDialFailure(clusterName, "agent_disconnected")
+			} else {
+				tunnel.CountDialFailure(clusterName, "dialer")
+			}
 			return nil, err
 		}
-		return d(ctx, network, address)
+		conn, err := d(ctx, network, address)
+		if err != nil {
+			tunnel.CountDialFailure(clusterName, "dial")
+			return nil, err
+		}
+		return tunnel.CountingConn(clusterName, conn), nil
 	}, nil
 }
 
@@ -0,0 +1,78 @@
+package etcdsnapshot
+
+import (
+	"fmt"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+const (
+	// keyBucket is the bucket holding every revision of every key stored in etcd
+	keyBucket = "key"
+
+	boltOpenTimeout = 10 * time.Second
+)
+
+// countKeys opens the bolt database of an etcd snapshot read-only, reads every page of every bucket and returns the
+// number of revisions in its key bucket. The pages are walked here rather than with tx.Check, which checks them in a
+// goroutine of its own where a panic on a corrupt page could not be recovered.
+func countKeys(path string) (keys int, err error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	db, err := bbolt.Open(path, 0400, &bbolt.Options{ReadOnly: true, Timeout: boltOpenTimeout})
+	if err != nil {
+		return 0, fmt.Errorf("snapshot is not a bolt database: %w", err)
+	}
+	defer db.Close()
+
+	// bbolt panics on corrupt pages instead of returning an error, and pages pointing outside of the file fault
+	defer debug.SetPanicOnFault(debug.SetPanicOnFault(true))
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("snapshot database is corrupt: %v", r)
+		}
+	}()
+
+	err = db.View(func(tx *bbolt.Tx) error {
+		// pages past the end of a truncated file are not mapped and can not be read
+		if tx.Size() > info.Size() {
+			return fmt.Errorf("snapshot is truncated, the database is %d bytes but only %d were read", tx.Size(), info.Size())
+		}
+
+		err := tx.ForEach(func(name []byte, b *bbolt.Bucket) error {
+			return walkBucket(b)
+		})
+		if err != nil {
+			return err
+		}
+
+		b := tx.Bucket([]byte(keyBucket))
+		if b == nil {
+			return fmt.Errorf("snapshot has no %q bucket", keyBucket)
+		}
+		return b.ForEach(func(k, v []byte) error {
+			keys++
+			return nil
+		})
+	})
+	return keys, err
+}
+
+// walkBucket reads every key and value of the bucket and its nested buckets.
+func walkBucket(b *bbolt.Bucket) error {
+	return b.ForEach(func(k, v []byte) error {
+		if v != nil {
+			return nil
+		}
+		nested := b.Bucket(k)
+		if nested == nil {
+			return fmt.Errorf("snapshot database is corrupt: key %q has no value", k)
+		}
+		return walkBucket(nested)
+	})
+}
@@ -0,0 +1,289 @@
+package etcdsnapshot
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Encrypted snapshots start with a header holding the magic, the ID of the key and the nonce prefix, followed by the
+// snapshot sealed with AES-256-GCM in chunks. Every chunk is sealed with the header as additional data and a nonce of
+// the prefix, the chunk counter and a flag marking the last chunk, so that chunks can not be reordered, dropped or
+// truncated without failing decryption.
+const (
+	encryptionMagic     = "cattle-etcd-snapshot-encrypted-v1\n"
+	encryptionChunkSize = 64 * 1024
+	noncePrefixSize     = 7
+
+	// KeySize is the size of the AES-256 keys snapshots are encrypted with.
+	KeySize = 32
+	// ActiveKeyAnnotation on the key secret selects the key new snapshots are encrypted with.
+	ActiveKeyAnnotation = "etcdsnapshot.cattle.io/active-key"
+	// KeyRingSecretKey holds the keys given to the etcd nodes as a JSON encoded KeyRing, in the secret the agent reads.
+	KeyRingSecretKey = "encryptionKeys"
+)
+
+// KeyRing holds the keys snapshots are decrypted with, keyed by their ID. Keys are rotated by adding a new key and
+// making it active, older keys have to be kept as long as snapshots encrypted with them exist.
+type KeyRing struct {
+	Active string            `json:"active"`
+	Keys   map[string][]byte `json:"keys"`
+}
+
+// KeyRingFromSecret reads the keys of a secret, either raw or base64 encoded. The active key is the given one, the one
+// in the ActiveKeyAnnotation of the secret, or the only key of the secret.
+func KeyRingFromSecret(secret *corev1.Secret, active string) (*KeyRing, error) {
+	ring := &KeyRing{
+		Active: active,
+		Keys:   map[string][]byte{},
+	}
+	for id, value := range secret.Data {
+		key := value
+		if len(key) != KeySize {
+			decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(value)))
+			if err != nil || len(decoded) != KeySize {
+				return nil, fmt.Errorf("key %s of secret %s/%s is not a %d byte key", id, secret.Namespace, secret.Name, KeySize)
+			}
+			key = decoded
+		}
+		ring.Keys[id] = key
+	}
+
+	if ring.Active == "" {
+		ring.Active = secret.Annotations[ActiveKeyAnnotation]
+	}
+	if ring.Active == "" && len(ring.Keys) == 1 {
+		for id := range ring.Keys {
+			ring.Active = id
+		}
+	}
+	if ring.Active == "" {
+		return nil, fmt.Errorf("secret %s/%s holds %d keys, the active key has to be selected", secret.Namespace, secret.Name, len(ring.Keys))
+	}
+	if _, ok := ring.Keys[ring.Active]; !ok {
+		return nil, fmt.Errorf("active key %s not found in secret %s/%s", ring.Active, secret.Namespace, secret.Name)
+	}
+	return ring, nil
+}
+
+func (k *KeyRing) aead(id string) (cipher.AEAD, error) {
+	if k == nil {
+		return nil, fmt.Errorf("snapshot is encrypted with key %s, but no keys are configured", id)
+	}
+	key, ok := k.Keys[id]
+	if !ok {
+		return nil, fmt.Errorf("snapshot is encrypted with key %s, which is not available", id)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// IsEncrypted returns true if the snapshot read by r is encrypted, without consuming it.
+func IsEncrypted(r *bufio.Reader) bool {
+	magic, err := r.Peek(len(encryptionMagic))
+	return err == nil && string(magic) == encryptionMagic
+}
+
+// KeyID returns the ID of the key the snapshot read by r is encrypted with, without consuming it. It is empty if the
+// snapshot is not encrypted.
+func KeyID(r *bufio.Reader) (string, error) {
+	if !IsEncrypted(r) {
+		return "", nil
+	}
+	header, err := r.Peek(len(encryptionMagic) + 1)
+	if err != nil {
+		return "", err
+	}
+	header, err = r.Peek(len(header) + int(header[len(header)-1]))
+	if err != nil {
+		return "", fmt.Errorf("reading encryption header: %w", err)
+	}
+	return string(header[len(encryptionMagic)+1:]), nil
+}
+
+// Encrypt returns a writer encrypting the snapshot written to it with the active key of the ring into w. The snapshot
+// is only complete once the writer is closed, closing does not close w.
+func Encrypt(w io.Writer, ring *KeyRing) (io.WriteCloser, error) {
+	if ring == nil || ring.Active == "" {
+		return nil, errors.New("no active encryption key")
+	}
+	if len(ring.Active) > 255 {
+		return nil, fmt.Errorf("key id %s is too long", ring.Active)
+	}
+	aead, err := ring.aead(ring.Active)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := make([]byte, noncePrefixSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, err
+	}
+	header := append([]byte(encryptionMagic), byte(len(ring.Active)))
+	header = append(append(header, ring.Active...), prefix...)
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &encryptWriter{
+		w: w,
+		chunks: chunks{
+			aead:   aead,
+			header: header,
+			prefix: prefix,
+		},
+	}, nil
+}
+
+// Decrypt returns a reader decrypting the snapshot read from r, and the ID of the key it was encrypted with.
+func Decrypt(r io.Reader, ring *KeyRing) (io.Reader, string, error) {
+	br := bufio.NewReader(r)
+	if !IsEncrypted(br) {
+		return nil, "", errors.New("snapshot is not encrypted")
+	}
+
+	header := make([]byte, len(encryptionMagic)+1)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, "", err
+	}
+	rest := make([]byte, int(header[len(header)-1])+noncePrefixSize)
+	if _, err := io.ReadFull(br, rest); err != nil {
+		return nil, "", fmt.Errorf("reading encryption header: %w", err)
+	}
+	header = append(header, rest...)
+	id := string(rest[:len(rest)-noncePrefixSize])
+
+	aead, err := ring.aead(id)
+	if err != nil {
+		return nil, id, err
+	}
+	return &decryptReader{
+		r: br,
+		chunks: chunks{
+			aead:   aead,
+			header: header,
+			prefix: rest[len(rest)-noncePrefixSize:],
+		},
+		buf: make([]byte, encryptionChunkSize+aead.Overhead()),
+	}, id, nil
+}
+
+type chunks struct {
+	aead    cipher.AEAD
+	header  []byte
+	prefix  []byte
+	counter uint32
+}
+
+func (c *chunks) nonce(last bool) ([]byte, error) {
+	if c.counter == ^uint32(0) {
+		return nil, errors.New("snapshot is too large to be encrypted")
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	copy(nonce, c.prefix)
+	binary.BigEndian.PutUint32(nonce[noncePrefixSize:], c.counter)
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+	c.counter++
+	return nonce, nil
+}
+
+type encryptWriter struct {
+	chunks
+	w      io.Writer
+	buf    []byte
+	closed bool
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	if e.closed {
+		return 0, errors.New("write to closed snapshot encryption")
+	}
+	e.buf = append(e.buf, p...)
+	// a full chunk is only sealed once more data follows, as the last chunk has to be flagged
+	for len(e.buf) > encryptionChunkSize {
+		if err := e.seal(e.buf[:encryptionChunkSize], false); err != nil {
+			return 0, err
+		}
+		e.buf = append(e.buf[:0], e.buf[encryptionChunkSize:]...)
+	}
+	return len(p), nil
+}
+
+func (e *encryptWriter) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	return e.seal(e.buf, true)
+}
+
+func (e *encryptWriter) seal(chunk []byte, last bool) error {
+	nonce, err := e.nonce(last)
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(e.aead.Seal(nil, nonce, chunk, e.header))
+	return err
+}
+
+type decryptReader struct {
+	chunks
+	r     *bufio.Reader
+	buf   []byte
+	plain []byte
+	done  bool
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.plain) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.plain)
+	d.plain = d.plain[n:]
+	return n, nil
+}
+
+func (d *decryptReader) open() error {
+	n, err := io.ReadFull(d.r, d.buf)
+	switch {
+	case err == io.EOF:
+		return errors.New("encrypted snapshot is truncated")
+	case err == io.ErrUnexpectedEOF:
+		d.done = true
+	case err != nil:
+		return err
+	default:
+		if _, err := d.r.Peek(1); err == io.EOF {
+			d.done = true
+		}
+	}
+
+	nonce, err := d.nonce(d.done)
+	if err != nil {
+		return err
+	}
+	d.plain, err = d.aead.Open(d.buf[:0], nonce, d.buf[:n], d.header)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt snapshot: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package etcdsnapshot
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func testKeyRing(t *testing.T, active string, ids ...string) *KeyRing {
+	ring := &KeyRing{Active: active, Keys: map[string][]byte{}}
+	for _, id := range ids {
+		key := make([]byte, KeySize)
+		_, err := rand.Read(key)
+		require.NoError(t, err)
+		ring.Keys[id] = key
+	}
+	return ring
+}
+
+func encrypt(t *testing.T, data []byte, ring *KeyRing) []byte {
+	buf := &bytes.Buffer{}
+	w, err := Encrypt(buf, ring)
+	require.NoError(t, err)
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestEncryption(t *testing.T) {
+	ring := testKeyRing(t, "key-1", "key-1", "key-2")
+
+	for _, size := range []int{0, 1, encryptionChunkSize - 1, encryptionChunkSize, encryptionChunkSize + 1, 3*encryptionChunkSize + 7} {
+		data := make([]byte, size)
+		_, err := rand.Read(data)
+		require.NoError(t, err)
+
+		encrypted := encrypt(t, data, ring)
+		keyID, err := KeyID(bufio.NewReader(bytes.NewReader(encrypted)))
+		require.NoError(t, err)
+		assert.Equal(t, "key-1", keyID)
+		if size > 16 {
+			assert.False(t, bytes.Contains(encrypted, data[:16]), "expected the data to be encrypted")
+		}
+
+		r, keyID, err := Decrypt(bytes.NewReader(encrypted), ring)
+		require.NoError(t, err)
+		assert.Equal(t, "key-1", keyID)
+		decrypted, err := ioutil.ReadAll(r)
+		require.NoError(t, err, "size %d", size)
+		assert.True(t, bytes.Equal(data, decrypted), "size %d", size)
+
+		if size > 0 {
+			r, _, err = Decrypt(bytes.NewReader(encrypted[:len(encrypted)-1]), ring)
+			require.NoError(t, err)
+			_, err = ioutil.ReadAll(r)
+			assert.Error(t, err, "expected a truncated snapshot of size %d to be rejected", size)
+		}
+	}
+
+	data := bytes.Repeat([]byte("snapshot"), encryptionChunkSize/3)
+	encrypted := encrypt(t, data, ring)
+
+	// dropping the last chunk must not yield a valid snapshot
+	r, _, err := Decrypt(bytes.NewReader(encrypted[:len(encrypted)-(len(data)%encryptionChunkSize)-16]), ring)
+	require.NoError(t, err)
+	_, err = ioutil.ReadAll(r)
+	assert.Error(t, err)
+
+	tampered := append([]byte(nil), encrypted...)
+	tampered[len(tampered)/2]++
+	r, _, err = Decrypt(bytes.NewReader(tampered), ring)
+	require.NoError(t, err)
+	_, err = ioutil.ReadAll(r)
+	assert.Error(t, err, "expected a tampered snapshot to be rejected")
+
+	// rotating the active key keeps old snapshots readable as long as their key is kept
+	rotated := &KeyRing{Active: "key-2", Keys: ring.Keys}
+	r, keyID, err := Decrypt(bytes.NewReader(encrypted), rotated)
+	require.NoError(t, err)
+	assert.Equal(t, "key-1", keyID)
+	decrypted, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, data, decrypted)
+
+	_, keyID, err = Decrypt(bytes.NewReader(encrypted), testKeyRing(t, "key-3", "key-3"))
+	assert.Error(t, err, "expected a missing key to be rejected")
+	assert.Equal(t, "key-1", keyID)
+
+	_, _, err = Decrypt(bytes.NewReader(data), ring)
+	assert.Error(t, err, "expected a cleartext snapshot to be rejected")
+	keyID, err = KeyID(bufio.NewReader(bytes.NewReader(data)))
+	require.NoError(t, err)
+	assert.Empty(t, keyID)
+}
+
+func TestVerifyEncrypted(t *testing.T) {
+	ring := testKeyRing(t, "key-1", "key-1")
+	encrypted := encrypt(t, testDB(t, keyBucket), ring)
+
+	info, err := Verify(bytes.NewReader(encrypted), ring)
+	require.NoError(t, err)
+	assert.Equal(t, 5, info.Keys)
+	assert.Equal(t, "key-1", info.KeyID)
+
+	_, err = Verify(bytes.NewReader(encrypted), nil)
+	assert.Error(t, err, "expected an encrypted snapshot to require its key")
+}
+
+func TestKeyRingFromSecret(t *testing.T) {
+	raw := bytes.Repeat([]byte{1}, KeySize)
+	encoded := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, KeySize))
+
+	ring, err := KeyRingFromSecret(&corev1.Secret{
+		Data: map[string][]byte{"only": raw},
+	}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "only", ring.Active)
+	assert.Equal(t, raw, ring.Keys["only"])
+
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			"old": raw,
+			"new": []byte(encoded + "\n"),
+		},
+	}
+	_, err = KeyRingFromSecret(secret, "")
+	assert.Error(t, err, "expected the active key to be required with several keys")
+
+	secret.Annotations = map[string]string{ActiveKeyAnnotation: "new"}
+	ring, err = KeyRingFromSecret(secret, "")
+	require.NoError(t, err)
+	assert.Equal(t, "new", ring.Active)
+	assert.Equal(t, bytes.Repeat([]byte{2}, KeySize), ring.Keys["new"])
+
+	ring, err = KeyRingFromSecret(secret, "old")
+	require.NoError(t, err)
+	assert.Equal(t, "old", ring.Active)
+
+	_, err = KeyRingFromSecret(secret, "missing")
+	assert.Error(t, err)
+
+	_, err = KeyRingFromSecret(&corev1.Secret{Data: map[string][]byte{"short": []byte("key")}}, "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,153 @@
+package etcdsnapshot
+
+import (
+	"archive/zip"
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// Info describes a snapshot that could be opened.
+type Info struct {
+	// Checksum is the hex encoded sha256 of the stored snapshot, as uploaded.
+	Checksum string
+	// Keys is the number of key revisions in the etcd database of the snapshot.
+	Keys int
+	// KeyID is the ID of the key the snapshot is encrypted with, empty if it is not encrypted.
+	KeyID string
+}
+
+// Checksum returns the hex encoded sha256 of a stored snapshot.
+func Checksum(r io.Reader) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Verify reads a stored snapshot, either the zip archive uploaded by RKE or a plain etcd database, and checks that
+// its database can be opened. The snapshot is spooled to a temporary file as it can be larger than what should be
+// held in memory. Encrypted snapshots are decrypted with the keys of ring.
+func Verify(r io.Reader, ring *KeyRing) (Info, error) {
+	f, err := ioutil.TempFile("", "etcd-snapshot-")
+	if err != nil {
+		return Info{}, err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(f, h), r)
+	if err != nil {
+		return Info{}, fmt.Errorf("reading snapshot: %w", err)
+	}
+
+	info := Info{
+		Checksum: hex.EncodeToString(h.Sum(nil)),
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return info, err
+	}
+	if br := bufio.NewReader(f); IsEncrypted(br) {
+		info.Keys, info.KeyID, err = inspectEncrypted(br, ring)
+		return info, err
+	}
+	info.Keys, err = inspect(f, size)
+	return info, err
+}
+
+func inspectEncrypted(r io.Reader, ring *KeyRing) (int, string, error) {
+	plain, keyID, err := Decrypt(r, ring)
+	if err != nil {
+		return 0, keyID, err
+	}
+
+	f, err := ioutil.TempFile("", "etcd-snapshot-decrypted-")
+	if err != nil {
+		return 0, keyID, err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	size, err := io.Copy(f, plain)
+	if err != nil {
+		return 0, keyID, err
+	}
+	keys, err := inspect(f, size)
+	return keys, keyID, err
+}
+
+func inspect(f *os.File, size int64) (int, error) {
+	archive, err := zip.NewReader(f, size)
+	if err != nil {
+		return countKeys(f.Name())
+	}
+
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		return inspectArchived(file)
+	}
+	return 0, errors.New("snapshot archive is empty")
+}
+
+func inspectArchived(file *zip.File) (int, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer rc.Close()
+
+	db, err := ioutil.TempFile("", "etcd-snapshot-db-")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(db.Name())
+	defer db.Close()
+
+	if _, err := io.Copy(db, rc); err != nil {
+		return 0, fmt.Errorf("extracting %s from snapshot archive: %w", file.Name, err)
+	}
+	if err := db.Close(); err != nil {
+		return 0, err
+	}
+	return countKeys(db.Name())
+}
+
+// CheckRestoreVersion returns an error if a snapshot taken on snapshotVersion of Kubernetes cannot be restored on a
+// cluster running clusterVersion. The data of a snapshot can be carried to the next minor version, like an upgrade,
+// but never to an older one.
+func CheckRestoreVersion(snapshotVersion, clusterVersion string) error {
+	// snapshots taken before the version was recorded cannot be checked
+	if snapshotVersion == "" || clusterVersion == "" {
+		return nil
+	}
+
+	snapshot, err := semver.NewVersion(snapshotVersion)
+	if err != nil {
+		return fmt.Errorf("invalid snapshot Kubernetes version %s: %w", snapshotVersion, err)
+	}
+	cluster, err := semver.NewVersion(clusterVersion)
+	if err != nil {
+		return fmt.Errorf("invalid cluster Kubernetes version %s: %w", clusterVersion, err)
+	}
+
+	switch {
+	case snapshot.Major() != cluster.Major():
+		return fmt.Errorf("snapshot was taken on Kubernetes %s and cannot be restored on %s", snapshotVersion, clusterVersion)
+	case snapshot.Minor() > cluster.Minor():
+		return fmt.Errorf("snapshot was taken on the newer Kubernetes %s and cannot be restored on %s", snapshotVersion, clusterVersion)
+	case cluster.Minor()-snapshot.Minor() > 1:
+		return fmt.Errorf("snapshot was taken on Kubernetes %s, more than one minor version behind %s", snapshotVersion, clusterVersion)
+	}
+	return nil
+}
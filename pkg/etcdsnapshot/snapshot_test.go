@@ -0,0 +1,154 @@
+package etcdsnapshot
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+)
+
+// boltPageSizeOffset is the offset of the page size in the meta pages, after the page header, magic and version.
+const boltPageSizeOffset = 16 + 8
+
+// testDB builds a database with a "key" bucket holding 5 keys, enough to span several pages, and a "meta" bucket.
+func testDB(t *testing.T, bucketName string) []byte {
+	path := filepath.Join(t.TempDir(), "db")
+	db, err := bbolt.Open(path, 0600, nil)
+	require.NoError(t, err)
+	err = db.Update(func(tx *bbolt.Tx) error {
+		b, err := tx.CreateBucket([]byte(bucketName))
+		if err != nil {
+			return err
+		}
+		for i := 0; i < 5; i++ {
+			if err := b.Put([]byte(fmt.Sprintf("rev-%d", i)), bytes.Repeat([]byte("v"), 2048)); err != nil {
+				return err
+			}
+		}
+		meta, err := tx.CreateBucket([]byte("meta"))
+		if err != nil {
+			return err
+		}
+		return meta.Put([]byte("consistent_index"), []byte("1"))
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	return data
+}
+
+func writeDB(t *testing.T, data []byte) string {
+	path := filepath.Join(t.TempDir(), "snapshot")
+	require.NoError(t, ioutil.WriteFile(path, data, 0600))
+	return path
+}
+
+// keyBucketRoot returns the id of the root page of the key bucket.
+func keyBucketRoot(t *testing.T, path string) int {
+	db, err := bbolt.Open(path, 0400, &bbolt.Options{ReadOnly: true})
+	require.NoError(t, err)
+	defer db.Close()
+
+	var root int
+	err = db.View(func(tx *bbolt.Tx) error {
+		root = int(tx.Bucket([]byte(keyBucket)).Root())
+		return nil
+	})
+	require.NoError(t, err)
+	return root
+}
+
+func TestCountKeys(t *testing.T) {
+	db := testDB(t, keyBucket)
+	count, err := countKeys(writeDB(t, db))
+	require.NoError(t, err)
+	assert.Equal(t, 5, count)
+
+	pageSize := int(binary.LittleEndian.Uint32(db[boltPageSizeOffset:]))
+	_, err = countKeys(writeDB(t, db[:4*pageSize]))
+	assert.Error(t, err, "expected a truncated snapshot to be rejected")
+
+	_, err = countKeys(writeDB(t, testDB(t, "other")))
+	assert.Error(t, err, "expected a snapshot without key bucket to be rejected")
+
+	corrupt := append([]byte{}, db...)
+	corrupt[boltPageSizeOffset+4]++
+	corrupt[pageSize+boltPageSizeOffset+4]++
+	_, err = countKeys(writeDB(t, corrupt))
+	assert.Error(t, err, "expected a corrupt meta page to be rejected")
+
+	_, err = countKeys(writeDB(t, []byte("not a snapshot")))
+	assert.Error(t, err)
+
+	// a corrupt page of a bucket is an error, not a panic of the process
+	root := keyBucketRoot(t, writeDB(t, db))
+	require.NotZero(t, root, "expected the key bucket to be stored in pages of its own")
+	for page := root; page < len(db)/pageSize; page++ {
+		garbage := append([]byte{}, db...)
+		for i := page * pageSize; i < (page+1)*pageSize; i++ {
+			garbage[i] = 0xff
+		}
+		_, err = countKeys(writeDB(t, garbage))
+		if page == root {
+			assert.Error(t, err, "expected a corrupt root page of the key bucket to be rejected")
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	archive := &bytes.Buffer{}
+	w := zip.NewWriter(archive)
+	f, err := w.Create("backup/snapshot")
+	require.NoError(t, err)
+	_, err = f.Write(testDB(t, keyBucket))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	sum := sha256.Sum256(archive.Bytes())
+	info, err := Verify(bytes.NewReader(archive.Bytes()), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 5, info.Keys)
+	assert.Equal(t, hex.EncodeToString(sum[:]), info.Checksum)
+
+	info, err = Verify(bytes.NewReader(testDB(t, keyBucket)), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 5, info.Keys)
+
+	_, err = Verify(bytes.NewReader([]byte("not a snapshot")), nil)
+	assert.Error(t, err)
+}
+
+func TestCheckRestoreVersion(t *testing.T) {
+	tests := []struct {
+		snapshot string
+		cluster  string
+		valid    bool
+	}{
+		{"v1.20.8-rancher1-1", "v1.20.9-rancher1-1", true},
+		{"v1.19.12-rancher1-1", "v1.20.8-rancher1-1", true},
+		{"v1.18.20-rancher1-1", "v1.20.8-rancher1-1", false},
+		{"v1.21.2-rancher1-1", "v1.20.8-rancher1-1", false},
+		{"", "v1.20.8-rancher1-1", true},
+		{"invalid", "v1.20.8-rancher1-1", false},
+	}
+
+	for _, tt := range tests {
+		err := CheckRestoreVersion(tt.snapshot, tt.cluster)
+		if tt.valid {
+			assert.NoError(t, err, "%s on %s", tt.snapshot, tt.cluster)
+		} else {
+			assert.Error(t, err, "%s on %s", tt.snapshot, tt.cluster)
+		}
+	}
+}
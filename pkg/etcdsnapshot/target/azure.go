@@ -0,0 +1,162 @@
+package target
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/storage"
+	"github.com/Azure/go-autorest/autorest/azure"
+)
+
+const (
+	defaultAzureEndpoint = "core.windows.net"
+	// azureBlockSize is the size of the blocks snapshots are uploaded in, a block blob holds up to 50000 blocks.
+	azureBlockSize = 8 * 1024 * 1024
+)
+
+// azureTarget stores snapshots in a blob container, authenticated with the shared key or a SAS token of the account.
+type azureTarget struct {
+	container *storage.Container
+	folder    string
+}
+
+func newAzure(config *Config) (Target, error) {
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("azure target requires a container")
+	}
+	account := config.Credentials[CredentialAccountName]
+	if account == "" {
+		return nil, fmt.Errorf("azure target requires an account name")
+	}
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = defaultAzureEndpoint
+	}
+
+	var client storage.Client
+	if token := config.Credentials[CredentialSASToken]; token != "" {
+		values, err := url.ParseQuery(strings.TrimPrefix(token, "?"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid azure sas token: %w", err)
+		}
+		client = storage.NewAccountSASClient(account, values, azure.Environment{StorageEndpointSuffix: endpoint})
+	} else {
+		key := config.Credentials[CredentialAccountKey]
+		if _, err := base64.StdEncoding.DecodeString(key); err != nil || key == "" {
+			return nil, fmt.Errorf("azure target requires a valid account key or sas token")
+		}
+		var err error
+		if client, err = storage.NewClient(account, key, endpoint, storage.DefaultAPIVersion, true); err != nil {
+			return nil, err
+		}
+	}
+
+	if config.Dialer != nil {
+		client.HTTPClient = &http.Client{
+			Transport: &http.Transport{
+				Proxy:       http.ProxyFromEnvironment,
+				DialContext: config.Dialer,
+			},
+		}
+	}
+	return newAzureTarget(client, config.Bucket, config.Folder), nil
+}
+
+func newAzureTarget(client storage.Client, container, folder string) *azureTarget {
+	blobs := client.GetBlobService()
+	return &azureTarget{
+		container: blobs.GetContainerReference(container),
+		folder:    folder,
+	}
+}
+
+func (a *azureTarget) Upload(ctx context.Context, name string, r io.Reader, size int64) error {
+	if err := validName(name); err != nil {
+		return err
+	}
+
+	blob := a.container.GetBlobReference(key(a.folder, name))
+	buf := make([]byte, azureBlockSize)
+	var blocks []storage.Block
+	for i := 0; ; i++ {
+		n, readErr := io.ReadFull(r, buf)
+		if n > 0 {
+			// block ids must have the same length within a blob
+			id := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%08d", i)))
+			if err := blob.PutBlock(id, buf[:n], nil); err != nil {
+				return err
+			}
+			blocks = append(blocks, storage.Block{ID: id, Status: storage.BlockStatusLatest})
+		}
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		} else if readErr != nil {
+			return readErr
+		}
+	}
+	return blob.PutBlockList(blocks, nil)
+}
+
+func (a *azureTarget) Download(ctx context.Context, name string) (io.ReadCloser, error) {
+	if err := validName(name); err != nil {
+		return nil, err
+	}
+	rc, err := a.container.GetBlobReference(key(a.folder, name)).Get(nil)
+	return rc, azureError(err)
+}
+
+func (a *azureTarget) List(ctx context.Context) ([]Object, error) {
+	var (
+		result []Object
+		p      = prefix(a.folder)
+		marker string
+	)
+	for {
+		list, err := a.container.ListBlobs(storage.ListBlobsParameters{
+			Prefix:    p,
+			Delimiter: "/",
+			Marker:    marker,
+		})
+		if err != nil {
+			return nil, azureError(err)
+		}
+
+		for _, blob := range list.Blobs {
+			name := strings.TrimPrefix(blob.Name, p)
+			if validName(name) != nil {
+				continue
+			}
+			result = append(result, Object{
+				Name:         name,
+				Size:         blob.Properties.ContentLength,
+				LastModified: time.Time(blob.Properties.LastModified),
+			})
+		}
+		if list.NextMarker == "" {
+			return result, nil
+		}
+		marker = list.NextMarker
+	}
+}
+
+func (a *azureTarget) Delete(ctx context.Context, name string) error {
+	if err := validName(name); err != nil {
+		return err
+	}
+	return azureError(a.container.GetBlobReference(key(a.folder, name)).Delete(nil))
+}
+
+func azureError(err error) error {
+	var serviceErr storage.AzureStorageServiceError
+	if errors.As(err, &serviceErr) && serviceErr.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+	return err
+}
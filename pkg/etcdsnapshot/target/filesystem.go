@@ -0,0 +1,100 @@
+package target
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+type filesystemTarget struct {
+	path string
+}
+
+func newFilesystem(config *Config) (Target, error) {
+	if !filepath.IsAbs(config.Path) {
+		return nil, fmt.Errorf("filesystem target requires an absolute path, got %q", config.Path)
+	}
+	return &filesystemTarget{
+		path: config.Path,
+	}, nil
+}
+
+func (f *filesystemTarget) Upload(ctx context.Context, name string, r io.Reader, size int64) error {
+	if err := validName(name); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(f.path, 0700); err != nil {
+		return err
+	}
+
+	// write to a temporary file first so that partial snapshots are never listed
+	tmp, err := ioutil.TempFile(f.path, "."+name+"-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(f.path, name))
+}
+
+func (f *filesystemTarget) Download(ctx context.Context, name string) (io.ReadCloser, error) {
+	if err := validName(name); err != nil {
+		return nil, err
+	}
+	file, err := os.Open(filepath.Join(f.path, name))
+	if err != nil {
+		return nil, filesystemError(err)
+	}
+	return file, nil
+}
+
+func (f *filesystemTarget) List(ctx context.Context) ([]Object, error) {
+	entries, err := ioutil.ReadDir(f.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var result []Object
+	for _, entry := range entries {
+		if !entry.Mode().IsRegular() || entry.Name()[0] == '.' {
+			continue
+		}
+		result = append(result, Object{
+			Name:         entry.Name(),
+			Size:         entry.Size(),
+			LastModified: entry.ModTime(),
+		})
+	}
+	return result, nil
+}
+
+func (f *filesystemTarget) Delete(ctx context.Context, name string) error {
+	if err := validName(name); err != nil {
+		return err
+	}
+	return filesystemError(os.Remove(filepath.Join(f.path, name)))
+}
+
+func filesystemError(err error) error {
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+	return err
+}
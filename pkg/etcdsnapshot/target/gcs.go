@@ -0,0 +1,108 @@
+package target
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	storage "google.golang.org/api/storage/v1"
+)
+
+type gcsTarget struct {
+	objects *storage.ObjectsService
+	bucket  string
+	folder  string
+}
+
+func newGCS(ctx context.Context, config *Config) (Target, error) {
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("gcs target requires a bucket")
+	}
+
+	if config.Dialer != nil {
+		// the oauth2 client, also used to fetch tokens, is built on top of the client in the context
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{
+			Transport: &http.Transport{
+				Proxy:       http.ProxyFromEnvironment,
+				DialContext: config.Dialer,
+			},
+		})
+	}
+
+	creds, err := google.CredentialsFromJSON(ctx, []byte(config.Credentials[CredentialAuthEncodedJSON]), storage.DevstorageReadWriteScope)
+	if err != nil {
+		return nil, err
+	}
+	svc, err := storage.NewService(ctx, option.WithHTTPClient(oauth2.NewClient(ctx, creds.TokenSource)))
+	if err != nil {
+		return nil, err
+	}
+	return &gcsTarget{
+		objects: svc.Objects,
+		bucket:  config.Bucket,
+		folder:  config.Folder,
+	}, nil
+}
+
+func (g *gcsTarget) Upload(ctx context.Context, name string, r io.Reader, size int64) error {
+	if err := validName(name); err != nil {
+		return err
+	}
+	_, err := g.objects.Insert(g.bucket, &storage.Object{Name: key(g.folder, name)}).Media(r).Context(ctx).Do()
+	return err
+}
+
+func (g *gcsTarget) Download(ctx context.Context, name string) (io.ReadCloser, error) {
+	if err := validName(name); err != nil {
+		return nil, err
+	}
+	resp, err := g.objects.Get(g.bucket, key(g.folder, name)).Context(ctx).Download()
+	if err != nil {
+		return nil, gcsError(err)
+	}
+	return resp.Body, nil
+}
+
+func (g *gcsTarget) List(ctx context.Context) ([]Object, error) {
+	var result []Object
+	p := prefix(g.folder)
+	err := g.objects.List(g.bucket).Prefix(p).Delimiter("/").Pages(ctx, func(objects *storage.Objects) error {
+		for _, object := range objects.Items {
+			name := strings.TrimPrefix(object.Name, p)
+			if validName(name) != nil {
+				continue
+			}
+			updated, _ := time.Parse(time.RFC3339, object.Updated)
+			result = append(result, Object{
+				Name:         name,
+				Size:         int64(object.Size),
+				LastModified: updated,
+			})
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (g *gcsTarget) Delete(ctx context.Context, name string) error {
+	if err := validName(name); err != nil {
+		return err
+	}
+	return gcsError(g.objects.Delete(g.bucket, key(g.folder, name)).Context(ctx).Do())
+}
+
+func gcsError(err error) error {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+		return fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+	return err
+}
@@ -0,0 +1,148 @@
+package target
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	minio "github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+const defaultS3Endpoint = "s3.amazonaws.com"
+
+type s3Target struct {
+	client *minio.Client
+	bucket string
+	folder string
+}
+
+// NewS3 returns a target storing the snapshots in the folder of the bucket with an existing client.
+func NewS3(client *minio.Client, bucket, folder string) Target {
+	return &s3Target{
+		client: client,
+		bucket: bucket,
+		folder: folder,
+	}
+}
+
+func newS3(config *Config) (Target, error) {
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("s3 target requires a bucket")
+	}
+	client, err := NewS3Client(config)
+	if err != nil {
+		return nil, err
+	}
+	return NewS3(client, config.Bucket, config.Folder), nil
+}
+
+// NewS3Client returns a client for the S3 endpoint of the config, the bucket and folder of the config are not used.
+func NewS3Client(config *Config) (*minio.Client, error) {
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           config.Dialer,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: config.SkipSSLVerify,
+		},
+	}
+	if config.EndpointCA != "" {
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM([]byte(config.EndpointCA))
+		transport.TLSClientConfig.RootCAs = pool
+	}
+
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = defaultS3Endpoint
+	}
+
+	// no access credentials, we assume IAM roles
+	creds := credentials.NewIAM("")
+	if config.Credentials[CredentialAccessKey] != "" && config.Credentials[CredentialSecretKey] != "" {
+		creds = credentials.NewStatic(config.Credentials[CredentialAccessKey], config.Credentials[CredentialSecretKey], "", credentials.SignatureDefault)
+	}
+
+	return minio.New(endpoint, &minio.Options{
+		Creds:        creds,
+		Region:       config.Region,
+		Secure:       true,
+		BucketLookup: bucketLookup(endpoint),
+		Transport:    transport,
+	})
+}
+
+// bucketLookup returns the bucket addressing of the endpoint, Alibaba Cloud OSS only supports virtual-hosted buckets.
+func bucketLookup(endpoint string) minio.BucketLookupType {
+	if strings.Contains(endpoint, "aliyun") {
+		return minio.BucketLookupDNS
+	}
+	return minio.BucketLookupAuto
+}
+
+func (s *s3Target) Upload(ctx context.Context, name string, r io.Reader, size int64) error {
+	if err := validName(name); err != nil {
+		return err
+	}
+	_, err := s.client.PutObject(ctx, s.bucket, key(s.folder, name), r, size, minio.PutObjectOptions{})
+	return err
+}
+
+func (s *s3Target) Download(ctx context.Context, name string) (io.ReadCloser, error) {
+	if err := validName(name); err != nil {
+		return nil, err
+	}
+	object, err := s.client.GetObject(ctx, s.bucket, key(s.folder, name), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, s3Error(err)
+	}
+	// the object is only fetched on first use, stat it to fail early if it is missing
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		return nil, s3Error(err)
+	}
+	return object, nil
+}
+
+func (s *s3Target) List(ctx context.Context) ([]Object, error) {
+	var result []Object
+	p := prefix(s.folder)
+	for info := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: p}) {
+		if info.Err != nil {
+			return nil, info.Err
+		}
+		name := strings.TrimPrefix(info.Key, p)
+		if validName(name) != nil {
+			continue
+		}
+		result = append(result, Object{
+			Name:         name,
+			Size:         info.Size,
+			LastModified: info.LastModified,
+		})
+	}
+	return result, nil
+}
+
+func (s *s3Target) Delete(ctx context.Context, name string) error {
+	if err := validName(name); err != nil {
+		return err
+	}
+	return s3Error(s.client.RemoveObject(ctx, s.bucket, key(s.folder, name), minio.RemoveObjectOptions{}))
+}
+
+func s3Error(err error) error {
+	if err != nil && minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+	return err
+}
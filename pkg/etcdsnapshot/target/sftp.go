@@ -0,0 +1,227 @@
+package target
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// sftpPartialSuffix marks snapshots which are still uploaded, they are renamed once complete
+	sftpPartialSuffix = ".part"
+	sftpDialTimeout   = 30 * time.Second
+)
+
+type sftpTarget struct {
+	host   string
+	folder string
+	config *ssh.ClientConfig
+	dialer func(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+func newSFTP(config *Config) (Target, error) {
+	if config.Host == "" {
+		return nil, fmt.Errorf("sftp target requires a host")
+	}
+	if config.HostKey == "" {
+		return nil, fmt.Errorf("sftp target requires the host key of the server")
+	}
+	hostKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(config.HostKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid sftp host key: %w", err)
+	}
+
+	var auth []ssh.AuthMethod
+	if privateKey := config.Credentials[CredentialPrivateKey]; privateKey != "" {
+		signer, err := ssh.ParsePrivateKey([]byte(privateKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid sftp private key: %w", err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if password := config.Credentials[CredentialPassword]; password != "" {
+		auth = append(auth, ssh.Password(password))
+	}
+
+	host := config.Host
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, "22")
+	}
+	dialer := config.Dialer
+	if dialer == nil {
+		dialer = (&net.Dialer{Timeout: sftpDialTimeout}).DialContext
+	}
+
+	return &sftpTarget{
+		host:   host,
+		folder: config.Folder,
+		dialer: dialer,
+		config: &ssh.ClientConfig{
+			User:            config.Credentials[CredentialUsername],
+			Auth:            auth,
+			HostKeyCallback: ssh.FixedHostKey(hostKey),
+			Timeout:         sftpDialTimeout,
+		},
+	}, nil
+}
+
+func (s *sftpTarget) path(name string) string {
+	if s.folder == "" {
+		return name
+	}
+	return path.Join(s.folder, name)
+}
+
+func (s *sftpTarget) Upload(ctx context.Context, name string, r io.Reader, size int64) error {
+	if err := validName(name); err != nil {
+		return err
+	}
+	c, err := s.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if s.folder != "" {
+		if err := c.MkdirAll(s.folder); err != nil {
+			return err
+		}
+	}
+
+	partial := s.path(name + sftpPartialSuffix)
+	f, err := c.OpenFile(partial, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	if err != nil {
+		return err
+	}
+	if _, err := f.ReadFrom(r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	// version 3 servers refuse to rename over an existing file
+	if err := c.Remove(s.path(name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return c.Rename(partial, s.path(name))
+}
+
+func (s *sftpTarget) Download(ctx context.Context, name string) (io.ReadCloser, error) {
+	if err := validName(name); err != nil {
+		return nil, err
+	}
+	c, err := s.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	f, err := c.Open(s.path(name))
+	if err != nil {
+		c.Close()
+		return nil, sftpError(err)
+	}
+	return &sftpFile{File: f, conn: c}, nil
+}
+
+func (s *sftpTarget) List(ctx context.Context) ([]Object, error) {
+	c, err := s.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	dir := s.folder
+	if dir == "" {
+		dir = "."
+	}
+	files, err := c.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var result []Object
+	for _, file := range files {
+		name := file.Name()
+		if validName(name) != nil || strings.HasSuffix(name, sftpPartialSuffix) || !file.Mode().IsRegular() {
+			continue
+		}
+		result = append(result, Object{
+			Name:         name,
+			Size:         file.Size(),
+			LastModified: file.ModTime(),
+		})
+	}
+	return result, nil
+}
+
+func (s *sftpTarget) Delete(ctx context.Context, name string) error {
+	if err := validName(name); err != nil {
+		return err
+	}
+	c, err := s.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	return sftpError(c.Remove(s.path(name)))
+}
+
+// sftpConn is an SFTP client and the SSH connection it runs on.
+type sftpConn struct {
+	*sftp.Client
+	ssh *ssh.Client
+}
+
+func (s *sftpTarget) connect(ctx context.Context) (*sftpConn, error) {
+	conn, err := s.dialer(ctx, "tcp", s.host)
+	if err != nil {
+		return nil, err
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, s.host, s.config)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	client := ssh.NewClient(sshConn, chans, reqs)
+	sftpClient, err := sftp.NewClient(client)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	return &sftpConn{Client: sftpClient, ssh: client}, nil
+}
+
+func (c *sftpConn) Close() error {
+	c.Client.Close()
+	return c.ssh.Close()
+}
+
+// sftpFile is a downloaded snapshot, closing it closes the connection.
+type sftpFile struct {
+	*sftp.File
+	conn *sftpConn
+}
+
+func (f *sftpFile) Close() error {
+	f.File.Close()
+	return f.conn.Close()
+}
+
+func sftpError(err error) error {
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+	return err
+}
@@ -0,0 +1,173 @@
+// Package target stores etcd snapshots in the backup targets supported besides the local disk of the etcd nodes.
+package target
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rancher/wrangler/pkg/kv"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	TypeS3         = "s3"
+	TypeAzure      = "azure"
+	TypeGCS        = "gcs"
+	TypeSFTP       = "sftp"
+	TypeFilesystem = "filesystem"
+)
+
+// ErrNotFound is returned when the snapshot does not exist in the target.
+var ErrNotFound = errors.New("snapshot not found")
+
+// Object is a snapshot stored in a target.
+type Object struct {
+	Name         string
+	Size         int64
+	LastModified time.Time
+}
+
+// Target is a place snapshots are uploaded to, listed, rotated and restored from. Names are relative to the folder
+// of the target.
+type Target interface {
+	Upload(ctx context.Context, name string, r io.Reader, size int64) error
+	Download(ctx context.Context, name string) (io.ReadCloser, error)
+	List(ctx context.Context) ([]Object, error)
+	Delete(ctx context.Context, name string) error
+}
+
+// Config describes a target. It is serialized into the plans of the etcd nodes, which transfer the snapshots, without
+// the credentials, which are stored as JSON in the etcd snapshot secret of the cluster.
+type Config struct {
+	Type string `json:"type"`
+	// Bucket is the S3 or GCS bucket, or the Azure blob container.
+	Bucket string `json:"bucket,omitempty"`
+	// Folder is prepended to the snapshot names in all remote targets.
+	Folder string `json:"folder,omitempty"`
+	// Endpoint is the S3 endpoint or the Azure storage endpoint suffix.
+	Endpoint      string `json:"endpoint,omitempty"`
+	EndpointCA    string `json:"endpointCA,omitempty"`
+	SkipSSLVerify bool   `json:"skipSSLVerify,omitempty"`
+	Region        string `json:"region,omitempty"`
+	// Host is the address of the SFTP server, with an optional port.
+	Host string `json:"host,omitempty"`
+	// HostKey is the public key of the SFTP server in authorized_keys format.
+	HostKey string `json:"hostKey,omitempty"`
+	// Path is the directory of the filesystem target, usually an NFS export or volume mounted on the etcd nodes.
+	Path string `json:"path,omitempty"`
+	// Credentials are the fields of the cloud credential of the target, see CredentialsFromSecret.
+	Credentials map[string]string `json:"credentials,omitempty"`
+
+	// Dialer is used by the remote targets to connect, the default dialer is used if nil.
+	Dialer func(ctx context.Context, network, address string) (net.Conn, error) `json:"-"`
+}
+
+// Credential keys read by the targets.
+const (
+	CredentialAccessKey       = "accessKey"
+	CredentialSecretKey       = "secretKey"
+	CredentialAccountName     = "accountName"
+	CredentialAccountKey      = "accountKey"
+	CredentialSASToken        = "sasToken"
+	CredentialAuthEncodedJSON = "authEncodedJson"
+	CredentialUsername        = "username"
+	CredentialPassword        = "password"
+	CredentialPrivateKey      = "privateKey"
+)
+
+// New returns the target described by the config.
+func New(ctx context.Context, config *Config) (Target, error) {
+	switch config.Type {
+	case TypeS3:
+		return newS3(config)
+	case TypeAzure:
+		return newAzure(config)
+	case TypeGCS:
+		return newGCS(ctx, config)
+	case TypeSFTP:
+		return newSFTP(config)
+	case TypeFilesystem:
+		return newFilesystem(config)
+	default:
+		return nil, fmt.Errorf("unsupported etcd snapshot target type %q", config.Type)
+	}
+}
+
+// IsRemote returns true if snapshots have to be transferred from the etcd nodes to the target. S3 is handled by the
+// Kubernetes distributions themselves and the filesystem target is written to directly.
+func IsRemote(targetType string) bool {
+	return targetType == TypeAzure || targetType == TypeGCS || targetType == TypeSFTP
+}
+
+// CredentialsFromSecret returns the fields of a cloud credential secret, without the prefix of the credential type,
+// so that amazonec2credentialConfig-accessKey is returned as accessKey.
+func CredentialsFromSecret(secret *corev1.Secret) map[string]string {
+	result := map[string]string{}
+	if secret == nil {
+		return result
+	}
+	for k, v := range secret.Data {
+		_, k = kv.RSplit(k, "-")
+		result[k] = string(v)
+	}
+	return result
+}
+
+// Rotate deletes the oldest snapshots of the target until at most retention remain, a retention of 0 keeps all.
+func Rotate(ctx context.Context, t Target, retention int) ([]string, error) {
+	if retention <= 0 {
+		return nil, nil
+	}
+
+	objects, err := t.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(objects) <= retention {
+		return nil, nil
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].LastModified.After(objects[j].LastModified)
+	})
+
+	var deleted []string
+	for _, object := range objects[retention:] {
+		if err := t.Delete(ctx, object.Name); err != nil && !errors.Is(err, ErrNotFound) {
+			return deleted, err
+		}
+		deleted = append(deleted, object.Name)
+	}
+	return deleted, nil
+}
+
+func validName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid snapshot name %q", name)
+	}
+	return nil
+}
+
+// key returns the name of the snapshot in a remote target, prefixed with the folder.
+func key(folder, name string) string {
+	folder = strings.Trim(folder, "/")
+	if folder == "" {
+		return name
+	}
+	return folder + "/" + name
+}
+
+// prefix returns the prefix of the keys of the folder in a remote target.
+func prefix(folder string) string {
+	folder = strings.Trim(folder, "/")
+	if folder == "" {
+		return ""
+	}
+	return folder + "/"
+}
@@ -0,0 +1,360 @@
+package target
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	azurestorage "github.com/Azure/azure-sdk-for-go/storage"
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestFilesystem(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "etcd-snapshot-target")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	target, err := New(ctx, &Config{Type: TypeFilesystem, Path: filepath.Join(dir, "snapshots")})
+	require.NoError(t, err)
+
+	objects, err := target.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, objects, "expected a missing directory to hold no snapshots")
+
+	require.NoError(t, target.Upload(ctx, "snapshot-1", strings.NewReader("data"), 4))
+	objects, err = target.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+	assert.Equal(t, "snapshot-1", objects[0].Name)
+	assert.Equal(t, int64(4), objects[0].Size)
+
+	rc, err := target.Download(ctx, "snapshot-1")
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(rc)
+	rc.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+
+	_, err = target.Download(ctx, "missing")
+	assert.True(t, errors.Is(err, ErrNotFound), "expected ErrNotFound, got %v", err)
+	assert.Error(t, target.Upload(ctx, "../escape", strings.NewReader("data"), 4))
+
+	require.NoError(t, target.Delete(ctx, "snapshot-1"))
+	assert.True(t, errors.Is(target.Delete(ctx, "snapshot-1"), ErrNotFound))
+
+	_, err = New(ctx, &Config{Type: TypeFilesystem, Path: "relative"})
+	assert.Error(t, err)
+}
+
+func TestRotate(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "etcd-snapshot-target")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	target, err := New(ctx, &Config{Type: TypeFilesystem, Path: dir})
+	require.NoError(t, err)
+
+	now := time.Now()
+	for i, name := range []string{"c", "a", "d", "b"} {
+		require.NoError(t, target.Upload(ctx, name, strings.NewReader(name), 1))
+		modified := now.Add(-time.Duration(i) * time.Hour)
+		require.NoError(t, os.Chtimes(filepath.Join(dir, name), modified, modified))
+	}
+
+	deleted, err := Rotate(ctx, target, 0)
+	require.NoError(t, err)
+	assert.Empty(t, deleted, "expected a retention of 0 to keep all snapshots")
+
+	deleted, err = Rotate(ctx, target, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"d", "b"}, deleted)
+
+	objects, err := target.List(ctx)
+	require.NoError(t, err)
+	var names []string
+	for _, object := range objects {
+		names = append(names, object.Name)
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"a", "c"}, names)
+}
+
+func TestCredentialsFromSecret(t *testing.T) {
+	credentials := CredentialsFromSecret(&corev1.Secret{
+		Data: map[string][]byte{
+			"azurecredentialConfig-accountName": []byte("account"),
+			"azurecredentialConfig-accountKey":  []byte("key"),
+		},
+	})
+	assert.Equal(t, map[string]string{
+		CredentialAccountName: "account",
+		CredentialAccountKey:  "key",
+	}, credentials)
+	assert.Empty(t, CredentialsFromSecret(nil))
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(context.Background(), &Config{Type: "tape"})
+	assert.Error(t, err)
+	_, err = New(context.Background(), &Config{Type: TypeS3})
+	assert.Error(t, err, "expected a bucket to be required")
+	_, err = New(context.Background(), &Config{Type: TypeSFTP, Host: "backup.example.com"})
+	assert.Error(t, err, "expected a host key to be required")
+}
+
+// newSFTPServer returns an sftp target config whose dialer connects to a local SSH server with an in-memory sftp
+// subsystem.
+func newSFTPServer(t *testing.T) *Config {
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(hostKey)
+	require.NoError(t, err)
+
+	serverConfig := &ssh.ServerConfig{
+		PasswordCallback: func(c ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if c.User() == "backup" && string(password) == "secret" {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("invalid password for %s", c.User())
+		},
+	}
+	serverConfig.AddHostKey(signer)
+
+	handlers := sftp.InMemHandler()
+	serve := func(conn net.Conn) {
+		_, chans, reqs, err := ssh.NewServerConn(conn, serverConfig)
+		if err != nil {
+			return
+		}
+		go ssh.DiscardRequests(reqs)
+		for newChannel := range chans {
+			channel, requests, err := newChannel.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				for req := range requests {
+					ok := req.Type == "subsystem" && string(req.Payload[4:]) == "sftp"
+					req.Reply(ok, nil)
+					if ok {
+						server := sftp.NewRequestServer(channel, handlers)
+						go func() {
+							server.Serve()
+							server.Close()
+						}()
+					}
+				}
+			}()
+		}
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serve(conn)
+		}
+	}()
+
+	return &Config{
+		Type:        TypeSFTP,
+		Host:        "backup.example.com",
+		HostKey:     string(ssh.MarshalAuthorizedKey(signer.PublicKey())),
+		Folder:      "/snapshots/cluster",
+		Credentials: map[string]string{CredentialUsername: "backup", CredentialPassword: "secret"},
+		Dialer: func(ctx context.Context, network, address string) (net.Conn, error) {
+			return net.Dial(network, listener.Addr().String())
+		},
+	}
+}
+
+func TestSFTP(t *testing.T) {
+	ctx := context.Background()
+	config := newSFTPServer(t)
+	target, err := New(ctx, config)
+	require.NoError(t, err)
+
+	objects, err := target.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, objects, "expected a missing folder to hold no snapshots")
+
+	require.NoError(t, target.Upload(ctx, "snapshot-1", strings.NewReader("data"), 4))
+	require.NoError(t, target.Upload(ctx, "snapshot-1", strings.NewReader("newer data"), 10), "expected an upload to replace the snapshot")
+	objects, err = target.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, objects, 1, "expected no partial uploads to be listed")
+	assert.Equal(t, "snapshot-1", objects[0].Name)
+	assert.Equal(t, int64(10), objects[0].Size)
+
+	rc, err := target.Download(ctx, "snapshot-1")
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(rc)
+	rc.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "newer data", string(data))
+
+	_, err = target.Download(ctx, "missing")
+	assert.True(t, errors.Is(err, ErrNotFound), "expected ErrNotFound, got %v", err)
+
+	require.NoError(t, target.Delete(ctx, "snapshot-1"))
+	assert.True(t, errors.Is(target.Delete(ctx, "snapshot-1"), ErrNotFound))
+
+	config.Credentials[CredentialPassword] = "wrong"
+	target, err = New(ctx, config)
+	require.NoError(t, err)
+	_, err = target.List(ctx)
+	assert.Error(t, err, "expected the credentials to be checked")
+
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	otherSigner, err := ssh.NewSignerFromKey(otherKey)
+	require.NoError(t, err)
+	config.Credentials[CredentialPassword] = "secret"
+	config.HostKey = string(ssh.MarshalAuthorizedKey(otherSigner.PublicKey()))
+	target, err = New(ctx, config)
+	require.NoError(t, err)
+	_, err = target.List(ctx)
+	assert.Error(t, err, "expected the host key to be checked")
+}
+
+// fakeBlobService is a minimal blob service holding the blobs of a container in memory.
+type fakeBlobService struct {
+	mu     sync.Mutex
+	blocks map[string][]byte
+	blobs  map[string][]byte
+}
+
+func (f *fakeBlobService) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !strings.HasPrefix(req.Header.Get("Authorization"), "SharedKey account:") {
+		rw.WriteHeader(http.StatusForbidden)
+		return
+	}
+	query := req.URL.Query()
+	blob := strings.TrimPrefix(req.URL.Path, "/snapshots/")
+	body, _ := ioutil.ReadAll(req.Body)
+	notFound := func() {
+		rw.Header().Set("Content-Type", "application/xml")
+		rw.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="utf-8"?><Error><Code>BlobNotFound</Code><Message>The specified blob does not exist.</Message></Error>`)
+	}
+
+	switch {
+	case req.Method == http.MethodPut && query.Get("comp") == "block":
+		f.blocks[blob+"/"+query.Get("blockid")] = body
+		rw.WriteHeader(http.StatusCreated)
+	case req.Method == http.MethodPut && query.Get("comp") == "blocklist":
+		list := struct {
+			Latest []string `xml:"Latest"`
+		}{}
+		if err := xml.Unmarshal(body, &list); err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var data []byte
+		for _, id := range list.Latest {
+			data = append(data, f.blocks[blob+"/"+id]...)
+		}
+		f.blobs[blob] = data
+		rw.WriteHeader(http.StatusCreated)
+	case req.Method == http.MethodGet && query.Get("comp") == "list":
+		var names []string
+		for name := range f.blobs {
+			if strings.HasPrefix(name, query.Get("prefix")) {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		fmt.Fprint(rw, `<?xml version="1.0" encoding="utf-8"?><EnumerationResults><Blobs>`)
+		for _, name := range names {
+			fmt.Fprintf(rw, `<Blob><Name>%s</Name><Properties><Last-Modified>%s</Last-Modified><Content-Length>%d</Content-Length></Properties></Blob>`,
+				name, time.Now().UTC().Format(http.TimeFormat), len(f.blobs[name]))
+		}
+		fmt.Fprint(rw, `</Blobs><NextMarker /></EnumerationResults>`)
+	case req.Method == http.MethodGet:
+		data, ok := f.blobs[blob]
+		if !ok {
+			notFound()
+			return
+		}
+		rw.Write(data)
+	case req.Method == http.MethodDelete:
+		if _, ok := f.blobs[blob]; !ok {
+			notFound()
+			return
+		}
+		delete(f.blobs, blob)
+		rw.WriteHeader(http.StatusAccepted)
+	default:
+		rw.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func TestAzure(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(&fakeBlobService{blocks: map[string][]byte{}, blobs: map[string][]byte{}})
+	defer server.Close()
+
+	client, err := azurestorage.NewClient("account", base64.StdEncoding.EncodeToString([]byte("key")), "example.com", azurestorage.DefaultAPIVersion, false)
+	require.NoError(t, err)
+	client.HTTPClient = &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+				return net.Dial(network, server.Listener.Addr().String())
+			},
+		},
+	}
+	target := newAzureTarget(client, "snapshots", "cluster")
+
+	require.NoError(t, target.Upload(ctx, "snapshot-1", strings.NewReader("data"), 4))
+	objects, err := target.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+	assert.Equal(t, "snapshot-1", objects[0].Name)
+	assert.Equal(t, int64(4), objects[0].Size)
+	assert.False(t, objects[0].LastModified.IsZero())
+
+	rc, err := target.Download(ctx, "snapshot-1")
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(rc)
+	rc.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+
+	_, err = target.Download(ctx, "missing")
+	assert.True(t, errors.Is(err, ErrNotFound), "expected ErrNotFound, got %v", err)
+
+	require.NoError(t, target.Delete(ctx, "snapshot-1"))
+	assert.True(t, errors.Is(target.Delete(ctx, "snapshot-1"), ErrNotFound))
+
+	_, err = New(ctx, &Config{Type: TypeAzure, Bucket: "snapshots", Credentials: map[string]string{CredentialAccountName: "account"}})
+	assert.Error(t, err, "expected an account key or sas token to be required")
+}
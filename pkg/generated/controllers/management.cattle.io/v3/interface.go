@@ -32,6 +32,7 @@ func init() {
 type Interface interface {
 	APIService() APIServiceController
 	ActiveDirectoryProvider() ActiveDirectoryProviderController
+	AlertSilence() AlertSilenceController
 	AuthConfig() AuthConfigController
 	AuthProvider() AuthProviderController
 	AuthToken() AuthTokenController
@@ -125,6 +126,9 @@ func (c *version) APIService() APIServiceController {
 func (c *version) ActiveDirectoryProvider() ActiveDirectoryProviderController {
 	return NewActiveDirectoryProviderController(schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "ActiveDirectoryProvider"}, "activedirectoryproviders", false, c.controllerFactory)
 }
+func (c *version) AlertSilence() AlertSilenceController {
+	return NewAlertSilenceController(schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "AlertSilence"}, "alertsilences", true, c.controllerFactory)
+}
 func (c *version) AuthConfig() AuthConfigController {
 	return NewAuthConfigController(schema.GroupVersionKind{Group: "management.cattle.io", Version: "v3", Kind: "AuthConfig"}, "authconfigs", false, c.controllerFactory)
 }
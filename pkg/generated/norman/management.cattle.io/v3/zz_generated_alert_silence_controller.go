@@ -0,0 +1,326 @@
+package v3
+
+import (
+	"context"
+	"time"
+
+	"github.com/rancher/norman/controller"
+	"github.com/rancher/norman/objectclient"
+	"github.com/rancher/norman/resource"
+	"github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+var (
+	AlertSilenceGroupVersionKind = schema.GroupVersionKind{
+		Version: Version,
+		Group:   GroupName,
+		Kind:    "AlertSilence",
+	}
+	AlertSilenceResource = metav1.APIResource{
+		Name:         "alertsilences",
+		SingularName: "alertsilence",
+		Namespaced:   true,
+
+		Kind: AlertSilenceGroupVersionKind.Kind,
+	}
+
+	AlertSilenceGroupVersionResource = schema.GroupVersionResource{
+		Group:    GroupName,
+		Version:  Version,
+		Resource: "alertsilences",
+	}
+)
+
+func init() {
+	resource.Put(AlertSilenceGroupVersionResource)
+}
+
+// Deprecated use v3.AlertSilence instead
+type AlertSilence = v3.AlertSilence
+
+func NewAlertSilence(namespace, name string, obj v3.AlertSilence) *v3.AlertSilence {
+	obj.APIVersion, obj.Kind = AlertSilenceGroupVersionKind.ToAPIVersionAndKind()
+	obj.Name = name
+	obj.Namespace = namespace
+	return &obj
+}
+
+type AlertSilenceHandlerFunc func(key string, obj *v3.AlertSilence) (runtime.Object, error)
+
+type AlertSilenceChangeHandlerFunc func(obj *v3.AlertSilence) (runtime.Object, error)
+
+type AlertSilenceLister interface {
+	List(namespace string, selector labels.Selector) (ret []*v3.AlertSilence, err error)
+	Get(namespace, name string) (*v3.AlertSilence, error)
+}
+
+type AlertSilenceController interface {
+	Generic() controller.GenericController
+	Informer() cache.SharedIndexInformer
+	Lister() AlertSilenceLister
+	AddHandler(ctx context.Context, name string, handler AlertSilenceHandlerFunc)
+	AddFeatureHandler(ctx context.Context, enabled func() bool, name string, sync AlertSilenceHandlerFunc)
+	AddClusterScopedHandler(ctx context.Context, name, clusterName string, handler AlertSilenceHandlerFunc)
+	AddClusterScopedFeatureHandler(ctx context.Context, enabled func() bool, name, clusterName string, handler AlertSilenceHandlerFunc)
+	Enqueue(namespace, name string)
+	EnqueueAfter(namespace, name string, after time.Duration)
+}
+
+type AlertSilenceInterface interface {
+	ObjectClient() *objectclient.ObjectClient
+	Create(*v3.AlertSilence) (*v3.AlertSilence, error)
+	GetNamespaced(namespace, name string, opts metav1.GetOptions) (*v3.AlertSilence, error)
+	Get(name string, opts metav1.GetOptions) (*v3.AlertSilence, error)
+	Update(*v3.AlertSilence) (*v3.AlertSilence, error)
+	Delete(name string, options *metav1.DeleteOptions) error
+	DeleteNamespaced(namespace, name string, options *metav1.DeleteOptions) error
+	List(opts metav1.ListOptions) (*v3.AlertSilenceList, error)
+	ListNamespaced(namespace string, opts metav1.ListOptions) (*v3.AlertSilenceList, error)
+	Watch(opts metav1.ListOptions) (watch.Interface, error)
+	DeleteCollection(deleteOpts *metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Controller() AlertSilenceController
+	AddHandler(ctx context.Context, name string, sync AlertSilenceHandlerFunc)
+	AddFeatureHandler(ctx context.Context, enabled func() bool, name string, sync AlertSilenceHandlerFunc)
+	AddLifecycle(ctx context.Context, name string, lifecycle AlertSilenceLifecycle)
+	AddFeatureLifecycle(ctx context.Context, enabled func() bool, name string, lifecycle AlertSilenceLifecycle)
+	AddClusterScopedHandler(ctx context.Context, name, clusterName string, sync AlertSilenceHandlerFunc)
+	AddClusterScopedFeatureHandler(ctx context.Context, enabled func() bool, name, clusterName string, sync AlertSilenceHandlerFunc)
+	AddClusterScopedLifecycle(ctx context.Context, name, clusterName string, lifecycle AlertSilenceLifecycle)
+	AddClusterScopedFeatureLifecycle(ctx context.Context, enabled func() bool, name, clusterName string, lifecycle AlertSilenceLifecycle)
+}
+
+type alertSilenceLister struct {
+	ns         string
+	controller *alertSilenceController
+}
+
+func (l *alertSilenceLister) List(namespace string, selector labels.Selector) (ret []*v3.AlertSilence, err error) {
+	if namespace == "" {
+		namespace = l.ns
+	}
+	err = cache.ListAllByNamespace(l.controller.Informer().GetIndexer(), namespace, selector, func(obj interface{}) {
+		ret = append(ret, obj.(*v3.AlertSilence))
+	})
+	return
+}
+
+func (l *alertSilenceLister) Get(namespace, name string) (*v3.AlertSilence, error) {
+	var key string
+	if namespace != "" {
+		key = namespace + "/" + name
+	} else {
+		key = name
+	}
+	obj, exists, err := l.controller.Informer().GetIndexer().GetByKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(schema.GroupResource{
+			Group:    AlertSilenceGroupVersionKind.Group,
+			Resource: AlertSilenceGroupVersionResource.Resource,
+		}, key)
+	}
+	return obj.(*v3.AlertSilence), nil
+}
+
+type alertSilenceController struct {
+	ns string
+	controller.GenericController
+}
+
+func (c *alertSilenceController) Generic() controller.GenericController {
+	return c.GenericController
+}
+
+func (c *alertSilenceController) Lister() AlertSilenceLister {
+	return &alertSilenceLister{
+		ns:         c.ns,
+		controller: c,
+	}
+}
+
+func (c *alertSilenceController) AddHandler(ctx context.Context, name string, handler AlertSilenceHandlerFunc) {
+	c.GenericController.AddHandler(ctx, name, func(key string, obj interface{}) (interface{}, error) {
+		if obj == nil {
+			return handler(key, nil)
+		} else if v, ok := obj.(*v3.AlertSilence); ok {
+			return handler(key, v)
+		} else {
+			return nil, nil
+		}
+	})
+}
+
+func (c *alertSilenceController) AddFeatureHandler(ctx context.Context, enabled func() bool, name string, handler AlertSilenceHandlerFunc) {
+	c.GenericController.AddHandler(ctx, name, func(key string, obj interface{}) (interface{}, error) {
+		if !enabled() {
+			return nil, nil
+		} else if obj == nil {
+			return handler(key, nil)
+		} else if v, ok := obj.(*v3.AlertSilence); ok {
+			return handler(key, v)
+		} else {
+			return nil, nil
+		}
+	})
+}
+
+func (c *alertSilenceController) AddClusterScopedHandler(ctx context.Context, name, cluster string, handler AlertSilenceHandlerFunc) {
+	c.GenericController.AddHandler(ctx, name, func(key string, obj interface{}) (interface{}, error) {
+		if obj == nil {
+			return handler(key, nil)
+		} else if v, ok := obj.(*v3.AlertSilence); ok && controller.ObjectInCluster(cluster, obj) {
+			return handler(key, v)
+		} else {
+			return nil, nil
+		}
+	})
+}
+
+func (c *alertSilenceController) AddClusterScopedFeatureHandler(ctx context.Context, enabled func() bool, name, cluster string, handler AlertSilenceHandlerFunc) {
+	c.GenericController.AddHandler(ctx, name, func(key string, obj interface{}) (interface{}, error) {
+		if !enabled() {
+			return nil, nil
+		} else if obj == nil {
+			return handler(key, nil)
+		} else if v, ok := obj.(*v3.AlertSilence); ok && controller.ObjectInCluster(cluster, obj) {
+			return handler(key, v)
+		} else {
+			return nil, nil
+		}
+	})
+}
+
+type alertSilenceFactory struct {
+}
+
+func (c alertSilenceFactory) Object() runtime.Object {
+	return &v3.AlertSilence{}
+}
+
+func (c alertSilenceFactory) List() runtime.Object {
+	return &v3.AlertSilenceList{}
+}
+
+func (s *alertSilenceClient) Controller() AlertSilenceController {
+	genericController := controller.NewGenericController(s.ns, AlertSilenceGroupVersionKind.Kind+"Controller",
+		s.client.controllerFactory.ForResourceKind(AlertSilenceGroupVersionResource, AlertSilenceGroupVersionKind.Kind, true))
+
+	return &alertSilenceController{
+		ns:                s.ns,
+		GenericController: genericController,
+	}
+}
+
+type alertSilenceClient struct {
+	client       *Client
+	ns           string
+	objectClient *objectclient.ObjectClient
+	controller   AlertSilenceController
+}
+
+func (s *alertSilenceClient) ObjectClient() *objectclient.ObjectClient {
+	return s.objectClient
+}
+
+func (s *alertSilenceClient) Create(o *v3.AlertSilence) (*v3.AlertSilence, error) {
+	obj, err := s.objectClient.Create(o)
+	return obj.(*v3.AlertSilence), err
+}
+
+func (s *alertSilenceClient) Get(name string, opts metav1.GetOptions) (*v3.AlertSilence, error) {
+	obj, err := s.objectClient.Get(name, opts)
+	return obj.(*v3.AlertSilence), err
+}
+
+func (s *alertSilenceClient) GetNamespaced(namespace, name string, opts metav1.GetOptions) (*v3.AlertSilence, error) {
+	obj, err := s.objectClient.GetNamespaced(namespace, name, opts)
+	return obj.(*v3.AlertSilence), err
+}
+
+func (s *alertSilenceClient) Update(o *v3.AlertSilence) (*v3.AlertSilence, error) {
+	obj, err := s.objectClient.Update(o.Name, o)
+	return obj.(*v3.AlertSilence), err
+}
+
+func (s *alertSilenceClient) UpdateStatus(o *v3.AlertSilence) (*v3.AlertSilence, error) {
+	obj, err := s.objectClient.UpdateStatus(o.Name, o)
+	return obj.(*v3.AlertSilence), err
+}
+
+func (s *alertSilenceClient) Delete(name string, options *metav1.DeleteOptions) error {
+	return s.objectClient.Delete(name, options)
+}
+
+func (s *alertSilenceClient) DeleteNamespaced(namespace, name string, options *metav1.DeleteOptions) error {
+	return s.objectClient.DeleteNamespaced(namespace, name, options)
+}
+
+func (s *alertSilenceClient) List(opts metav1.ListOptions) (*v3.AlertSilenceList, error) {
+	obj, err := s.objectClient.List(opts)
+	return obj.(*v3.AlertSilenceList), err
+}
+
+func (s *alertSilenceClient) ListNamespaced(namespace string, opts metav1.ListOptions) (*v3.AlertSilenceList, error) {
+	obj, err := s.objectClient.ListNamespaced(namespace, opts)
+	return obj.(*v3.AlertSilenceList), err
+}
+
+func (s *alertSilenceClient) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+	return s.objectClient.Watch(opts)
+}
+
+// Patch applies the patch and returns the patched deployment.
+func (s *alertSilenceClient) Patch(o *v3.AlertSilence, patchType types.PatchType, data []byte, subresources ...string) (*v3.AlertSilence, error) {
+	obj, err := s.objectClient.Patch(o.Name, o, patchType, data, subresources...)
+	return obj.(*v3.AlertSilence), err
+}
+
+func (s *alertSilenceClient) DeleteCollection(deleteOpts *metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	return s.objectClient.DeleteCollection(deleteOpts, listOpts)
+}
+
+func (s *alertSilenceClient) AddHandler(ctx context.Context, name string, sync AlertSilenceHandlerFunc) {
+	s.Controller().AddHandler(ctx, name, sync)
+}
+
+func (s *alertSilenceClient) AddFeatureHandler(ctx context.Context, enabled func() bool, name string, sync AlertSilenceHandlerFunc) {
+	s.Controller().AddFeatureHandler(ctx, enabled, name, sync)
+}
+
+func (s *alertSilenceClient) AddLifecycle(ctx context.Context, name string, lifecycle AlertSilenceLifecycle) {
+	sync := NewAlertSilenceLifecycleAdapter(name, false, s, lifecycle)
+	s.Controller().AddHandler(ctx, name, sync)
+}
+
+func (s *alertSilenceClient) AddFeatureLifecycle(ctx context.Context, enabled func() bool, name string, lifecycle AlertSilenceLifecycle) {
+	sync := NewAlertSilenceLifecycleAdapter(name, false, s, lifecycle)
+	s.Controller().AddFeatureHandler(ctx, enabled, name, sync)
+}
+
+func (s *alertSilenceClient) AddClusterScopedHandler(ctx context.Context, name, clusterName string, sync AlertSilenceHandlerFunc) {
+	s.Controller().AddClusterScopedHandler(ctx, name, clusterName, sync)
+}
+
+func (s *alertSilenceClient) AddClusterScopedFeatureHandler(ctx context.Context, enabled func() bool, name, clusterName string, sync AlertSilenceHandlerFunc) {
+	s.Controller().AddClusterScopedFeatureHandler(ctx, enabled, name, clusterName, sync)
+}
+
+func (s *alertSilenceClient) AddClusterScopedLifecycle(ctx context.Context, name, clusterName string, lifecycle AlertSilenceLifecycle) {
+	sync := NewAlertSilenceLifecycleAdapter(name+"_"+clusterName, true, s, lifecycle)
+	s.Controller().AddClusterScopedHandler(ctx, name, clusterName, sync)
+}
+
+func (s *alertSilenceClient) AddClusterScopedFeatureLifecycle(ctx context.Context, enabled func() bool, name, clusterName string, lifecycle AlertSilenceLifecycle) {
+	sync := NewAlertSilenceLifecycleAdapter(name+"_"+clusterName, true, s, lifecycle)
+	s.Controller().AddClusterScopedFeatureHandler(ctx, enabled, name, clusterName, sync)
+}
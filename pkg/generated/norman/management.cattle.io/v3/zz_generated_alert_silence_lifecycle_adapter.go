@@ -0,0 +1,67 @@
+package v3
+
+import (
+	"github.com/rancher/norman/lifecycle"
+	"github.com/rancher/norman/resource"
+	"github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type AlertSilenceLifecycle interface {
+	Create(obj *v3.AlertSilence) (runtime.Object, error)
+	Remove(obj *v3.AlertSilence) (runtime.Object, error)
+	Updated(obj *v3.AlertSilence) (runtime.Object, error)
+}
+
+type alertSilenceLifecycleAdapter struct {
+	lifecycle AlertSilenceLifecycle
+}
+
+func (w *alertSilenceLifecycleAdapter) HasCreate() bool {
+	o, ok := w.lifecycle.(lifecycle.ObjectLifecycleCondition)
+	return !ok || o.HasCreate()
+}
+
+func (w *alertSilenceLifecycleAdapter) HasFinalize() bool {
+	o, ok := w.lifecycle.(lifecycle.ObjectLifecycleCondition)
+	return !ok || o.HasFinalize()
+}
+
+func (w *alertSilenceLifecycleAdapter) Create(obj runtime.Object) (runtime.Object, error) {
+	o, err := w.lifecycle.Create(obj.(*v3.AlertSilence))
+	if o == nil {
+		return nil, err
+	}
+	return o, err
+}
+
+func (w *alertSilenceLifecycleAdapter) Finalize(obj runtime.Object) (runtime.Object, error) {
+	o, err := w.lifecycle.Remove(obj.(*v3.AlertSilence))
+	if o == nil {
+		return nil, err
+	}
+	return o, err
+}
+
+func (w *alertSilenceLifecycleAdapter) Updated(obj runtime.Object) (runtime.Object, error) {
+	o, err := w.lifecycle.Updated(obj.(*v3.AlertSilence))
+	if o == nil {
+		return nil, err
+	}
+	return o, err
+}
+
+func NewAlertSilenceLifecycleAdapter(name string, clusterScoped bool, client AlertSilenceInterface, l AlertSilenceLifecycle) AlertSilenceHandlerFunc {
+	if clusterScoped {
+		resource.PutClusterScoped(AlertSilenceGroupVersionResource)
+	}
+	adapter := &alertSilenceLifecycleAdapter{lifecycle: l}
+	syncFn := lifecycle.NewObjectLifecycleAdapter(name, clusterScoped, adapter, client.ObjectClient())
+	return func(key string, obj *v3.AlertSilence) (runtime.Object, error) {
+		newObj, err := syncFn(key, obj)
+		if o, ok := newObj.(runtime.Object); ok {
+			return o, err
+		}
+		return nil, err
+	}
+}
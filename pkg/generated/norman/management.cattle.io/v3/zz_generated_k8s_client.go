@@ -53,6 +53,7 @@ type Interface interface {
 	ProjectAlertGroupsGetter
 	ClusterAlertRulesGetter
 	ProjectAlertRulesGetter
+	AlertSilencesGetter
 	ComposeConfigsGetter
 	ProjectCatalogsGetter
 	ClusterCatalogsGetter
@@ -703,6 +704,20 @@ func (c *Client) ProjectAlertRules(namespace string) ProjectAlertRuleInterface {
 	}
 }
 
+type AlertSilencesGetter interface {
+	AlertSilences(namespace string) AlertSilenceInterface
+}
+
+func (c *Client) AlertSilences(namespace string) AlertSilenceInterface {
+	sharedClient := c.clientFactory.ForResourceKind(AlertSilenceGroupVersionResource, AlertSilenceGroupVersionKind.Kind, true)
+	objectClient := objectclient.NewObjectClient(namespace, sharedClient, &AlertSilenceResource, AlertSilenceGroupVersionKind, alertSilenceFactory{})
+	return &alertSilenceClient{
+		ns:           namespace,
+		client:       c,
+		objectClient: objectClient,
+	}
+}
+
 type ComposeConfigsGetter interface {
 	ComposeConfigs(namespace string) ComposeConfigInterface
 }
@@ -8,6 +8,7 @@
 	dto "github.com/prometheus/client_model/go"
 	v1 "github.com/rancher/rancher/pkg/generated/norman/core/v1"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/metrics/tunnel"
 	"github.com/rancher/rancher/pkg/settings"
 	rm "github.com/rancher/remotedialer/metrics"
 	"github.com/sirupsen/logrus"
@@ -52,6 +53,7 @@ func (gc *metricGarbageCollector) metricGarbageCollection() {
 			observedResourceNames[cluster.Name] = true
 		}
 	}
+	tunnel.PruneClusterAgents(observedResourceNames)
 	// Get Nodes
 	nodes, err := gc.nodeLister.List("", labels.Everything())
 	if err != nil {
@@ -84,7 +86,7 @@ func (gc *metricGarbageCollector) metricGarbageCollection() {
 
 	buildObservedLabelMaps(targetMetricsByNameForClientKey, "clientkey", observedLabelsMap)
 	buildObservedLabelMaps(targetMetricsByIPForPeer, "peer", observedLabelsMap)
-	buildObservedLabelMaps([]interface{}{clusterOwner}, "cluster", observedLabelsMap)
+	buildObservedLabelMaps(append([]interface{}{clusterOwner}, tunnel.ClusterCollectors()...), "cluster", observedLabelsMap)
 
 	removedCount := removeMetricsForDeletedResource(observedLabelsMap, observedResourceNames)
 
@@ -5,6 +5,7 @@
 	"encoding/json"
 	"errors"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -12,6 +13,7 @@
 	"github.com/rancher/norman/httperror"
 	"github.com/rancher/rancher/pkg/auth/util"
 	"github.com/rancher/rancher/pkg/clustermanager"
+	"github.com/rancher/rancher/pkg/metrics/tunnel"
 	"github.com/rancher/rancher/pkg/settings"
 	"github.com/rancher/rancher/pkg/types/config"
 	"github.com/rancher/wrangler/pkg/ticker"
@@ -35,6 +37,17 @@
 		},
 		[]string{"cluster", "owner"},
 	)
+
+	certificateExpiration = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: "cluster_manager",
+			Name:      "certificate_expiration_timestamp_seconds",
+			Help:      "Expiration date of a certificate of a cluster in seconds since epoch",
+		},
+		[]string{"cluster", "certificate"},
+	)
+	certificateExpirationLabels     = map[string]map[string]bool{}
+	certificateExpirationLabelsLock sync.Mutex
 )
 
 type metricsHandler struct {
@@ -222,6 +235,12 @@ func Register(ctx context.Context, scaledContext *config.ScaledContext) {
 	// Cluster Owner
 	prometheus.MustRegister(clusterOwner)
 
+	// Certificate Expiration
+	prometheus.MustRegister(certificateExpiration)
+
+	// Agent tunnels and cluster dialers
+	tunnel.Register()
+
 	gc := metricGarbageCollector{
 		clusterLister:  scaledContext.Management.Clusters("").Controller().Lister(),
 		nodeLister:     scaledContext.Management.Nodes("").Controller().Lister(),
@@ -254,3 +273,39 @@ func UnsetClusterOwner(id, clusterID string) {
 			}).Set(float64(0))
 	}
 }
+
+// SetCertificateExpirations replaces the certificate expiration dates reported for the cluster.
+func SetCertificateExpirations(clusterID string, expirations map[string]time.Time) {
+	if !prometheusMetrics {
+		return
+	}
+
+	certificateExpirationLabelsLock.Lock()
+	defer certificateExpirationLabelsLock.Unlock()
+
+	for certificate := range certificateExpirationLabels[clusterID] {
+		if _, ok := expirations[certificate]; !ok {
+			certificateExpiration.Delete(prometheus.Labels{"cluster": clusterID, "certificate": certificate})
+		}
+	}
+
+	certificates := map[string]bool{}
+	for certificate, date := range expirations {
+		certificateExpiration.With(
+			prometheus.Labels{
+				"cluster":     clusterID,
+				"certificate": certificate,
+			}).Set(float64(date.Unix()))
+		certificates[certificate] = true
+	}
+	certificateExpirationLabels[clusterID] = certificates
+}
+
+// UnsetCertificateExpirations removes the certificate expiration dates of a deleted cluster.
+func UnsetCertificateExpirations(clusterID string) {
+	SetCertificateExpirations(clusterID, nil)
+
+	certificateExpirationLabelsLock.Lock()
+	delete(certificateExpirationLabels, clusterID)
+	certificateExpirationLabelsLock.Unlock()
+}
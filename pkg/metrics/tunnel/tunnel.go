@@ -0,0 +1,200 @@
+/*
+Package tunnel holds the metrics of the agent tunnels and the dialers to the downstream clusters. It only depends on
+prometheus, so that the tunnel server and the dialers can report metrics without importing the rest of pkg/metrics.
+*/
+package tunnel
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// enabled is set to 1 once the metrics are registered, it is read by the dialers of all clusters
+	enabled int32
+
+	agentConnected = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: "cluster_manager",
+			Name:      "cluster_agent_connected",
+			Help:      "Whether the cluster agent of a cluster is connected and answers pings",
+		},
+		[]string{"cluster"},
+	)
+
+	agentConnects = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "cluster_manager",
+			Name:      "cluster_agent_connects_total",
+			Help:      "Total count of cluster agents becoming connected",
+		},
+		[]string{"cluster"},
+	)
+
+	agentDisconnects = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "cluster_manager",
+			Name:      "cluster_agent_disconnects_total",
+			Help:      "Total count of cluster agents becoming disconnected",
+		},
+		[]string{"cluster"},
+	)
+
+	agentPing = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: "cluster_manager",
+			Name:      "cluster_agent_ping_seconds",
+			Help:      "Round trip time of the last ping through the tunnel of a cluster agent",
+		},
+		[]string{"cluster"},
+	)
+
+	nodeAgentsConnected = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: "cluster_manager",
+			Name:      "node_agents_connected",
+			Help:      "Number of connected node agents of a cluster",
+		},
+		[]string{"cluster"},
+	)
+
+	dialerBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "cluster_manager",
+			Name:      "cluster_dialer_bytes_total",
+			Help:      "Total bytes transmitted and received through the dialer of a cluster",
+		},
+		[]string{"cluster", "direction"},
+	)
+
+	dialFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "cluster_manager",
+			Name:      "cluster_dial_failures_total",
+			Help:      "Total count of failed dials to a cluster",
+		},
+		[]string{"cluster", "reason"},
+	)
+
+	peers = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Subsystem: "tunnel_server",
+			Name:      "peers",
+			Help:      "Number of rancher server peers the tunnel server is connected to",
+		},
+	)
+
+	agentStates     = map[string]bool{}
+	agentStatesLock sync.Mutex
+)
+
+// Register registers the metrics and enables the metrics with labels.
+func Register() {
+	atomic.StoreInt32(&enabled, 1)
+	prometheus.MustRegister(agentConnected, agentConnects, agentDisconnects, agentPing, nodeAgentsConnected,
+		dialerBytes, dialFailures, peers)
+}
+
+func isEnabled() bool {
+	return atomic.LoadInt32(&enabled) == 1
+}
+
+// ClusterCollectors returns the collectors with a cluster label, whose metrics are removed with the cluster.
+func ClusterCollectors() []interface{} {
+	return []interface{}{
+		agentConnected, agentConnects, agentDisconnects, agentPing, nodeAgentsConnected, dialerBytes, dialFailures,
+	}
+}
+
+// ObserveClusterAgent records whether the cluster agent is connected and the round trip time of its ping, changes of
+// the connectivity are counted as connects and disconnects.
+func ObserveClusterAgent(cluster string, connected bool, rtt time.Duration) {
+	if !isEnabled() {
+		return
+	}
+
+	agentStatesLock.Lock()
+	previous, known := agentStates[cluster]
+	agentStates[cluster] = connected
+	agentStatesLock.Unlock()
+
+	if known && previous != connected {
+		if connected {
+			agentConnects.WithLabelValues(cluster).Inc()
+		} else {
+			agentDisconnects.WithLabelValues(cluster).Inc()
+		}
+	}
+	if connected {
+		agentConnected.WithLabelValues(cluster).Set(1)
+		agentPing.WithLabelValues(cluster).Set(rtt.Seconds())
+	} else {
+		agentConnected.WithLabelValues(cluster).Set(0)
+		agentPing.DeleteLabelValues(cluster)
+	}
+}
+
+// PruneClusterAgents forgets the connectivity of the cluster agents of the clusters that are not in existing, so that
+// the states of removed clusters are not kept and a cluster created again with the same name starts afresh.
+func PruneClusterAgents(existing map[string]bool) {
+	agentStatesLock.Lock()
+	defer agentStatesLock.Unlock()
+	for cluster := range agentStates {
+		if !existing[cluster] {
+			delete(agentStates, cluster)
+		}
+	}
+}
+
+// SetNodeAgentsConnected records the number of connected node agents of the cluster.
+func SetNodeAgentsConnected(cluster string, count int) {
+	if isEnabled() {
+		nodeAgentsConnected.WithLabelValues(cluster).Set(float64(count))
+	}
+}
+
+// CountDialFailure counts a failed dial to the cluster.
+func CountDialFailure(cluster, reason string) {
+	if isEnabled() {
+		dialFailures.WithLabelValues(cluster, reason).Inc()
+	}
+}
+
+// SetPeers records the number of peers of the tunnel server.
+func SetPeers(count int) {
+	peers.Set(float64(count))
+}
+
+// CountingConn returns the connection to the cluster counting the bytes transmitted and received through it.
+func CountingConn(cluster string, conn net.Conn) net.Conn {
+	if !isEnabled() {
+		return conn
+	}
+	return &countingConn{
+		Conn:        conn,
+		transmitted: dialerBytes.WithLabelValues(cluster, "transmit"),
+		received:    dialerBytes.WithLabelValues(cluster, "receive"),
+	}
+}
+
+type countingConn struct {
+	net.Conn
+	transmitted prometheus.Counter
+	received    prometheus.Counter
+}
+
+func (c *countingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.received.Add(float64(n))
+	return n, err
+}
+
+func (c *countingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	c.transmitted.Add(float64(n))
+	return n, err
+}
@@ -0,0 +1,63 @@
+package notifiers
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	AlertStatusFiring   = "firing"
+	AlertStatusResolved = "resolved"
+
+	runbookURLKey = "runbook_url"
+)
+
+// Alert is the context of the alert a message is sent for, it is available to the templates of the notifiers and
+// is included in the structured payloads.
+type Alert struct {
+	Status      string            `json:"status,omitempty"`
+	Name        string            `json:"name,omitempty"`
+	Type        string            `json:"type,omitempty"`
+	Severity    string            `json:"severity,omitempty"`
+	ClusterID   string            `json:"clusterId,omitempty"`
+	ClusterName string            `json:"clusterName,omitempty"`
+	ProjectName string            `json:"projectName,omitempty"`
+	GroupID     string            `json:"groupId,omitempty"`
+	RuleID      string            `json:"ruleId,omitempty"`
+	RunbookURL  string            `json:"runbookUrl,omitempty"`
+	StartsAt    time.Time         `json:"startsAt,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Fingerprint identifies the alerts of alertmanager.
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// AlertFromLabels returns the context of an alert from the labels and annotations the alert watchers set on it.
+func AlertFromLabels(labels, annotations map[string]string) *Alert {
+	alert := &Alert{
+		Status:      AlertStatusFiring,
+		Name:        labels["alert_name"],
+		Type:        labels["alert_type"],
+		Severity:    labels["severity"],
+		ClusterName: labels["cluster_name"],
+		ProjectName: labels["project_name"],
+		GroupID:     labels["group_id"],
+		RuleID:      labels["rule_id"],
+		RunbookURL:  annotations[runbookURLKey],
+		Labels:      labels,
+		Annotations: annotations,
+	}
+	if alert.RunbookURL == "" {
+		alert.RunbookURL = labels[runbookURLKey]
+	}
+	// group ids are namespaced by the cluster, as in c-xxxxx:g-xxxxx
+	if i := strings.Index(alert.GroupID, ":"); i > 0 {
+		alert.ClusterID = alert.GroupID[:i]
+	}
+	return alert
+}
+
+// Resolved returns whether the alert is resolved.
+func (a *Alert) Resolved() bool {
+	return a.Status == AlertStatusResolved
+}
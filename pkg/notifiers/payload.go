@@ -0,0 +1,411 @@
+package notifiers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/prometheus/common/model"
+)
+
+const (
+	// WebhookPayloadVersion is the version of the JSON schema of the payload sent to webhooks for alerts.
+	WebhookPayloadVersion = "v1"
+
+	// slackHeaderMaxLength is the maximum length of the text of a header block.
+	slackHeaderMaxLength = 150
+	// opsgenieMessageMaxLength is the maximum length of the message of an Opsgenie alert.
+	opsgenieMessageMaxLength = 130
+	// telegramTextMaxLength is the maximum length of the text of a Telegram message.
+	telegramTextMaxLength = 4096
+)
+
+// WebhookPayload is the body sent to webhooks for alerts.
+type WebhookPayload struct {
+	Version string `json:"version"`
+	Title   string `json:"title,omitempty"`
+	Text    string `json:"text"`
+	Alert   *Alert `json:"alert,omitempty"`
+}
+
+// alertFacts returns the facts about the alert that are shown by the chat channels.
+func alertFacts(alert *Alert) [][2]string {
+	var facts [][2]string
+	for _, f := range [][2]string{
+		{"Status", alert.Status},
+		{"Severity", alert.Severity},
+		{"Cluster", alert.ClusterName},
+		{"Project", alert.ProjectName},
+		{"Alert", alert.Name},
+		{"Type", alert.Type},
+	} {
+		if f[1] != "" {
+			facts = append(facts, f)
+		}
+	}
+	if !alert.StartsAt.IsZero() {
+		facts = append(facts, [2]string{"Started", alert.StartsAt.UTC().Format(time.RFC3339)})
+	}
+	return facts
+}
+
+// truncate shortens the text to the maximum length, in bytes, without splitting a character.
+func truncate(text string, max int) string {
+	if len(text) <= max {
+		return text
+	}
+	text = text[:max-3]
+	for len(text) > 0 && !utf8.ValidString(text) {
+		text = text[:len(text)-1]
+	}
+	return text + "..."
+}
+
+// severityColor returns the color of the attachments of Slack compatible channels for the severity.
+func severityColor(severity string) string {
+	switch severity {
+	case "critical":
+		return "#d9534f"
+	case "warning":
+		return "#f0ad4e"
+	}
+	return "#5cb85c"
+}
+
+func slackPayload(channel string, msg *Message) ([]byte, error) {
+	req := map[string]interface{}{
+		"text":    msg.Content,
+		"channel": channel,
+	}
+	if msg.Alert == nil && msg.Title == "" {
+		return json.Marshal(req)
+	}
+
+	var blocks []interface{}
+	if msg.Title != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "header",
+			"text": map[string]interface{}{"type": "plain_text", "text": truncate(msg.Title, slackHeaderMaxLength)},
+		})
+		req["text"] = msg.Title + "\n" + msg.Content
+	}
+	if msg.Content != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "section",
+			"text": map[string]interface{}{"type": "mrkdwn", "text": msg.Content},
+		})
+	}
+	if msg.Alert != nil {
+		var fields []interface{}
+		for _, f := range alertFacts(msg.Alert) {
+			fields = append(fields, map[string]interface{}{"type": "mrkdwn", "text": fmt.Sprintf("*%s:*\n%s", f[0], f[1])})
+		}
+		if len(fields) > 0 {
+			// sections hold at most 10 fields
+			if len(fields) > 10 {
+				fields = fields[:10]
+			}
+			blocks = append(blocks, map[string]interface{}{"type": "section", "fields": fields})
+		}
+		if msg.Alert.RunbookURL != "" {
+			blocks = append(blocks, map[string]interface{}{
+				"type": "actions",
+				"elements": []interface{}{map[string]interface{}{
+					"type": "button",
+					"text": map[string]interface{}{"type": "plain_text", "text": "Runbook"},
+					"url":  msg.Alert.RunbookURL,
+				}},
+			})
+		}
+	}
+	req["blocks"] = blocks
+	return json.Marshal(req)
+}
+
+func msTeamsPayload(msg *Message) ([]byte, error) {
+	if msg.Alert == nil && msg.Title == "" {
+		return json.Marshal(map[string]interface{}{"text": msg.Content})
+	}
+
+	var body []interface{}
+	if msg.Title != "" {
+		body = append(body, map[string]interface{}{
+			"type":   "TextBlock",
+			"text":   msg.Title,
+			"size":   "Large",
+			"weight": "Bolder",
+			"wrap":   true,
+		})
+	}
+	if msg.Content != "" {
+		body = append(body, map[string]interface{}{
+			"type": "TextBlock",
+			"text": msg.Content,
+			"wrap": true,
+		})
+	}
+	var actions []interface{}
+	if msg.Alert != nil {
+		var facts []interface{}
+		for _, f := range alertFacts(msg.Alert) {
+			facts = append(facts, map[string]interface{}{"title": f[0], "value": f[1]})
+		}
+		if len(facts) > 0 {
+			body = append(body, map[string]interface{}{"type": "FactSet", "facts": facts})
+		}
+		if msg.Alert.RunbookURL != "" {
+			actions = append(actions, map[string]interface{}{
+				"type":  "Action.OpenUrl",
+				"title": "Runbook",
+				"url":   msg.Alert.RunbookURL,
+			})
+		}
+	}
+
+	card := map[string]interface{}{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.2",
+		"body":    body,
+	}
+	if len(actions) > 0 {
+		card["actions"] = actions
+	}
+	return json.Marshal(map[string]interface{}{
+		"type": "message",
+		"attachments": []interface{}{map[string]interface{}{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content":     card,
+		}},
+	})
+}
+
+func dingtalkPayload(msg *Message) ([]byte, error) {
+	if msg.Alert == nil && msg.Title == "" {
+		return json.Marshal(map[string]interface{}{
+			"msgtype": "text",
+			"text":    map[string]interface{}{"content": msg.Content},
+			"at":      map[string]interface{}{"isAtAll": true},
+		})
+	}
+
+	title := msg.Title
+	if title == "" {
+		title = msg.Alert.Name
+	}
+	lines := []string{"### " + title, msg.Content}
+	if msg.Alert != nil {
+		for _, f := range alertFacts(msg.Alert) {
+			lines = append(lines, fmt.Sprintf("- **%s:** %s", f[0], f[1]))
+		}
+		if msg.Alert.RunbookURL != "" {
+			lines = append(lines, fmt.Sprintf("[Runbook](%s)", msg.Alert.RunbookURL))
+		}
+	}
+	return json.Marshal(map[string]interface{}{
+		"msgtype":  "markdown",
+		"markdown": map[string]interface{}{"title": title, "text": strings.Join(lines, "\n\n")},
+		"at":       map[string]interface{}{"isAtAll": true},
+	})
+}
+
+func webhookPayload(msg *Message) ([]byte, error) {
+	if msg.Alert == nil {
+		// the format of the test messages sent before the payload was versioned
+		return json.Marshal(model.Alerts{
+			&model.Alert{
+				Labels: map[model.LabelName]model.LabelValue{
+					model.LabelName("test_msg"): model.LabelValue(msg.Content),
+				},
+			},
+		})
+	}
+	return json.Marshal(WebhookPayload{
+		Version: WebhookPayloadVersion,
+		Title:   msg.Title,
+		Text:    msg.Content,
+		Alert:   msg.Alert,
+	})
+}
+
+func pagerdutyEvent(key string, msg *Message) *pagerDutyEvent {
+	pd := &pagerDutyEvent{
+		RoutingKey:  key,
+		EventAction: "trigger",
+		Payload: pagerDutyEventPayload{
+			Summary:  msg.Content,
+			Source:   "rancher",
+			Severity: "info",
+			Group:    "Rancher alert testing",
+		},
+	}
+	if msg.Title != "" {
+		pd.Payload.Summary = msg.Title
+	}
+	if msg.Alert == nil {
+		return pd
+	}
+
+	pd.Payload.Severity = pagerdutySeverity(msg.Alert.Severity)
+	pd.Payload.Group = msg.Alert.ClusterName
+	pd.Payload.Class = msg.Alert.Type
+	if msg.Alert.RuleID != "" {
+		// resolving an alert requires the key of the event that triggered it
+		pd.DedupKey = msg.Alert.RuleID
+	}
+	if msg.Alert.Resolved() {
+		pd.EventAction = "resolve"
+	}
+	details := map[string]string{}
+	if msg.Title != "" {
+		details["text"] = msg.Content
+	}
+	for k, v := range msg.Alert.Labels {
+		details[k] = v
+	}
+	pd.Payload.CustomDetails = details
+	if msg.Alert.RunbookURL != "" {
+		pd.Links = []pagerDutyLink{{Href: msg.Alert.RunbookURL, Text: "Runbook"}}
+	}
+	return pd
+}
+
+// pagerdutySeverity maps the severity of an alert to one of the severities of PagerDuty.
+func pagerdutySeverity(severity string) string {
+	switch severity {
+	case "critical", "error", "warning", "info":
+		return severity
+	}
+	return "info"
+}
+
+func mattermostPayload(channel, username string, msg *Message) ([]byte, error) {
+	req := map[string]interface{}{
+		"text": msg.Content,
+	}
+	if channel != "" {
+		req["channel"] = channel
+	}
+	if username != "" {
+		req["username"] = username
+	}
+	if msg.Alert == nil && msg.Title == "" {
+		return json.Marshal(req)
+	}
+
+	// Mattermost incoming webhooks are Slack compatible, without the support for blocks
+	attachment := map[string]interface{}{
+		"fallback": strings.TrimSpace(msg.Title + "\n" + msg.Content),
+		"title":    msg.Title,
+		"text":     msg.Content,
+	}
+	if msg.Alert != nil {
+		var fields []interface{}
+		for _, f := range alertFacts(msg.Alert) {
+			fields = append(fields, map[string]interface{}{"short": true, "title": f[0], "value": f[1]})
+		}
+		attachment["fields"] = fields
+		attachment["color"] = severityColor(msg.Alert.Severity)
+		if msg.Alert.RunbookURL != "" {
+			attachment["title_link"] = msg.Alert.RunbookURL
+		}
+	}
+	req["text"] = ""
+	req["attachments"] = []interface{}{attachment}
+	return json.Marshal(req)
+}
+
+func googleChatPayload(msg *Message) ([]byte, error) {
+	var lines []string
+	if msg.Title != "" {
+		lines = append(lines, "*"+msg.Title+"*")
+	}
+	if msg.Content != "" {
+		lines = append(lines, msg.Content)
+	}
+	if msg.Alert != nil {
+		for _, f := range alertFacts(msg.Alert) {
+			lines = append(lines, fmt.Sprintf("*%s:* %s", f[0], f[1]))
+		}
+		if msg.Alert.RunbookURL != "" {
+			lines = append(lines, fmt.Sprintf("<%s|Runbook>", msg.Alert.RunbookURL))
+		}
+	}
+	return json.Marshal(map[string]interface{}{"text": strings.Join(lines, "\n")})
+}
+
+func telegramPayload(chatID string, msg *Message) ([]byte, error) {
+	var lines []string
+	if msg.Title != "" {
+		lines = append(lines, msg.Title, "")
+	}
+	if msg.Content != "" {
+		lines = append(lines, msg.Content)
+	}
+	if msg.Alert != nil {
+		lines = append(lines, "")
+		for _, f := range alertFacts(msg.Alert) {
+			lines = append(lines, fmt.Sprintf("%s: %s", f[0], f[1]))
+		}
+		if msg.Alert.RunbookURL != "" {
+			lines = append(lines, "Runbook: "+msg.Alert.RunbookURL)
+		}
+	}
+	return json.Marshal(map[string]interface{}{
+		"chat_id":                  chatID,
+		"text":                     truncate(strings.TrimSpace(strings.Join(lines, "\n")), telegramTextMaxLength),
+		"disable_web_page_preview": true,
+	})
+}
+
+func opsgeniePayload(team string, tags []string, msg *Message) ([]byte, error) {
+	message := msg.Title
+	if message == "" {
+		message = msg.Content
+	}
+	req := map[string]interface{}{
+		"message":     truncate(message, opsgenieMessageMaxLength),
+		"description": msg.Content,
+		"source":      "rancher",
+		"priority":    "P3",
+	}
+	if team != "" {
+		req["responders"] = []interface{}{map[string]interface{}{"type": "team", "name": team}}
+	}
+	if len(tags) > 0 {
+		req["tags"] = tags
+	}
+	if msg.Alert != nil {
+		req["priority"] = opsgeniePriority(msg.Alert.Severity)
+		if msg.Alert.RuleID != "" {
+			req["alias"] = msg.Alert.RuleID
+		}
+		details := map[string]string{}
+		for k, v := range msg.Alert.Labels {
+			details[k] = v
+		}
+		if msg.Alert.RunbookURL != "" {
+			details["runbook_url"] = msg.Alert.RunbookURL
+		}
+		req["details"] = details
+		if msg.Alert.ClusterName != "" {
+			req["entity"] = msg.Alert.ClusterName
+		}
+	}
+	return json.Marshal(req)
+}
+
+// opsgeniePriority maps the severity of an alert to a priority of Opsgenie.
+func opsgeniePriority(severity string) string {
+	switch severity {
+	case "critical":
+		return "P1"
+	case "warning":
+		return "P3"
+	case "info":
+		return "P5"
+	}
+	return "P3"
+}
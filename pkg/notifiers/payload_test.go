@@ -0,0 +1,144 @@
+package notifiers
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlackPayload(t *testing.T) {
+	data, err := slackPayload("#alerts", &Message{Content: "test"})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"text": "test", "channel": "#alerts"}`, string(data))
+
+	data, err = slackPayload("#alerts", &Message{Title: "High CPU", Content: "cpu is high", Alert: testAlert()})
+	assert.NoError(t, err)
+	var req struct {
+		Text   string                   `json:"text"`
+		Blocks []map[string]interface{} `json:"blocks"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &req))
+	assert.Equal(t, "High CPU\ncpu is high", req.Text)
+	assert.Len(t, req.Blocks, 4)
+	assert.Equal(t, "header", req.Blocks[0]["type"])
+	assert.Equal(t, "section", req.Blocks[1]["type"])
+	assert.Len(t, req.Blocks[2]["fields"], 6)
+	assert.Equal(t, "actions", req.Blocks[3]["type"])
+}
+
+func TestMSTeamsPayload(t *testing.T) {
+	data, err := msTeamsPayload(&Message{Content: `say "hi"`})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"text": "say \"hi\""}`, string(data))
+
+	data, err = msTeamsPayload(&Message{Title: "High CPU", Alert: testAlert()})
+	assert.NoError(t, err)
+	var req struct {
+		Attachments []struct {
+			ContentType string `json:"contentType"`
+			Content     struct {
+				Type    string                   `json:"type"`
+				Body    []map[string]interface{} `json:"body"`
+				Actions []map[string]interface{} `json:"actions"`
+			} `json:"content"`
+		} `json:"attachments"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &req))
+	assert.Len(t, req.Attachments, 1)
+	card := req.Attachments[0].Content
+	assert.Equal(t, "AdaptiveCard", card.Type)
+	assert.Len(t, card.Body, 2)
+	assert.Equal(t, "FactSet", card.Body[1]["type"])
+	assert.Equal(t, "https://runbooks.example.com/cpu", card.Actions[0]["url"])
+}
+
+func TestWebhookPayload(t *testing.T) {
+	data, err := webhookPayload(&Message{Content: "test"})
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"test_msg":"test"`)
+
+	data, err = webhookPayload(&Message{Title: "High CPU", Content: "cpu is high", Alert: testAlert()})
+	assert.NoError(t, err)
+	var payload WebhookPayload
+	assert.NoError(t, json.Unmarshal(data, &payload))
+	assert.Equal(t, WebhookPayloadVersion, payload.Version)
+	assert.Equal(t, "cpu is high", payload.Text)
+	assert.Equal(t, "c-abcde", payload.Alert.ClusterID)
+	assert.Equal(t, "critical", payload.Alert.Labels["severity"])
+}
+
+func TestPagerdutyEvent(t *testing.T) {
+	pd := pagerdutyEvent("key", &Message{Content: "test"})
+	assert.Equal(t, "info", pd.Payload.Severity)
+	assert.Equal(t, "test", pd.Payload.Summary)
+
+	alert := testAlert()
+	alert.Severity = "unknown"
+	alert.Status = AlertStatusResolved
+	pd = pagerdutyEvent("key", &Message{Title: "High CPU", Content: "cpu is high", Alert: alert})
+	assert.Equal(t, "resolve", pd.EventAction)
+	assert.Equal(t, "info", pd.Payload.Severity)
+	assert.Equal(t, "High CPU", pd.Payload.Summary)
+	assert.Equal(t, "cpu is high", pd.Payload.CustomDetails["text"])
+	assert.Equal(t, alert.RuleID, pd.DedupKey)
+}
+
+func TestMattermostPayload(t *testing.T) {
+	data, err := mattermostPayload("", "", &Message{Content: "test"})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"text": "test"}`, string(data))
+
+	data, err = mattermostPayload("alerts", "rancher", &Message{Title: "High CPU", Content: "cpu is high", Alert: testAlert()})
+	assert.NoError(t, err)
+	var req struct {
+		Channel     string                   `json:"channel"`
+		Username    string                   `json:"username"`
+		Attachments []map[string]interface{} `json:"attachments"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &req))
+	assert.Equal(t, "alerts", req.Channel)
+	assert.Equal(t, "rancher", req.Username)
+	assert.Len(t, req.Attachments, 1)
+	assert.Equal(t, "#d9534f", req.Attachments[0]["color"])
+	assert.Equal(t, "https://runbooks.example.com/cpu", req.Attachments[0]["title_link"])
+	assert.Len(t, req.Attachments[0]["fields"], 6)
+}
+
+func TestGoogleChatPayload(t *testing.T) {
+	data, err := googleChatPayload(&Message{Title: "High CPU", Content: "cpu is high", Alert: testAlert()})
+	assert.NoError(t, err)
+	var req struct {
+		Text string `json:"text"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &req))
+	assert.Contains(t, req.Text, "*High CPU*\ncpu is high\n*Status:* firing")
+	assert.Contains(t, req.Text, "<https://runbooks.example.com/cpu|Runbook>")
+}
+
+func TestTelegramPayload(t *testing.T) {
+	data, err := telegramPayload("-1001", &Message{Content: strings.Repeat("é", telegramTextMaxLength)})
+	assert.NoError(t, err)
+	var req struct {
+		ChatID string `json:"chat_id"`
+		Text   string `json:"text"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &req))
+	assert.Equal(t, "-1001", req.ChatID)
+	assert.True(t, len(req.Text) <= telegramTextMaxLength)
+	assert.True(t, utf8.ValidString(req.Text))
+}
+
+func TestOpsgeniePayload(t *testing.T) {
+	data, err := opsgeniePayload("ops", []string{"rancher"}, &Message{Title: "High CPU", Content: "cpu is high", Alert: testAlert()})
+	assert.NoError(t, err)
+	var req map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &req))
+	assert.Equal(t, "High CPU", req["message"])
+	assert.Equal(t, "P1", req["priority"])
+	assert.Equal(t, "c-abcde:g-12345_r-1", req["alias"])
+	assert.Equal(t, "prod", req["entity"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"type": "team", "name": "ops"}}, req["responders"])
+}
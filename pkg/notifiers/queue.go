@@ -0,0 +1,382 @@
+package notifiers
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rancher/norman/types/slice"
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
+	corecontrollers "github.com/rancher/wrangler/pkg/generated/controllers/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// QueueLabel labels the config maps holding the delivery queues, its value is the name of the notifier.
+	QueueLabel = "cattle.io/notifier-queue"
+	queueKey   = "queue"
+
+	// MaxAttempts is the number of attempts to deliver a message before it is moved to the dead letters.
+	MaxAttempts = 8
+	// maxPending and maxDeadLetters bound the size of the queue, so that it fits in a config map.
+	maxPending     = 200
+	maxDeadLetters = 50
+	maxFiring      = 200
+
+	retryBase = 30 * time.Second
+	retryMax  = 30 * time.Minute
+)
+
+// Delivery is a message waiting to be delivered by a notifier.
+type Delivery struct {
+	ID          string    `json:"id"`
+	Fingerprint string    `json:"fingerprint"`
+	Recipient   string    `json:"recipient,omitempty"`
+	Title       string    `json:"title,omitempty"`
+	Content     string    `json:"content,omitempty"`
+	Alert       *Alert    `json:"alert,omitempty"`
+	Attempts    int       `json:"attempts,omitempty"`
+	Created     time.Time `json:"created"`
+	NextAttempt time.Time `json:"nextAttempt"`
+	LastError   string    `json:"lastError,omitempty"`
+}
+
+// Message returns the message of the delivery.
+func (d *Delivery) Message() *Message {
+	return &Message{
+		Title:   d.Title,
+		Content: d.Content,
+		Alert:   d.Alert,
+	}
+}
+
+// Queue is the delivery queue of a notifier.
+type Queue struct {
+	Pending     []*Delivery `json:"pending,omitempty"`
+	DeadLetters []*Delivery `json:"deadLetters,omitempty"`
+	// Delivered holds when the messages were last delivered by their fingerprint, to drop duplicates.
+	Delivered     map[string]time.Time `json:"delivered,omitempty"`
+	LastDelivered time.Time            `json:"lastDelivered,omitempty"`
+	LastFailed    time.Time            `json:"lastFailed,omitempty"`
+	LastError     string               `json:"lastError,omitempty"`
+	// Firing holds the alerts of alertmanager queued as firing, by recipient and alert fingerprint, to queue them
+	// again every repeat interval and as resolved once they are no longer active.
+	Firing map[string]*FiringAlert `json:"firing,omitempty"`
+}
+
+// FiringAlert is an alert of alertmanager queued as firing for a recipient.
+type FiringAlert struct {
+	Recipient string    `json:"recipient,omitempty"`
+	Alert     *Alert    `json:"alert"`
+	Queued    time.Time `json:"queued"`
+}
+
+// AlertRoute routes the active alerts of an alert group to a recipient of the notifier.
+type AlertRoute struct {
+	GroupID        string
+	Recipient      string
+	RepeatInterval time.Duration
+	Alerts         []*Alert
+}
+
+// QueueConfigMapName returns the name of the config map holding the delivery queue of the notifier, in the namespace
+// of the notifier.
+func QueueConfigMapName(notifierName string) string {
+	return "notifier-queue-" + notifierName
+}
+
+// LoadQueue returns the delivery queue stored in the config map.
+func LoadQueue(cm *corev1.ConfigMap) (*Queue, error) {
+	q := &Queue{}
+	if data := cm.Data[queueKey]; data != "" {
+		if err := json.Unmarshal([]byte(data), q); err != nil {
+			return nil, fmt.Errorf("invalid delivery queue in config map %s/%s: %v", cm.Namespace, cm.Name, err)
+		}
+	}
+	if q.Delivered == nil {
+		q.Delivered = map[string]time.Time{}
+	}
+	if q.Firing == nil {
+		q.Firing = map[string]*FiringAlert{}
+	}
+	return q, nil
+}
+
+// Store stores the delivery queue in the config map.
+func (q *Queue) Store(cm *corev1.ConfigMap) error {
+	data, err := json.Marshal(q)
+	if err != nil {
+		return err
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[queueKey] = string(data)
+	return nil
+}
+
+// Fingerprint identifies a message to a recipient, alerts are identified by their status and alertmanager
+// fingerprint, or by their rule, status and labels, so that every firing of an alert is delivered once.
+func Fingerprint(recipient string, msg *Message) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n", recipient)
+	if msg.Alert == nil {
+		fmt.Fprintf(h, "%s\n%s", msg.Title, msg.Content)
+	} else if msg.Alert.Fingerprint != "" {
+		fmt.Fprintf(h, "%s\n%s\n", msg.Alert.Status, msg.Alert.Fingerprint)
+	} else {
+		fmt.Fprintf(h, "%s\n%s\n%s\n", msg.Alert.Status, msg.Alert.GroupID, msg.Alert.RuleID)
+		keys := make([]string, 0, len(msg.Alert.Labels))
+		for k := range msg.Alert.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(h, "%s=%s\n", k, msg.Alert.Labels[k])
+		}
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))[:32]
+}
+
+// Add adds the message to the queue, unless the same message is pending or was delivered within the dedup window. It
+// returns whether the message was added.
+func (q *Queue) Add(recipient string, msg *Message, now time.Time, dedupWindow time.Duration) bool {
+	fingerprint := Fingerprint(recipient, msg)
+	for _, d := range q.Pending {
+		if d.Fingerprint == fingerprint {
+			return false
+		}
+	}
+	if delivered, ok := q.Delivered[fingerprint]; ok && now.Sub(delivered) < dedupWindow {
+		return false
+	}
+
+	if len(q.Pending) >= maxPending {
+		// the oldest message is given up on, so that the queue keeps up with the new messages
+		q.deadLetter(q.Pending[0], "dropped, the delivery queue is full")
+		q.Pending = q.Pending[1:]
+	}
+	q.Pending = append(q.Pending, &Delivery{
+		ID:          now.UTC().Format("20060102150405") + "-" + rand.String(5),
+		Fingerprint: fingerprint,
+		Recipient:   recipient,
+		Title:       msg.Title,
+		Content:     msg.Content,
+		Alert:       msg.Alert,
+		Created:     now,
+		NextAttempt: now,
+	})
+	return true
+}
+
+// SyncAlerts queues the messages of the active alerts of alertmanager routed to the recipients of the notifier. An
+// alert is queued when it starts firing and again every repeat interval of its route while it is active, and it is
+// queued as resolved once it is no longer active if sendResolved is set. Alerts of routes that no longer exist are
+// forgotten. It returns whether the queue changed.
+func (q *Queue) SyncAlerts(routes []AlertRoute, sendResolved bool, message func(*Alert) *Message, now time.Time) bool {
+	changed := false
+	routed := map[string]bool{}
+	active := map[string]bool{}
+	for _, route := range routes {
+		routed[route.GroupID+"/"+route.Recipient] = true
+		for _, alert := range route.Alerts {
+			key := firingKey(route.Recipient, alert)
+			active[key] = true
+			if firing, ok := q.Firing[key]; ok && now.Sub(firing.Queued) < route.RepeatInterval {
+				continue
+			}
+			q.Add(route.Recipient, message(alert), now, 0)
+			q.addFiring(key, &FiringAlert{Recipient: route.Recipient, Alert: alert, Queued: now})
+			changed = true
+		}
+	}
+
+	for key, firing := range q.Firing {
+		if active[key] {
+			continue
+		}
+		delete(q.Firing, key)
+		changed = true
+		if sendResolved && routed[firing.Alert.GroupID+"/"+firing.Recipient] {
+			resolved := *firing.Alert
+			resolved.Status = AlertStatusResolved
+			q.Add(firing.Recipient, message(&resolved), now, 0)
+		}
+	}
+	return changed
+}
+
+func firingKey(recipient string, alert *Alert) string {
+	return recipient + "/" + alert.Fingerprint
+}
+
+func (q *Queue) addFiring(key string, firing *FiringAlert) {
+	if _, ok := q.Firing[key]; !ok && len(q.Firing) >= maxFiring {
+		// the alert queued the longest ago is forgotten, so that the queue fits in a config map
+		oldest := ""
+		for k, f := range q.Firing {
+			if oldest == "" || f.Queued.Before(q.Firing[oldest].Queued) {
+				oldest = k
+			}
+		}
+		delete(q.Firing, oldest)
+	}
+	q.Firing[key] = firing
+}
+
+// Due returns the pending deliveries that are due to be attempted, oldest first.
+func (q *Queue) Due(now time.Time) []*Delivery {
+	var due []*Delivery
+	for _, d := range q.Pending {
+		if !d.NextAttempt.After(now) {
+			due = append(due, d)
+		}
+	}
+	return due
+}
+
+// Done records the result of an attempt of the delivery. Failed deliveries are retried with an exponential backoff
+// until they run out of attempts and are moved to the dead letters.
+func (q *Queue) Done(id string, err error, now time.Time, dedupWindow time.Duration) {
+	for i, d := range q.Pending {
+		if d.ID != id {
+			continue
+		}
+		d.Attempts++
+		if err == nil {
+			q.Pending = append(q.Pending[:i], q.Pending[i+1:]...)
+			q.Delivered[d.Fingerprint] = now
+			q.LastDelivered = now
+			break
+		}
+
+		d.LastError = err.Error()
+		q.LastFailed = now
+		q.LastError = d.LastError
+		if d.Attempts >= MaxAttempts {
+			q.Pending = append(q.Pending[:i], q.Pending[i+1:]...)
+			q.deadLetter(d, "")
+		} else {
+			d.NextAttempt = now.Add(Backoff(d.Attempts))
+		}
+		break
+	}
+	q.prune(now, dedupWindow)
+}
+
+// Resend moves the dead letters with the ids, or all of them if no ids are given, back to the pending deliveries. It
+// returns the number of dead letters that are resent.
+func (q *Queue) Resend(ids []string, now time.Time) int {
+	resent := 0
+	var deadLetters []*Delivery
+	for _, d := range q.DeadLetters {
+		if len(ids) > 0 && !slice.ContainsString(ids, d.ID) {
+			deadLetters = append(deadLetters, d)
+			continue
+		}
+		d.Attempts = 0
+		d.NextAttempt = now
+		d.LastError = ""
+		q.Pending = append(q.Pending, d)
+		resent++
+	}
+	q.DeadLetters = deadLetters
+	return resent
+}
+
+func (q *Queue) deadLetter(d *Delivery, reason string) {
+	if reason != "" {
+		d.LastError = reason
+	}
+	q.DeadLetters = append(q.DeadLetters, d)
+	if len(q.DeadLetters) > maxDeadLetters {
+		q.DeadLetters = q.DeadLetters[len(q.DeadLetters)-maxDeadLetters:]
+	}
+}
+
+// prune forgets the fingerprints of the messages delivered before the dedup window.
+func (q *Queue) prune(now time.Time, dedupWindow time.Duration) {
+	for fingerprint, delivered := range q.Delivered {
+		if now.Sub(delivered) >= dedupWindow {
+			delete(q.Delivered, fingerprint)
+		}
+	}
+}
+
+// Backoff returns the delay before the next attempt of a delivery that failed the number of attempts.
+func Backoff(attempts int) time.Duration {
+	delay := retryBase
+	for i := 1; i < attempts && delay < retryMax; i++ {
+		delay *= 2
+	}
+	if delay > retryMax {
+		delay = retryMax
+	}
+	return delay
+}
+
+// DedupWindow returns the window within which identical messages of a notifier are delivered once.
+func DedupWindow() time.Duration {
+	return time.Duration(settings.NotifierDedupWindowMinutes.GetInt()) * time.Minute
+}
+
+// Enqueue adds the message to the delivery queue of the notifier, the notifier delivery controller delivers it and
+// retries on failures.
+func Enqueue(configMaps corecontrollers.ConfigMapClient, notifier *v3.Notifier, recipient string, msg *Message) error {
+	_, err := UpdateQueue(configMaps, notifier, func(q *Queue) bool {
+		return q.Add(recipient, msg, time.Now(), DedupWindow())
+	})
+	return err
+}
+
+// UpdateQueue updates the delivery queue of the notifier, creating it if it does not exist. The update returns whether
+// it changed the queue.
+func UpdateQueue(configMaps corecontrollers.ConfigMapClient, notifier *v3.Notifier, update func(q *Queue) bool) (*Queue, error) {
+	var q *Queue
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := configMaps.Get(notifier.Namespace, QueueConfigMapName(notifier.Name), metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			cm = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      QueueConfigMapName(notifier.Name),
+					Namespace: notifier.Namespace,
+					Labels:    map[string]string{QueueLabel: notifier.Name},
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: v3.NotifierGroupVersionKind.GroupVersion().String(),
+						Kind:       v3.NotifierGroupVersionKind.Kind,
+						Name:       notifier.Name,
+						UID:        notifier.UID,
+					}},
+				},
+			}
+		} else if err != nil {
+			return err
+		} else {
+			cm = cm.DeepCopy()
+		}
+
+		if q, err = LoadQueue(cm); err != nil {
+			return err
+		}
+		if !update(q) {
+			return nil
+		}
+		if err := q.Store(cm); err != nil {
+			return err
+		}
+		if cm.ResourceVersion == "" {
+			_, err = configMaps.Create(cm)
+		} else {
+			_, err = configMaps.Update(cm)
+		}
+		return err
+	})
+	return q, err
+}
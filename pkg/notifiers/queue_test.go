@@ -0,0 +1,147 @@
+package notifiers
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestQueueAdd(t *testing.T) {
+	now := time.Now()
+	q := &Queue{Delivered: map[string]time.Time{}}
+
+	assert.True(t, q.Add("#alerts", &Message{Alert: testAlert()}, now, time.Hour))
+	assert.False(t, q.Add("#alerts", &Message{Alert: testAlert()}, now, time.Hour), "expected a pending duplicate to be dropped")
+	assert.True(t, q.Add("#other", &Message{Alert: testAlert()}, now, time.Hour))
+
+	resolved := testAlert()
+	resolved.Status = AlertStatusResolved
+	assert.True(t, q.Add("#alerts", &Message{Alert: resolved}, now, time.Hour), "expected the resolution to be delivered")
+
+	q.Done(q.Pending[0].ID, nil, now, time.Hour)
+	assert.Len(t, q.Pending, 2)
+	assert.False(t, q.Add("#alerts", &Message{Alert: testAlert()}, now.Add(time.Minute), time.Hour), "expected a delivered duplicate to be dropped")
+	assert.True(t, q.Add("#alerts", &Message{Alert: testAlert()}, now.Add(2*time.Hour), time.Hour))
+}
+
+func TestQueueFull(t *testing.T) {
+	now := time.Now()
+	q := &Queue{Delivered: map[string]time.Time{}}
+	for i := 0; i <= maxPending; i++ {
+		assert.True(t, q.Add("", &Message{Content: fmt.Sprint(i)}, now, time.Hour))
+	}
+	assert.Len(t, q.Pending, maxPending)
+	assert.Equal(t, "1", q.Pending[0].Content)
+	assert.Len(t, q.DeadLetters, 1)
+	assert.Equal(t, "0", q.DeadLetters[0].Content)
+}
+
+func TestQueueDone(t *testing.T) {
+	now := time.Now()
+	q := &Queue{Delivered: map[string]time.Time{}}
+	q.Add("#alerts", &Message{Content: "test"}, now, time.Hour)
+	id := q.Pending[0].ID
+
+	assert.Len(t, q.Due(now), 1)
+	q.Done(id, errors.New("unavailable"), now, time.Hour)
+	assert.Len(t, q.Pending, 1)
+	assert.Empty(t, q.Due(now), "expected the failed delivery to back off")
+	assert.Len(t, q.Due(now.Add(retryBase)), 1)
+	assert.Equal(t, "unavailable", q.LastError)
+
+	for i := 1; i < MaxAttempts; i++ {
+		q.Done(id, errors.New("unavailable"), now, time.Hour)
+	}
+	assert.Empty(t, q.Pending)
+	assert.Len(t, q.DeadLetters, 1)
+	assert.Equal(t, MaxAttempts, q.DeadLetters[0].Attempts)
+
+	assert.Equal(t, 0, q.Resend([]string{"unknown"}, now))
+	assert.Equal(t, 1, q.Resend(nil, now))
+	assert.Empty(t, q.DeadLetters)
+	assert.Len(t, q.Due(now), 1)
+	assert.Equal(t, 0, q.Pending[0].Attempts)
+}
+
+func TestQueueStore(t *testing.T) {
+	now := time.Now()
+	q := &Queue{Delivered: map[string]time.Time{}}
+	q.Add("#alerts", &Message{Title: "High CPU", Alert: testAlert()}, now, time.Hour)
+
+	cm := &corev1.ConfigMap{}
+	assert.NoError(t, q.Store(cm))
+	loaded, err := LoadQueue(cm)
+	assert.NoError(t, err)
+	assert.Len(t, loaded.Pending, 1)
+	assert.Equal(t, "High CPU", loaded.Pending[0].Message().Title)
+	assert.Equal(t, "c-abcde", loaded.Pending[0].Alert.ClusterID)
+
+	_, err = LoadQueue(&corev1.ConfigMap{Data: map[string]string{queueKey: "{"}})
+	assert.Error(t, err)
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, retryBase, Backoff(1))
+	assert.Equal(t, 4*retryBase, Backoff(3))
+	assert.Equal(t, retryMax, Backoff(20))
+}
+
+func TestQueueSyncAlerts(t *testing.T) {
+	now := time.Now()
+	q := &Queue{Delivered: map[string]time.Time{}, Firing: map[string]*FiringAlert{}}
+	message := func(alert *Alert) *Message {
+		return &Message{Title: alert.Status + " " + alert.Name, Alert: alert}
+	}
+	alert := testAlert()
+	alert.Fingerprint = "0123456789abcdef"
+	routes := []AlertRoute{{GroupID: alert.GroupID, Recipient: "#alerts", RepeatInterval: time.Hour, Alerts: []*Alert{alert}}}
+
+	assert.True(t, q.SyncAlerts(routes, true, message, now))
+	assert.Len(t, q.Pending, 1)
+	assert.Equal(t, "firing High CPU", q.Pending[0].Title)
+	assert.Equal(t, "0123456789abcdef", q.Pending[0].Alert.Fingerprint)
+
+	assert.False(t, q.SyncAlerts(routes, true, message, now.Add(time.Minute)), "expected an active alert to be queued once per repeat interval")
+	q.Done(q.Pending[0].ID, nil, now, time.Hour)
+	assert.True(t, q.SyncAlerts(routes, true, message, now.Add(time.Hour)))
+	assert.Len(t, q.Pending, 1, "expected the alert to be queued again after the repeat interval")
+	q.Done(q.Pending[0].ID, nil, now, 0)
+
+	routes[0].Alerts = nil
+	assert.True(t, q.SyncAlerts(routes, true, message, now.Add(2*time.Hour)))
+	assert.Empty(t, q.Firing)
+	if assert.Len(t, q.Pending, 1) {
+		assert.Equal(t, "resolved High CPU", q.Pending[0].Title)
+		assert.True(t, q.Pending[0].Alert.Resolved())
+	}
+
+	q = &Queue{Delivered: map[string]time.Time{}, Firing: map[string]*FiringAlert{}}
+	routes[0].Alerts = []*Alert{alert}
+	q.SyncAlerts(routes, false, message, now)
+	q.Done(q.Pending[0].ID, nil, now, 0)
+	routes[0].Alerts = nil
+	assert.True(t, q.SyncAlerts(routes, false, message, now.Add(time.Minute)))
+	assert.Empty(t, q.Pending, "expected resolved alerts to be dropped without send resolved")
+
+	routes[0].Alerts = []*Alert{alert}
+	q.SyncAlerts(routes, true, message, now)
+	q.Done(q.Pending[0].ID, nil, now, 0)
+	assert.True(t, q.SyncAlerts(nil, true, message, now.Add(time.Minute)))
+	assert.Empty(t, q.Firing)
+	assert.Empty(t, q.Pending, "expected the alerts of removed routes to be forgotten")
+}
+
+func TestQueueFiringFull(t *testing.T) {
+	now := time.Now()
+	q := &Queue{Delivered: map[string]time.Time{}, Firing: map[string]*FiringAlert{}}
+	route := AlertRoute{Recipient: "#alerts", RepeatInterval: time.Hour}
+	for i := 0; i <= maxFiring; i++ {
+		route.Alerts = append(route.Alerts, &Alert{Status: AlertStatusFiring, Fingerprint: fmt.Sprint(i)})
+	}
+	q.SyncAlerts([]AlertRoute{route}, false, func(alert *Alert) *Message { return &Message{Alert: alert} }, now)
+	assert.Len(t, q.Firing, maxFiring)
+}
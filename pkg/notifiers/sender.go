@@ -7,6 +7,7 @@
 	"crypto/sha256"
 	"crypto/tls"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -24,16 +25,26 @@
 	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 
 	"github.com/pkg/errors"
-	"github.com/prometheus/common/model"
 	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"github.com/rancher/rancher/pkg/types/config/dialer"
 )
 
-const contentTypeJSON = "application/json"
+const (
+	contentTypeJSON = "application/json"
+
+	defaultOpsgenieURL = "https://api.opsgenie.com"
+	defaultTelegramURL = "https://api.telegram.org"
+
+	// WebhookTimestampHeader and WebhookSignatureHeader carry the signature of the requests to webhooks with a secret.
+	WebhookTimestampHeader = "X-Rancher-Timestamp"
+	WebhookSignatureHeader = "X-Rancher-Signature"
+)
 
 type Message struct {
 	Title   string
 	Content string
+	// Alert is the context of the alert the message is sent for, it is nil for test messages.
+	Alert *Alert
 }
 
 type wechatToken struct {
@@ -50,12 +61,36 @@ type dingtalkResponse struct {
 	Errmsg  string `json:"errmsg"`
 }
 
+type telegramResponse struct {
+	OK          bool   `json:"ok"`
+	Description string `json:"description"`
+}
+
+// DeliveredByRancher returns whether the alerts routed to the notifier are delivered by Rancher through its delivery
+// queue rather than by alertmanager, which can not render the templates of notifiers, sign the requests to webhooks,
+// or send to Telegram and Google Chat.
+func DeliveredByRancher(notifier *v3.Notifier) bool {
+	spec := notifier.Spec
+	if t := spec.Template; t != nil && (t.Title != "" || t.Text != "" || t.Payload != "") {
+		return true
+	}
+	return spec.TelegramConfig != nil || spec.GoogleChatConfig != nil ||
+		(spec.WebhookConfig != nil && spec.WebhookConfig.Secret != "")
+}
+
+// SendMessage renders the templates of the notifier for the message and sends it in the native format of the channel of
+// the notifier.
 func SendMessage(ctx context.Context, notifier *v3.Notifier, recipient string, msg *Message, dialer dialer.Dialer) error {
+	msg, payload, err := Render(notifier.Spec.Template, msg)
+	if err != nil {
+		return err
+	}
+
 	if notifier.Spec.SlackConfig != nil {
 		if recipient == "" {
 			recipient = notifier.Spec.SlackConfig.DefaultRecipient
 		}
-		return TestSlack(notifier.Spec.SlackConfig.URL, recipient, msg.Content, notifier.Spec.SlackConfig.HTTPClientConfig, dialer)
+		return sendSlack(notifier.Spec.SlackConfig.URL, recipient, msg, payload, notifier.Spec.SlackConfig.HTTPClientConfig, dialer)
 	}
 
 	if notifier.Spec.SMTPConfig != nil {
@@ -67,7 +102,7 @@ func SendMessage(ctx context.Context, notifier *v3.Notifier, recipient string, m
 	}
 
 	if notifier.Spec.PagerdutyConfig != nil {
-		return TestPagerduty(notifier.Spec.PagerdutyConfig.ServiceKey, msg.Content, notifier.Spec.PagerdutyConfig.HTTPClientConfig, dialer)
+		return sendPagerduty(notifier.Spec.PagerdutyConfig.ServiceKey, msg, notifier.Spec.PagerdutyConfig.HTTPClientConfig, dialer)
 	}
 
 	if notifier.Spec.WechatConfig != nil {
@@ -75,40 +110,76 @@ func SendMessage(ctx context.Context, notifier *v3.Notifier, recipient string, m
 		if recipient == "" {
 			recipient = s.DefaultRecipient
 		}
+		content := msg.Content
+		if msg.Title != "" {
+			content = msg.Title + "\n" + content
+		}
 		return TestWechat(notifier.Spec.WechatConfig.Secret, notifier.Spec.WechatConfig.Agent, notifier.Spec.WechatConfig.Corp, notifier.Spec.WechatConfig.RecipientType,
-			recipient, msg.Content, notifier.Spec.WechatConfig.HTTPClientConfig, dialer)
+			recipient, content, notifier.Spec.WechatConfig.HTTPClientConfig, dialer)
 	}
 
 	if notifier.Spec.WebhookConfig != nil {
-		return TestWebhook(notifier.Spec.WebhookConfig.URL, msg.Content, notifier.Spec.WebhookConfig.HTTPClientConfig, dialer)
+		s := notifier.Spec.WebhookConfig
+		// the recipients of alert groups override the url of webhooks, as they do for the alerts sent by alertmanager
+		if recipient == "" {
+			recipient = s.URL
+		}
+		return sendWebhook(recipient, s.Secret, msg, payload, s.HTTPClientConfig, dialer)
 	}
 
 	if notifier.Spec.DingtalkConfig != nil {
-		return TestDingtalk(notifier.Spec.DingtalkConfig.URL, notifier.Spec.DingtalkConfig.Secret, msg.Content, notifier.Spec.DingtalkConfig.HTTPClientConfig, dialer)
+		return sendDingtalk(notifier.Spec.DingtalkConfig.URL, notifier.Spec.DingtalkConfig.Secret, msg, payload, notifier.Spec.DingtalkConfig.HTTPClientConfig, dialer)
 	}
 
 	if notifier.Spec.MSTeamsConfig != nil {
-		return TestMicrosoftTeams(notifier.Spec.MSTeamsConfig.URL, msg.Content, notifier.Spec.MSTeamsConfig.HTTPClientConfig, dialer)
+		return sendMicrosoftTeams(notifier.Spec.MSTeamsConfig.URL, msg, payload, notifier.Spec.MSTeamsConfig.HTTPClientConfig, dialer)
+	}
+
+	if notifier.Spec.OpsgenieConfig != nil {
+		s := notifier.Spec.OpsgenieConfig
+		if recipient == "" {
+			recipient = s.DefaultRecipient
+		}
+		return sendOpsgenie(s.APIURL, s.APIKey, recipient, s.Tags, msg, s.HTTPClientConfig, dialer)
+	}
+
+	if notifier.Spec.TelegramConfig != nil {
+		s := notifier.Spec.TelegramConfig
+		if recipient == "" {
+			recipient = s.DefaultRecipient
+		}
+		return sendTelegram(s.APIURL, s.BotToken, recipient, msg, s.HTTPClientConfig, dialer)
+	}
+
+	if notifier.Spec.GoogleChatConfig != nil {
+		return sendGoogleChat(notifier.Spec.GoogleChatConfig.URL, msg, payload, notifier.Spec.GoogleChatConfig.HTTPClientConfig, dialer)
+	}
+
+	if notifier.Spec.MattermostConfig != nil {
+		s := notifier.Spec.MattermostConfig
+		if recipient == "" {
+			recipient = s.DefaultRecipient
+		}
+		return sendMattermost(s.URL, recipient, s.Username, msg, payload, s.HTTPClientConfig, dialer)
 	}
 
 	return errors.New("Notifier not configured")
 }
 
-func TestPagerduty(key, msg string, cfg *v32.HTTPClientConfig, dialer dialer.Dialer) error {
-	if msg == "" {
-		msg = "Pagerduty setting validated"
+// withDefaultContent returns the message with the content set to the default, if the message is empty.
+func withDefaultContent(msg *Message, content string) *Message {
+	if msg.Content != "" || msg.Title != "" || msg.Alert != nil {
+		return msg
 	}
+	return &Message{Content: content}
+}
 
-	pd := &pagerDutyEvent{
-		RoutingKey:  key,
-		EventAction: "trigger",
-		Payload: pagerDutyEventPayload{
-			Summary:  msg,
-			Source:   "rancher",
-			Severity: "info",
-			Group:    "Rancher alert testing",
-		},
-	}
+func TestPagerduty(key, msg string, cfg *v32.HTTPClientConfig, dialer dialer.Dialer) error {
+	return sendPagerduty(key, &Message{Content: msg}, cfg, dialer)
+}
+
+func sendPagerduty(key string, msg *Message, cfg *v32.HTTPClientConfig, dialer dialer.Dialer) error {
+	pd := pagerdutyEvent(key, withDefaultContent(msg, "Pagerduty setting validated"))
 
 	url := "https://events.pagerduty.com/v2/enqueue"
 
@@ -225,14 +296,17 @@ func TestWechat(secret, agent, corp, receiverType, receiver, msg string, cfg *v3
 }
 
 func TestDingtalk(url, secret, msg string, cfg *v32.HTTPClientConfig, dialer dialer.Dialer) error {
-	if msg == "" {
-		msg = "Dingtalk setting validated"
-	}
+	return sendDingtalk(url, secret, &Message{Content: msg}, nil, cfg, dialer)
+}
 
-	content := `{"msgtype": "text",
-		"text": {"content": "` + msg + `"},
-		"at": {"isAtAll": true}
-	}`
+func sendDingtalk(url, secret string, msg *Message, payload []byte, cfg *v32.HTTPClientConfig, dialer dialer.Dialer) error {
+	content := payload
+	if content == nil {
+		var err error
+		if content, err = dingtalkPayload(withDefaultContent(msg, "Dingtalk setting validated")); err != nil {
+			return err
+		}
+	}
 
 	url = getDingtalkURL(url, secret)
 
@@ -241,13 +315,7 @@ func TestDingtalk(url, secret, msg string, cfg *v32.HTTPClientConfig, dialer dia
 		return err
 	}
 
-	req, err := http.NewRequest("POST", url, strings.NewReader(content))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", contentTypeJSON)
-
-	resp, err := client.Do(req)
+	resp, err := post(client, url, contentTypeJSON, bytes.NewReader(content))
 	if err != nil {
 		return err
 	}
@@ -275,22 +343,64 @@ func TestDingtalk(url, secret, msg string, cfg *v32.HTTPClientConfig, dialer dia
 }
 
 func TestMicrosoftTeams(url, msg string, cfg *v32.HTTPClientConfig, dialer dialer.Dialer) error {
-	if msg == "" {
-		msg = "MicrosoftTeams setting validated"
+	return sendMicrosoftTeams(url, &Message{Content: msg}, nil, cfg, dialer)
+}
+
+func sendMicrosoftTeams(url string, msg *Message, payload []byte, cfg *v32.HTTPClientConfig, dialer dialer.Dialer) error {
+	content := payload
+	if content == nil {
+		var err error
+		if content, err = msTeamsPayload(withDefaultContent(msg, "MicrosoftTeams setting validated")); err != nil {
+			return err
+		}
+	}
+
+	client, err := NewClientFromConfig(cfg, dialer)
+	if err != nil {
+		return err
+	}
+
+	resp, err := post(client, url, contentTypeJSON, bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("HTTP status code is %d, not included in the 2xx success HTTP status codes", resp.StatusCode)
 	}
 
-	content := `{"text":"` + msg + `"}`
+	return nil
+}
+
+func TestWebhook(url, msg string, cfg *v32.HTTPClientConfig, dialer dialer.Dialer) error {
+	return sendWebhook(url, "", &Message{Content: msg}, nil, cfg, dialer)
+}
+
+func sendWebhook(url, secret string, msg *Message, payload []byte, cfg *v32.HTTPClientConfig, dialer dialer.Dialer) error {
+	alertData := payload
+	if alertData == nil {
+		var err error
+		if alertData, err = webhookPayload(withDefaultContent(msg, "Webhook setting validated")); err != nil {
+			return err
+		}
+	}
 
 	client, err := NewClientFromConfig(cfg, dialer)
 	if err != nil {
 		return err
 	}
 
-	req, err := http.NewRequest("POST", url, strings.NewReader(content))
+	req, err := http.NewRequest("POST", url, bytes.NewReader(alertData))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", contentTypeJSON)
+	if secret != "" {
+		timestamp := time.Now().Unix()
+		req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+		req.Header.Set(WebhookSignatureHeader, WebhookSignature(secret, timestamp, alertData))
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -305,29 +415,113 @@ func TestMicrosoftTeams(url, msg string, cfg *v32.HTTPClientConfig, dialer diale
 	return nil
 }
 
-func TestWebhook(url, msg string, cfg *v32.HTTPClientConfig, dialer dialer.Dialer) error {
-	if msg == "" {
-		msg = "Webhook setting validated"
+// WebhookSignature returns the signature of a request to a webhook, the hex encoded HMAC-SHA256 of the timestamp, a
+// dot and the body, keyed with the secret of the webhook. Receivers should reject requests with old timestamps.
+func WebhookSignature(secret string, timestamp int64, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(h, "%d.", timestamp)
+	h.Write(body)
+	return "sha256=" + hex.EncodeToString(h.Sum(nil))
+}
+
+func sendOpsgenie(apiURL, apiKey, team string, tags []string, msg *Message, cfg *v32.HTTPClientConfig, dialer dialer.Dialer) error {
+	msg = withDefaultContent(msg, "Opsgenie setting validated")
+	if apiURL == "" {
+		apiURL = defaultOpsgenieURL
 	}
-	alertList := model.Alerts{
-		&model.Alert{
-			Labels: map[model.LabelName]model.LabelValue{
-				model.LabelName("test_msg"): model.LabelValue(msg),
-			},
-		},
+	apiURL = strings.TrimSuffix(apiURL, "/")
+
+	endpoint := apiURL + "/v2/alerts"
+	body, err := opsgeniePayload(team, tags, msg)
+	if msg.Alert != nil && msg.Alert.Resolved() && msg.Alert.RuleID != "" {
+		// alerts are created with the rule id as alias, so that they are closed when the rule is resolved
+		endpoint = fmt.Sprintf("%s/v2/alerts/%s/close?identifierType=alias", apiURL, url.PathEscape(msg.Alert.RuleID))
+		body, err = json.Marshal(map[string]string{"source": "rancher", "note": msg.Content})
+	}
+	if err != nil {
+		return err
+	}
+
+	client, err := NewClientFromConfig(cfg, dialer)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentTypeJSON)
+	req.Header.Set("Authorization", "GenieKey "+apiKey)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		res, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("HTTP status code is %d, not included in the 2xx success HTTP status codes, response: %v", resp.StatusCode, string(res))
+	}
+
+	return nil
+}
+
+func sendTelegram(apiURL, botToken, chatID string, msg *Message, cfg *v32.HTTPClientConfig, dialer dialer.Dialer) error {
+	if apiURL == "" {
+		apiURL = defaultTelegramURL
+	}
+	data, err := telegramPayload(chatID, withDefaultContent(msg, "Telegram setting validated"))
+	if err != nil {
+		return err
+	}
+
+	client, err := NewClientFromConfig(cfg, dialer)
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("%s/bot%s/sendMessage", strings.TrimSuffix(apiURL, "/"), botToken)
+	resp, err := post(client, endpoint, contentTypeJSON, bytes.NewReader(data))
+	if err != nil {
+		// the url holds the token of the bot, which must not end up in the error
+		return errors.New("Failed to send Telegram message, the Telegram API is unreachable")
 	}
+	defer resp.Body.Close()
 
-	alertData, err := json.Marshal(alertList)
+	respBytes, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return err
 	}
 
+	var tgResp telegramResponse
+	if err := json.Unmarshal(respBytes, &tgResp); err != nil {
+		return fmt.Errorf("HTTP status code is %d, failed to parse the response of Telegram: %v", resp.StatusCode, err)
+	}
+
+	if !tgResp.OK {
+		return fmt.Errorf("Failed to send Telegram message. %s", tgResp.Description)
+	}
+
+	return nil
+}
+
+func sendGoogleChat(url string, msg *Message, payload []byte, cfg *v32.HTTPClientConfig, dialer dialer.Dialer) error {
+	data := payload
+	if data == nil {
+		var err error
+		if data, err = googleChatPayload(withDefaultContent(msg, "Google Chat setting validated")); err != nil {
+			return err
+		}
+	}
+
 	client, err := NewClientFromConfig(cfg, dialer)
 	if err != nil {
 		return err
 	}
 
-	resp, err := post(client, url, contentTypeJSON, bytes.NewBuffer(alertData))
+	resp, err := post(client, url, contentTypeJSON, bytes.NewReader(data))
 	if err != nil {
 		return err
 	}
@@ -340,22 +534,46 @@ func TestWebhook(url, msg string, cfg *v32.HTTPClientConfig, dialer dialer.Diale
 	return nil
 }
 
-func TestSlack(url, channel, msg string, cfg *v32.HTTPClientConfig, dialer dialer.Dialer) error {
-	if msg == "" {
-		msg = "Slack setting validated"
+func sendMattermost(url, channel, username string, msg *Message, payload []byte, cfg *v32.HTTPClientConfig, dialer dialer.Dialer) error {
+	data := payload
+	if data == nil {
+		var err error
+		if data, err = mattermostPayload(channel, username, withDefaultContent(msg, "Mattermost setting validated")); err != nil {
+			return err
+		}
 	}
-	req := struct {
-		Text    string `json:"text"`
-		Channel string `json:"channel"`
-	}{}
 
-	req.Text = msg
-	req.Channel = channel
+	client, err := NewClientFromConfig(cfg, dialer)
+	if err != nil {
+		return err
+	}
 
-	data, err := json.Marshal(req)
+	resp, err := post(client, url, contentTypeJSON, bytes.NewReader(data))
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		res, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("HTTP status code is %d, not included in the 2xx success HTTP status codes, response: %v", resp.StatusCode, string(res))
+	}
+
+	return nil
+}
+
+func TestSlack(url, channel, msg string, cfg *v32.HTTPClientConfig, dialer dialer.Dialer) error {
+	return sendSlack(url, channel, &Message{Content: msg}, nil, cfg, dialer)
+}
+
+func sendSlack(url, channel string, msg *Message, payload []byte, cfg *v32.HTTPClientConfig, dialer dialer.Dialer) error {
+	data := payload
+	if data == nil {
+		var err error
+		if data, err = slackPayload(channel, withDefaultContent(msg, "Slack setting validated")); err != nil {
+			return err
+		}
+	}
 
 	client, err := NewClientFromConfig(cfg, dialer)
 	if err != nil {
@@ -539,16 +757,25 @@ func smtpSend(c *smtp.Client, title, content, receiver, sender string) error {
 }
 
 type pagerDutyEventPayload struct {
-	Summary  string `json:"summary"`
-	Source   string `json:"source"`
-	Severity string `json:"severity"`
-	Group    string `json:"group"`
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	Group         string            `json:"group"`
+	Class         string            `json:"class,omitempty"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+type pagerDutyLink struct {
+	Href string `json:"href"`
+	Text string `json:"text,omitempty"`
 }
 
 type pagerDutyEvent struct {
 	RoutingKey  string                `json:"routing_key"`
 	EventAction string                `json:"event_action"`
+	DedupKey    string                `json:"dedup_key,omitempty"`
 	Payload     pagerDutyEventPayload `json:"payload"`
+	Links       []pagerDutyLink       `json:"links,omitempty"`
 }
 
 func hashKey(s string) string {
@@ -1,10 +1,16 @@
 package notifiers
 
 import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"testing"
 
 	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 
+	v3 "github.com/rancher/rancher/pkg/generated/norman/management.cattle.io/v3"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -39,3 +45,49 @@ type testCase struct {
 	}
 
 }
+
+func TestSendSignedWebhook(t *testing.T) {
+	var header http.Header
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		header = req.Header
+		body, _ = ioutil.ReadAll(req.Body)
+	}))
+	defer server.Close()
+
+	notifier := &v3.Notifier{
+		Spec: v32.NotifierSpec{
+			WebhookConfig: &v32.WebhookConfig{URL: server.URL, Secret: "secret"},
+		},
+	}
+	err := SendMessage(context.Background(), notifier, "", &Message{Content: "test", Alert: testAlert()}, nil)
+	assert.NoError(t, err)
+
+	timestamp, err := strconv.ParseInt(header.Get(WebhookTimestampHeader), 10, 64)
+	assert.NoError(t, err)
+	assert.Equal(t, WebhookSignature("secret", timestamp, body), header.Get(WebhookSignatureHeader))
+	assert.NotEqual(t, WebhookSignature("other", timestamp, body), header.Get(WebhookSignatureHeader))
+	assert.Contains(t, string(body), `"version":"v1"`)
+
+	notifier.Spec.WebhookConfig.Secret = ""
+	assert.NoError(t, SendMessage(context.Background(), notifier, "", &Message{Content: "test"}, nil))
+	assert.Empty(t, header.Get(WebhookSignatureHeader))
+}
+
+func TestDeliveredByRancher(t *testing.T) {
+	tests := []struct {
+		spec v32.NotifierSpec
+		want bool
+	}{
+		{spec: v32.NotifierSpec{SlackConfig: &v32.SlackConfig{}}},
+		{spec: v32.NotifierSpec{SlackConfig: &v32.SlackConfig{}, Template: &v32.NotifierTemplate{}}},
+		{spec: v32.NotifierSpec{SlackConfig: &v32.SlackConfig{}, Template: &v32.NotifierTemplate{Text: "{{ .Alert.Name }}"}}, want: true},
+		{spec: v32.NotifierSpec{WebhookConfig: &v32.WebhookConfig{}}},
+		{spec: v32.NotifierSpec{WebhookConfig: &v32.WebhookConfig{Secret: "secret"}}, want: true},
+		{spec: v32.NotifierSpec{TelegramConfig: &v32.TelegramConfig{}}, want: true},
+		{spec: v32.NotifierSpec{GoogleChatConfig: &v32.GoogleChatConfig{}}, want: true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, DeliveredByRancher(&v3.Notifier{Spec: tt.spec}))
+	}
+}
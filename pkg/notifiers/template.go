@@ -0,0 +1,94 @@
+package notifiers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+)
+
+// TemplateData is the data the templates of a notifier are rendered with.
+type TemplateData struct {
+	Title string
+	Text  string
+	Alert *Alert
+}
+
+// ValidateTemplate returns an error if a template of the notifier does not parse.
+func ValidateTemplate(tmpl *v32.NotifierTemplate) error {
+	if tmpl == nil {
+		return nil
+	}
+	for name, text := range map[string]string{"title": tmpl.Title, "text": tmpl.Text, "payload": tmpl.Payload} {
+		if _, err := parseTemplate(name, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Render renders the templates of the notifier for the message. It returns the message with the rendered title and
+// text and, if the notifier has a payload template, the rendered payload which replaces the body of the request.
+func Render(tmpl *v32.NotifierTemplate, msg *Message) (*Message, []byte, error) {
+	if tmpl == nil {
+		return msg, nil, nil
+	}
+
+	data := TemplateData{
+		Title: msg.Title,
+		Text:  msg.Content,
+		Alert: msg.Alert,
+	}
+	if data.Alert == nil {
+		data.Alert = &Alert{}
+	}
+
+	rendered := *msg
+	var err error
+	if rendered.Title, err = execute("title", tmpl.Title, msg.Title, data); err != nil {
+		return nil, nil, err
+	}
+	if rendered.Content, err = execute("text", tmpl.Text, msg.Content, data); err != nil {
+		return nil, nil, err
+	}
+	if tmpl.Payload == "" {
+		return &rendered, nil, nil
+	}
+
+	data.Title, data.Text = rendered.Title, rendered.Content
+	payload, err := execute("payload", tmpl.Payload, "", data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !json.Valid([]byte(payload)) {
+		return nil, nil, fmt.Errorf("notifier template payload did not render valid JSON")
+	}
+	return &rendered, []byte(payload), nil
+}
+
+func parseTemplate(name, text string) (*template.Template, error) {
+	t, err := template.New(name).Funcs(sprig.TxtFuncMap()).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid notifier %s template: %v", name, err)
+	}
+	return t, nil
+}
+
+// execute renders the template, or returns the default if the template is empty.
+func execute(name, text, def string, data TemplateData) (string, error) {
+	if text == "" {
+		return def, nil
+	}
+	t, err := parseTemplate(name, text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render notifier %s template: %v", name, err)
+	}
+	return buf.String(), nil
+}
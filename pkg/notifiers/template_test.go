@@ -0,0 +1,69 @@
+package notifiers
+
+import (
+	"testing"
+
+	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func testAlert() *Alert {
+	return AlertFromLabels(map[string]string{
+		"alert_name":   "High CPU",
+		"alert_type":   "metric",
+		"severity":     "critical",
+		"cluster_name": "prod",
+		"project_name": "web",
+		"group_id":     "c-abcde:g-12345",
+		"rule_id":      "c-abcde:g-12345_r-1",
+	}, map[string]string{
+		"runbook_url": "https://runbooks.example.com/cpu",
+	})
+}
+
+func TestAlertFromLabels(t *testing.T) {
+	alert := testAlert()
+	assert.Equal(t, AlertStatusFiring, alert.Status)
+	assert.Equal(t, "High CPU", alert.Name)
+	assert.Equal(t, "c-abcde", alert.ClusterID)
+	assert.Equal(t, "prod", alert.ClusterName)
+	assert.Equal(t, "https://runbooks.example.com/cpu", alert.RunbookURL)
+}
+
+func TestRender(t *testing.T) {
+	msg := &Message{Title: "title", Content: "content", Alert: testAlert()}
+
+	rendered, payload, err := Render(nil, msg)
+	assert.NoError(t, err)
+	assert.Equal(t, msg, rendered)
+	assert.Nil(t, payload)
+
+	rendered, payload, err = Render(&v32.NotifierTemplate{
+		Title: `[{{ .Alert.Severity | upper }}] {{ .Alert.Name }}`,
+	}, msg)
+	assert.NoError(t, err)
+	assert.Equal(t, "[CRITICAL] High CPU", rendered.Title)
+	assert.Equal(t, "content", rendered.Content, "expected the text to be kept without a template")
+	assert.Nil(t, payload)
+
+	_, payload, err = Render(&v32.NotifierTemplate{
+		Text:    `{{ .Alert.ClusterName }}/{{ .Alert.ProjectName }}`,
+		Payload: `{"summary": {{ .Text | toJson }}, "runbook": {{ .Alert.RunbookURL | quote }}}`,
+	}, msg)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"summary": "prod/web", "runbook": "https://runbooks.example.com/cpu"}`, string(payload))
+
+	rendered, _, err = Render(&v32.NotifierTemplate{Text: `{{ .Alert.Name | default "test" }}`}, &Message{})
+	assert.NoError(t, err)
+	assert.Equal(t, "test", rendered.Content, "expected test messages to render without an alert")
+
+	_, _, err = Render(&v32.NotifierTemplate{Payload: `not {{ .Text }}`}, msg)
+	assert.Error(t, err)
+}
+
+func TestValidateTemplate(t *testing.T) {
+	assert.NoError(t, ValidateTemplate(nil))
+	assert.NoError(t, ValidateTemplate(&v32.NotifierTemplate{Title: `{{ .Alert.Name | trunc 10 }}`}))
+	assert.Error(t, ValidateTemplate(&v32.NotifierTemplate{Text: `{{ .Alert.Name `}))
+	assert.Error(t, ValidateTemplate(&v32.NotifierTemplate{Payload: `{{ unknownFunc }}`}))
+}
@@ -0,0 +1,242 @@
+package planner
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1/plan"
+	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
+	rkecontroller "github.com/rancher/rancher/pkg/generated/controllers/rke.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/wrangler"
+	apierror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1alpha4"
+)
+
+const (
+	certificateRotationGenerationEnv = "CERTIFICATE_ROTATION_GENERATION"
+
+	// automaticRotationInterval is the minimum time between two rotations triggered by expiring certificates, it
+	// gives the downstream cluster time to report the new expiration dates.
+	automaticRotationInterval = 24 * time.Hour
+)
+
+// certificateRotationVersions are the RKE2 and K3s versions that have the "certificate rotate" command.
+var certificateRotationVersions = mustConstraint("~1.21.8 || >= 1.22.5")
+
+func mustConstraint(constraint string) *semver.Constraints {
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// supportsCertificateRotation returns true if the runtime of the Kubernetes version can rotate certificates.
+func supportsCertificateRotation(kubernetesVersion string) bool {
+	version, err := semver.NewVersion(kubernetesVersion)
+	if err != nil {
+		return false
+	}
+	return certificateRotationVersions.Check(version)
+}
+
+type certificateRotation struct {
+	controlPlane       rkecontroller.RKEControlPlaneClient
+	managementClusters mgmtcontrollers.ClusterCache
+}
+
+func newCertificateRotation(clients *wrangler.Context) *certificateRotation {
+	return &certificateRotation{
+		controlPlane:       clients.RKE.RKEControlPlane(),
+		managementClusters: clients.Mgmt.Cluster().Cache(),
+	}
+}
+
+func (r *certificateRotation) setState(controlPlane *rkev1.RKEControlPlane, update func(status *rkev1.RKEControlPlaneStatus)) error {
+	controlPlane = controlPlane.DeepCopy()
+	update(&controlPlane.Status)
+	_, err := r.controlPlane.UpdateStatus(controlPlane)
+	if err != nil {
+		return err
+	}
+	return ErrWaiting("refreshing certificate rotation state")
+}
+
+// Rotate starts a certificate rotation when the generation of the spec changed or, if configured, when a certificate
+// of the cluster is about to expire. The rotation itself is rolled out by the regular reconcile of the node plans,
+// which see the increased CertificateRotationGeneration.
+func (r *certificateRotation) Rotate(controlPlane *rkev1.RKEControlPlane, clusterPlan *plan.Plan) error {
+	if !Provisioned.IsTrue(controlPlane) && controlPlane.Status.CertificateRotationPhase == "" {
+		return nil
+	}
+
+	if controlPlane.Status.CertificateRotationPhase == rkev1.CertificateRotationPhaseRotating {
+		return r.updateProgress(controlPlane, clusterPlan)
+	}
+
+	spec := controlPlane.Spec.RotateCertificates
+	if spec == nil {
+		return nil
+	}
+
+	requested := spec.Generation > 0 &&
+		(controlPlane.Status.RotateCertificates == nil || controlPlane.Status.RotateCertificates.Generation != spec.Generation)
+	if !supportsCertificateRotation(controlPlane.Spec.KubernetesVersion) {
+		if !requested {
+			return nil
+		}
+		return r.setState(controlPlane, func(status *rkev1.RKEControlPlaneStatus) {
+			status.CertificateRotationPhase = rkev1.CertificateRotationPhaseUnsupported
+			status.CertificateRotationProgress = fmt.Sprintf("Kubernetes version %s can not rotate certificates, "+
+				"upgrade to v1.21.8, v1.22.5 or newer", controlPlane.Spec.KubernetesVersion)
+			status.RotateCertificates = spec.DeepCopy()
+		})
+	}
+
+	start := requested
+	if !start {
+		expiring, err := r.expiring(controlPlane, spec.ExpirationThresholdDays)
+		if err != nil {
+			return err
+		}
+		start = expiring
+	}
+	if !start {
+		return nil
+	}
+
+	now := metav1.Now()
+	return r.setState(controlPlane, func(status *rkev1.RKEControlPlaneStatus) {
+		status.CertificateRotationGeneration++
+		status.CertificateRotationPhase = rkev1.CertificateRotationPhaseRotating
+		status.CertificateRotationProgress = ""
+		status.CertificateRotationTime = &now
+		status.RotateCertificates = spec.DeepCopy()
+	})
+}
+
+// Finish marks the running certificate rotation as finished, it is called once all nodes applied their plans.
+func (r *certificateRotation) Finish(controlPlane *rkev1.RKEControlPlane, clusterPlan *plan.Plan) error {
+	if controlPlane.Status.CertificateRotationPhase != rkev1.CertificateRotationPhaseRotating {
+		return nil
+	}
+	return r.setState(controlPlane, func(status *rkev1.RKEControlPlaneStatus) {
+		status.CertificateRotationPhase = rkev1.CertificateRotationPhaseFinished
+		status.CertificateRotationProgress = rotationProgress(controlPlane, clusterPlan)
+	})
+}
+
+func (r *certificateRotation) updateProgress(controlPlane *rkev1.RKEControlPlane, clusterPlan *plan.Plan) error {
+	progress := rotationProgress(controlPlane, clusterPlan)
+	if progress == controlPlane.Status.CertificateRotationProgress {
+		return nil
+	}
+	return r.setState(controlPlane, func(status *rkev1.RKEControlPlaneStatus) {
+		status.CertificateRotationProgress = progress
+	})
+}
+
+// expiring returns true if one of the certificates reported for the cluster expires within the given number of days.
+func (r *certificateRotation) expiring(controlPlane *rkev1.RKEControlPlane, days int) (bool, error) {
+	if days <= 0 {
+		return false, nil
+	}
+	if last := controlPlane.Status.CertificateRotationTime; last != nil && time.Since(last.Time) < automaticRotationInterval {
+		return false, nil
+	}
+
+	cluster, err := r.managementClusters.Get(controlPlane.Spec.ManagementClusterName)
+	if apierror.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	deadline := time.Now().Add(time.Duration(days) * 24 * time.Hour)
+	for certName, certExp := range cluster.Status.CertificatesExpiration {
+		// the CA is not rotated with the leaf certificates
+		if certName == "kube-ca" {
+			continue
+		}
+		date, err := time.Parse(time.RFC3339, certExp.ExpirationDate)
+		if err != nil {
+			continue
+		}
+		if date.Before(deadline) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func rotationProgress(controlPlane *rkev1.RKEControlPlane, clusterPlan *plan.Plan) string {
+	entries := collect(clusterPlan, func(machine *capi.Machine) bool {
+		return true
+	})
+
+	env := fmt.Sprintf("%s=%d", certificateRotationGenerationEnv, controlPlane.Status.CertificateRotationGeneration)
+	rotated := 0
+	for _, entry := range entries {
+		if entry.Plan != nil && entry.Plan.InSync && hasInstructionEnv(entry.Plan.Plan, env) {
+			rotated++
+		}
+	}
+	return fmt.Sprintf("%d/%d nodes rotated", rotated, len(entries))
+}
+
+func hasInstructionEnv(nodePlan plan.NodePlan, env string) bool {
+	for _, instruction := range nodePlan.Instructions {
+		for _, e := range instruction.Env {
+			if e == env {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// addCertificateRotationInstruction rotates the certificates of server nodes before the runtime is started again by
+// the run.sh instruction. The generation is recorded on the node so that reapplying the plan does not rotate again.
+// If the rotation fails the runtime is started with its old certificates, so the node does not stay down until the
+// plan is applied again.
+func addCertificateRotationInstruction(nodePlan plan.NodePlan, controlPlane *rkev1.RKEControlPlane, machine *capi.Machine) plan.NodePlan {
+	generation := controlPlane.Status.CertificateRotationGeneration
+	if generation == 0 || isOnlyWorker(machine) || !supportsCertificateRotation(controlPlane.Spec.KubernetesVersion) {
+		return nodePlan
+	}
+
+	runtime := GetRuntime(controlPlane.Spec.KubernetesVersion)
+	dataDir := fmt.Sprintf("/var/lib/rancher/%s/server", runtime)
+	marker := dataDir + "/certificate-rotation-generation"
+
+	// the services are passed as positional parameters so that they are never interpreted by the shell
+	args := []string{
+		"-c",
+		fmt.Sprintf(`[ "$(cat %[1]s 2>/dev/null)" = "%[2]d" ] && exit 0; `+
+			`if [ -d %[3]s/tls ]; then systemctl stop %[4]s || exit 1; `+
+			`if ! %[5]s certificate rotate "$@"; then systemctl start %[4]s; exit 1; fi; fi; `+
+			`mkdir -p %[3]s && echo %[2]d > %[1]s`,
+			marker, generation, dataDir,
+			GetRuntimeServerUnit(controlPlane.Spec.KubernetesVersion),
+			GetRuntimeCommand(controlPlane.Spec.KubernetesVersion)),
+		"rotate-certificates",
+	}
+	if controlPlane.Status.RotateCertificates != nil {
+		for _, service := range controlPlane.Status.RotateCertificates.Services {
+			args = append(args, "--service="+service)
+		}
+	}
+
+	nodePlan.Instructions = append(nodePlan.Instructions, plan.Instruction{
+		Name:    "rotate-certificates",
+		Command: "sh",
+		Args:    args,
+		Env: []string{
+			fmt.Sprintf("%s=%d", certificateRotationGenerationEnv, generation),
+		},
+	})
+	return nodePlan
+}
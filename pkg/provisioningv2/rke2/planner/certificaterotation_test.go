@@ -0,0 +1,140 @@
+package planner
+
+import (
+	"strings"
+	"testing"
+
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1/plan"
+	rkecontroller "github.com/rancher/rancher/pkg/generated/controllers/rke.cattle.io/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1alpha4"
+)
+
+type fakeControlPlaneClient struct {
+	rkecontroller.RKEControlPlaneClient
+	updated []*rkev1.RKEControlPlane
+}
+
+func (f *fakeControlPlaneClient) UpdateStatus(controlPlane *rkev1.RKEControlPlane) (*rkev1.RKEControlPlane, error) {
+	f.updated = append(f.updated, controlPlane)
+	return controlPlane, nil
+}
+
+func newRotationControlPlane(version string, generation int64) *rkev1.RKEControlPlane {
+	controlPlane := &rkev1.RKEControlPlane{
+		Spec: rkev1.RKEControlPlaneSpec{
+			KubernetesVersion:  version,
+			RotateCertificates: &rkev1.RotateCertificates{Generation: generation},
+		},
+	}
+	Provisioned.True(controlPlane)
+	return controlPlane
+}
+
+func TestSupportsCertificateRotation(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected bool
+	}{
+		{version: "v1.20.15+rke2r1", expected: false},
+		{version: "v1.21.7+rke2r2", expected: false},
+		{version: "v1.21.8+rke2r1", expected: true},
+		{version: "v1.21.14+k3s1", expected: true},
+		{version: "v1.22.4+k3s1", expected: false},
+		{version: "v1.22.5+k3s1", expected: true},
+		{version: "v1.23.4+rke2r1", expected: true},
+		{version: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			assert.Equal(t, tt.expected, supportsCertificateRotation(tt.version))
+		})
+	}
+}
+
+func TestRotateRejectsUnsupportedVersion(t *testing.T) {
+	client := &fakeControlPlaneClient{}
+	r := &certificateRotation{controlPlane: client}
+
+	err := r.Rotate(newRotationControlPlane("v1.21.7+rke2r2", 1), &plan.Plan{})
+	assert.IsType(t, ErrWaiting(""), err)
+	require.Len(t, client.updated, 1)
+
+	status := client.updated[0].Status
+	assert.Equal(t, rkev1.CertificateRotationPhaseUnsupported, status.CertificateRotationPhase)
+	assert.Contains(t, status.CertificateRotationProgress, "v1.21.7+rke2r2")
+	assert.Zero(t, status.CertificateRotationGeneration, "expected no rotation to be rolled out")
+	require.NotNil(t, status.RotateCertificates)
+	assert.Equal(t, int64(1), status.RotateCertificates.Generation)
+
+	// the rejected request is recorded and not rejected again
+	rejected := client.updated[0]
+	client.updated = nil
+	assert.NoError(t, r.Rotate(rejected, &plan.Plan{}))
+	assert.Empty(t, client.updated)
+}
+
+func TestRotateStartsRotation(t *testing.T) {
+	client := &fakeControlPlaneClient{}
+	r := &certificateRotation{controlPlane: client}
+
+	err := r.Rotate(newRotationControlPlane("v1.22.5+rke2r1", 1), &plan.Plan{})
+	assert.IsType(t, ErrWaiting(""), err)
+	require.Len(t, client.updated, 1)
+
+	status := client.updated[0].Status
+	assert.Equal(t, rkev1.CertificateRotationPhaseRotating, status.CertificateRotationPhase)
+	assert.Equal(t, int64(1), status.CertificateRotationGeneration)
+}
+
+func TestAddCertificateRotationInstruction(t *testing.T) {
+	server := &capi.Machine{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+		ControlPlaneRoleLabel: "true",
+	}}}
+	worker := &capi.Machine{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+		WorkerRoleLabel: "true",
+	}}}
+
+	controlPlane := newRotationControlPlane("v1.22.5+rke2r1", 1)
+	controlPlane.Status.CertificateRotationGeneration = 3
+	controlPlane.Status.RotateCertificates = &rkev1.RotateCertificates{Generation: 1, Services: []string{"etcd", "kubelet"}}
+
+	nodePlan := addCertificateRotationInstruction(plan.NodePlan{}, controlPlane, server)
+	require.Len(t, nodePlan.Instructions, 1)
+	instruction := nodePlan.Instructions[0]
+	assert.Equal(t, "rotate-certificates", instruction.Name)
+	assert.Equal(t, []string{certificateRotationGenerationEnv + "=3"}, instruction.Env)
+	require.Len(t, instruction.Args, 5)
+	assert.Equal(t, []string{"rotate-certificates", "--service=etcd", "--service=kubelet"}, instruction.Args[2:])
+
+	script := instruction.Args[1]
+	marker := "/var/lib/rancher/rke2/server/certificate-rotation-generation"
+	assert.True(t, strings.HasPrefix(script, `[ "$(cat `+marker+` 2>/dev/null)" = "3" ] && exit 0;`),
+		"expected the rotation to be skipped once the marker records the generation")
+	assert.Contains(t, script, "echo 3 > "+marker)
+	assert.Contains(t, script, `if ! rke2 certificate rotate "$@"; then systemctl start rke2-server; exit 1; fi`,
+		"expected the runtime to be started again when the rotation fails")
+
+	k3s := newRotationControlPlane("v1.22.5+k3s1", 1)
+	k3s.Status.CertificateRotationGeneration = 1
+	nodePlan = addCertificateRotationInstruction(plan.NodePlan{}, k3s, server)
+	require.Len(t, nodePlan.Instructions, 1)
+	assert.Contains(t, nodePlan.Instructions[0].Args[1], "/var/lib/rancher/k3s/server/certificate-rotation-generation")
+	assert.Contains(t, nodePlan.Instructions[0].Args[1], `k3s certificate rotate "$@"`)
+
+	assert.Empty(t, addCertificateRotationInstruction(plan.NodePlan{}, controlPlane, worker).Instructions,
+		"expected no rotation on workers")
+
+	unsupported := controlPlane.DeepCopy()
+	unsupported.Spec.KubernetesVersion = "v1.21.7+rke2r2"
+	assert.Empty(t, addCertificateRotationInstruction(plan.NodePlan{}, unsupported, server).Instructions,
+		"expected no rotation on versions without the certificate rotate command")
+
+	notRotated := controlPlane.DeepCopy()
+	notRotated.Status.CertificateRotationGeneration = 0
+	assert.Empty(t, addCertificateRotationInstruction(plan.NodePlan{}, notRotated, server).Instructions)
+}
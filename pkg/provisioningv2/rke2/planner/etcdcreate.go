@@ -5,6 +5,7 @@
 
 	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
 	"github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1/plan"
+	"github.com/rancher/rancher/pkg/etcdsnapshot/target"
 	rkecontroller "github.com/rancher/rancher/pkg/generated/controllers/rke.cattle.io/v1"
 	"github.com/rancher/rancher/pkg/wrangler"
 	corecontrollers "github.com/rancher/wrangler/pkg/generated/controllers/core/v1"
@@ -17,6 +18,7 @@ type etcdCreate struct {
 	secrets      corecontrollers.SecretCache
 	store        *PlanStore
 	s3Args       *s3Args
+	etcdTarget   *etcdTarget
 }
 
 func newETCDCreate(clients *wrangler.Context, store *PlanStore) *etcdCreate {
@@ -28,6 +30,7 @@ func newETCDCreate(clients *wrangler.Context, store *PlanStore) *etcdCreate {
 			secretCache: clients.Core.Secret().Cache(),
 			env:         true,
 		},
+		etcdTarget: newETCDTarget(clients),
 	}
 }
 
@@ -90,12 +93,21 @@ func (e *etcdCreate) createPlan(controlPlane *rkev1.RKEControlPlane, snapshot *r
 		args = append(args, fmt.Sprintf("--node-name=%s", nodeName))
 	}
 
-	s3Args, s3Env, s3Files, err := e.s3Args.ToArgs(snapshot.S3, controlPlane)
+	if snapshot.Target != nil && snapshot.Target.Type == target.TypeFilesystem {
+		args = append(args, fmt.Sprintf("--dir=%s", snapshot.Target.Path))
+	}
+
+	s3 := snapshot.S3
+	if isEncrypted(controlPlane) {
+		// encrypted snapshots are uploaded by the agent
+		s3 = nil
+	}
+	s3Args, s3Env, s3Files, err := e.s3Args.ToArgs(s3, controlPlane)
 	if err != nil {
 		return plan.NodePlan{}, err
 	}
 
-	return commonNodePlan(e.secrets, controlPlane, plan.NodePlan{
+	nodePlan := plan.NodePlan{
 		Files: s3Files,
 		Instructions: []plan.Instruction{{
 			Name:    "create",
@@ -103,7 +115,19 @@ func (e *etcdCreate) createPlan(controlPlane *rkev1.RKEControlPlane, snapshot *r
 			Env:     s3Env,
 			Args:    append(args, s3Args...),
 		}},
-	})
+	}
+
+	files, agentArgs, ok, err := e.etcdTarget.AgentTransfer(snapshot.S3, snapshot.Target, controlPlane)
+	if err != nil {
+		return plan.NodePlan{}, err
+	}
+	if ok {
+		nodePlan.Files = append(nodePlan.Files, files...)
+		nodePlan.Instructions = append(nodePlan.Instructions, e.etcdTarget.Instruction(controlPlane, "sync",
+			append(agentArgs, fmt.Sprintf("--retention=%d", etcdSnapshotRetention(controlPlane)))...))
+	}
+
+	return commonNodePlan(e.secrets, controlPlane, nodePlan)
 }
 
 func (e *etcdCreate) Create(controlPlane *rkev1.RKEControlPlane, clusterPlan *plan.Plan) error {
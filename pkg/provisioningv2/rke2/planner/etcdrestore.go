@@ -2,10 +2,12 @@
 
 import (
 	"fmt"
+	"path"
 	"strings"
 
 	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
 	"github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1/plan"
+	"github.com/rancher/rancher/pkg/etcdsnapshot/target"
 	rkecontroller "github.com/rancher/rancher/pkg/generated/controllers/rke.cattle.io/v1"
 	"github.com/rancher/rancher/pkg/wrangler"
 	corecontrollers "github.com/rancher/wrangler/pkg/generated/controllers/core/v1"
@@ -16,6 +18,7 @@ type etcdRestore struct {
 	controlPlane rkecontroller.RKEControlPlaneClient
 	secrets      corecontrollers.SecretCache
 	s3Args       *s3Args
+	etcdTarget   *etcdTarget
 	store        *PlanStore
 }
 
@@ -29,6 +32,7 @@ func newETCDRestore(clients *wrangler.Context, store *PlanStore) *etcdRestore {
 			prefix:      "etcd-",
 			env:         true,
 		},
+		etcdTarget: newETCDTarget(clients),
 	}
 }
 
@@ -61,7 +65,7 @@ func (e *etcdRestore) etcdRestore(controlPlane *rkev1.RKEControlPlane, clusterPl
 	servers := collect(clusterPlan, isEtcd)
 
 	for _, server := range servers {
-		if controlPlane.Spec.ETCDSnapshotRestore.S3 != nil ||
+		if controlPlane.Spec.ETCDSnapshotRestore.S3 != nil || isRemoteTarget(controlPlane.Spec.ETCDSnapshotRestore.Target) ||
 			(server.Machine.Status.NodeRef != nil &&
 				server.Machine.Status.NodeRef.Name == controlPlane.Spec.ETCDSnapshotRestore.NodeName) {
 			restorePlan, err := e.restorePlan(controlPlane, controlPlane.Spec.ETCDSnapshotRestore)
@@ -86,39 +90,68 @@ func ensureInstalledInstruction(controlPlane *rkev1.RKEControlPlane) plan.Instru
 }
 
 func (e *etcdRestore) restorePlan(controlPlane *rkev1.RKEControlPlane, snapshot *rkev1.ETCDSnapshot) (plan.NodePlan, error) {
+	stopPlan, err := e.stopPlan(controlPlane)
+	if err != nil {
+		return plan.NodePlan{}, err
+	}
+
+	files, instructions, err := e.restoreInstructions(controlPlane, snapshot)
+	if err != nil {
+		return plan.NodePlan{}, err
+	}
+
+	return commonNodePlan(e.secrets, controlPlane, plan.NodePlan{
+		Files:        files,
+		Instructions: append(stopPlan.Instructions, instructions...),
+	})
+}
+
+// restoreInstructions returns the files and instructions installing the runtime, downloading the snapshot if it is
+// transferred by the agent and resetting the cluster to it. The restore is always the last instruction.
+func (e *etcdRestore) restoreInstructions(controlPlane *rkev1.RKEControlPlane, snapshot *rkev1.ETCDSnapshot) ([]plan.File, []plan.Instruction, error) {
 	args := []string{
 		"server",
 		"--cluster-reset",
 	}
 
-	if snapshot.S3 == nil {
-		args = append(args, fmt.Sprintf("--cluster-reset-restore-path=db/snapshots/%s", snapshot.Name))
-	} else {
+	s3 := snapshot.S3
+	if isEncrypted(controlPlane) {
+		// encrypted snapshots are downloaded and decrypted by the agent
+		s3 = nil
+	}
+
+	switch {
+	case s3 != nil:
 		args = append(args, fmt.Sprintf("--cluster-reset-restore-path=%s", snapshot.Name))
+	case snapshot.Target != nil && snapshot.Target.Type == target.TypeFilesystem:
+		args = append(args, fmt.Sprintf("--cluster-reset-restore-path=%s", path.Join(snapshot.Target.Path, snapshot.Name)))
+	default:
+		// snapshots transferred by the agent are downloaded to the local snapshot directory first
+		args = append(args, fmt.Sprintf("--cluster-reset-restore-path=db/snapshots/%s", snapshot.Name))
 	}
 
-	s3Args, s3Env, s3Files, err := e.s3Args.ToArgs(snapshot.S3, controlPlane)
+	s3Args, s3Env, s3Files, err := e.s3Args.ToArgs(s3, controlPlane)
 	if err != nil {
-		return plan.NodePlan{}, err
+		return nil, nil, err
 	}
 
-	stopPlan, err := e.stopPlan(controlPlane)
+	instructions := []plan.Instruction{ensureInstalledInstruction(controlPlane)}
+	files, agentArgs, ok, err := e.etcdTarget.AgentTransfer(snapshot.S3, snapshot.Target, controlPlane)
 	if err != nil {
-		return plan.NodePlan{}, err
+		return nil, nil, err
+	}
+	if ok {
+		s3Files = append(s3Files, files...)
+		instructions = append(instructions, e.etcdTarget.Instruction(controlPlane, "download",
+			append(agentArgs, fmt.Sprintf("--name=%s", snapshot.Name))...))
 	}
 
-	return commonNodePlan(e.secrets, controlPlane, plan.NodePlan{
-		Files: s3Files,
-		Instructions: append(stopPlan.Instructions, []plan.Instruction{
-			ensureInstalledInstruction(controlPlane),
-			{
-				Name:    "restore",
-				Env:     s3Env,
-				Args:    append(args, s3Args...),
-				Command: GetRuntimeCommand(controlPlane.Spec.KubernetesVersion),
-			},
-		}...),
-	})
+	return s3Files, append(instructions, plan.Instruction{
+		Name:    "restore",
+		Env:     s3Env,
+		Args:    append(args, s3Args...),
+		Command: GetRuntimeCommand(controlPlane.Spec.KubernetesVersion),
+	}), nil
 }
 
 func (e *etcdRestore) stopPlan(controlPlane *rkev1.RKEControlPlane) (plan.NodePlan, error) {
@@ -0,0 +1,260 @@
+package planner
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1/plan"
+	"github.com/rancher/rancher/pkg/controllers/management/rbac"
+	provisioningcontrollers "github.com/rancher/rancher/pkg/generated/controllers/provisioning.cattle.io/v1"
+	rkecontroller "github.com/rancher/rancher/pkg/generated/controllers/rke.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/wrangler"
+	corecontrollers "github.com/rancher/wrangler/pkg/generated/controllers/core/v1"
+	"github.com/rancher/wrangler/pkg/name"
+	authv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	authv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1alpha4"
+)
+
+// etcdSource bootstraps a new cluster from a snapshot of another cluster. The init node restores the snapshot with
+// cluster-reset before the runtime is started for the first time, the control plane nodes then remove the nodes of the
+// source cluster and the cluster agent restored with the snapshot. The cluster agent of the new cluster is deployed by
+// the manifests of the plans, so the cluster registers under its own name.
+type etcdSource struct {
+	controlPlane         rkecontroller.RKEControlPlaneClient
+	clusters             provisioningcontrollers.ClusterCache
+	secrets              corecontrollers.SecretCache
+	subjectAccessReviews authv1client.SubjectAccessReviewInterface
+	etcdRestore          *etcdRestore
+}
+
+func newETCDSource(clients *wrangler.Context, etcdRestore *etcdRestore) *etcdSource {
+	return &etcdSource{
+		controlPlane:         clients.RKE.RKEControlPlane(),
+		clusters:             clients.Provisioning.Cluster().Cache(),
+		secrets:              clients.Core.Secret().Cache(),
+		subjectAccessReviews: clients.K8s.AuthorizationV1().SubjectAccessReviews(),
+		etcdRestore:          etcdRestore,
+	}
+}
+
+func (e *etcdSource) setState(controlPlane *rkev1.RKEControlPlane, phase rkev1.ETCDSnapshotPhase) error {
+	controlPlane = controlPlane.DeepCopy()
+	controlPlane.Status.ETCDSnapshotSourcePhase = phase
+	_, err := e.controlPlane.UpdateStatus(controlPlane)
+	if err != nil {
+		return err
+	}
+	return ErrWaiting("refreshing etcd snapshot source state")
+}
+
+// Start marks a new cluster to be bootstrapped from its snapshot source. Clusters that already applied a plan on one of
+// their machines are never reset to the source, even if it is added later.
+func (e *etcdSource) Start(controlPlane *rkev1.RKEControlPlane, clusterPlan *plan.Plan) error {
+	if controlPlane.Spec.ETCDSnapshotSource == nil || controlPlane.Status.ETCDSnapshotSourcePhase != "" {
+		return nil
+	}
+	for _, node := range clusterPlan.Nodes {
+		if node != nil && node.AppliedPlan != nil {
+			return nil
+		}
+	}
+	return e.setState(controlPlane, rkev1.ETCDSnapshotPhaseRestore)
+}
+
+// Finish marks the restore of the snapshot source as finished, it is called once all nodes applied their plans.
+func (e *etcdSource) Finish(controlPlane *rkev1.RKEControlPlane) error {
+	if !restoringSource(controlPlane) {
+		return nil
+	}
+	return e.setState(controlPlane, rkev1.ETCDSnapshotPhaseFinished)
+}
+
+// ServerToken returns the server token of the source cluster, the snapshot can not be restored with another token. It
+// is empty if the cluster has no snapshot source. The token is only returned if the creator of the cluster can get the
+// source cluster and the secret of the token, and a secret given by serverTokenSecretName must be created by them.
+func (e *etcdSource) ServerToken(controlPlane *rkev1.RKEControlPlane) (string, error) {
+	source := controlPlane.Spec.ETCDSnapshotSource
+	if source == nil {
+		return "", nil
+	}
+
+	secretName := source.ServerTokenSecretName
+	if secretName == "" && source.ClusterName != "" {
+		secretName = name.SafeConcatName(source.ClusterName, "rke", "state")
+	}
+	if secretName == "" {
+		return "", fmt.Errorf("etcd snapshot source of cluster %s/%s requires clusterName or serverTokenSecretName", controlPlane.Namespace, controlPlane.Name)
+	}
+
+	creator, err := e.getCreator(controlPlane)
+	if err != nil {
+		return "", err
+	}
+	if source.ClusterName != "" {
+		if err := e.authorize(creator, authv1.ResourceAttributes{
+			Namespace: controlPlane.Namespace,
+			Verb:      "get",
+			Group:     "provisioning.cattle.io",
+			Resource:  "clusters",
+			Name:      source.ClusterName,
+		}); err != nil {
+			return "", err
+		}
+	}
+	if err := e.authorize(creator, authv1.ResourceAttributes{
+		Namespace: controlPlane.Namespace,
+		Verb:      "get",
+		Resource:  "secrets",
+		Name:      secretName,
+	}); err != nil {
+		return "", err
+	}
+
+	secret, err := e.secrets.Get(controlPlane.Namespace, secretName)
+	if err != nil {
+		return "", fmt.Errorf("failed to lookup server token of etcd snapshot source: %w", err)
+	}
+	if source.ServerTokenSecretName != "" && secret.Annotations[rbac.CreatorIDAnn] != creator {
+		return "", fmt.Errorf("secret %s/%s of etcd snapshot source was not created by %s", secret.Namespace, secret.Name, creator)
+	}
+	token := string(secret.Data["serverToken"])
+	if token == "" {
+		return "", fmt.Errorf("secret %s/%s of etcd snapshot source has no serverToken", secret.Namespace, secret.Name)
+	}
+	return token, nil
+}
+
+// getCreator returns the user that created the provisioning cluster of the control plane, the snapshot source is
+// resolved with their permissions.
+func (e *etcdSource) getCreator(controlPlane *rkev1.RKEControlPlane) (string, error) {
+	cluster, err := e.clusters.Get(controlPlane.Namespace, controlPlane.Spec.ClusterName)
+	if err != nil {
+		return "", fmt.Errorf("failed to lookup cluster of etcd snapshot source: %w", err)
+	}
+	creator := cluster.Annotations[rbac.CreatorIDAnn]
+	if creator == "" {
+		return "", fmt.Errorf("cluster %s/%s has no creator, its etcd snapshot source can not be authorized", cluster.Namespace, cluster.Name)
+	}
+	return creator, nil
+}
+
+func (e *etcdSource) authorize(user string, attributes authv1.ResourceAttributes) error {
+	review, err := e.subjectAccessReviews.Create(context.TODO(), &authv1.SubjectAccessReview{
+		Spec: authv1.SubjectAccessReviewSpec{
+			User:               user,
+			ResourceAttributes: &attributes,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+	if !review.Status.Allowed {
+		return fmt.Errorf("user %s can not %s %s %s/%s of etcd snapshot source", user, attributes.Verb, attributes.Resource,
+			attributes.Namespace, attributes.Name)
+	}
+	return nil
+}
+
+func restoringSource(controlPlane *rkev1.RKEControlPlane) bool {
+	return controlPlane.Spec.ETCDSnapshotSource != nil &&
+		controlPlane.Status.ETCDSnapshotSourcePhase == rkev1.ETCDSnapshotPhaseRestore
+}
+
+// addRestoreInstructions restores the snapshot source on the init node before the runtime is started by the run.sh
+// instruction. The restore is recorded on the node, so that reapplying the plan does not reset the cluster again.
+func (e *etcdSource) addRestoreInstructions(nodePlan plan.NodePlan, controlPlane *rkev1.RKEControlPlane, initNode bool) (plan.NodePlan, error) {
+	if !initNode || !restoringSource(controlPlane) {
+		return nodePlan, nil
+	}
+
+	source := controlPlane.Spec.ETCDSnapshotSource
+	files, instructions, err := e.etcdRestore.restoreInstructions(controlPlane, &rkev1.ETCDSnapshot{
+		Name:            source.Name,
+		S3:              source.S3,
+		Target:          source.Target,
+		EncryptionKeyID: source.EncryptionKeyID,
+	})
+	if err != nil {
+		return nodePlan, err
+	}
+
+	// the files are removed once the cluster is provisioned, which must not restart the node
+	for i := range files {
+		files[i].Dynamic = true
+	}
+
+	marker := fmt.Sprintf("/var/lib/rancher/%s/server/etcd-snapshot-source", GetRuntime(controlPlane.Spec.KubernetesVersion))
+	restore := instructions[len(instructions)-1]
+	instructions[len(instructions)-1] = plan.Instruction{
+		Name:    "restore-etcd-snapshot-source",
+		Command: "sh",
+		Env:     restore.Env,
+		// the restore command is passed as positional parameters so that it is never interpreted by the shell
+		Args: append([]string{
+			"-c",
+			fmt.Sprintf(`[ -f %[1]s ] && exit 0; "$@" && touch %[1]s`, marker),
+			"restore-etcd-snapshot-source",
+			restore.Command,
+		}, restore.Args...),
+	}
+
+	nodePlan.Files = append(nodePlan.Files, files...)
+	nodePlan.Instructions = append(nodePlan.Instructions, instructions...)
+	return nodePlan, nil
+}
+
+// addRemoveSourceNodesInstruction deletes the nodes of the source cluster restored with the snapshot, and their node
+// passwords so that machines reusing their names can join. Nodes are matched by the machine label every node of a
+// provisioned cluster is registered with, the nodes of the machines of the new cluster are kept. The agent credentials
+// of the source cluster are deleted with the pods of the agents, so that the restored agents can not connect as the
+// source cluster; the agents of the new cluster are recreated with their own credentials.
+func (e *etcdSource) addRemoveSourceNodesInstruction(nodePlan plan.NodePlan, controlPlane *rkev1.RKEControlPlane, clusterPlan *plan.Plan, machine *capi.Machine) plan.NodePlan {
+	if !isControlPlane(machine) || !restoringSource(controlPlane) {
+		return nodePlan
+	}
+
+	var uids []string
+	for _, m := range clusterPlan.Machines {
+		uids = append(uids, string(m.UID))
+	}
+	sort.Strings(uids)
+	selector := fmt.Sprintf("%[1]s,%[1]s notin (%[2]s)", MachineUIDLabel, strings.Join(uids, ","))
+
+	runtime := GetRuntime(controlPlane.Spec.KubernetesVersion)
+	kubectl := "k3s kubectl"
+	if runtime == RuntimeRKE2 {
+		kubectl = "/var/lib/rancher/rke2/bin/kubectl --kubeconfig /etc/rancher/rke2/rke2.yaml"
+	}
+
+	nodePlan.Instructions = append(nodePlan.Instructions, plan.Instruction{
+		Name:    "remove-etcd-snapshot-source-nodes",
+		Command: "sh",
+		Args: []string{
+			"-c",
+			fmt.Sprintf(`for i in $(seq 60); do %[1]s get --raw=/readyz >/dev/null 2>&1 && break; sleep 5; done; `+
+				`nodes=$(%[1]s get nodes -l "$1" -o name) || exit 1; `+
+				`for node in $nodes; do `+
+				`%[1]s delete "$node" && %[1]s -n kube-system delete secret --ignore-not-found "${node#node/}.node-password.%[2]s" || exit 1; `+
+				`done; `+
+				`secrets=$(%[1]s -n cattle-system get secrets -o name) || exit 1; `+
+				`for secret in $secrets; do `+
+				`case "$secret" in secret/cattle-credentials-*) ;; *) continue ;; esac; `+
+				`[ "$(%[1]s -n cattle-system get "$secret" -o jsonpath='{.data.namespace}')" = "$2" ] && continue; `+
+				`%[1]s -n cattle-system delete "$secret" || exit 1; `+
+				`done; `+
+				`%[1]s -n cattle-system delete pods --ignore-not-found -l 'app in (cattle-cluster-agent,cattle-agent,cattle-agent-windows)'`,
+				kubectl, runtime),
+			"remove-etcd-snapshot-source-nodes",
+			selector,
+			// the credentials of the agents of the new cluster are for its management cluster
+			base64.StdEncoding.EncodeToString([]byte(controlPlane.Spec.ManagementClusterName)),
+		},
+	})
+	return nodePlan
+}
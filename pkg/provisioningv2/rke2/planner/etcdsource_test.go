@@ -0,0 +1,276 @@
+package planner
+
+import (
+	"encoding/base64"
+	"testing"
+
+	provv1 "github.com/rancher/rancher/pkg/apis/provisioning.cattle.io/v1"
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1/plan"
+	"github.com/rancher/rancher/pkg/controllers/management/rbac"
+	provisioningcontrollers "github.com/rancher/rancher/pkg/generated/controllers/provisioning.cattle.io/v1"
+	corecontrollers "github.com/rancher/wrangler/pkg/generated/controllers/core/v1"
+	"github.com/stretchr/testify/assert"
+	authv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	capi "sigs.k8s.io/cluster-api/api/v1alpha4"
+)
+
+type fakeClusterCache struct {
+	provisioningcontrollers.ClusterCache
+	clusters []*provv1.Cluster
+}
+
+func (f *fakeClusterCache) Get(namespace, name string) (*provv1.Cluster, error) {
+	for _, cluster := range f.clusters {
+		if cluster.Namespace == namespace && cluster.Name == name {
+			return cluster, nil
+		}
+	}
+	return nil, apierrors.NewNotFound(schema.GroupResource{Group: "provisioning.cattle.io", Resource: "clusters"}, name)
+}
+
+type fakeSecretCache struct {
+	corecontrollers.SecretCache
+	secrets []*corev1.Secret
+}
+
+func (f *fakeSecretCache) Get(namespace, name string) (*corev1.Secret, error) {
+	for _, secret := range f.secrets {
+		if secret.Namespace == namespace && secret.Name == name {
+			return secret, nil
+		}
+	}
+	return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, name)
+}
+
+// newTestETCDSource returns an etcd source whose subject access reviews allow the requests of user for the given
+// resource/name pairs.
+func newTestETCDSource(cluster *provv1.Cluster, secrets []*corev1.Secret, user string, allowed ...string) *etcdSource {
+	k8s := fake.NewSimpleClientset()
+	k8s.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authv1.SubjectAccessReview)
+		attributes := review.Spec.ResourceAttributes
+		for _, a := range allowed {
+			if review.Spec.User == user && attributes.Verb == "get" && a == attributes.Resource+"/"+attributes.Name {
+				review.Status.Allowed = true
+			}
+		}
+		return true, review, nil
+	})
+	return &etcdSource{
+		clusters:             &fakeClusterCache{clusters: []*provv1.Cluster{cluster}},
+		secrets:              &fakeSecretCache{secrets: secrets},
+		subjectAccessReviews: k8s.AuthorizationV1().SubjectAccessReviews(),
+	}
+}
+
+func TestServerToken(t *testing.T) {
+	cluster := &provv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "clone",
+			Namespace:   "fleet-default",
+			Annotations: map[string]string{rbac.CreatorIDAnn: "u-creator"},
+		},
+	}
+	state := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "source-rke-state", Namespace: "fleet-default"},
+		Data:       map[string][]byte{"serverToken": []byte("source-token")},
+	}
+	owned := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "owned-token",
+			Namespace:   "fleet-default",
+			Annotations: map[string]string{rbac.CreatorIDAnn: "u-creator"},
+		},
+		Data: map[string][]byte{"serverToken": []byte("owned-token")},
+	}
+	other := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "other-token",
+			Namespace:   "fleet-default",
+			Annotations: map[string]string{rbac.CreatorIDAnn: "u-other"},
+		},
+		Data: map[string][]byte{"serverToken": []byte("other-token")},
+	}
+
+	tests := []struct {
+		name    string
+		source  *rkev1.ETCDSnapshotSource
+		creator string
+		allowed []string
+		token   string
+		wantErr bool
+	}{
+		{
+			name: "no source",
+		},
+		{
+			name:    "source cluster state",
+			source:  &rkev1.ETCDSnapshotSource{ClusterName: "source"},
+			creator: "u-creator",
+			allowed: []string{"clusters/source", "secrets/source-rke-state"},
+			token:   "source-token",
+		},
+		{
+			name:    "source cluster not allowed",
+			source:  &rkev1.ETCDSnapshotSource{ClusterName: "source"},
+			creator: "u-creator",
+			allowed: []string{"secrets/source-rke-state"},
+			wantErr: true,
+		},
+		{
+			name:    "source cluster state not allowed",
+			source:  &rkev1.ETCDSnapshotSource{ClusterName: "source"},
+			creator: "u-creator",
+			allowed: []string{"clusters/source"},
+			wantErr: true,
+		},
+		{
+			name:    "cluster without creator",
+			source:  &rkev1.ETCDSnapshotSource{ClusterName: "source"},
+			allowed: []string{"clusters/source", "secrets/source-rke-state"},
+			wantErr: true,
+		},
+		{
+			name:    "secret of the creator",
+			source:  &rkev1.ETCDSnapshotSource{ServerTokenSecretName: "owned-token"},
+			creator: "u-creator",
+			allowed: []string{"secrets/owned-token"},
+			token:   "owned-token",
+		},
+		{
+			name:    "secret of another user",
+			source:  &rkev1.ETCDSnapshotSource{ServerTokenSecretName: "other-token"},
+			creator: "u-creator",
+			allowed: []string{"secrets/other-token"},
+			wantErr: true,
+		},
+		{
+			name:    "missing secret",
+			source:  &rkev1.ETCDSnapshotSource{ServerTokenSecretName: "missing"},
+			creator: "u-creator",
+			allowed: []string{"secrets/missing"},
+			wantErr: true,
+		},
+		{
+			name:    "no cluster or secret",
+			source:  &rkev1.ETCDSnapshotSource{Name: "snapshot"},
+			creator: "u-creator",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := cluster.DeepCopy()
+			if tt.creator == "" {
+				delete(cluster.Annotations, rbac.CreatorIDAnn)
+			}
+			e := newTestETCDSource(cluster, []*corev1.Secret{state, owned, other}, "u-creator", tt.allowed...)
+			controlPlane := &rkev1.RKEControlPlane{
+				ObjectMeta: metav1.ObjectMeta{Name: "clone", Namespace: "fleet-default"},
+				Spec: rkev1.RKEControlPlaneSpec{
+					ClusterName:        "clone",
+					ETCDSnapshotSource: tt.source,
+				},
+			}
+
+			token, err := e.ServerToken(controlPlane)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.token, token)
+		})
+	}
+}
+
+func TestAddRemoveSourceNodesInstruction(t *testing.T) {
+	controlPlaneMachine := &capi.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "cp",
+			UID:    types.UID("uid-b"),
+			Labels: map[string]string{ControlPlaneRoleLabel: "true"},
+		},
+	}
+	workerMachine := &capi.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "worker",
+			UID:    types.UID("uid-a"),
+			Labels: map[string]string{WorkerRoleLabel: "true"},
+		},
+	}
+	clusterPlan := &plan.Plan{
+		Machines: map[string]*capi.Machine{
+			"cp":     controlPlaneMachine,
+			"worker": workerMachine,
+		},
+	}
+	newControlPlane := func(kubernetesVersion string, phase rkev1.ETCDSnapshotPhase) *rkev1.RKEControlPlane {
+		return &rkev1.RKEControlPlane{
+			Spec: rkev1.RKEControlPlaneSpec{
+				KubernetesVersion:     kubernetesVersion,
+				ManagementClusterName: "c-m-clone",
+				ETCDSnapshotSource:    &rkev1.ETCDSnapshotSource{ClusterName: "source", Name: "snapshot"},
+			},
+			Status: rkev1.RKEControlPlaneStatus{
+				ETCDSnapshotSourcePhase: phase,
+			},
+		}
+	}
+
+	e := &etcdSource{}
+
+	nodePlan := e.addRemoveSourceNodesInstruction(plan.NodePlan{}, newControlPlane("v1.21.4+rke2r2", rkev1.ETCDSnapshotPhaseFinished), clusterPlan, controlPlaneMachine)
+	assert.Empty(t, nodePlan.Instructions, "source nodes are only removed while the source is restored")
+
+	nodePlan = e.addRemoveSourceNodesInstruction(plan.NodePlan{}, newControlPlane("v1.21.4+rke2r2", rkev1.ETCDSnapshotPhaseRestore), clusterPlan, workerMachine)
+	assert.Empty(t, nodePlan.Instructions, "source nodes are only removed by control plane nodes")
+
+	for _, tt := range []struct {
+		kubernetesVersion string
+		kubectl           string
+		nodePassword      string
+	}{
+		{
+			kubernetesVersion: "v1.21.4+rke2r2",
+			kubectl:           "/var/lib/rancher/rke2/bin/kubectl --kubeconfig /etc/rancher/rke2/rke2.yaml",
+			nodePassword:      ".node-password.rke2",
+		},
+		{
+			kubernetesVersion: "v1.21.4+k3s1",
+			kubectl:           "k3s kubectl",
+			nodePassword:      ".node-password.k3s",
+		},
+	} {
+		t.Run(tt.kubernetesVersion, func(t *testing.T) {
+			nodePlan := e.addRemoveSourceNodesInstruction(plan.NodePlan{}, newControlPlane(tt.kubernetesVersion, rkev1.ETCDSnapshotPhaseRestore), clusterPlan, controlPlaneMachine)
+			if !assert.Len(t, nodePlan.Instructions, 1) {
+				return
+			}
+			instruction := nodePlan.Instructions[0]
+			assert.Equal(t, "remove-etcd-snapshot-source-nodes", instruction.Name)
+			assert.Equal(t, "sh", instruction.Command)
+			if !assert.Len(t, instruction.Args, 5) {
+				return
+			}
+			assert.Equal(t, "-c", instruction.Args[0])
+			assert.Contains(t, instruction.Args[1], tt.kubectl+` get nodes -l "$1" -o name`)
+			assert.Contains(t, instruction.Args[1], tt.nodePassword)
+			assert.Contains(t, instruction.Args[1], "secret/cattle-credentials-*")
+			assert.Contains(t, instruction.Args[1], "delete pods --ignore-not-found -l 'app in (cattle-cluster-agent,cattle-agent,cattle-agent-windows)'")
+			// the nodes of the machines of the cluster are kept, the selector is passed as a parameter of the script
+			assert.Equal(t, MachineUIDLabel+","+MachineUIDLabel+" notin (uid-a,uid-b)", instruction.Args[3])
+			assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("c-m-clone")), instruction.Args[4])
+		})
+	}
+}
@@ -0,0 +1,333 @@
+package planner
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1/plan"
+	"github.com/rancher/rancher/pkg/controllers/provisioningv2/rke2/machineprovision"
+	"github.com/rancher/rancher/pkg/etcdsnapshot"
+	"github.com/rancher/rancher/pkg/etcdsnapshot/target"
+	"github.com/rancher/rancher/pkg/settings"
+	"github.com/rancher/rancher/pkg/wrangler"
+	corecontrollers "github.com/rancher/wrangler/pkg/generated/controllers/core/v1"
+	"github.com/rancher/wrangler/pkg/name"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1alpha4"
+)
+
+const (
+	etcdSnapshotTargetFile = "etcd-snapshot-target.json"
+	// defaultSnapshotRetention is the retention of RKE2 and K3s, also used for the remote targets if none is set
+	defaultSnapshotRetention = 5
+)
+
+// etcdTarget renders the backup targets of the snapshots into the plans. The snapshots are transferred to and from
+// the remote targets, and S3 if they are encrypted, by the agent binary, run from the agent image on the etcd nodes.
+type etcdTarget struct {
+	secretCache  corecontrollers.SecretCache
+	secretClient corecontrollers.SecretClient
+}
+
+func newETCDTarget(clients *wrangler.Context) *etcdTarget {
+	return &etcdTarget{
+		secretCache:  clients.Core.Secret().Cache(),
+		secretClient: clients.Core.Secret(),
+	}
+}
+
+// ETCDSnapshotSecretName returns the secret holding the target credentials and encryption keys the etcd nodes of the
+// cluster are given. The agent reads it with the service account of the system agent, the etcd machines are allowed to
+// get it.
+func ETCDSnapshotSecretName(clusterName string) string {
+	return name.SafeConcatName(clusterName, "etcd", "snapshot")
+}
+
+// AgentTransfer returns the plan files and arguments of the agent for transferring the snapshots to the remote target,
+// or S3 if the snapshots are encrypted. The credentials of the target and the encryption keys are not part of the
+// plan, they are stored in the etcd snapshot secret of the cluster, which the agent reads. It returns false if the
+// snapshots are transferred by the distribution.
+func (e *etcdTarget) AgentTransfer(s3 *rkev1.ETCDSnapshotS3, t *rkev1.ETCDSnapshotTarget, controlPlane *rkev1.RKEControlPlane) ([]plan.File, []string, bool, error) {
+	var (
+		config   *target.Config
+		credName string
+		err      error
+	)
+	switch {
+	case isRemoteTarget(t):
+		config, credName, err = e.targetConfig(t, controlPlane)
+	case s3 != nil && isEncrypted(controlPlane):
+		config, credName, err = e.s3Config(s3, controlPlane)
+	default:
+		return nil, nil, false, nil
+	}
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	var args []string
+	data := map[string][]byte{}
+	if credName != "" {
+		credentials, err := json.Marshal(config.Credentials)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		key := credentialsSecretKey(credName)
+		data[key] = credentials
+		args = append(args, fmt.Sprintf("--credentials=%s", key))
+	}
+	config.Credentials = nil
+	if isEncrypted(controlPlane) {
+		ring, err := e.keyRing(controlPlane)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		keys, err := json.Marshal(ring)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		data[etcdsnapshot.KeyRingSecretKey] = keys
+	}
+
+	file, configPath, err := jsonFile(controlPlane, etcdSnapshotTargetFile, config)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	files := []plan.File{file}
+	args = append(args, fmt.Sprintf("--config=%s", configPath))
+
+	if len(data) > 0 {
+		secretName, err := e.ensureSecret(controlPlane, data)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		args = append(args, fmt.Sprintf("--secret=%s", secretName))
+	}
+
+	return files, args, true, nil
+}
+
+// keyRing returns the keys the etcd nodes are given: the active key, and the key of the snapshot that is restored
+// while a restore is in progress. The other keys of the encryption secret stay in rancher.
+func (e *etcdTarget) keyRing(controlPlane *rkev1.RKEControlPlane) (*etcdsnapshot.KeyRing, error) {
+	encryption := controlPlane.Spec.ETCD.Encryption
+	secret, err := e.secretCache.Get(controlPlane.Namespace, encryption.SecretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup etcd snapshot encryption secretName: %w", err)
+	}
+	ring, err := etcdsnapshot.KeyRingFromSecret(secret, encryption.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &etcdsnapshot.KeyRing{
+		Active: ring.Active,
+		Keys: map[string][]byte{
+			ring.Active: ring.Keys[ring.Active],
+		},
+	}
+	for _, keyID := range restoreKeyIDs(controlPlane) {
+		key, ok := ring.Keys[keyID]
+		if !ok {
+			return nil, fmt.Errorf("etcd snapshot encryption key %s not found in secret %s/%s", keyID, secret.Namespace, secret.Name)
+		}
+		result.Keys[keyID] = key
+	}
+	return result, nil
+}
+
+// restoreKeyIDs returns the keys of the snapshots restored or bootstrapped from that are not finished yet.
+func restoreKeyIDs(controlPlane *rkev1.RKEControlPlane) []string {
+	var result []string
+	if restore := controlPlane.Spec.ETCDSnapshotRestore; restore != nil && restore.EncryptionKeyID != "" &&
+		controlPlane.Status.ETCDSnapshotRestorePhase != rkev1.ETCDSnapshotPhaseFinished {
+		result = append(result, restore.EncryptionKeyID)
+	}
+	if source := controlPlane.Spec.ETCDSnapshotSource; source != nil && source.EncryptionKeyID != "" &&
+		controlPlane.Status.ETCDSnapshotSourcePhase != rkev1.ETCDSnapshotPhaseFinished {
+		result = append(result, source.EncryptionKeyID)
+	}
+	return result
+}
+
+// credentialsSecretKey returns the entry of the etcd snapshot secret holding the credentials of the cloud credential.
+// Every cloud credential gets its own entry, so that restoring from or saving to another target does not change the
+// credentials the periodic sync of the nodes reads.
+func credentialsSecretKey(credName string) string {
+	return "credentials-" + strings.ReplaceAll(credName, ":", ".")
+}
+
+// ensureSecret stores the entries of data in the etcd snapshot secret of the cluster and returns its name. The
+// credentials of other cloud credentials are kept, the encryption keys are replaced.
+func (e *etcdTarget) ensureSecret(controlPlane *rkev1.RKEControlPlane, entries map[string][]byte) (string, error) {
+	secretName := ETCDSnapshotSecretName(controlPlane.Name)
+	secret, err := e.secretCache.Get(controlPlane.Namespace, secretName)
+	if apierror.IsNotFound(err) {
+		_, err = e.secretClient.Create(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretName,
+				Namespace: controlPlane.Namespace,
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: "rke.cattle.io/v1",
+						Kind:       "RKEControlPlane",
+						Name:       controlPlane.Name,
+						UID:        controlPlane.UID,
+					},
+				},
+			},
+			Data: entries,
+			Type: SecretTypeETCDSnapshot,
+		})
+		return secretName, err
+	} else if err != nil {
+		return "", err
+	}
+
+	data := map[string][]byte{}
+	for k, v := range secret.Data {
+		if k != etcdsnapshot.KeyRingSecretKey {
+			data[k] = v
+		}
+	}
+	for k, v := range entries {
+		data[k] = v
+	}
+
+	if !equality.Semantic.DeepEqual(secret.Data, data) {
+		secret = secret.DeepCopy()
+		secret.Data = data
+		if _, err := e.secretClient.Update(secret); err != nil {
+			return "", err
+		}
+	}
+	return secretName, nil
+}
+
+// targetConfig returns the config of the target and the name of its cloud credential, the credentials of the target
+// default to the ones of the cluster target.
+func (e *etcdTarget) targetConfig(t *rkev1.ETCDSnapshotTarget, controlPlane *rkev1.RKEControlPlane) (*target.Config, string, error) {
+	config := &target.Config{
+		Type:     t.Type,
+		Bucket:   t.Bucket,
+		Folder:   t.Folder,
+		Endpoint: t.Endpoint,
+		Host:     t.Host,
+		HostKey:  t.HostKey,
+		Path:     t.Path,
+	}
+
+	credName := t.CloudCredentialName
+	if credName == "" && controlPlane.Spec.ETCD != nil && controlPlane.Spec.ETCD.Target != nil {
+		credName = controlPlane.Spec.ETCD.Target.CloudCredentialName
+	}
+	if credName != "" {
+		secret, err := machineprovision.GetCloudCredentialSecret(e.secretCache, controlPlane.Namespace, credName)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to lookup etcd snapshot target cloudCredentialName: %w", err)
+		}
+		config.Credentials = target.CredentialsFromSecret(secret)
+	}
+	return config, credName, nil
+}
+
+// s3Config returns the config of the S3 target and the name of its cloud credential, the credentials default to the
+// ones of the cluster S3 target like the arguments of the distribution.
+func (e *etcdTarget) s3Config(s3 *rkev1.ETCDSnapshotS3, controlPlane *rkev1.RKEControlPlane) (*target.Config, string, error) {
+	credName := s3.CloudCredentialName
+	if credName == "" && controlPlane.Spec.ETCD != nil && controlPlane.Spec.ETCD.S3 != nil {
+		credName = controlPlane.Spec.ETCD.S3.CloudCredentialName
+	}
+	s3Cred, err := getS3Credential(e.secretCache, controlPlane.Namespace, credName, s3.Region)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &target.Config{
+		Type:          target.TypeS3,
+		Bucket:        s3.Bucket,
+		Folder:        s3.Folder,
+		Endpoint:      s3.Endpoint,
+		EndpointCA:    s3.EndpointCA,
+		SkipSSLVerify: s3.SkipSSLVerify,
+		Region:        s3Cred.Region,
+		Credentials: map[string]string{
+			target.CredentialAccessKey: s3Cred.AccessKey,
+			target.CredentialSecretKey: s3Cred.SecretKey,
+		},
+	}, credName, nil
+}
+
+func jsonFile(controlPlane *rkev1.RKEControlPlane, name string, obj interface{}) (plan.File, string, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return plan.File{}, "", err
+	}
+	filePath := configFile(controlPlane, name)
+	return plan.File{
+		Content: base64.StdEncoding.EncodeToString(data),
+		Path:    filePath,
+	}, filePath, nil
+}
+
+// Instruction returns an instruction running the etcd-snapshot command of the agent with the arguments returned by
+// AgentTransfer.
+func (e *etcdTarget) Instruction(controlPlane *rkev1.RKEControlPlane, name string, args ...string) plan.Instruction {
+	return plan.Instruction{
+		Name:    "etcd-snapshot-" + name,
+		Image:   settings.PrefixPrivateRegistry(settings.AgentImage.Get()),
+		Command: "sh",
+		Args: append([]string{
+			"-c",
+			`exec ./usr/bin/agent etcd-snapshot "$@"`,
+			"etcd-snapshot",
+			name,
+			fmt.Sprintf("--dir=%s", etcdSnapshotDir(controlPlane)),
+		}, args...),
+	}
+}
+
+// isEncrypted returns true if the snapshots of the cluster are encrypted, S3 is then transferred by the agent.
+func isEncrypted(controlPlane *rkev1.RKEControlPlane) bool {
+	return controlPlane.Spec.ETCD != nil && controlPlane.Spec.ETCD.Encryption != nil
+}
+
+func isRemoteTarget(t *rkev1.ETCDSnapshotTarget) bool {
+	return t != nil && target.IsRemote(t.Type)
+}
+
+func etcdSnapshotDir(controlPlane *rkev1.RKEControlPlane) string {
+	return fmt.Sprintf("/var/lib/rancher/%s/server/db/snapshots", GetRuntime(controlPlane.Spec.KubernetesVersion))
+}
+
+func etcdSnapshotRetention(controlPlane *rkev1.RKEControlPlane) int {
+	if controlPlane.Spec.ETCD != nil && controlPlane.Spec.ETCD.SnapshotRetention > 0 {
+		return controlPlane.Spec.ETCD.SnapshotRetention
+	}
+	return defaultSnapshotRetention
+}
+
+// addETCDSnapshotSync installs a periodic sync of the scheduled snapshots of the etcd nodes to the remote target of the
+// cluster, or S3 if the snapshots are encrypted. It is not part of the config of the distribution, so that changing the
+// target does not restart the nodes.
+func (p *Planner) addETCDSnapshotSync(nodePlan plan.NodePlan, controlPlane *rkev1.RKEControlPlane, machine *capi.Machine) (plan.NodePlan, error) {
+	etcd := controlPlane.Spec.ETCD
+	if !isEtcd(machine) || etcd == nil || etcd.DisableSnapshots {
+		return nodePlan, nil
+	}
+
+	files, args, ok, err := p.etcdTarget.AgentTransfer(etcd.S3, etcd.Target, controlPlane)
+	if err != nil || !ok {
+		return nodePlan, err
+	}
+	nodePlan.Files = append(nodePlan.Files, files...)
+	nodePlan.Instructions = append(nodePlan.Instructions, p.etcdTarget.Instruction(controlPlane, "install",
+		append(args, fmt.Sprintf("--retention=%d", etcdSnapshotRetention(controlPlane)))...))
+	return nodePlan, nil
+}
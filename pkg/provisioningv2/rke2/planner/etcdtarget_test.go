@@ -0,0 +1,145 @@
+package planner
+
+import (
+	"bytes"
+	"testing"
+
+	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
+	"github.com/rancher/rancher/pkg/etcdsnapshot"
+	corecontrollers "github.com/rancher/wrangler/pkg/generated/controllers/core/v1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestETCDTargetKeyRing(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "fleet-default",
+			Name:        "etcd-keys",
+			Annotations: map[string]string{etcdsnapshot.ActiveKeyAnnotation: "k2"},
+		},
+		Data: map[string][]byte{
+			"k1": bytes.Repeat([]byte{1}, etcdsnapshot.KeySize),
+			"k2": bytes.Repeat([]byte{2}, etcdsnapshot.KeySize),
+			"k3": bytes.Repeat([]byte{3}, etcdsnapshot.KeySize),
+		},
+	}
+	e := &etcdTarget{
+		secretCache: &fakeSecretCache{secrets: []*corev1.Secret{secret}},
+	}
+
+	tests := []struct {
+		name    string
+		restore *rkev1.ETCDSnapshot
+		phase   rkev1.ETCDSnapshotPhase
+		keys    []string
+		wantErr bool
+	}{
+		{
+			name: "active key only",
+			keys: []string{"k2"},
+		},
+		{
+			name:    "key of the restored snapshot",
+			restore: &rkev1.ETCDSnapshot{Name: "snapshot", EncryptionKeyID: "k1"},
+			phase:   rkev1.ETCDSnapshotPhaseRestore,
+			keys:    []string{"k1", "k2"},
+		},
+		{
+			name:    "finished restore",
+			restore: &rkev1.ETCDSnapshot{Name: "snapshot", EncryptionKeyID: "k1"},
+			phase:   rkev1.ETCDSnapshotPhaseFinished,
+			keys:    []string{"k2"},
+		},
+		{
+			name:    "unknown key of the restored snapshot",
+			restore: &rkev1.ETCDSnapshot{Name: "snapshot", EncryptionKeyID: "k9"},
+			phase:   rkev1.ETCDSnapshotPhaseRestore,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controlPlane := &rkev1.RKEControlPlane{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "fleet-default", Name: "cluster"},
+			}
+			controlPlane.Spec.ETCD = &rkev1.ETCD{
+				Encryption: &rkev1.ETCDSnapshotEncryption{SecretName: "etcd-keys"},
+			}
+			controlPlane.Spec.ETCDSnapshotRestore = tt.restore
+			controlPlane.Status.ETCDSnapshotRestorePhase = tt.phase
+
+			ring, err := e.keyRing(controlPlane)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "k2", ring.Active)
+			var keys []string
+			for id, key := range ring.Keys {
+				assert.Equal(t, secret.Data[id], key)
+				keys = append(keys, id)
+			}
+			assert.ElementsMatch(t, tt.keys, keys)
+		})
+	}
+}
+
+type fakeSecretClient struct {
+	corecontrollers.SecretClient
+	created []*corev1.Secret
+	updated []*corev1.Secret
+}
+
+func (f *fakeSecretClient) Create(secret *corev1.Secret) (*corev1.Secret, error) {
+	f.created = append(f.created, secret)
+	return secret, nil
+}
+
+func (f *fakeSecretClient) Update(secret *corev1.Secret) (*corev1.Secret, error) {
+	f.updated = append(f.updated, secret)
+	return secret, nil
+}
+
+func TestETCDTargetEnsureSecret(t *testing.T) {
+	controlPlane := &rkev1.RKEControlPlane{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "fleet-default", Name: "cluster"},
+	}
+	existing := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "fleet-default", Name: ETCDSnapshotSecretName("cluster")},
+		Data: map[string][]byte{
+			credentialsSecretKey("cattle-global-data:cc-old"):  []byte(`{"username":"old"}`),
+			credentialsSecretKey("cattle-global-data:cc-sync"): []byte(`{"username":"sync"}`),
+			etcdsnapshot.KeyRingSecretKey:                      []byte(`{"active":"k1"}`),
+		},
+	}
+
+	client := &fakeSecretClient{}
+	e := &etcdTarget{
+		secretCache:  &fakeSecretCache{},
+		secretClient: client,
+	}
+	name, err := e.ensureSecret(controlPlane, map[string][]byte{
+		credentialsSecretKey("cattle-global-data:cc-sync"): []byte(`{"username":"sync"}`),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "cluster-etcd-snapshot", name)
+	if assert.Len(t, client.created, 1) {
+		assert.Equal(t, SecretTypeETCDSnapshot, string(client.created[0].Type))
+		assert.Equal(t, "cluster", client.created[0].OwnerReferences[0].Name)
+	}
+
+	e.secretCache = &fakeSecretCache{secrets: []*corev1.Secret{existing}}
+	_, err = e.ensureSecret(controlPlane, map[string][]byte{
+		credentialsSecretKey("cattle-global-data:cc-sync"): []byte(`{"username":"new"}`),
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, client.updated, 1) {
+		assert.Equal(t, map[string][]byte{
+			"credentials-cattle-global-data.cc-old":  []byte(`{"username":"old"}`),
+			"credentials-cattle-global-data.cc-sync": []byte(`{"username":"new"}`),
+		}, client.updated[0].Data)
+	}
+}
@@ -17,6 +17,7 @@
 	v3 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
 	rkev1 "github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1"
 	"github.com/rancher/rancher/pkg/apis/rke.cattle.io/v1/plan"
+	"github.com/rancher/rancher/pkg/etcdsnapshot/target"
 	capicontrollers "github.com/rancher/rancher/pkg/generated/controllers/cluster.x-k8s.io/v1alpha4"
 	mgmtcontrollers "github.com/rancher/rancher/pkg/generated/controllers/management.cattle.io/v3"
 	rkecontrollers "github.com/rancher/rancher/pkg/generated/controllers/rke.cattle.io/v1"
@@ -66,7 +67,8 @@
 	AddressAnnotation         = "rke.cattle.io/address"
 	InternalAddressAnnotation = "rke.cattle.io/internal-address"
 
-	SecretTypeMachinePlan = "rke.cattle.io/machine-plan"
+	SecretTypeMachinePlan  = "rke.cattle.io/machine-plan"
+	SecretTypeETCDSnapshot = "rke.cattle.io/etcd-snapshot"
 
 	authnWebhookFileName = "/var/lib/rancher/%s/kube-api-authn-webhook.yaml"
 	ConfigYamlFileName   = "/etc/rancher/%s/config.yaml.d/50-rancher.yaml"
@@ -129,7 +131,10 @@ type Planner struct {
 	locker                        locker.Locker
 	etcdRestore                   *etcdRestore
 	etcdCreate                    *etcdCreate
+	certificateRotation           *certificateRotation
+	etcdSource                    *etcdSource
 	etcdArgs                      s3Args
+	etcdTarget                    *etcdTarget
 }
 
 func New(ctx context.Context, clients *wrangler.Context) *Planner {
@@ -138,6 +143,7 @@ func New(ctx context.Context, clients *wrangler.Context) *Planner {
 	})
 	store := NewStore(clients.Core.Secret(),
 		clients.CAPI.Machine().Cache())
+	etcdRestore := newETCDRestore(clients, store)
 	return &Planner{
 		ctx:                           ctx,
 		store:                         store,
@@ -149,12 +155,15 @@ func New(ctx context.Context, clients *wrangler.Context) *Planner {
 		managementClusters:            clients.Mgmt.Cluster().Cache(),
 		rkeControlPlanes:              clients.RKE.RKEControlPlane(),
 		kubeconfig:                    kubeconfig.New(clients),
-		etcdRestore:                   newETCDRestore(clients, store),
+		etcdRestore:                   etcdRestore,
 		etcdCreate:                    newETCDCreate(clients, store),
+		certificateRotation:           newCertificateRotation(clients),
+		etcdSource:                    newETCDSource(clients, etcdRestore),
 		etcdArgs: s3Args{
 			prefix:      "etcd-",
 			secretCache: clients.Core.Secret().Cache(),
 		},
+		etcdTarget: newETCDTarget(clients),
 	}
 }
 
@@ -207,6 +216,14 @@ func (p *Planner) Process(controlPlane *rkev1.RKEControlPlane) error {
 		return err
 	}
 
+	if err := p.certificateRotation.Rotate(controlPlane, plan); err != nil {
+		return err
+	}
+
+	if err := p.etcdSource.Start(controlPlane, plan); err != nil {
+		return err
+	}
+
 	if _, err := p.electInitNode(controlPlane, plan); err != nil {
 		return err
 	}
@@ -261,7 +278,11 @@ func (p *Planner) Process(controlPlane *rkev1.RKEControlPlane) error {
 		return ErrWaiting(firstIgnoreError.Error())
 	}
 
-	return nil
+	if err := p.etcdSource.Finish(controlPlane); err != nil {
+		return err
+	}
+
+	return p.certificateRotation.Finish(controlPlane, plan)
 }
 
 func ignoreErrors(firstIgnoreError error, err error) (error, error) {
@@ -456,7 +477,7 @@ func (p *Planner) reconcile(controlPlane *rkev1.RKEControlPlane, secret plan.Sec
 		}
 		messages[entry.Machine.Name] = strings.Join(summary.Message, ", ")
 
-		plan, err := p.desiredPlan(controlPlane, secret, entry, isInitNode(entry.Machine), joinServer)
+		plan, err := p.desiredPlan(controlPlane, secret, clusterPlan, entry, isInitNode(entry.Machine), joinServer)
 		if err != nil {
 			return err
 		}
@@ -557,8 +578,16 @@ func (p *Planner) addETCD(config map[string]interface{}, controlPlane *rkev1.RKE
 	if controlPlane.Spec.ETCD.SnapshotScheduleCron != "" {
 		config["etcd-snapshot-schedule-cron"] = controlPlane.Spec.ETCD.SnapshotScheduleCron
 	}
+	if t := controlPlane.Spec.ETCD.Target; t != nil && t.Type == target.TypeFilesystem {
+		config["etcd-snapshot-dir"] = t.Path
+	}
 
-	args, _, files, err := p.etcdArgs.ToArgs(controlPlane.Spec.ETCD.S3, controlPlane)
+	s3 := controlPlane.Spec.ETCD.S3
+	if isEncrypted(controlPlane) {
+		// encrypted snapshots are uploaded by the agent, see addETCDSnapshotSync
+		s3 = nil
+	}
+	args, _, files, err := p.etcdArgs.ToArgs(s3, controlPlane)
 	if err != nil {
 		return nil, err
 	}
@@ -722,6 +751,9 @@ func restartStamp(nodePlan plan.NodePlan, controlPlane *rkev1.RKEControlPlane, i
 		restartStamp.Write([]byte(file.Content))
 	}
 	restartStamp.Write([]byte(strconv.FormatInt(controlPlane.Status.ConfigGeneration, 10)))
+	if controlPlane.Status.CertificateRotationGeneration > 0 {
+		restartStamp.Write([]byte(strconv.FormatInt(controlPlane.Status.CertificateRotationGeneration, 10)))
+	}
 	return hex.EncodeToString(restartStamp.Sum(nil))
 }
 
@@ -740,6 +772,11 @@ func (p *Planner) addInstruction(nodePlan plan.NodePlan, controlPlane *rkev1.RKE
 	if isOnlyWorker(machine) {
 		instruction.Env = append(instruction.Env, fmt.Sprintf("INSTALL_%s_EXEC=agent", GetRuntimeEnv(controlPlane.Spec.KubernetesVersion)))
 	}
+	if controlPlane.Status.CertificateRotationGeneration > 0 {
+		instruction.Env = append(instruction.Env, fmt.Sprintf("%s=%d", certificateRotationGenerationEnv, controlPlane.Status.CertificateRotationGeneration))
+	}
+
+	nodePlan = addCertificateRotationInstruction(nodePlan, controlPlane, machine)
 	nodePlan.Instructions = append(nodePlan.Instructions, instruction)
 	return nodePlan, nil
 }
@@ -898,7 +935,7 @@ func (p *Planner) addConfigFile(nodePlan plan.NodePlan, controlPlane *rkev1.RKEC
 	return nodePlan, nil
 }
 
-func (p *Planner) desiredPlan(controlPlane *rkev1.RKEControlPlane, secret plan.Secret, entry planEntry, initNode bool, joinServer string) (nodePlan plan.NodePlan, err error) {
+func (p *Planner) desiredPlan(controlPlane *rkev1.RKEControlPlane, secret plan.Secret, clusterPlan *plan.Plan, entry planEntry, initNode bool, joinServer string) (nodePlan plan.NodePlan, err error) {
 	if !controlPlane.Spec.UnmanagedConfig {
 		nodePlan, err = commonNodePlan(p.secretCache, controlPlane, plan.NodePlan{})
 		if err != nil {
@@ -931,12 +968,24 @@ func (p *Planner) desiredPlan(controlPlane *rkev1.RKEControlPlane, secret plan.S
 		return nodePlan, err
 	}
 
+	nodePlan, err = p.etcdSource.addRestoreInstructions(nodePlan, controlPlane, initNode)
+	if err != nil {
+		return nodePlan, err
+	}
+
 	// Add instruction last because it hashes config content
 	nodePlan, err = p.addInstruction(nodePlan, controlPlane, entry.Machine)
 	if err != nil {
 		return nodePlan, err
 	}
 
+	nodePlan = p.etcdSource.addRemoveSourceNodesInstruction(nodePlan, controlPlane, clusterPlan, entry.Machine)
+
+	nodePlan, err = p.addETCDSnapshotSync(nodePlan, controlPlane, entry.Machine)
+	if err != nil {
+		return nodePlan, err
+	}
+
 	if initNode && IsOnlyEtcd(entry.Machine) {
 		nodePlan, err = p.addInitNodeInstruction(nodePlan, controlPlane, entry.Machine)
 		if err != nil {
@@ -1035,10 +1084,17 @@ func (p *Planner) ensureRKEStateSecret(controlPlane *rkev1.RKEControlPlane, full
 	name := name.SafeConcatName(controlPlane.Name, "rke", "state")
 	secret, err := p.secretCache.Get(controlPlane.Namespace, name)
 	if apierror.IsNotFound(err) {
-		serverToken, err := randomtoken.Generate()
+		// clusters bootstrapped from a snapshot of another cluster have to use its server token
+		serverToken, err := p.etcdSource.ServerToken(controlPlane)
 		if err != nil {
 			return "", plan.Secret{}, err
 		}
+		if serverToken == "" {
+			serverToken, err = randomtoken.Generate()
+			if err != nil {
+				return "", plan.Secret{}, err
+			}
+		}
 
 		agentToken, err := randomtoken.Generate()
 		if err != nil {
@@ -7,6 +7,7 @@
 	"time"
 
 	v32 "github.com/rancher/rancher/pkg/apis/management.cattle.io/v3"
+	"github.com/rancher/rancher/pkg/settings"
 
 	rkeCluster "github.com/rancher/rke/cluster"
 	"github.com/rancher/rke/pki"
@@ -14,6 +15,12 @@
 	v1 "k8s.io/api/core/v1"
 )
 
+const (
+	CertificateExpired  = "Expired"
+	CertificateCritical = "Critical"
+	CertificateWarning  = "Warning"
+)
+
 func CleanCertificateBundle(certs map[string]pki.CertificatePKI) {
 	for name := range certs {
 		if strings.Contains(name, "token") || strings.Contains(name, "header") || strings.Contains(name, "admin") {
@@ -43,6 +50,28 @@ func GetCertExpirationDate(c string) (*time.Time, error) {
 	return &certs[0].NotAfter, nil
 }
 
+// ExpirationThresholds returns how long before their expiration certificates are reported as critical and as warning.
+func ExpirationThresholds() (critical, warning time.Duration) {
+	days := func(s settings.Setting) time.Duration {
+		return time.Duration(s.GetInt()) * 24 * time.Hour
+	}
+	return days(settings.CertificateExpirationCriticalDays), days(settings.CertificateExpirationWarningDays)
+}
+
+// ExpirationSeverity classifies the expiration date of a certificate, an empty severity means the certificate
+// does not expire within the warning threshold.
+func ExpirationSeverity(date, now time.Time, critical, warning time.Duration) string {
+	switch {
+	case !now.Before(date):
+		return CertificateExpired
+	case now.Add(critical).After(date):
+		return CertificateCritical
+	case now.Add(warning).After(date):
+		return CertificateWarning
+	}
+	return ""
+}
+
 func CertBundleFromConfig(cm *v1.ConfigMap) (map[string]pki.CertificatePKI, error) {
 	if cm == nil {
 		return nil, errors.New("full-cluster-state configmap not found")
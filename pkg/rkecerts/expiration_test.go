@@ -115,3 +115,26 @@ func TestCertBundleFromConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestExpirationSeverity(t *testing.T) {
+	now := time.Date(2021, 02, 24, 16, 10, 01, 0, time.UTC)
+	critical, warning := 7*24*time.Hour, 30*24*time.Hour
+	tests := []struct {
+		name string
+		date time.Time
+		want string
+	}{
+		{name: "expired", date: now.Add(-time.Hour), want: CertificateExpired},
+		{name: "expires now", date: now, want: CertificateExpired},
+		{name: "critical", date: now.AddDate(0, 0, 3), want: CertificateCritical},
+		{name: "warning", date: now.AddDate(0, 0, 20), want: CertificateWarning},
+		{name: "valid", date: now.AddDate(1, 0, 0), want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExpirationSeverity(tt.date, now, critical, warning); got != tt.want {
+				t.Errorf("ExpirationSeverity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
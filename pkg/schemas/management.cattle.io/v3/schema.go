@@ -251,8 +251,11 @@ func clusterTypes(schemas *types.Schemas) *types.Schemas {
 		MustImport(&Version, v3.MonitoringInput{}).
 		MustImport(&Version, v3.MonitoringOutput{}).
 		MustImport(&Version, v3.RestoreFromEtcdBackupInput{}).
+		MustImport(&Version, v3.RestoreFromEtcdBackupOutput{}).
 		MustImport(&Version, v3.SaveAsTemplateInput{}).
 		MustImport(&Version, v3.SaveAsTemplateOutput{}).
+		MustImport(&Version, v3.ExpiringCertificatesInput{}).
+		MustImport(&Version, v3.ExpiringCertificatesOutput{}).
 		AddMapperForType(&Version, v1.EnvVar{},
 			&m.Move{
 				From: "envVar",
@@ -295,7 +298,8 @@ func clusterTypes(schemas *types.Schemas) *types.Schemas {
 			}
 			schema.ResourceActions[v3.ClusterActionBackupEtcd] = types.Action{}
 			schema.ResourceActions[v3.ClusterActionRestoreFromEtcdBackup] = types.Action{
-				Input: "restoreFromEtcdBackupInput",
+				Input:  "restoreFromEtcdBackupInput",
+				Output: "restoreFromEtcdBackupOutput",
 			}
 			schema.ResourceActions[v3.ClusterActionRotateCertificates] = types.Action{
 				Input:  "rotateCertificateInput",
@@ -311,6 +315,12 @@ func clusterTypes(schemas *types.Schemas) *types.Schemas {
 				Input:  "saveAsTemplateInput",
 				Output: "saveAsTemplateOutput",
 			}
+			schema.CollectionActions = map[string]types.Action{
+				v3.ClusterCollectionActionExpiringCertificates: {
+					Input:  "expiringCertificatesInput",
+					Output: "expiringCertificatesOutput",
+				},
+			}
 		})
 }
 
@@ -443,9 +453,15 @@ func nodeTypes(schemas *types.Schemas) *types.Schemas {
 
 func tokens(schemas *types.Schemas) *types.Schemas {
 	return schemas.
+		MustImport(&Version, v3.TokenRevokeInput{}).
+		MustImport(&Version, v3.TokenRevokeOutput{}).
 		MustImportAndCustomize(&Version, v3.Token{}, func(schema *types.Schema) {
 			schema.CollectionActions = map[string]types.Action{
 				"logout": {},
+				"revoke": {
+					Input:  "tokenRevokeInput",
+					Output: "tokenRevokeOutput",
+				},
 			}
 		})
 }
@@ -472,6 +488,8 @@ func authnTypes(schemas *types.Schemas) *types.Schemas {
 		MustImport(&Version, v3.SearchPrincipalsInput{}).
 		MustImport(&Version, v3.ChangePasswordInput{}).
 		MustImport(&Version, v3.SetPasswordInput{}).
+		MustImport(&Version, v3.VerifyMFAInput{}).
+		MustImport(&Version, v3.MFAEnrollOutput{}).
 		MustImportAndCustomize(&Version, v3.User{}, func(schema *types.Schema) {
 			schema.ResourceActions = map[string]types.Action{
 				"setpassword": {
@@ -479,12 +497,22 @@ func authnTypes(schemas *types.Schemas) *types.Schemas {
 					Output: "user",
 				},
 				"refreshauthprovideraccess": {},
+				"unlock": {
+					Output: "user",
+				},
+				"resetmfa": {},
 			}
 			schema.CollectionActions = map[string]types.Action{
 				"changepassword": {
 					Input: "changePasswordInput",
 				},
 				"refreshauthprovideraccess": {},
+				"enablemfa": {
+					Output: "mfaEnrollOutput",
+				},
+				"verifymfa": {
+					Input: "verifyMFAInput",
+				},
 			}
 		}).
 		MustImportAndCustomize(&Version, v3.AuthConfig{}, func(schema *types.Schema) {
@@ -749,6 +777,7 @@ func alertTypes(schema *types.Schemas) *types.Schemas {
 		MustImport(&Version, v3.ClusterAlert{}).
 		MustImport(&Version, v3.ProjectAlert{}).
 		MustImport(&Version, v3.Notification{}).
+		MustImport(&Version, v3.NotifierResendInput{}).
 		MustImportAndCustomize(&Version, v3.Notifier{}, func(schema *types.Schema) {
 			schema.CollectionActions = map[string]types.Action{
 				"send": {
@@ -759,6 +788,9 @@ func alertTypes(schema *types.Schemas) *types.Schemas {
 				"send": {
 					Input: "notification",
 				},
+				"resend": {
+					Input: "notifierResendInput",
+				},
 			}
 		}).
 		MustImport(&Version, v3.AlertStatus{}).
@@ -791,7 +823,11 @@ func alertTypes(schema *types.Schemas) *types.Schemas {
 				"mute":       {},
 				"unmute":     {},
 			}
-		})
+		}).
+		AddMapperForType(&Version, v3.AlertSilence{},
+			&m.Embed{Field: "status"},
+			m.DisplayName{}).
+		MustImport(&Version, v3.AlertSilence{})
 
 }
 
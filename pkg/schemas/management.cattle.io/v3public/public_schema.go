@@ -41,11 +41,23 @@ func authProvidersTypes(schemas *types.Schemas) *types.Schemas {
 					Input:  "basicLogin",
 					Output: "token",
 				},
+				"mfalogin": {
+					Input:  "mfaLoginInput",
+					Output: "token",
+				},
+				"mfaenroll": {
+					Input:  "mfaEnrollInput",
+					Output: "mfaEnrollOutput",
+				},
 			}
 			schema.CollectionMethods = []string{}
 			schema.ResourceMethods = []string{http.MethodGet}
 		}).
 		MustImport(&PublicVersion, v3.BasicLogin{}).
+		MustImport(&PublicVersion, v3.MFAChallenge{}).
+		MustImport(&PublicVersion, v3.MFALoginInput{}).
+		MustImport(&PublicVersion, v3.MFAEnrollInput{}).
+		MustImport(&PublicVersion, v3.MFAEnrollOutput{}).
 		// Github provider
 		MustImportAndCustomize(&PublicVersion, v3.GithubProvider{}, func(schema *types.Schema) {
 			schema.BaseType = "authProvider"
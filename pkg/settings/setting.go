@@ -22,12 +22,24 @@
 	AgentImage                        = NewSetting("agent-image", "rancher/rancher-agent:master-head")
 	AgentRolloutTimeout               = NewSetting("agent-rollout-timeout", "300s")
 	AgentRolloutWait                  = NewSetting("agent-rollout-wait", "true")
+	AuditLogSinks                     = NewSetting("audit-log-sinks", "file")      // comma separated audit log destinations: file, webhook, syslog and stdout
+	AuditLogSyslogAddress             = NewSetting("audit-log-syslog-address", "") // host:port of the syslog server the syslog sink sends to over TCP
+	AuditLogSyslogCACerts             = NewSetting("audit-log-syslog-cacerts", "") // PEM CA bundle verifying the syslog server, the system roots are used when empty
+	AuditLogSyslogTLS                 = NewSetting("audit-log-syslog-tls", "false")
+	AuditLogWebhookBatchSize          = NewSetting("audit-log-webhook-batch-size", "100")  // maximum number of entries POSTed to the webhook in a single request
+	AuditLogWebhookFlushSeconds       = NewSetting("audit-log-webhook-flush-seconds", "5") // maximum time entries are buffered before being POSTed to the webhook
+	AuditLogWebhookMaxRetries         = NewSetting("audit-log-webhook-max-retries", "5")   // failed deliveries are retried this many times before the batch is dropped
+	AuditLogWebhookURL                = NewSetting("audit-log-webhook-url", "")
+	AuditPolicy                       = NewSetting("audit-policy", "") // JSON encoded audit policy.Policy, applied before the global audit level
 	AuthImage                         = NewSetting("auth-image", v32.ToolsSystemImages.AuthSystemImages.KubeAPIAuth)
-	AuthTokenMaxTTLMinutes            = NewSetting("auth-token-max-ttl-minutes", "0") // never expire
+	AuthTokenIdleTimeoutDays          = NewSetting("auth-token-idle-timeout-days", "0") // tokens unused for this many days are revoked, 0 disables idle expiry
+	AuthTokenMaxTTLMinutes            = NewSetting("auth-token-max-ttl-minutes", "0")   // never expire
 	AuthorizationCacheTTLSeconds      = NewSetting("authorization-cache-ttl-seconds", "10")
 	AuthorizationDenyCacheTTLSeconds  = NewSetting("authorization-deny-cache-ttl-seconds", "10")
 	AzureGroupCacheSize               = NewSetting("azure-group-cache-size", "10000")
 	CACerts                           = NewSetting("cacerts", "")
+	CertificateExpirationCriticalDays = NewSetting("certificate-expiration-critical-days", "7") // certificates expiring within this many days are reported as critical
+	CertificateExpirationWarningDays  = NewSetting("certificate-expiration-warning-days", "30") // certificates expiring within this many days are reported as warning
 	CLIURLDarwin                      = NewSetting("cli-url-darwin", "https://releases.rancher.com/cli/v1.0.0-alpha8/rancher-darwin-amd64-v1.0.0-alpha8.tar.gz")
 	CLIURLLinux                       = NewSetting("cli-url-linux", "https://releases.rancher.com/cli/v1.0.0-alpha8/rancher-linux-amd64-v1.0.0-alpha8.tar.gz")
 	CLIURLWindows                     = NewSetting("cli-url-windows", "https://releases.rancher.com/cli/v1.0.0-alpha8/rancher-windows-386-v1.0.0-alpha8.zip")
@@ -36,6 +48,7 @@
 	EngineISOURL                      = NewSetting("engine-iso-url", "https://releases.rancher.com/os/latest/rancheros-vmware.iso")
 	EngineNewestVersion               = NewSetting("engine-newest-version", "v17.12.0")
 	EngineSupportedRange              = NewSetting("engine-supported-range", "~v1.11.2 || ~v1.12.0 || ~v1.13.0 || ~v17.03.0 || ~v17.06.0 || ~v17.09.0 || ~v18.06.0 || ~v18.09.0 || ~v19.03.0 || ~v20.10.0 ")
+	EtcdSnapshotVerifyIntervalHours   = NewSetting("etcd-snapshot-verify-interval-hours", "24") // stored etcd snapshots are verified again after this many hours, 0 only verifies them once saved
 	FirstLogin                        = NewSetting("first-login", "true")
 	GlobalRegistryEnabled             = NewSetting("global-registry-enabled", "false")
 	GithubProxyAPIURL                 = NewSetting("github-proxy-api-url", "https://api.github.com")
@@ -54,8 +67,17 @@
 	KubernetesVersionsCurrent         = NewSetting("k8s-versions-current", "")
 	KubernetesVersionsDeprecated      = NewSetting("k8s-versions-deprecated", "")
 	KDMBranch                         = NewSetting("kdm-branch", "dev-v2.6")
+	LocalAuthLockoutDurationMinutes   = NewSetting("local-auth-lockout-duration-minutes", "15")
+	LocalAuthLockoutThreshold         = NewSetting("local-auth-lockout-threshold", "0") // failed logins before a local user is locked out, 0 disables lockout
+	LocalAuthMFARequired              = NewSetting("local-auth-mfa-required", "none")   // none, admins or all local users must use a second factor
 	MachineVersion                    = NewSetting("machine-version", "dev")
 	Namespace                         = NewSetting("namespace", os.Getenv("CATTLE_NAMESPACE"))
+	NotifierDedupWindowMinutes        = NewSetting("notifier-dedup-window-minutes", "60")  // identical messages of a notifier are delivered once within this window
+	NotifierRateLimitPerMinute        = NewSetting("notifier-rate-limit-per-minute", "30") // messages a notifier delivers per minute, 0 disables the limit
+	PasswordHistorySize               = NewSetting("password-history-size", "0")           // number of previous passwords a local user may not reuse
+	PasswordMaxAgeDays                = NewSetting("password-max-age-days", "0")           // 0 means local passwords never expire
+	PasswordMinLength                 = NewSetting("password-min-length", "12")
+	PasswordRequireComplexity         = NewSetting("password-require-complexity", "false") // require upper and lower case letters, digits and symbols
 	PeerServices                      = NewSetting("peer-service", os.Getenv("CATTLE_PEER_SERVICE"))
 	RDNSServerBaseURL                 = NewSetting("rdns-base-url", "https://api.lb.rancher.cloud/v1")
 	RkeVersion                        = NewSetting("rke-version", "")
@@ -63,6 +85,13 @@
 	ServerImage                       = NewSetting("server-image", "rancher/rancher")
 	ServerURL                         = NewSetting("server-url", "")
 	ServerVersion                     = NewSetting("server-version", "dev")
+	ShowbackCPUCoreHourPrice          = NewSetting("showback-cpu-core-hour-price", "0")
+	ShowbackCurrency                  = NewSetting("showback-currency", "USD")
+	ShowbackMemoryGiBHourPrice        = NewSetting("showback-memory-gib-hour-price", "0")
+	ShowbackRetentionDays             = NewSetting("showback-retention-days", "400") // 0 disables showback
+	ShowbackStorageGiBHourPrice       = NewSetting("showback-storage-gib-hour-price", "0")
+	StatsHistoryIntervalMinutes       = NewSetting("stats-history-interval-minutes", "5") // resource totals of clusters and projects are sampled at this interval
+	StatsHistoryRetentionDays         = NewSetting("stats-history-retention-days", "30")  // 0 disables the stats history
 	SystemAgentVersion                = NewSetting("system-agent-version", "")
 	SystemAgentInstallScript          = NewSetting("system-agent-install-script", "https://raw.githubusercontent.com/rancher/system-agent/main/install.sh")
 	WindowsRke2InstallScript          = NewSetting("windows-rke2-install-script", "https://raw.githubusercontent.com/rancher/rke2/master/windows/rke2-install.ps1")
@@ -0,0 +1,135 @@
+package showback
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// NamespaceReport is the usage and cost of a namespace over the days of a report.
+type NamespaceReport struct {
+	Namespace string  `json:"namespace"`
+	Usage     Usage   `json:"usage"`
+	Cost      float64 `json:"cost"`
+}
+
+// ProjectReport is the usage and cost of the namespaces of a project over the days of a report. Namespaces that are
+// not in a project are reported with an empty project.
+type ProjectReport struct {
+	Project    string            `json:"project"`
+	Usage      Usage             `json:"usage"`
+	Cost       float64           `json:"cost"`
+	Namespaces []NamespaceReport `json:"namespaces"`
+}
+
+// Report is the usage and cost of a cluster, or a project of the cluster, from the first to the last day of the
+// report.
+type Report struct {
+	Cluster  string          `json:"cluster"`
+	From     string          `json:"from"`
+	To       string          `json:"to"`
+	Prices   Prices          `json:"prices"`
+	Usage    Usage           `json:"usage"`
+	Cost     float64         `json:"cost"`
+	Projects []ProjectReport `json:"projects"`
+}
+
+// Days returns the days from the first to the last day, both included.
+func Days(from, to time.Time) []string {
+	var days []string
+	for day := from.UTC().Truncate(24 * time.Hour); !day.After(to); day = day.Add(24 * time.Hour) {
+		days = append(days, Day(day))
+	}
+	return days
+}
+
+// NewReport sums up the usage of the days by project and namespace and prices it. If project is set, only the
+// namespaces of that project are reported.
+func NewReport(cluster, project string, days []string, usage map[string]DayUsage, prices Prices) *Report {
+	report := &Report{
+		Cluster:  cluster,
+		Prices:   prices,
+		Projects: []ProjectReport{},
+	}
+	if len(days) > 0 {
+		report.From, report.To = days[0], days[len(days)-1]
+	}
+
+	projects := map[string]map[string]*Usage{}
+	for _, day := range days {
+		for namespace, nsUsage := range usage[day] {
+			if project != "" && nsUsage.Project != project {
+				continue
+			}
+			namespaces := projects[nsUsage.Project]
+			if namespaces == nil {
+				namespaces = map[string]*Usage{}
+				projects[nsUsage.Project] = namespaces
+			}
+			if namespaces[namespace] == nil {
+				namespaces[namespace] = &Usage{}
+			}
+			namespaces[namespace].Add(nsUsage.Usage)
+		}
+	}
+
+	for projectName, namespaces := range projects {
+		projectReport := ProjectReport{
+			Project: projectName,
+		}
+		for namespace, nsUsage := range namespaces {
+			projectReport.Namespaces = append(projectReport.Namespaces, NamespaceReport{
+				Namespace: namespace,
+				Usage:     *nsUsage,
+				Cost:      prices.Cost(*nsUsage),
+			})
+			projectReport.Usage.Add(*nsUsage)
+		}
+		sort.Slice(projectReport.Namespaces, func(i, j int) bool {
+			return projectReport.Namespaces[i].Namespace < projectReport.Namespaces[j].Namespace
+		})
+		projectReport.Cost = prices.Cost(projectReport.Usage)
+		report.Projects = append(report.Projects, projectReport)
+		report.Usage.Add(projectReport.Usage)
+	}
+	sort.Slice(report.Projects, func(i, j int) bool {
+		return report.Projects[i].Project < report.Projects[j].Project
+	})
+	report.Cost = prices.Cost(report.Usage)
+	return report
+}
+
+// WriteCSV writes the report with a row per namespace.
+func WriteCSV(w io.Writer, report *Report) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"cluster", "project", "namespace", "from", "to", "cpuCoreHours", "memoryGiBHours",
+		"storageGiBHours", "cost", "currency"}); err != nil {
+		return err
+	}
+	for _, project := range report.Projects {
+		for _, namespace := range project.Namespaces {
+			if err := out.Write([]string{
+				report.Cluster,
+				project.Project,
+				namespace.Namespace,
+				report.From,
+				report.To,
+				formatFloat(namespace.Usage.CPUCoreHours),
+				formatFloat(namespace.Usage.MemoryGiBHours),
+				formatFloat(namespace.Usage.StorageGiBHours),
+				formatFloat(namespace.Cost),
+				report.Prices.Currency,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 4, 64)
+}
@@ -0,0 +1,183 @@
+/*
+Package showback accounts the resources requested by the namespaces of the downstream clusters and prices them for
+showback reports. The usage of a namespace is the integral of its requested CPU, memory and storage over time, which
+is accumulated per UTC day and stored compressed in a config map per cluster and day in the namespace of the cluster in
+the management cluster.
+*/
+package showback
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"time"
+
+	"github.com/rancher/rancher/pkg/settings"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// DateFormat is the format of the days of the usage and the reports.
+	DateFormat = "2006-01-02"
+	// Label marks the config maps holding the usage.
+	Label = "cattle.io/showback"
+
+	// usageKey is the key of the binary data of the config maps holding the gzip compressed usage of the day
+	usageKey = "usage.json.gz"
+	// maxUsageSize is the size the usage of a day may take, leaving room for the metadata below the 1 MiB limit of
+	// config maps
+	maxUsageSize = 900 * 1024
+
+	gib = 1 << 30
+)
+
+// Usage is an amount of requested resources over time.
+type Usage struct {
+	CPUCoreHours    float64 `json:"cpuCoreHours"`
+	MemoryGiBHours  float64 `json:"memoryGiBHours"`
+	StorageGiBHours float64 `json:"storageGiBHours"`
+}
+
+// Add adds the other usage to the usage.
+func (u *Usage) Add(other Usage) {
+	u.CPUCoreHours += other.CPUCoreHours
+	u.MemoryGiBHours += other.MemoryGiBHours
+	u.StorageGiBHours += other.StorageGiBHours
+}
+
+// UsageOf returns the usage of requesting the resources of the list and the storage for the duration.
+func UsageOf(requests corev1.ResourceList, storage int64, d time.Duration) Usage {
+	hours := d.Hours()
+	return Usage{
+		CPUCoreHours:    float64(requests.Cpu().MilliValue()) / 1000 * hours,
+		MemoryGiBHours:  float64(requests.Memory().Value()) / gib * hours,
+		StorageGiBHours: float64(storage) / gib * hours,
+	}
+}
+
+// NamespaceUsage is the usage of a namespace on a day. Project is the name of the project the namespace was last seen
+// in on that day, it is empty if the namespace is not in a project.
+type NamespaceUsage struct {
+	Project string `json:"project,omitempty"`
+	Usage
+}
+
+// DayUsage is the usage of the namespaces of a cluster on a day, by the name of the namespace.
+type DayUsage map[string]*NamespaceUsage
+
+// Add adds the usage to the namespace and updates its project.
+func (d DayUsage) Add(namespace, project string, usage Usage) {
+	ns := d[namespace]
+	if ns == nil {
+		ns = &NamespaceUsage{}
+		d[namespace] = ns
+	}
+	ns.Project = project
+	ns.Usage.Add(usage)
+}
+
+// Day returns the day of the time as used for the usage and reports.
+func Day(t time.Time) string {
+	return t.UTC().Format(DateFormat)
+}
+
+// ConfigMapName returns the name of the config map holding the usage of the day of the time.
+func ConfigMapName(t time.Time) string {
+	return "showback-" + Day(t)
+}
+
+// LoadDay returns the usage of the day stored in the binary data of its config map.
+func LoadDay(data map[string][]byte) (DayUsage, error) {
+	usage := DayUsage{}
+	value, ok := data[usageKey]
+	if !ok {
+		return usage, nil
+	}
+	r, err := gzip.NewReader(bytes.NewReader(value))
+	if err != nil {
+		return nil, fmt.Errorf("invalid showback usage: %w", err)
+	}
+	value, err = ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid showback usage: %w", err)
+	}
+	if err := json.Unmarshal(value, &usage); err != nil {
+		return nil, fmt.Errorf("invalid showback usage: %w", err)
+	}
+	return usage, nil
+}
+
+// StoreDay stores the usage of the day in the binary data of its config map. It fails if the compressed usage does
+// not fit into the config map.
+func StoreDay(data map[string][]byte, usage DayUsage) error {
+	value, err := json.Marshal(usage)
+	if err != nil {
+		return err
+	}
+	buf := &bytes.Buffer{}
+	w := gzip.NewWriter(buf)
+	if _, err := w.Write(value); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	if buf.Len() > maxUsageSize {
+		return fmt.Errorf("showback usage of %d namespaces takes %d bytes, more than the %d bytes a config map can hold",
+			len(usage), buf.Len(), maxUsageSize)
+	}
+	data[usageKey] = buf.Bytes()
+	return nil
+}
+
+// Retention returns how long the usage is kept, showback is disabled if it is zero.
+func Retention() time.Duration {
+	days := settings.ShowbackRetentionDays.GetInt()
+	if days <= 0 {
+		return 0
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// Prices are the prices of the resources in Currency.
+type Prices struct {
+	Currency       string  `json:"currency"`
+	CPUCoreHour    float64 `json:"cpuCoreHour"`
+	MemoryGiBHour  float64 `json:"memoryGiBHour"`
+	StorageGiBHour float64 `json:"storageGiBHour"`
+}
+
+// GetPrices returns the prices configured in the settings, invalid prices are zero.
+func GetPrices() Prices {
+	return Prices{
+		Currency:       settings.ShowbackCurrency.Get(),
+		CPUCoreHour:    parsePrice(settings.ShowbackCPUCoreHourPrice.Get()),
+		MemoryGiBHour:  parsePrice(settings.ShowbackMemoryGiBHourPrice.Get()),
+		StorageGiBHour: parsePrice(settings.ShowbackStorageGiBHourPrice.Get()),
+	}
+}
+
+// ParsePrice parses the value of a price setting.
+func ParsePrice(value string) (float64, error) {
+	price, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	if price < 0 {
+		return 0, fmt.Errorf("must not be negative")
+	}
+	return price, nil
+}
+
+func parsePrice(value string) float64 {
+	price, _ := ParsePrice(value)
+	return price
+}
+
+// Cost returns the price of the usage.
+func (p Prices) Cost(usage Usage) float64 {
+	return usage.CPUCoreHours*p.CPUCoreHour + usage.MemoryGiBHours*p.MemoryGiBHour + usage.StorageGiBHours*p.StorageGiBHour
+}
@@ -0,0 +1,131 @@
+package showback
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestUsageOf(t *testing.T) {
+	usage := UsageOf(corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("500m"),
+		corev1.ResourceMemory: resource.MustParse("2Gi"),
+	}, 10<<30, 30*time.Minute)
+	assert.Equal(t, Usage{CPUCoreHours: 0.25, MemoryGiBHours: 1, StorageGiBHours: 5}, usage)
+}
+
+func TestStoreLoadDay(t *testing.T) {
+	data := map[string][]byte{}
+	day := DayUsage{}
+	day.Add("ns1", "p-1", Usage{CPUCoreHours: 1})
+	day.Add("ns1", "p-2", Usage{CPUCoreHours: 2})
+	day.Add("ns2", "", Usage{MemoryGiBHours: 3})
+	require.NoError(t, StoreDay(data, day))
+
+	loaded, err := LoadDay(data)
+	require.NoError(t, err)
+	assert.Equal(t, day, loaded)
+	assert.Equal(t, "p-2", loaded["ns1"].Project, "expected the namespace to be in the project it was last seen in")
+	assert.Equal(t, float64(3), loaded["ns1"].CPUCoreHours)
+
+	loaded, err = LoadDay(nil)
+	require.NoError(t, err)
+	assert.Empty(t, loaded)
+
+	data[usageKey] = []byte("{")
+	_, err = LoadDay(data)
+	assert.Error(t, err)
+}
+
+func TestStoreDayManyNamespaces(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	randomDay := func(namespaces int) DayUsage {
+		day := DayUsage{}
+		for i := 0; i < namespaces; i++ {
+			day.Add(fmt.Sprintf("team-%d-%x", i, rnd.Int63()), fmt.Sprintf("p-%05d", rnd.Intn(1000)), Usage{
+				CPUCoreHours:    rnd.Float64() * 100,
+				MemoryGiBHours:  rnd.Float64() * 400,
+				StorageGiBHours: rnd.Float64() * 2400,
+			})
+		}
+		return day
+	}
+
+	// far more than the 1 MiB of a config map uncompressed
+	day := randomDay(10000)
+	data := map[string][]byte{}
+	require.NoError(t, StoreDay(data, day))
+	assert.LessOrEqual(t, len(data[usageKey]), maxUsageSize)
+	loaded, err := LoadDay(data)
+	require.NoError(t, err)
+	assert.Equal(t, day, loaded)
+
+	data = map[string][]byte{}
+	assert.Error(t, StoreDay(data, randomDay(100000)), "expected usage exceeding a config map to be rejected")
+	assert.Empty(t, data)
+}
+
+func TestParsePrice(t *testing.T) {
+	price, err := ParsePrice("0.031")
+	require.NoError(t, err)
+	assert.Equal(t, 0.031, price)
+
+	_, err = ParsePrice("-1")
+	assert.Error(t, err)
+	_, err = ParsePrice("1 USD")
+	assert.Error(t, err)
+}
+
+func TestDays(t *testing.T) {
+	from := time.Date(2021, 7, 30, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []string{"2021-07-30", "2021-07-31", "2021-08-01"}, Days(from, from.Add(48*time.Hour)))
+	assert.Equal(t, []string{"2021-07-30"}, Days(from, from))
+	assert.Empty(t, Days(from, from.Add(-time.Hour)))
+}
+
+func TestReport(t *testing.T) {
+	usage := map[string]DayUsage{
+		"2021-08-01": {
+			"app":    {Project: "p-1", Usage: Usage{CPUCoreHours: 24, MemoryGiBHours: 48}},
+			"db":     {Project: "p-1", Usage: Usage{CPUCoreHours: 12, StorageGiBHours: 240}},
+			"system": {Usage: Usage{CPUCoreHours: 6}},
+		},
+		"2021-08-02": {
+			"app": {Project: "p-1", Usage: Usage{CPUCoreHours: 24, MemoryGiBHours: 48}},
+			"web": {Project: "p-2", Usage: Usage{CPUCoreHours: 2}},
+		},
+		"2021-08-03": {
+			"app": {Project: "p-1", Usage: Usage{CPUCoreHours: 100}},
+		},
+	}
+	prices := Prices{Currency: "EUR", CPUCoreHour: 0.03, MemoryGiBHour: 0.004, StorageGiBHour: 0.0001}
+
+	report := NewReport("c-1", "", []string{"2021-08-01", "2021-08-02"}, usage, prices)
+	assert.Equal(t, "2021-08-01", report.From)
+	assert.Equal(t, "2021-08-02", report.To)
+	require.Len(t, report.Projects, 3)
+	assert.Equal(t, "", report.Projects[0].Project)
+	assert.Equal(t, "p-1", report.Projects[1].Project)
+	require.Len(t, report.Projects[1].Namespaces, 2)
+	assert.Equal(t, "app", report.Projects[1].Namespaces[0].Namespace)
+	assert.Equal(t, Usage{CPUCoreHours: 48, MemoryGiBHours: 96}, report.Projects[1].Namespaces[0].Usage)
+	assert.InDelta(t, 48*0.03+96*0.004, report.Projects[1].Namespaces[0].Cost, 1e-9)
+	assert.Equal(t, Usage{CPUCoreHours: 68, MemoryGiBHours: 96, StorageGiBHours: 240}, report.Usage)
+	assert.InDelta(t, 68*0.03+96*0.004+240*0.0001, report.Cost, 1e-9)
+
+	report = NewReport("c-1", "p-2", []string{"2021-08-01", "2021-08-02"}, usage, prices)
+	require.Len(t, report.Projects, 1)
+	assert.Equal(t, Usage{CPUCoreHours: 2}, report.Usage)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, WriteCSV(buf, report))
+	assert.Equal(t, "cluster,project,namespace,from,to,cpuCoreHours,memoryGiBHours,storageGiBHours,cost,currency\n"+
+		"c-1,p-2,web,2021-08-01,2021-08-02,2.0000,0.0000,0.0000,0.0600,EUR\n", buf.String())
+}
@@ -0,0 +1,293 @@
+/*
+Package statshistory keeps a rolling history of the resource totals of clusters and projects. The samples are stored
+compactly in config maps next to the clusters and projects in the management cluster: every value is stored as the
+varint encoded difference to the previous sample, which is zero for most of the samples, and the result is gzipped.
+*/
+package statshistory
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/rancher/rancher/pkg/settings"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	formatVersion = 1
+	// columns is the number of values of a sample, the time and the four resource totals
+	columns = 13
+)
+
+// Resources are the totals of a sample, CPU in millicores and memory in bytes.
+type Resources struct {
+	CPU    int64 `json:"cpuMillicores"`
+	Memory int64 `json:"memoryBytes"`
+	Pods   int64 `json:"pods"`
+}
+
+// ResourcesFromList returns the CPU, memory and pods of the list.
+func ResourcesFromList(list corev1.ResourceList) Resources {
+	return Resources{
+		CPU:    list.Cpu().MilliValue(),
+		Memory: list.Memory().Value(),
+		Pods:   list.Pods().Value(),
+	}
+}
+
+// Sample holds the resource totals of a cluster or project at a time. Projects have no capacity or allocatable.
+type Sample struct {
+	Time        time.Time `json:"time"`
+	Capacity    Resources `json:"capacity"`
+	Allocatable Resources `json:"allocatable"`
+	Requested   Resources `json:"requested"`
+	Limits      Resources `json:"limits"`
+}
+
+func (s Sample) values() [columns]int64 {
+	return [columns]int64{
+		s.Time.Unix(),
+		s.Capacity.CPU, s.Capacity.Memory, s.Capacity.Pods,
+		s.Allocatable.CPU, s.Allocatable.Memory, s.Allocatable.Pods,
+		s.Requested.CPU, s.Requested.Memory, s.Requested.Pods,
+		s.Limits.CPU, s.Limits.Memory, s.Limits.Pods,
+	}
+}
+
+func sampleFromValues(v [columns]int64) Sample {
+	return Sample{
+		Time:        time.Unix(v[0], 0).UTC(),
+		Capacity:    Resources{CPU: v[1], Memory: v[2], Pods: v[3]},
+		Allocatable: Resources{CPU: v[4], Memory: v[5], Pods: v[6]},
+		Requested:   Resources{CPU: v[7], Memory: v[8], Pods: v[9]},
+		Limits:      Resources{CPU: v[10], Memory: v[11], Pods: v[12]},
+	}
+}
+
+// Interval returns the minimum time between two samples.
+func Interval() time.Duration {
+	minutes := settings.StatsHistoryIntervalMinutes.GetInt()
+	if minutes <= 0 {
+		minutes = 5
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// Retention returns how long samples are kept, the history is disabled if it is zero.
+func Retention() time.Duration {
+	days := settings.StatsHistoryRetentionDays.GetInt()
+	if days <= 0 {
+		return 0
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// Encode returns the compact representation of the samples, which have to be sorted by time.
+func Encode(samples []Sample) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+
+	varint := make([]byte, binary.MaxVarintLen64)
+	write := func(n int) error {
+		_, err := gz.Write(varint[:n])
+		return err
+	}
+	if err := write(binary.PutUvarint(varint, formatVersion)); err != nil {
+		return nil, err
+	}
+	if err := write(binary.PutUvarint(varint, uint64(len(samples)))); err != nil {
+		return nil, err
+	}
+
+	var prev [columns]int64
+	for _, sample := range samples {
+		values := sample.values()
+		for i, value := range values {
+			if err := write(binary.PutVarint(varint, value-prev[i])); err != nil {
+				return nil, err
+			}
+		}
+		prev = values
+	}
+
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode returns the samples encoded by Encode.
+func Decode(data []byte) ([]Sample, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	r := bufio.NewReader(gz)
+
+	version, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if version != formatVersion {
+		return nil, fmt.Errorf("unsupported stats history version %d", version)
+	}
+	count, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		samples []Sample
+		values  [columns]int64
+	)
+	for i := uint64(0); i < count; i++ {
+		for j := range values {
+			delta, err := binary.ReadVarint(r)
+			if err == io.EOF {
+				return nil, io.ErrUnexpectedEOF
+			} else if err != nil {
+				return nil, err
+			}
+			values[j] += delta
+		}
+		samples = append(samples, sampleFromValues(values))
+	}
+	return samples, nil
+}
+
+// Append adds the sample to the history unless the last sample is more recent than the interval, and drops the
+// samples older than the retention. It returns false if the sample was not added.
+func Append(samples []Sample, sample Sample, interval, retention time.Duration) ([]Sample, bool) {
+	if len(samples) > 0 && sample.Time.Sub(samples[len(samples)-1].Time) < interval {
+		return samples, false
+	}
+	samples = append(samples, sample)
+
+	cutoff := sample.Time.Add(-retention)
+	i := 0
+	for i < len(samples) && samples[i].Time.Before(cutoff) {
+		i++
+	}
+	return samples[i:], true
+}
+
+// Query returns the samples since the given time. If step is set, the samples of every step are averaged into one
+// sample at the start of the step.
+func Query(samples []Sample, since time.Time, step time.Duration) []Sample {
+	var result []Sample
+	for _, sample := range samples {
+		if !sample.Time.Before(since) {
+			result = append(result, sample)
+		}
+	}
+	if step <= 0 || len(result) == 0 {
+		return result
+	}
+
+	var (
+		averaged []Sample
+		sum      [columns]int64
+		count    int64
+		start    time.Time
+	)
+	flush := func() {
+		if count == 0 {
+			return
+		}
+		for i := range sum {
+			sum[i] /= count
+		}
+		sum[0] = start.Unix()
+		averaged = append(averaged, sampleFromValues(sum))
+		sum, count = [columns]int64{}, 0
+	}
+	for _, sample := range result {
+		bucket := sample.Time.Truncate(step)
+		if !bucket.Equal(start) {
+			flush()
+			start = bucket
+		}
+		for i, value := range sample.values() {
+			sum[i] += value
+		}
+		count++
+	}
+	flush()
+	return averaged
+}
+
+// Trend is the change of the requested resources over the samples.
+type Trend struct {
+	// RequestedPerDay is the growth of the requested resources per day, fitted with a linear regression.
+	RequestedPerDay Resources `json:"requestedPerDay"`
+	// CPUExhaustedAt and MemoryExhaustedAt estimate when the requested resources reach the allocatable resources of
+	// the last sample if the growth continues. They are not set if the requests do not grow or there is nothing
+	// allocatable, as for projects.
+	CPUExhaustedAt    *time.Time `json:"cpuExhaustedAt,omitempty"`
+	MemoryExhaustedAt *time.Time `json:"memoryExhaustedAt,omitempty"`
+}
+
+// GetTrend returns the trend of the samples, or nil if there are less than two samples.
+func GetTrend(samples []Sample) *Trend {
+	if len(samples) < 2 {
+		return nil
+	}
+
+	last := samples[len(samples)-1]
+	cpu := slopePerDay(samples, func(s Sample) int64 { return s.Requested.CPU })
+	memory := slopePerDay(samples, func(s Sample) int64 { return s.Requested.Memory })
+	pods := slopePerDay(samples, func(s Sample) int64 { return s.Requested.Pods })
+
+	return &Trend{
+		RequestedPerDay: Resources{
+			CPU:    int64(cpu),
+			Memory: int64(memory),
+			Pods:   int64(pods),
+		},
+		CPUExhaustedAt:    exhaustedAt(last.Time, last.Requested.CPU, last.Allocatable.CPU, cpu),
+		MemoryExhaustedAt: exhaustedAt(last.Time, last.Requested.Memory, last.Allocatable.Memory, memory),
+	}
+}
+
+func slopePerDay(samples []Sample, value func(Sample) int64) float64 {
+	origin := samples[0].Time
+	var sumX, sumY, sumXY, sumXX float64
+	for _, sample := range samples {
+		x := sample.Time.Sub(origin).Hours() / 24
+		y := float64(value(sample))
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	n := float64(len(samples))
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denominator
+}
+
+func exhaustedAt(now time.Time, requested, allocatable int64, perDay float64) *time.Time {
+	if allocatable <= 0 || perDay <= 0 {
+		return nil
+	}
+	days := float64(allocatable-requested) / perDay
+	if days < 0 {
+		days = 0
+	}
+	// estimates beyond ten years are meaningless
+	if days > 3650 {
+		return nil
+	}
+	at := now.Add(time.Duration(days * 24 * float64(time.Hour)))
+	return &at
+}
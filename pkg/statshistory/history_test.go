@@ -0,0 +1,107 @@
+package statshistory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var start = time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC)
+
+func testSamples(n int, interval time.Duration) []Sample {
+	var samples []Sample
+	for i := 0; i < n; i++ {
+		samples = append(samples, Sample{
+			Time:        start.Add(time.Duration(i) * interval),
+			Capacity:    Resources{CPU: 8000, Memory: 32 << 30, Pods: 330},
+			Allocatable: Resources{CPU: 7500, Memory: 30 << 30, Pods: 330},
+			Requested:   Resources{CPU: 1000 + int64(i/12)*100, Memory: 4 << 30, Pods: 40 + int64(i%3)},
+			Limits:      Resources{CPU: 2000, Memory: 8 << 30},
+		})
+	}
+	return samples
+}
+
+func TestEncodeDecode(t *testing.T) {
+	samples := testSamples(30*24*12, 5*time.Minute)
+
+	data, err := Encode(samples)
+	require.NoError(t, err)
+	// a month of 5 minute samples has to fit into a config map easily
+	assert.Less(t, len(data), 64*1024)
+
+	decoded, err := Decode(data)
+	require.NoError(t, err)
+	assert.Equal(t, samples, decoded)
+
+	decoded, err = Decode(nil)
+	require.NoError(t, err)
+	assert.Empty(t, decoded)
+
+	_, err = Decode(data[:len(data)/2])
+	assert.Error(t, err)
+}
+
+func TestResourcesFromList(t *testing.T) {
+	assert.Equal(t, Resources{CPU: 1500, Memory: 2 << 30, Pods: 110}, ResourcesFromList(corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("1500m"),
+		corev1.ResourceMemory: resource.MustParse("2Gi"),
+		corev1.ResourcePods:   resource.MustParse("110"),
+	}))
+	assert.Equal(t, Resources{}, ResourcesFromList(nil))
+}
+
+func TestAppend(t *testing.T) {
+	var (
+		samples []Sample
+		added   bool
+	)
+	for _, minutes := range []int{0, 2, 5, 9, 10, 20} {
+		samples, _ = Append(samples, Sample{Time: start.Add(time.Duration(minutes) * time.Minute)}, 5*time.Minute, time.Hour)
+	}
+	require.Len(t, samples, 4)
+	assert.Equal(t, start.Add(20*time.Minute), samples[3].Time)
+
+	samples, added = Append(samples, Sample{Time: start.Add(70 * time.Minute)}, 5*time.Minute, time.Hour)
+	assert.True(t, added)
+	require.Len(t, samples, 3, "expected the samples older than the retention to be dropped")
+	assert.Equal(t, start.Add(10*time.Minute), samples[0].Time)
+
+	_, added = Append(samples, Sample{Time: start.Add(71 * time.Minute)}, 5*time.Minute, time.Hour)
+	assert.False(t, added)
+}
+
+func TestQuery(t *testing.T) {
+	samples := testSamples(48, 5*time.Minute)
+
+	result := Query(samples, start.Add(3*time.Hour), 0)
+	require.Len(t, result, 12)
+	assert.Equal(t, samples[36:], result)
+
+	result = Query(samples, start, time.Hour)
+	require.Len(t, result, 4)
+	assert.Equal(t, start.Add(time.Hour), result[1].Time)
+	assert.Equal(t, int64(1100), result[1].Requested.CPU)
+	assert.Equal(t, int64(41), result[1].Requested.Pods)
+	assert.Equal(t, int64(7500), result[1].Allocatable.CPU)
+}
+
+func TestGetTrend(t *testing.T) {
+	assert.Nil(t, GetTrend(testSamples(1, time.Hour)))
+
+	// the requested CPU grows by 100m every 12 hours
+	trend := GetTrend(testSamples(24*12, time.Hour))
+	require.NotNil(t, trend)
+	assert.InDelta(t, 200, trend.RequestedPerDay.CPU, 5)
+	assert.Equal(t, int64(0), trend.RequestedPerDay.Memory)
+	assert.Nil(t, trend.MemoryExhaustedAt)
+	require.NotNil(t, trend.CPUExhaustedAt)
+
+	// 7500m allocatable, 3300m requested at the end, growing by 200m per day
+	expected := start.Add(287 * time.Hour).Add(21 * 24 * time.Hour)
+	assert.WithinDuration(t, expected, *trend.CPUExhaustedAt, 24*time.Hour)
+}
@@ -0,0 +1,104 @@
+package statshistory
+
+import (
+	"fmt"
+	"time"
+
+	v1 "github.com/rancher/rancher/pkg/generated/norman/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	dataKey                = "samples"
+	clusterConfigMapName   = "cluster-stats-history"
+	projectConfigMapPrefix = "project-stats-history-"
+)
+
+// ClusterConfigMapName returns the name of the config map in the namespace of the cluster holding its history.
+func ClusterConfigMapName() string {
+	return clusterConfigMapName
+}
+
+// ProjectConfigMapName returns the name of the config map in the namespace of the cluster holding the history of the
+// project.
+func ProjectConfigMapName(projectName string) string {
+	return projectConfigMapPrefix + projectName
+}
+
+// Load returns the samples stored in the config map.
+func Load(cm *corev1.ConfigMap) ([]Sample, error) {
+	return Decode(cm.BinaryData[dataKey])
+}
+
+// Recorder appends samples to the histories, it remembers the time of the last sample of every history so that the
+// config maps are only read when a sample is due.
+type Recorder struct {
+	configMaps v1.ConfigMapInterface
+	last       map[string]time.Time
+}
+
+func NewRecorder(configMaps v1.ConfigMapInterface) *Recorder {
+	return &Recorder{
+		configMaps: configMaps,
+		last:       map[string]time.Time{},
+	}
+}
+
+// Record appends the sample to the history in the config map, which is created and owned by owner if it does not
+// exist. Invalid histories are replaced.
+func (r *Recorder) Record(owner metav1.OwnerReference, namespace, name string, sample Sample) error {
+	interval, retention := Interval(), Retention()
+	if retention == 0 {
+		return nil
+	}
+	key := namespace + "/" + name
+	if sample.Time.Sub(r.last[key]) < interval {
+		return nil
+	}
+
+	cm, err := r.configMaps.GetNamespaced(namespace, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       namespace,
+				OwnerReferences: []metav1.OwnerReference{owner},
+			},
+		}
+	} else if err != nil {
+		return err
+	}
+
+	samples, err := Load(cm)
+	if err != nil {
+		samples = nil
+	}
+	samples, added := Append(samples, sample, interval, retention)
+	if !added {
+		r.last[key] = samples[len(samples)-1].Time
+		return nil
+	}
+
+	data, err := Encode(samples)
+	if err != nil {
+		return err
+	}
+	cm = cm.DeepCopy()
+	if cm.BinaryData == nil {
+		cm.BinaryData = map[string][]byte{}
+	}
+	cm.BinaryData[dataKey] = data
+
+	if cm.ResourceVersion == "" {
+		_, err = r.configMaps.Create(cm)
+	} else {
+		_, err = r.configMaps.Update(cm)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store stats history %s: %w", key, err)
+	}
+	r.last[key] = sample.Time
+	return nil
+}
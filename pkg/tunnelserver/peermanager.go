@@ -12,6 +12,7 @@
 
 	"github.com/pkg/errors"
 	"github.com/rancher/norman/types/set"
+	"github.com/rancher/rancher/pkg/metrics/tunnel"
 	"github.com/rancher/rancher/pkg/peermanager"
 	"github.com/rancher/rancher/pkg/settings"
 	"github.com/rancher/remotedialer"
@@ -179,6 +180,7 @@ func (p *peerManager) addRemovePeers(endpoints *v1.Endpoints) {
 
 	p.peers = newSet
 	p.ready = ready
+	tunnel.SetPeers(len(newSet))
 	p.notify()
 }
 